@@ -20,7 +20,6 @@ import (
 //go:embed node_modules/@fortawesome/fontawesome-free/css/solid.min.css
 //go:embed node_modules/@fortawesome/fontawesome-free/webfonts/fa-solid-900.woff2
 //go:embed node_modules/leaflet/dist
-//go:embed node_modules/leaflet-geosearch/dist
 //go:embed node_modules/jquery/dist/jquery.min.js
 //go:embed node_modules/jquery-ui/dist/jquery-ui.min.js
 //go:embed node_modules/jquery-ui/dist/themes/base
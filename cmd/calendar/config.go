@@ -4,13 +4,35 @@ import (
 	"cmp"
 	"errors"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mgnsk/calendar/pkg/nominatim"
 )
 
 // Config is the calendar configuration.
 type Config struct {
-	ListenAddr  string
-	DatabaseDir string
+	ListenAddr      string
+	DatabaseDir     string
+	UploadsDir      string
+	TileURL         string
+	TileAttribution string
+	GeocoderURL     string
+
+	// BaseURL is the public address of the site used in links of emails
+	// sent in the background. It is required when mail is enabled.
+	// Setting it enables ActivityPub federation.
+	BaseURL string
+
+	// SMTP settings. Mail is disabled when SMTPHost is empty.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
 }
 
 // LoadConfig loads the configuration.
@@ -18,8 +40,23 @@ func LoadConfig() (*Config, error) {
 	var errs []error
 
 	c := &Config{
-		ListenAddr:  cmp.Or(os.Getenv("LISTEN_ADDR"), ":8080"),
-		DatabaseDir: os.Getenv("DATABASE_DIR"),
+		ListenAddr:      cmp.Or(os.Getenv("LISTEN_ADDR"), ":8080"),
+		DatabaseDir:     os.Getenv("DATABASE_DIR"),
+		UploadsDir:      os.Getenv("UPLOADS_DIR"),
+		TileURL:         cmp.Or(os.Getenv("TILE_URL"), "https://tile.openstreetmap.org/{z}/{x}/{y}.png"),
+		TileAttribution: cmp.Or(os.Getenv("TILE_ATTRIBUTION"), `&copy; <a href="https://www.openstreetmap.org/copyright">OpenStreetMap</a> contributors`),
+		GeocoderURL:     cmp.Or(os.Getenv("GEOCODER_URL"), nominatim.DefaultBaseURL),
+		BaseURL:         os.Getenv("BASE_URL"),
+		SMTPHost:        os.Getenv("SMTP_HOST"),
+		SMTPUsername:    os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:    os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:        os.Getenv("SMTP_FROM"),
+	}
+
+	if port, err := strconv.Atoi(cmp.Or(os.Getenv("SMTP_PORT"), "587")); err != nil || port <= 0 || port > 65535 {
+		errs = append(errs, fmt.Errorf("smtp_port: must be a valid port"))
+	} else {
+		c.SMTPPort = port
 	}
 
 	if c.ListenAddr == "" {
@@ -30,9 +67,58 @@ func LoadConfig() (*Config, error) {
 		errs = append(errs, fmt.Errorf("database_dir: is required"))
 	}
 
+	if u, err := url.Parse(c.TileURL); err != nil || u.Host == "" {
+		errs = append(errs, fmt.Errorf("tile_url: must be an absolute URL"))
+	}
+
+	if u, err := url.Parse(c.GeocoderURL); err != nil || u.Host == "" {
+		errs = append(errs, fmt.Errorf("geocoder_url: must be an absolute URL"))
+	}
+
+	if c.SMTPHost != "" {
+		if c.SMTPFrom == "" {
+			errs = append(errs, fmt.Errorf("smtp_from: is required when smtp_host is set"))
+		} else if _, err := mail.ParseAddress(c.SMTPFrom); err != nil {
+			errs = append(errs, fmt.Errorf("smtp_from: must be an email address"))
+		}
+
+		if u, err := url.Parse(c.BaseURL); err != nil || u.Host == "" {
+			errs = append(errs, fmt.Errorf("base_url: must be an absolute URL when smtp_host is set"))
+		}
+	}
+
+	if c.BaseURL != "" {
+		if u, err := url.Parse(c.BaseURL); err != nil || u.Host == "" {
+			errs = append(errs, fmt.Errorf("base_url: must be an absolute URL"))
+		}
+	}
+
 	if len(errs) > 0 {
 		return nil, errors.Join(errs...)
 	}
 
 	return c, nil
 }
+
+// TileOrigin returns the tile server origin for the content security policy.
+func (c *Config) TileOrigin() string {
+	u, err := url.Parse(c.TileURL)
+	if err != nil {
+		panic(err)
+	}
+
+	// Subdomain placeholder such as {s}.tile.example.com.
+	host := strings.ReplaceAll(u.Host, "{s}", "*")
+
+	return u.Scheme + "://" + host
+}
+
+// MailEnabled returns whether sending email is configured.
+func (c *Config) MailEnabled() bool {
+	return c.SMTPHost != ""
+}
+
+// FederationEnabled returns whether ActivityPub federation is configured.
+func (c *Config) FederationEnabled() bool {
+	return c.BaseURL != ""
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/nominatim"
+	"github.com/samber/lo"
+)
+
+// nominatimGeocoder adapts the Nominatim client to handler.Geocoder.
+type nominatimGeocoder struct {
+	client *nominatim.Client
+}
+
+func (g *nominatimGeocoder) Search(ctx context.Context, query, language string) ([]*domain.Place, error) {
+	places, err := g.client.Search(ctx, query, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(places, func(p nominatim.Place, _ int) *domain.Place {
+		return &domain.Place{
+			Label:     p.DisplayName,
+			OSMType:   p.OSMType,
+			OSMID:     p.OSMID,
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+		}
+	}), nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/mailer"
+)
+
+// smtpMailer adapts the SMTP client to handler.Mailer.
+type smtpMailer struct {
+	client *mailer.Client
+}
+
+func (m *smtpMailer) Send(ctx context.Context, email *domain.Email) error {
+	return m.client.Send(ctx, mailer.Message{
+		To:      email.To,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	})
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -15,8 +17,14 @@ import (
 	"github.com/alexedwards/scs/bunstore"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/html"
 	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/activitypub"
+	"github.com/mgnsk/calendar/pkg/blobstore"
+	"github.com/mgnsk/calendar/pkg/mailer"
+	"github.com/mgnsk/calendar/pkg/nominatim"
 	"github.com/mgnsk/calendar/pkg/sqlite"
 	"github.com/mgnsk/calendar/server"
 	"github.com/ringsaturn/tzf"
@@ -49,6 +57,14 @@ func run() error {
 
 	filename := filepath.Join(databaseDir, "calendar.sqlite")
 
+	// Uploaded files default to a directory next to the database.
+	uploadsDir, err := filepath.Abs(cmp.Or(cfg.UploadsDir, filepath.Join(databaseDir, "uploads")))
+	if err != nil {
+		return calendar.Internal.New("invalid uploads dir", err)
+	}
+
+	files := blobstore.New(uploadsDir)
+
 	db := sqlite.NewDB(filename).Connect()
 	defer func() {
 		if err := db.Close(); err != nil {
@@ -94,11 +110,187 @@ func run() error {
 				if err := model.DeleteExpiredInvites(ctx, db); err != nil {
 					return err
 				}
+
+				if err := model.DeleteExpiredPasswordResets(ctx, db); err != nil {
+					return err
+				}
+
+				if err := model.DeleteUnconfirmedSubscriptions(ctx, db, time.Now().Add(-72*time.Hour)); err != nil {
+					return err
+				}
+
+				if err := model.DeleteOldWebhookDeliveries(ctx, db, time.Now().Add(-30*24*time.Hour)); err != nil {
+					return err
+				}
+			}
+		}
+	})
+
+	// Run scheduled publishing and old events retention periodic task.
+	g.Go(func() error {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case <-ticker.C:
+				now := time.Now()
+
+				if ids, err := model.PublishScheduledEvents(ctx, db, now); err != nil {
+					return err
+				} else if len(ids) > 0 {
+					slog.Info("published scheduled events", slog.Int("count", len(ids)))
+				}
+
+				settings, err := model.GetCachedSettings(ctx, db)
+				if err != nil {
+					if errors.Is(err, calendar.NotFound) {
+						// Not set up yet.
+						continue
+					}
+					return err
+				}
+
+				if cutoff := settings.GetRetentionCutoff(now); !cutoff.IsZero() {
+					if ids, err := model.ApplyRetention(ctx, db, settings.RetentionAction, cutoff); err != nil {
+						return err
+					} else if len(ids) > 0 {
+						slog.Info("applied retention to old events", slog.String("action", string(settings.RetentionAction)), slog.Int("count", len(ids)))
+					}
+				}
 			}
 		}
 	})
 
-	e := server.NewServer()
+	// Run email outbox periodic task.
+	if cfg.MailEnabled() {
+		m := &smtpMailer{
+			client: mailer.NewClient(mailer.Config{
+				Host:     cfg.SMTPHost,
+				Port:     cfg.SMTPPort,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.SMTPFrom,
+			}),
+		}
+
+		g.Go(func() error {
+			ticker := time.NewTicker(10 * time.Second)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+
+				case <-ticker.C:
+					if n, err := handler.ProcessOutbox(ctx, db, m, time.Now()); err != nil {
+						return err
+					} else if n > 0 {
+						slog.Info("sent queued emails", slog.Int("count", n))
+					}
+				}
+			}
+		})
+	}
+
+	// Run digest subscriptions periodic task.
+	if cfg.MailEnabled() {
+		g.Go(func() error {
+			ticker := time.NewTicker(time.Hour)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+
+				case <-ticker.C:
+					if n, err := handler.SendDigests(ctx, db, cfg.BaseURL, time.Now()); err != nil {
+						if errors.Is(err, calendar.NotFound) {
+							// Not set up yet.
+							continue
+						}
+						return err
+					} else if n > 0 {
+						slog.Info("queued digest emails", slog.Int("count", n))
+					}
+				}
+			}
+		})
+	}
+
+	webhookClient := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	// Run webhook deliveries periodic task.
+	g.Go(func() error {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case <-ticker.C:
+				if n, err := handler.ProcessWebhooks(ctx, db, webhookClient, time.Now()); err != nil {
+					return err
+				} else if n > 0 {
+					slog.Info("delivered webhooks", slog.Int("count", n))
+				}
+			}
+		}
+	})
+
+	// Remote servers choose the addresses of actors and inboxes.
+	activityPubClient := activitypub.NewPublicHTTPClient(10 * time.Second)
+
+	// Run ActivityPub deliveries periodic task.
+	if cfg.FederationEnabled() {
+		g.Go(func() error {
+			ticker := time.NewTicker(10 * time.Second)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+
+				case <-ticker.C:
+					if n, err := handler.ProcessActivityPub(ctx, db, activityPubClient, cfg.BaseURL, time.Now()); err != nil {
+						return err
+					} else if n > 0 {
+						slog.Info("delivered activities", slog.Int("count", n))
+					}
+				}
+			}
+		})
+	}
+
+	// Run orphaned uploads cleanup periodic task.
+	g.Go(func() error {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case <-ticker.C:
+				if err := handler.DeleteOrphanedFiles(ctx, db, files, time.Hour); err != nil {
+					return err
+				}
+			}
+		}
+	})
+
+	e := server.NewServer(cfg.TileOrigin())
 
 	// Initialize the session store.
 	store, err := bunstore.New(db)
@@ -127,6 +319,17 @@ func run() error {
 		return calendar.Internal.New("error creating tzf", err)
 	}
 
+	// Resolve time zone names for events created before time zones were stored.
+	if n, err := model.ResolveEventTimezones(context.Background(), db, finder.GetTimezoneName); err != nil {
+		return calendar.Internal.New("error resolving event time zones", err)
+	} else if n > 0 {
+		slog.Info("resolved event time zones", slog.Int("count", n))
+	}
+
+	geocoder := &nominatimGeocoder{
+		client: nominatim.NewClient(cfg.GeocoderURL),
+	}
+
 	// Static assets.
 	calendar.RegisterAssetsHandler(e)
 
@@ -148,7 +351,7 @@ func run() error {
 			sessionMiddleware,
 		)
 
-		h := handler.NewAuthenticationHandler(db, sm)
+		h := handler.NewAuthenticationHandler(db, sm, cfg.MailEnabled(), cfg.BaseURL)
 		h.Register(g)
 	}
 
@@ -166,11 +369,24 @@ func run() error {
 	// Events management.
 	{
 		g := e.Group("",
+			// Replace the default limit before the CSRF check reads the form.
+			server.BodyLimit(contract.AttachmentBodyLimit),
 			csrfMiddleware,
 			sessionMiddleware,
 		)
 
-		h := handler.NewEditEventHandler(db, sm, finder)
+		h := handler.NewEditEventHandler(db, sm, finder, geocoder, files)
+		h.Register(g)
+	}
+
+	// Location search.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewGeocodeHandler(db, sm, geocoder)
 		h.Register(g)
 	}
 
@@ -181,7 +397,7 @@ func run() error {
 			sessionMiddleware,
 		)
 
-		h := handler.NewUsersHandler(db, sm)
+		h := handler.NewUsersHandler(db, sm, cfg.MailEnabled(), cfg.BaseURL)
 		h.Register(g)
 	}
 
@@ -196,6 +412,182 @@ func run() error {
 		h.Register(g)
 	}
 
+	// Categories management.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewCategoriesHandler(db, sm)
+		h.Register(g)
+	}
+
+	// Site settings.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewSettingsHandler(db, sm)
+		h.Register(g)
+	}
+
+	// Embedded event list.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewEmbedHandler(db, sm)
+		h.Register(g)
+	}
+
+	// Printed program.
+	{
+		h := handler.NewPrintHandler(db, cfg.BaseURL)
+		h.Register(e.Group(""))
+	}
+
+	// Moderation.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewModerationHandler(db, sm, cfg.MailEnabled(), cfg.BaseURL)
+		h.Register(g)
+	}
+
+	// Event templates.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewTemplatesHandler(db, sm)
+		h.Register(g)
+	}
+
+	// RSVPs.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewRSVPHandler(db, sm)
+		h.Register(g)
+	}
+
+	// Digest subscriptions.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewSubscriptionsHandler(db, sm, cfg.MailEnabled(), cfg.BaseURL)
+		h.Register(g)
+	}
+
+	// Webhooks.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewWebhooksHandler(db, sm, webhookClient)
+		h.Register(g)
+	}
+
+	// ActivityPub federation.
+	if cfg.FederationEnabled() {
+		g := e.Group("")
+
+		h := handler.NewActivityPubHandler(db, activityPubClient, cfg.BaseURL)
+		h.Register(g)
+	}
+
+	// CalDAV.
+	{
+		g := e.Group("")
+
+		h := handler.NewCalDAVHandler(db, finder, files)
+		h.Register(g)
+	}
+
+	// App passwords.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewAppPasswordsHandler(db, sm)
+		h.Register(g)
+	}
+
+	// Event management.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewManageHandler(db, sm, files)
+		h.Register(g)
+	}
+
+	// Venues.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewVenuesHandler(db, sm, geocoder)
+		h.Register(g)
+	}
+
+	// Viewer time zone.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewTimezoneHandler(db, sm)
+		h.Register(g)
+	}
+
+	// Map.
+	{
+		g := e.Group("",
+			csrfMiddleware,
+			sessionMiddleware,
+		)
+
+		h := handler.NewMapHandler(db, sm, html.MapTiles{
+			URL:         cfg.TileURL,
+			Attribution: cfg.TileAttribution,
+		})
+		h.Register(g)
+	}
+
+	// Uploaded files.
+	{
+		g := e.Group("")
+
+		h := handler.NewUploadsHandler(db, files)
+		h.Register(g)
+	}
+
 	// Feeds.
 	{
 		// TODO: proper caching middleware for RSS and calendar feeds.
@@ -0,0 +1,6 @@
+package contract
+
+// WebFingerRequest is a WebFinger lookup.
+type WebFingerRequest struct {
+	Resource string `query:"resource"`
+}
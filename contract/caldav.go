@@ -0,0 +1,36 @@
+package contract
+
+import (
+	"net/url"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// CalDAVRequest is a request to a resource in the calendar of a user.
+type CalDAVRequest struct {
+	Username string `param:"username"`
+
+	// Name is the name of a calendar object resource.
+	Name string `param:"name"`
+}
+
+// AppPasswordForm is the add app password form.
+type AppPasswordForm struct {
+	Name string `form:"name"`
+}
+
+// Validate the form.
+func (f *AppPasswordForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.Name == "" {
+		errs.Set("name", "Required")
+	}
+
+	return errs
+}
+
+// AppPasswordRequest is a request to delete an app password.
+type AppPasswordRequest struct {
+	AppPasswordID snowflake.ID `form:"app_password_id"`
+}
@@ -0,0 +1,26 @@
+package contract
+
+import (
+	"net/url"
+
+	"github.com/mgnsk/calendar/domain"
+)
+
+// EditCategoriesForm is the edit categories form.
+type EditCategoriesForm struct {
+	Categories string `form:"categories"`
+	TagsPage   string `form:"tags_page"`
+}
+
+// Validate the form.
+func (f *EditCategoriesForm) Validate() url.Values {
+	errs := url.Values{}
+
+	switch domain.TagsPageMode(f.TagsPage) {
+	case domain.TagsPageWords, domain.TagsPageCategories, domain.TagsPageBoth:
+	default:
+		errs.Set("tags_page", "Invalid value")
+	}
+
+	return errs
+}
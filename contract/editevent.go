@@ -4,6 +4,8 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/mgnsk/calendar/pkg/snowflake"
 )
 
@@ -15,15 +17,91 @@ type EditEventForm struct {
 	Description string       `form:"desc"`
 	URL         string       `form:"url"`
 	StartAt     string       `form:"start_at"`
+	Categories  []string     `form:"categories"`
 
-	Location string `form:"location"`
-	OSMType  string `form:"osm_type"`
-	OSMID    uint64 `form:"osm_id"`
+	// PublishAt is the time when a draft is published. Empty means not scheduled.
+	PublishAt string `form:"publish_at"`
 
-	Latitude       float64 `form:"latitude"`
-	Longitude      float64 `form:"longitude"`
-	TimezoneOffset int     `form:"timezone_offset"`
-	UserTimezone   string  `form:"user_timezone"`
+	// Capacity is the maximum number of attendees. Zero means unlimited.
+	Capacity int `form:"capacity"`
+
+	// IsPending is set when the event awaits moderation. It is not bound from the request.
+	IsPending bool
+
+	// Attachments are the current attachments of the event. It is not bound from the request.
+	Attachments []domain.Attachment
+
+	// KeepAttachments are the hashes of current attachments to keep.
+	KeepAttachments []string `form:"keep_attachment"`
+
+	// DuplicateOf is the event a new event is copied from.
+	DuplicateOf snowflake.ID `query:"duplicate" form:"duplicate"`
+
+	// TemplateID is the template a new event is started from.
+	TemplateID snowflake.ID `query:"template" form:"template"`
+
+	VenueID  snowflake.ID `form:"venue_id"`
+	Location string       `form:"location"`
+	OSMType  string       `form:"osm_type"`
+	OSMID    uint64       `form:"osm_id"`
+
+	Latitude  float64 `form:"latitude"`
+	Longitude float64 `form:"longitude"`
+
+	// Timezone overrides the IANA time zone resolved from the location.
+	Timezone     string `form:"timezone"`
+	UserTimezone string `form:"user_timezone"`
+
+	// Language is the language of title and description.
+	Language string `form:"language"`
+
+	// Translations of title and description. The slices are parallel.
+	TranslationLanguages    []string `form:"translation_language"`
+	TranslationTitles       []string `form:"translation_title"`
+	TranslationDescriptions []string `form:"translation_desc"`
+}
+
+// GetTranslation returns the translation to language.
+func (r *EditEventForm) GetTranslation(language string) domain.EventTranslation {
+	for _, t := range r.GetTranslations() {
+		if t.Language == language {
+			return t
+		}
+	}
+
+	return domain.EventTranslation{Language: language}
+}
+
+// GetTranslations returns the translations of title and description.
+func (r *EditEventForm) GetTranslations() []domain.EventTranslation {
+	var translations []domain.EventTranslation
+
+	for i, language := range r.TranslationLanguages {
+		if i >= len(r.TranslationTitles) || i >= len(r.TranslationDescriptions) {
+			break
+		}
+
+		translations = append(translations, domain.EventTranslation{
+			Language:    language,
+			Title:       r.TranslationTitles[i],
+			Description: r.TranslationDescriptions[i],
+		})
+	}
+
+	return translations
+}
+
+// SetTranslations sets the translations of title and description.
+func (r *EditEventForm) SetTranslations(translations []domain.EventTranslation) {
+	r.TranslationLanguages = nil
+	r.TranslationTitles = nil
+	r.TranslationDescriptions = nil
+
+	for _, t := range translations {
+		r.TranslationLanguages = append(r.TranslationLanguages, t.Language)
+		r.TranslationTitles = append(r.TranslationTitles, t.Title)
+		r.TranslationDescriptions = append(r.TranslationDescriptions, t.Description)
+	}
 }
 
 // IsDraftOrNew reports whether the current event is draft or a new event.
@@ -31,6 +109,16 @@ func (r *EditEventForm) IsDraftOrNew() bool {
 	return r.IsDraft || r.EventID == 0
 }
 
+// SetVenue sets the venue location on the form.
+func (r *EditEventForm) SetVenue(v *domain.Venue) {
+	r.VenueID = v.ID
+	r.Location = v.GetLocation()
+	r.OSMType = v.OSMType
+	r.OSMID = v.OSMID
+	r.Latitude = v.Latitude
+	r.Longitude = v.Longitude
+}
+
 // Validate the form.
 func (r *EditEventForm) Validate() url.Values {
 	errs := url.Values{}
@@ -55,12 +143,54 @@ func (r *EditEventForm) Validate() url.Values {
 		errs.Set("start_at", "Invalid format")
 	}
 
+	if r.PublishAt != "" {
+		if _, err := time.Parse(FormDateTimeLayout, r.PublishAt); err != nil {
+			errs.Set("publish_at", "Invalid format")
+		}
+	}
+
 	if r.Location == "" {
 		errs.Set("location", "Required")
 	}
 
+	if r.Capacity < 0 {
+		errs.Set("capacity", "Invalid value")
+	}
+
+	if r.Timezone != "" {
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			errs.Set("timezone", "Unknown timezone")
+		}
+	}
+
+	if r.Language != "" && !i18n.IsSupported(r.Language) {
+		errs.Set("language", "Unsupported language")
+	}
+
+	if len(r.TranslationTitles) != len(r.TranslationLanguages) || len(r.TranslationDescriptions) != len(r.TranslationLanguages) {
+		errs.Set("translation_language", "Invalid value")
+	}
+
+	for _, language := range r.TranslationLanguages {
+		if !i18n.IsSupported(language) {
+			errs.Set("translation_language", "Unsupported language")
+		}
+	}
+
 	return errs
 }
 
+// Attachment limits.
+const (
+	// MaxAttachments is the maximum number of attachments per event.
+	MaxAttachments = 10
+
+	// MaxAttachmentSize is the maximum size of an uploaded file.
+	MaxAttachmentSize = 10 << 20
+
+	// AttachmentBodyLimit is the request body limit of forms with attachments.
+	AttachmentBodyLimit = "110M"
+)
+
 // FormDateTimeLayout is the HTML datetime-local input time format.
 const FormDateTimeLayout = "2006-01-02T15:04"
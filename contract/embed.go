@@ -0,0 +1,79 @@
+package contract
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// Embedded event list limits.
+const (
+	EmbedDefaultLimit = 10
+	EmbedMaxLimit     = 50
+)
+
+// EmbedThemeNone renders the embedded event list without styles.
+const EmbedThemeNone = "none"
+
+// EmbedRequest is a request to render the embedded event list of upcoming events.
+type EmbedRequest struct {
+	Category string       `query:"category"`
+	Venue    snowflake.ID `query:"venue"`
+	Tags     []string     `query:"tag"`
+	TagMatch string       `query:"tag_match"`
+	Limit    int          `query:"limit"`
+
+	// Language selects the event translations and the UI language.
+	// Defaults to the viewer language.
+	Language string `query:"lang"`
+
+	// Theme is empty for the site theme or EmbedThemeNone.
+	Theme string `query:"theme"`
+}
+
+// GetLimit returns the number of events to show.
+func (r *EmbedRequest) GetLimit() int {
+	if r.Limit <= 0 {
+		return EmbedDefaultLimit
+	}
+
+	return min(r.Limit, EmbedMaxLimit)
+}
+
+// Query returns the request parameters without defaults.
+func (r *EmbedRequest) Query() url.Values {
+	q := url.Values{}
+
+	if r.Category != "" {
+		q.Set("category", r.Category)
+	}
+
+	if r.Venue > 0 {
+		q.Set("venue", r.Venue.String())
+	}
+
+	for _, tag := range r.Tags {
+		if tag != "" {
+			q.Add("tag", tag)
+		}
+	}
+
+	if r.TagMatch != "" && len(q["tag"]) > 1 {
+		q.Set("tag_match", r.TagMatch)
+	}
+
+	if r.Limit > 0 {
+		q.Set("limit", strconv.Itoa(r.GetLimit()))
+	}
+
+	if r.Language != "" {
+		q.Set("lang", r.Language)
+	}
+
+	if r.Theme != "" {
+		q.Set("theme", r.Theme)
+	}
+
+	return q
+}
@@ -4,9 +4,39 @@ import "github.com/mgnsk/calendar/pkg/snowflake"
 
 // ListEventsRequest is a request to list events.
 type ListEventsRequest struct {
-	Offset int64  `form:"offset"`
-	LastID int64  `form:"last_id"`
-	Search string `form:"search"`
+	Offset   int64    `form:"offset"`
+	LastID   int64    `form:"last_id"`
+	Search   string   `form:"search"`
+	Sort     string   `form:"sort"`
+	Category string   `form:"category" query:"category"`
+	Tags     []string `query:"tag"`
+	TagMatch string   `query:"tag_match"`
+	Near     string   `query:"near"`
+	Radius   string   `query:"radius"`
+}
+
+// FeedRequest is a request to render a feed.
+type FeedRequest struct {
+	Category string       `query:"category"`
+	Venue    snowflake.ID `query:"venue"`
+	Tags     []string     `query:"tag"`
+	TagMatch string       `query:"tag_match"`
+	Near     string       `query:"near"`
+	Radius   string       `query:"radius"`
+
+	// Language selects the event translations. Defaults to the site language.
+	Language string `query:"lang"`
+}
+
+// TagsRequest is a request to render the tag list.
+type TagsRequest struct {
+	Tags     []string `query:"tag"`
+	TagMatch string   `query:"tag_match"`
+}
+
+// EventRequest is a request to show an event.
+type EventRequest struct {
+	EventID snowflake.ID `param:"event_id"`
 }
 
 // DeleteEventRequest is a request to delete an event.
@@ -14,5 +44,19 @@ type DeleteEventRequest struct {
 	EventID snowflake.ID `param:"event_id"`
 }
 
+// ModerateEventRequest is a request to approve or reject a pending event.
+type ModerateEventRequest struct {
+	EventID snowflake.ID `param:"event_id"`
+}
+
+// Sort values.
+const (
+	// SortRelevance sorts search results by relevance.
+	SortRelevance = "relevance"
+
+	// SortDistance sorts events by distance from the near point.
+	SortDistance = "distance"
+)
+
 // EventLimitPerPage specifies maximum number of events per page.
 const EventLimitPerPage = 25
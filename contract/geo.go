@@ -0,0 +1,80 @@
+package contract
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mgnsk/calendar"
+)
+
+// Radius limits for near filters in kilometers.
+const (
+	DefaultRadiusKM = 10
+	MaxRadiusKM     = 1000
+)
+
+// NearFilter filters events by distance from a point.
+type NearFilter struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKM  float64
+}
+
+// ParseNear parses the near=lat,lng and radius=10km request parameters.
+// Radius accepts km and m units and defaults to kilometers.
+// Returns nil if near is empty.
+func ParseNear(near, radius string) (*NearFilter, error) {
+	if near == "" {
+		return nil, nil
+	}
+
+	latStr, lngStr, ok := strings.Cut(near, ",")
+	if !ok {
+		return nil, calendar.InvalidValue.New("Invalid near value, use the format near=lat,lng")
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || !isFinite(lat) || lat < -90 || lat > 90 {
+		return nil, calendar.InvalidValue.New("Invalid near latitude", err)
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil || !isFinite(lng) || lng < -180 || lng > 180 {
+		return nil, calendar.InvalidValue.New("Invalid near longitude", err)
+	}
+
+	filter := &NearFilter{
+		Latitude:  lat,
+		Longitude: lng,
+		RadiusKM:  DefaultRadiusKM,
+	}
+
+	if radius = strings.ToLower(strings.TrimSpace(radius)); radius != "" {
+		scale := 1.0
+
+		switch {
+		case strings.HasSuffix(radius, "km"):
+			radius = strings.TrimSuffix(radius, "km")
+		case strings.HasSuffix(radius, "m"):
+			radius = strings.TrimSuffix(radius, "m")
+			scale = 0.001
+		}
+
+		km, err := strconv.ParseFloat(radius, 64)
+		if err != nil || !isFinite(km) || km <= 0 || km*scale > MaxRadiusKM {
+			return nil, calendar.InvalidValue.New(fmt.Sprintf("Invalid radius, use a distance up to %dkm such as radius=10km", MaxRadiusKM), err)
+		}
+
+		filter.RadiusKM = km * scale
+	}
+
+	return filter, nil
+}
+
+// isFinite reports whether v is neither NaN nor infinite.
+// NaN fails every comparison and would pass the range checks.
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
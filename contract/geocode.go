@@ -0,0 +1,9 @@
+package contract
+
+// GeocodeRequest is a request to search locations.
+type GeocodeRequest struct {
+	Query string `query:"q"`
+}
+
+// GeocodeMinQueryLength specifies the minimum location search query length in runes.
+const GeocodeMinQueryLength = 3
@@ -0,0 +1,129 @@
+package contract
+
+import (
+	"net/url"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// ManageEventsRequest is a request to list events in the management table.
+type ManageEventsRequest struct {
+	Title    string       `query:"title"`
+	Status   string       `query:"status"`
+	Category string       `query:"category"`
+	Owner    snowflake.ID `query:"owner"`
+	Sort     string       `query:"sort"`
+	Offset   int64        `query:"offset"`
+}
+
+// Query returns the request as query values without the offset.
+func (r *ManageEventsRequest) Query() url.Values {
+	q := url.Values{}
+
+	if r.Title != "" {
+		q.Set("title", r.Title)
+	}
+
+	if r.Status != "" {
+		q.Set("status", r.Status)
+	}
+
+	if r.Category != "" {
+		q.Set("category", r.Category)
+	}
+
+	if r.Owner > 0 {
+		q.Set("owner", r.Owner.String())
+	}
+
+	if r.Sort != "" {
+		q.Set("sort", r.Sort)
+	}
+
+	return q
+}
+
+// Event status filter values.
+const (
+	StatusPublished = "published"
+	StatusDraft     = "draft"
+	StatusPending   = "pending"
+)
+
+// Management table sort values. A leading minus sorts in descending order.
+const (
+	ManageSortStartAt = "start_at"
+	ManageSortTitle   = "title"
+)
+
+// ManageEventsLimit specifies maximum number of events per management table page.
+const ManageEventsLimit = 100
+
+// Bulk action values.
+const (
+	BulkPublish        = "publish"
+	BulkUnpublish      = "unpublish"
+	BulkDelete         = "delete"
+	BulkChangeOwner    = "change_owner"
+	BulkAddCategory    = "add_category"
+	BulkRemoveCategory = "remove_category"
+	BulkShiftDate      = "shift_date"
+)
+
+// BulkMaxShiftDays specifies the maximum number of days to shift events by in either direction.
+const BulkMaxShiftDays = 3650
+
+// BulkEventsForm is the bulk action form of the management table.
+type BulkEventsForm struct {
+	EventIDs []snowflake.ID `form:"event_id"`
+	Action   string         `form:"action"`
+	Owner    snowflake.ID   `form:"new_owner"`
+	Category string         `form:"bulk_category"`
+	Days     int            `form:"days"`
+}
+
+// Validate the form.
+func (f *BulkEventsForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if len(f.EventIDs) == 0 {
+		errs.Set("event_id", "No events selected")
+	}
+
+	switch f.Action {
+	case BulkPublish, BulkUnpublish, BulkDelete:
+
+	case BulkChangeOwner:
+		if f.Owner == 0 {
+			errs.Set("new_owner", "Required")
+		}
+
+	case BulkAddCategory, BulkRemoveCategory:
+		if f.Category == "" {
+			errs.Set("bulk_category", "Required")
+		}
+
+	case BulkShiftDate:
+		if f.Days == 0 || f.Days < -BulkMaxShiftDays || f.Days > BulkMaxShiftDays {
+			errs.Set("days", "Invalid value")
+		}
+
+	default:
+		errs.Set("action", "Invalid value")
+	}
+
+	return errs
+}
+
+// BulkSummary is the result of a bulk action.
+type BulkSummary struct {
+	Succeeded int
+	Failures  []BulkFailure
+}
+
+// BulkFailure is an event for which a bulk action failed.
+type BulkFailure struct {
+	EventID snowflake.ID
+	Title   string
+	Reason  string
+}
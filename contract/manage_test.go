@@ -0,0 +1,31 @@
+package contract_test
+
+import (
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("validating the shift date bulk action",
+	func(days int, valid bool) {
+		form := contract.BulkEventsForm{
+			EventIDs: []snowflake.ID{snowflake.Generate()},
+			Action:   contract.BulkShiftDate,
+			Days:     days,
+		}
+
+		if valid {
+			Expect(form.Validate()).To(BeEmpty())
+		} else {
+			Expect(form.Validate()).To(HaveKey("days"))
+		}
+	},
+	Entry("forward", 7, true),
+	Entry("backward", -7, true),
+	Entry("maximum", contract.BulkMaxShiftDays, true),
+	Entry("zero", 0, false),
+	Entry("too far forward", contract.BulkMaxShiftDays+1, false),
+	Entry("too far backward", -contract.BulkMaxShiftDays-1, false),
+	Entry("overflowing", 1<<40, false),
+)
@@ -0,0 +1,79 @@
+package contract
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mgnsk/calendar"
+)
+
+// MapEventLimit specifies maximum number of events on the map.
+const MapEventLimit = 1000
+
+// MapRequest is a request to render events on a map.
+type MapRequest struct {
+	Past     bool     `query:"past"`
+	Search   string   `query:"search"`
+	Category string   `query:"category"`
+	Tags     []string `query:"tag"`
+	TagMatch string   `query:"tag_match"`
+	Near     string   `query:"near"`
+	Radius   string   `query:"radius"`
+	BBox     string   `query:"bbox"`
+}
+
+// MapPopupRequest is a request to render the events of a map marker.
+type MapPopupRequest struct {
+	IDs []int64 `query:"id"`
+}
+
+// BBoxFilter filters events by a bounding box.
+type BBoxFilter struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+// ParseBBox parses the bbox=minLng,minLat,maxLng,maxLat request parameter
+// in the GeoJSON axis order. Returns nil if bbox is empty.
+func ParseBBox(bbox string) (*BBoxFilter, error) {
+	if bbox == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(bbox, ",")
+	if len(parts) != 4 {
+		return nil, calendar.InvalidValue.New("Invalid bbox value, use the format bbox=minLng,minLat,maxLng,maxLat")
+	}
+
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || !isFinite(v) {
+			return nil, calendar.InvalidValue.New("Invalid bbox value, use the format bbox=minLng,minLat,maxLng,maxLat", err)
+		}
+		values[i] = v
+	}
+
+	filter := &BBoxFilter{
+		MinLongitude: values[0],
+		MinLatitude:  values[1],
+		MaxLongitude: values[2],
+		MaxLatitude:  values[3],
+	}
+
+	if filter.MinLatitude < -90 || filter.MaxLatitude > 90 {
+		return nil, calendar.InvalidValue.New("Invalid bbox latitude")
+	}
+
+	if filter.MinLongitude < -180 || filter.MaxLongitude > 180 {
+		return nil, calendar.InvalidValue.New("Invalid bbox longitude")
+	}
+
+	if filter.MinLatitude > filter.MaxLatitude || filter.MinLongitude > filter.MaxLongitude {
+		return nil, calendar.InvalidValue.New("Invalid bbox value, minimum must not exceed maximum")
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,63 @@
+package contract
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// PrintDateLayout is the date format of the printed program date range.
+const PrintDateLayout = "2006-01-02"
+
+// PrintMaxDays is the maximum number of days in a printed program.
+const PrintMaxDays = 366
+
+// PrintMaxEvents is the maximum number of events in a printed program.
+const PrintMaxEvents = 300
+
+// PrintRequest is a request to print the program of events in a date range.
+type PrintRequest struct {
+	// From and Until are the first and the last day of the program.
+	// Defaults to the current month.
+	From  string `query:"from"`
+	Until string `query:"until"`
+
+	Category string       `query:"category"`
+	Venue    snowflake.ID `query:"venue"`
+
+	// QR adds QR codes linking to the event pages.
+	QR bool `query:"qr"`
+}
+
+// ParseDateRange parses the date range of the program in the location of now.
+// It returns the start of the first day and the start of the day after the last day.
+func (r *PrintRequest) ParseDateRange(now time.Time) (from, until time.Time, err error) {
+	from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	until = from.AddDate(0, 1, 0)
+
+	if r.From != "" {
+		if from, err = time.ParseInLocation(PrintDateLayout, r.From, now.Location()); err != nil {
+			return time.Time{}, time.Time{}, calendar.InvalidValue.New("Invalid date", err)
+		}
+
+		if r.Until == "" {
+			until = from.AddDate(0, 1, 0)
+		}
+	}
+
+	if r.Until != "" {
+		last, err := time.ParseInLocation(PrintDateLayout, r.Until, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, calendar.InvalidValue.New("Invalid date", err)
+		}
+
+		until = last.AddDate(0, 0, 1)
+	}
+
+	if !until.After(from) || until.After(from.AddDate(0, 0, PrintMaxDays)) {
+		return time.Time{}, time.Time{}, calendar.InvalidValue.New("Invalid date range")
+	}
+
+	return from, until, nil
+}
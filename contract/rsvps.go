@@ -0,0 +1,68 @@
+package contract
+
+import (
+	"net/mail"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// RSVPForm is the event RSVP form.
+type RSVPForm struct {
+	EventID snowflake.ID `param:"event_id"`
+	Status  string       `form:"status"`
+
+	// Name and Email are only used for anonymous RSVPs.
+	Name  string `form:"name"`
+	Email string `form:"email"`
+}
+
+// Validate the form.
+func (f *RSVPForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if !domain.RSVPStatus(f.Status).IsValid() {
+		errs.Set("status", "Invalid value")
+	}
+
+	if f.Email != "" {
+		if addr, err := mail.ParseAddress(f.Email); err != nil || addr.Address != f.Email {
+			errs.Set("email", "Invalid email address")
+		}
+	}
+
+	return errs
+}
+
+// ValidateAnonymous validates the form of a visitor who is not logged in.
+func (f *RSVPForm) ValidateAnonymous() url.Values {
+	errs := f.Validate()
+
+	if f.Name == "" {
+		errs.Set("name", "Required")
+	}
+
+	if f.Email == "" {
+		errs.Set("email", "Required")
+	}
+
+	return errs
+}
+
+// AttendeesRequest is a request to list the attendees of an event.
+type AttendeesRequest struct {
+	EventID snowflake.ID `param:"event_id"`
+}
+
+// RemoveAttendeeRequest is a request to remove an RSVP from an event.
+type RemoveAttendeeRequest struct {
+	EventID snowflake.ID `param:"event_id"`
+	RSVPID  snowflake.ID `form:"rsvp_id"`
+}
+
+// PersonalCalendarRequest is a request for the calendar feed of a user.
+type PersonalCalendarRequest struct {
+	Token uuid.UUID `param:"token"`
+}
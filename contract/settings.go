@@ -0,0 +1,99 @@
+package contract
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
+)
+
+// SettingsForm is the site settings form.
+type SettingsForm struct {
+	Title            string `form:"pagetitle"`
+	Description      string `form:"pagedesc"`
+	Locale           string `form:"locale"`
+	Timezone         string `form:"timezone"`
+	AccentColor      string `form:"accent_color"`
+	Footer           string `form:"footer"`
+	ContactEmail     string `form:"contact_email"`
+	PublicSubmission bool   `form:"public_submission"`
+	Moderation       bool   `form:"moderation"`
+	TagsPageEnabled  bool   `form:"tags_page_enabled"`
+	AnonymousRSVP    bool   `form:"anonymous_rsvp"`
+	RemoveLogo       bool   `form:"remove_logo"`
+	RemoveFavicon    bool   `form:"remove_favicon"`
+	RetentionDays    int    `form:"retention_days"`
+	RetentionAction  string `form:"retention_action"`
+
+	// EmbedOrigins is a list of origins separated by whitespace.
+	EmbedOrigins string `form:"embed_origins"`
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Validate the form.
+func (f *SettingsForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.Title == "" {
+		errs.Set("pagetitle", "Title must be set")
+	}
+
+	if f.Locale != "" && !i18n.IsSupported(f.Locale) {
+		errs.Set("locale", "Unsupported language")
+	}
+
+	if f.Timezone != "" {
+		if _, err := time.LoadLocation(f.Timezone); err != nil {
+			errs.Set("timezone", "Unknown timezone")
+		}
+	}
+
+	if f.AccentColor != "" && !colorPattern.MatchString(f.AccentColor) {
+		errs.Set("accent_color", "Invalid format")
+	}
+
+	if f.ContactEmail != "" {
+		if addr, err := mail.ParseAddress(f.ContactEmail); err != nil || addr.Address != f.ContactEmail {
+			errs.Set("contact_email", "Invalid email address")
+		}
+	}
+
+	if f.RetentionDays < 0 {
+		errs.Set("retention_days", "Invalid value")
+	}
+
+	switch domain.RetentionAction(f.RetentionAction) {
+	case domain.RetentionKeep, domain.RetentionArchive, domain.RetentionDelete:
+	default:
+		errs.Set("retention_action", "Invalid value")
+	}
+
+	for _, origin := range strings.Fields(f.EmbedOrigins) {
+		if !IsOrigin(origin) {
+			errs.Set("embed_origins", "Invalid origin")
+			break
+		}
+	}
+
+	return errs
+}
+
+// IsOrigin reports whether s is a http or https origin.
+// The host may start with a wildcard subdomain.
+func IsOrigin(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+
+	host := strings.TrimPrefix(u.Hostname(), "*.")
+
+	return host != "" && !strings.Contains(host, "*") &&
+		u.User == nil && u.Path == "" && u.RawQuery == "" && u.Fragment == "" &&
+		s == u.Scheme+"://"+u.Host
+}
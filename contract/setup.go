@@ -1,11 +1,16 @@
 package contract
 
-import "net/url"
+import (
+	"net/url"
+
+	"github.com/mgnsk/calendar/i18n"
+)
 
 // SetupForm is a setup form.
 type SetupForm struct {
 	Title       string `form:"pagetitle"`
 	Description string `form:"pagedesc"`
+	Locale      string `form:"locale"`
 	Username    string `form:"username"`
 	Password1   string `form:"password1"`
 	Password2   string `form:"password2"`
@@ -19,6 +24,10 @@ func (f *SetupForm) Validate() url.Values {
 		errs.Set("pagetitle", "Title must be set")
 	}
 
+	if f.Locale != "" && !i18n.IsSupported(f.Locale) {
+		errs.Set("locale", "Unsupported language")
+	}
+
 	if f.Username == "" {
 		errs.Set("username", "Username must be set")
 	}
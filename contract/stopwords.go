@@ -1,6 +1,42 @@
 package contract
 
+import (
+	"net/url"
+
+	"github.com/mgnsk/calendar/pkg/textfilter"
+)
+
 // EditStopWordsForm is the edit stop words form.
 type EditStopWordsForm struct {
-	Words string `form:"words"`
+	Words       string `form:"words"`
+	TagLanguage string `form:"tag_language"`
+}
+
+// Validate the form.
+func (f *EditStopWordsForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.TagLanguage != "" {
+		if _, ok := textfilter.NewStemmer(f.TagLanguage); !ok {
+			errs.Set("tag_language", "Invalid value")
+		}
+	}
+
+	return errs
+}
+
+// LoadStopWordPresetForm is the load stop word preset form.
+type LoadStopWordPresetForm struct {
+	Preset string `form:"preset"`
+}
+
+// Validate the form.
+func (f *LoadStopWordPresetForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if _, err := textfilter.LoadStopWordPreset(f.Preset); err != nil {
+		errs.Set("preset", "Invalid value")
+	}
+
+	return errs
 }
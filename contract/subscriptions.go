@@ -0,0 +1,45 @@
+package contract
+
+import (
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar/domain"
+)
+
+// SubscribeRequest is a request to render the subscribe page
+// with the filter of the current event list.
+type SubscribeRequest struct {
+	Category string   `query:"category"`
+	Tags     []string `query:"tag"`
+}
+
+// SubscribeForm is the digest subscription form.
+type SubscribeForm struct {
+	Email     string `form:"email"`
+	Frequency string `form:"frequency"`
+	Category  string `form:"category"`
+	Tag       string `form:"tag"`
+}
+
+// Validate the form.
+func (f *SubscribeForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.Email == "" {
+		errs.Set("email", "Required")
+	} else if !isValidEmail(f.Email) {
+		errs.Set("email", "Invalid email address")
+	}
+
+	if !domain.DigestFrequency(f.Frequency).IsValid() {
+		errs.Set("frequency", "Invalid value")
+	}
+
+	return errs
+}
+
+// SubscriptionRequest is a request to confirm or cancel a subscription.
+type SubscriptionRequest struct {
+	Token uuid.UUID `param:"token"`
+}
@@ -0,0 +1,14 @@
+package contract
+
+import "github.com/mgnsk/calendar/pkg/snowflake"
+
+// SaveTemplateForm is the save as template part of the edit event form.
+type SaveTemplateForm struct {
+	Name     string `form:"template_name"`
+	SiteWide bool   `form:"template_site_wide"`
+}
+
+// DeleteTemplateRequest is a request to delete an event template.
+type DeleteTemplateRequest struct {
+	TemplateID snowflake.ID `form:"template_id"`
+}
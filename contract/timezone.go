@@ -0,0 +1,25 @@
+package contract
+
+import (
+	"net/url"
+	"time"
+)
+
+// TimezoneForm is a form to set the viewer time zone.
+// An empty time zone resets to the browser time zone.
+type TimezoneForm struct {
+	Timezone string `form:"timezone"`
+}
+
+// Validate the form.
+func (r *TimezoneForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if r.Timezone != "" {
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			errs.Set("timezone", "Unknown timezone")
+		}
+	}
+
+	return errs
+}
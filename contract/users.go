@@ -1,6 +1,7 @@
 package contract
 
 import (
+	"net/mail"
 	"net/url"
 
 	"github.com/google/uuid"
@@ -17,6 +18,23 @@ type UpgradeUserRoleRequest struct {
 	UserID snowflake.ID `form:"user_id"`
 }
 
+// InviteForm is the invite form.
+type InviteForm struct {
+	// Email is optional. The invite link is emailed when set.
+	Email string `form:"email"`
+}
+
+// Validate the form.
+func (f *InviteForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.Email != "" && !isValidEmail(f.Email) {
+		errs.Set("email", "Invalid email address")
+	}
+
+	return errs
+}
+
 // RegisterRequest is a request to render the register page.
 type RegisterRequest struct {
 	Token uuid.UUID `param:"token"`
@@ -25,6 +43,7 @@ type RegisterRequest struct {
 // RegisterForm is the register form.
 type RegisterForm struct {
 	Username  string `form:"username"`
+	Email     string `form:"email"`
 	Password1 string `form:"password1"`
 	Password2 string `form:"password2"`
 }
@@ -41,6 +60,10 @@ func (f *RegisterForm) Validate() url.Values {
 		errs.Set("username", "Username must be at most 30 characters")
 	}
 
+	if f.Email != "" && !isValidEmail(f.Email) {
+		errs.Set("email", "Invalid email address")
+	}
+
 	// TODO: password strength check
 	if f.Password1 == "" {
 		errs.Set("password1", "Password must be set")
@@ -56,3 +79,73 @@ func (f *RegisterForm) Validate() url.Values {
 
 	return errs
 }
+
+// AccountForm is the account settings form.
+type AccountForm struct {
+	Email string `form:"email"`
+}
+
+// Validate the form.
+func (f *AccountForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.Email != "" && !isValidEmail(f.Email) {
+		errs.Set("email", "Invalid email address")
+	}
+
+	return errs
+}
+
+// ForgotPasswordForm is a form to request a password reset link.
+type ForgotPasswordForm struct {
+	Email string `form:"email"`
+}
+
+// Validate the form.
+func (f *ForgotPasswordForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.Email == "" {
+		errs.Set("email", "Required")
+	} else if !isValidEmail(f.Email) {
+		errs.Set("email", "Invalid email address")
+	}
+
+	return errs
+}
+
+// ResetPasswordRequest is a request to render the password reset page.
+type ResetPasswordRequest struct {
+	Token uuid.UUID `param:"token"`
+}
+
+// ResetPasswordForm is the password reset form.
+type ResetPasswordForm struct {
+	Password1 string `form:"password1"`
+	Password2 string `form:"password2"`
+}
+
+// Validate the form.
+func (f *ResetPasswordForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.Password1 == "" {
+		errs.Set("password1", "Password must be set")
+	}
+
+	if f.Password2 == "" {
+		errs.Set("password2", "Password must be set")
+	}
+
+	if f.Password1 != f.Password2 {
+		errs.Set("password2", "Passwords must match")
+	}
+
+	return errs
+}
+
+// isValidEmail returns whether s is a bare email address.
+func isValidEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
@@ -0,0 +1,73 @@
+package contract
+
+import (
+	"net/url"
+	"slices"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// VenueRequest is a request to render a venue page.
+type VenueRequest struct {
+	VenueID snowflake.ID `param:"venue_id"`
+	Offset  int64        `form:"offset"`
+}
+
+// EditVenueForm is an edit venue form.
+type EditVenueForm struct {
+	VenueID       snowflake.ID `param:"venue_id"`
+	Name          string       `form:"name"`
+	Address       string       `form:"address"`
+	URL           string       `form:"url"`
+	Accessibility string       `form:"accessibility"`
+
+	OSMType   string  `form:"osm_type"`
+	OSMID     uint64  `form:"osm_id"`
+	Latitude  float64 `form:"latitude"`
+	Longitude float64 `form:"longitude"`
+}
+
+// Validate the form.
+func (f *EditVenueForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.Name == "" {
+		errs.Set("name", "Required")
+	}
+
+	if f.URL != "" {
+		if _, err := url.Parse(f.URL); err != nil {
+			errs.Set("url", "Invalid URL")
+		}
+	}
+
+	return errs
+}
+
+// DeleteVenueRequest is a request to delete a venue.
+type DeleteVenueRequest struct {
+	VenueID snowflake.ID `form:"venue_id"`
+}
+
+// MergeVenuesForm is the merge venues form.
+type MergeVenuesForm struct {
+	TargetID  snowflake.ID   `form:"target_id"`
+	SourceIDs []snowflake.ID `form:"source_id"`
+}
+
+// Validate the form.
+func (f *MergeVenuesForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if f.TargetID == 0 {
+		errs.Set("target_id", "Select the venue to keep")
+	}
+
+	if len(f.SourceIDs) == 0 {
+		errs.Set("source_id", "Select venues to merge")
+	} else if slices.Contains(f.SourceIDs, f.TargetID) {
+		errs.Set("source_id", "Venue to keep can't be merged into itself")
+	}
+
+	return errs
+}
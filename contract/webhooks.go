@@ -0,0 +1,45 @@
+package contract
+
+import (
+	"net/url"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// WebhookForm is the add webhook form.
+type WebhookForm struct {
+	URL        string   `form:"url"`
+	EventTypes []string `form:"event_types"`
+}
+
+// Validate the form.
+func (f *WebhookForm) Validate() url.Values {
+	errs := url.Values{}
+
+	if u, err := url.Parse(f.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.Set("url", "Invalid URL")
+	}
+
+	if len(f.EventTypes) == 0 {
+		errs.Set("event_types", "Select at least one event type")
+	}
+
+	for _, t := range f.EventTypes {
+		if !domain.WebhookEventType(t).IsValid() {
+			errs.Set("event_types", "Invalid event type")
+		}
+	}
+
+	return errs
+}
+
+// WebhookRequest is a request to delete or test a webhook.
+type WebhookRequest struct {
+	WebhookID snowflake.ID `form:"webhook_id"`
+}
+
+// WebhookDeliveriesRequest is a request to render the delivery log of a webhook.
+type WebhookDeliveriesRequest struct {
+	WebhookID snowflake.ID `param:"webhook_id"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// ActivityType is the type of an ActivityPub activity sent to followers.
+type ActivityType string
+
+// Activity types.
+const (
+	ActivityCreate ActivityType = "Create"
+	ActivityUpdate ActivityType = "Update"
+	ActivityDelete ActivityType = "Delete"
+	ActivityAccept ActivityType = "Accept"
+)
+
+// NewActivityType returns the activity type of an event lifecycle change.
+// Withdrawn events are deleted from followers.
+func NewActivityType(t WebhookEventType) ActivityType {
+	switch t {
+	case WebhookPublished:
+		return ActivityCreate
+	case WebhookUpdated:
+		return ActivityUpdate
+	default:
+		return ActivityDelete
+	}
+}
+
+// Follower is a remote ActivityPub actor following the site.
+type Follower struct {
+	ID snowflake.ID
+
+	// ActorID is the ID of the remote actor.
+	ActorID string
+
+	// Inbox is the shared inbox of the remote server or the inbox of the actor.
+	Inbox string
+
+	// FollowID is the ID of the Follow activity which is accepted.
+	FollowID string
+}
+
+// MaxActivityAttempts is the number of times delivering an activity is attempted.
+const MaxActivityAttempts = 10
+
+// ActivityDelivery is a queued activity for a remote inbox. Event activities refer
+// to the event and Accept activities to the follower. The activity is built on delivery.
+type ActivityDelivery struct {
+	ID         snowflake.ID
+	Inbox      string
+	Type       ActivityType
+	EventID    snowflake.ID
+	FollowerID snowflake.ID
+
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+// SetFailed records a failed delivery attempt and schedules the next one.
+func (d *ActivityDelivery) SetFailed(err error, now time.Time) {
+	d.NextAttemptAt = now.Add(retryBackoff(d.Attempts))
+	d.Attempts++
+	d.LastError = err.Error()
+}
+
+// IsExhausted returns whether the delivery has no attempts left.
+func (d *ActivityDelivery) IsExhausted() bool {
+	return d.Attempts >= MaxActivityAttempts
+}
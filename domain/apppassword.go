@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// AppPassword is a password for signing in from calendar apps.
+type AppPassword struct {
+	ID     snowflake.ID
+	UserID snowflake.ID
+	Name   string
+
+	// Hash is the SHA-256 hash of the password. The password itself is not stored.
+	Hash string
+
+	// LastUsedAt is zero if the password has not been used.
+	LastUsedAt time.Time
+}
+
+// NewAppPassword creates a new random app password.
+// The password is returned in plain text only once.
+func NewAppPassword(userID snowflake.ID, name string) (*AppPassword, string) {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	password := hex.EncodeToString(b)
+
+	return &AppPassword{
+		ID:     snowflake.Generate(),
+		UserID: userID,
+		Name:   name,
+		Hash:   HashAppPassword(password),
+	}, password
+}
+
+// HashAppPassword returns the hash of an app password.
+// App passwords are random so a fast hash is sufficient.
+func HashAppPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// GetCreatedAt returns the app password created at time.
+func (p *AppPassword) GetCreatedAt() time.Time {
+	return snowflake.ParseTime(p.ID.Int64())
+}
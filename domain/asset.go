@@ -0,0 +1,14 @@
+package domain
+
+// Site asset names.
+const (
+	AssetLogo    = "logo"
+	AssetFavicon = "favicon"
+)
+
+// SiteAsset is an uploaded site asset such as the logo.
+type SiteAsset struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Attachment is a file attached to an event.
+// Files are stored by the hash of their content.
+type Attachment struct {
+	Hash string
+
+	// ThumbnailHash is the hash of the image thumbnail.
+	// Empty for other files.
+	ThumbnailHash string
+
+	// Name is the original file name.
+	Name        string
+	ContentType string
+	Size        int64
+
+	// Width and Height are set for images.
+	Width  int
+	Height int
+}
+
+// IsImage reports whether the attachment is an image.
+func (a Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// GetURL returns the path of the file.
+func (a Attachment) GetURL() string {
+	return "/uploads/" + a.Hash + "/" + url.PathEscape(a.Name)
+}
+
+// GetThumbnailURL returns the path of the thumbnail or the file itself when there is no thumbnail.
+func (a Attachment) GetThumbnailURL() string {
+	if a.ThumbnailHash == "" {
+		return a.GetURL()
+	}
+
+	return "/uploads/" + a.ThumbnailHash + "/" + url.PathEscape(a.Name)
+}
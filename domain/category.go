@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Category is the curated category domain model.
+type Category struct {
+	Name       string
+	EventCount uint64
+}
+
+// CategoryList is a domain model for the list of curated categories.
+// Category names are trimmed and deduplicated case-insensitively.
+type CategoryList []string
+
+// NewCategoryList creates a new category list.
+func NewCategoryList(names ...string) CategoryList {
+	names = lo.FilterMap(names, func(name string, _ int) (string, bool) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return "", false
+		}
+
+		return name, true
+	})
+
+	return lo.UniqBy(names, strings.ToLower)
+}
@@ -0,0 +1,34 @@
+package domain_test
+
+import (
+	"github.com/mgnsk/calendar/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("creating a category list", func() {
+	Specify("names are trimmed", func() {
+		names := domain.NewCategoryList(" Music ")
+
+		Expect(names).To(HaveExactElements(
+			"Music",
+		))
+	})
+
+	Specify("empty names are removed", func() {
+		names := domain.NewCategoryList("Music", " ", "")
+
+		Expect(names).To(HaveExactElements(
+			"Music",
+		))
+	})
+
+	Specify("duplicate names are removed case-insensitively", func() {
+		names := domain.NewCategoryList("Music", "music", "Theatre")
+
+		Expect(names).To(HaveExactElements(
+			"Music",
+			"Theatre",
+		))
+	})
+})
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// MaxEmailAttempts is the number of times sending an email is attempted.
+const MaxEmailAttempts = 10
+
+// Email is a queued outgoing email.
+type Email struct {
+	ID      snowflake.ID
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+// SetFailed records a failed sending attempt and schedules the next one.
+func (e *Email) SetFailed(err error, now time.Time) {
+	e.NextAttemptAt = now.Add(retryBackoff(e.Attempts))
+	e.Attempts++
+	e.LastError = err.Error()
+}
+
+// IsExhausted returns whether the email has no attempts left.
+func (e *Email) IsExhausted() bool {
+	return e.Attempts >= MaxEmailAttempts
+}
+
+// retryBackoff returns the exponential backoff after the given number of
+// previous attempts, starting from a minute and capped at 6 hours.
+func retryBackoff(attempts int) time.Duration {
+	return min(time.Minute<<min(attempts, 10), 6*time.Hour)
+}
+
+// PasswordReset is a one-time password reset token.
+type PasswordReset struct {
+	Token      uuid.UUID
+	UserID     snowflake.ID
+	ValidUntil time.Time
+}
+
+// IsValid returns whether the password reset is valid.
+func (r *PasswordReset) IsValid() bool {
+	return time.Until(r.ValidUntil) > 0
+}
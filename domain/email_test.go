@@ -0,0 +1,32 @@
+package domain_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("email sending failures", func() {
+	Specify("retries back off exponentially up to a limit", func() {
+		now := time.Now()
+		email := &domain.Email{}
+
+		email.SetFailed(errors.New("connection refused"), now)
+		Expect(email.Attempts).To(Equal(1))
+		Expect(email.LastError).To(Equal("connection refused"))
+		Expect(email.NextAttemptAt).To(Equal(now.Add(time.Minute)))
+
+		email.SetFailed(errors.New("connection refused"), now)
+		Expect(email.NextAttemptAt).To(Equal(now.Add(2 * time.Minute)))
+
+		for !email.IsExhausted() {
+			email.SetFailed(errors.New("connection refused"), now)
+		}
+
+		Expect(email.Attempts).To(Equal(domain.MaxEmailAttempts))
+		Expect(email.NextAttemptAt).To(Equal(now.Add(6 * time.Hour)))
+	})
+})
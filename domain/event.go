@@ -2,7 +2,6 @@ package domain
 
 import (
 	"slices"
-	"strings"
 	"time"
 
 	"github.com/mgnsk/calendar/pkg/snowflake"
@@ -21,8 +20,78 @@ type Event struct {
 	OSMID       uint64
 	Latitude    float64
 	Longitude   float64
+	VenueID     snowflake.ID
 	IsDraft     bool
 	UserID      snowflake.ID
+	Categories  []string
+
+	// IsPending is set on drafts awaiting moderation.
+	IsPending bool
+
+	// PublishAt is the time when a draft is published. Zero means not scheduled.
+	PublishAt time.Time
+
+	// Capacity is the maximum number of attendees. Zero means unlimited.
+	Capacity int
+
+	// TimezoneOverride is set when the time zone was chosen explicitly
+	// instead of resolved from the location.
+	TimezoneOverride bool
+
+	// Attendance is the number of RSVPs by status.
+	Attendance Attendance
+
+	// Language is the language code of Title and Description.
+	// Empty means the site default language.
+	Language     string
+	Translations []EventTranslation
+
+	// UID is the iCalendar UID of events created by calendar clients.
+	// Empty means the event ID.
+	UID string
+
+	// Attachments are uploaded images and documents in display order.
+	Attachments []Attachment
+
+	// Snippet is set on search results.
+	Snippet Snippet
+}
+
+// EventTranslation is a translation of the event title and description.
+type EventTranslation struct {
+	Language    string
+	Title       string
+	Description string
+}
+
+// IsScheduled reports whether the event is a draft scheduled to be published.
+func (e *Event) IsScheduled() bool {
+	return e.IsDraft && !e.PublishAt.IsZero()
+}
+
+// IsFull reports whether all places of a limited capacity event are taken.
+func (e *Event) IsFull() bool {
+	return e.Capacity > 0 && e.Attendance.Going >= e.Capacity
+}
+
+// GetSpotsLeft returns the number of free places or -1 if the capacity is unlimited.
+func (e *Event) GetSpotsLeft() int {
+	if e.Capacity <= 0 {
+		return -1
+	}
+
+	return max(0, e.Capacity-e.Attendance.Going)
+}
+
+// GetPoster returns the first image attachment or nil if there is none.
+func (e *Event) GetPoster() *Attachment {
+	for i := range e.Attachments {
+		if e.Attachments[i].IsImage() {
+			return &e.Attachments[i]
+		}
+	}
+
+	return nil
 }
 
 // GetCreatedAt returns the event created at time.
@@ -30,26 +99,50 @@ func (e *Event) GetCreatedAt() time.Time {
 	return snowflake.ParseTime(e.ID.Int64())
 }
 
-// GetDateString returns a formatted string with event start datetime.
-func (e *Event) GetDateString() string {
-	var buf strings.Builder
-	buf.WriteString(e.StartAt.Format("January _2, 2006 "))
+// GetUID returns the iCalendar UID of the event.
+func (e *Event) GetUID() string {
+	if e.UID != "" {
+		return e.UID
+	}
 
-	if e.StartAt.Minute() == 0 {
-		buf.WriteString(e.StartAt.Format("3PM"))
-	} else {
-		buf.WriteString(e.StartAt.Format("3:04PM"))
+	return e.ID.String()
+}
+
+// EventChange is a change of an event in the calendar of a user.
+type EventChange struct {
+	// Seq is the position of the change in the change log.
+	Seq     int64
+	EventID snowflake.ID
+	UserID  snowflake.ID
+	UID     string
+	Deleted bool
+}
+
+// GetUID returns the iCalendar UID of the changed event.
+func (c *EventChange) GetUID() string {
+	if c.UID != "" {
+		return c.UID
 	}
 
-	return buf.String()
+	return c.EventID.String()
 }
 
-// GetTags returns unique words in title and description.
-// A word is defined as having at least 3 characters.
-func (e *Event) GetTags() []string {
+// GetTimezoneName returns the IANA time zone name of the event start time
+// or an empty string if the event has only a fixed UTC offset.
+func (e *Event) GetTimezoneName() string {
+	name := e.StartAt.Location().String()
+	if name == "Local" {
+		return ""
+	}
+
+	return name
+}
+
+// GetTags returns unique tags extracted from title, description and location.
+func (e *Event) GetTags(x *textfilter.TagExtractor) []string {
 	var words []string
 	for _, source := range []string{e.Title, e.Description, e.Location} {
-		words = append(words, textfilter.GetTags(source)...)
+		words = append(words, x.GetTags(source)...)
 	}
 
 	slices.Sort(words)
@@ -57,3 +150,61 @@ func (e *Event) GetTags() []string {
 
 	return words
 }
+
+// Translate returns the event with title and description in the given language.
+// The event itself is returned when it has no translation to the language.
+// Empty translated fields fall back to the primary language.
+func (e *Event) Translate(language string) *Event {
+	if language == "" || language == e.Language {
+		return e
+	}
+
+	for _, t := range e.Translations {
+		if t.Language != language {
+			continue
+		}
+
+		ev := *e
+		if t.Title != "" {
+			ev.Title = t.Title
+		}
+		if t.Description != "" {
+			ev.Description = t.Description
+		}
+
+		return &ev
+	}
+
+	return e
+}
+
+// EventDay is the events starting on a day.
+type EventDay struct {
+	// Date is the start of the day in the location of the first event.
+	Date   time.Time
+	Events []*Event
+}
+
+// GroupEventsByDay groups events ordered by start time by the day they start on.
+// Days are in the location of each event.
+func GroupEventsByDay(events []*Event) []EventDay {
+	var days []EventDay
+
+	for _, ev := range events {
+		y, m, d := ev.StartAt.Date()
+
+		if n := len(days); n > 0 {
+			if ly, lm, ld := days[n-1].Date.Date(); ly == y && lm == m && ld == d {
+				days[n-1].Events = append(days[n-1].Events, ev)
+				continue
+			}
+		}
+
+		days = append(days, EventDay{
+			Date:   time.Date(y, m, d, 0, 0, 0, 0, ev.StartAt.Location()),
+			Events: []*Event{ev},
+		})
+	}
+
+	return days
+}
@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("event translations", func() {
+	ev := &domain.Event{
+		Title:       "Poetry evening",
+		Description: "Poems read aloud",
+		Language:    "en",
+		Translations: []domain.EventTranslation{
+			{Language: "et", Title: "Luuleõhtu", Description: ""},
+		},
+	}
+
+	Specify("translated fields replace the primary language", func() {
+		translated := ev.Translate("et")
+
+		Expect(translated.Title).To(Equal("Luuleõhtu"))
+		Expect(translated.Description).To(Equal("Poems read aloud"))
+		Expect(ev.Title).To(Equal("Poetry evening"))
+	})
+
+	Specify("primary language is used without a translation", func() {
+		Expect(ev.Translate("en")).To(BeIdenticalTo(ev))
+		Expect(ev.Translate("de")).To(BeIdenticalTo(ev))
+	})
+})
+
+var _ = Describe("event capacity", func() {
+	Specify("unlimited capacity is never full", func() {
+		ev := &domain.Event{Attendance: domain.Attendance{Going: 100}}
+
+		Expect(ev.IsFull()).To(BeFalse())
+		Expect(ev.GetSpotsLeft()).To(Equal(-1))
+	})
+
+	Specify("limited capacity is full when all places are taken", func() {
+		ev := &domain.Event{Capacity: 2, Attendance: domain.Attendance{Going: 1, Waitlisted: 3}}
+
+		Expect(ev.IsFull()).To(BeFalse())
+		Expect(ev.GetSpotsLeft()).To(Equal(1))
+
+		ev.Attendance.Going = 2
+
+		Expect(ev.IsFull()).To(BeTrue())
+		Expect(ev.GetSpotsLeft()).To(Equal(0))
+	})
+})
+
+var _ = Describe("grouping events by day", func() {
+	Specify("events are grouped by the day in their location", func() {
+		tallinn := Must(time.LoadLocation("Europe/Tallinn"))
+
+		first := &domain.Event{Title: "First", StartAt: time.Date(2030, 1, 2, 10, 0, 0, 0, tallinn)}
+		second := &domain.Event{Title: "Second", StartAt: time.Date(2030, 1, 2, 23, 30, 0, 0, tallinn)}
+		third := &domain.Event{Title: "Third", StartAt: time.Date(2030, 1, 4, 1, 0, 0, 0, tallinn)}
+
+		Expect(domain.GroupEventsByDay([]*domain.Event{first, second, third})).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("Date", Equal(time.Date(2030, 1, 2, 0, 0, 0, 0, tallinn))),
+				HaveField("Events", HaveExactElements(first, second)),
+			),
+			SatisfyAll(
+				HaveField("Date", Equal(time.Date(2030, 1, 4, 0, 0, 0, 0, tallinn))),
+				HaveField("Events", HaveExactElements(third)),
+			),
+		))
+	})
+
+	Specify("no events have no days", func() {
+		Expect(domain.GroupEventsByDay(nil)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,10 @@
+package domain
+
+// Place is a geocoded location.
+type Place struct {
+	Label     string
+	OSMType   string
+	OSMID     uint64
+	Latitude  float64
+	Longitude float64
+}
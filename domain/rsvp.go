@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// RSVPStatus is the response of an attendee.
+type RSVPStatus string
+
+// RSVP statuses.
+const (
+	RSVPGoing    RSVPStatus = "going"
+	RSVPMaybe    RSVPStatus = "maybe"
+	RSVPNotGoing RSVPStatus = "not_going"
+)
+
+// IsValid reports whether the status is known.
+func (s RSVPStatus) IsValid() bool {
+	switch s {
+	case RSVPGoing, RSVPMaybe, RSVPNotGoing:
+		return true
+	default:
+		return false
+	}
+}
+
+// RSVP is the event RSVP domain model.
+type RSVP struct {
+	ID      snowflake.ID
+	EventID snowflake.ID
+
+	// UserID is the responding user. Anonymous RSVPs have no user.
+	UserID snowflake.ID
+
+	Name   string
+	Email  string
+	Status RSVPStatus
+
+	// Waitlisted is set on going RSVPs over the event capacity.
+	// They are promoted in order when someone cancels.
+	Waitlisted bool
+
+	UpdatedAt time.Time
+}
+
+// GetCreatedAt returns the RSVP created at time.
+func (r *RSVP) GetCreatedAt() time.Time {
+	return snowflake.ParseTime(r.ID.Int64())
+}
+
+// IsAnonymous reports whether the RSVP was made without logging in.
+func (r *RSVP) IsAnonymous() bool {
+	return r.UserID == 0
+}
+
+// IsAttending reports whether the attendee has a place at the event.
+func (r *RSVP) IsAttending() bool {
+	return r.Status == RSVPGoing && !r.Waitlisted
+}
+
+// Attendance is the number of RSVPs of an event by status.
+type Attendance struct {
+	// Going does not include waitlisted RSVPs.
+	Going      int
+	Maybe      int
+	NotGoing   int
+	Waitlisted int
+}
+
+// IsEmpty reports whether nobody has responded.
+func (a Attendance) IsEmpty() bool {
+	return a == Attendance{}
+}
@@ -1,15 +1,114 @@
 package domain
 
+import "time"
+
+// TagsPageMode specifies what is shown on the tags page.
+type TagsPageMode string
+
+// Tags page modes.
+const (
+	// TagsPageWords shows automatically extracted word tags.
+	TagsPageWords TagsPageMode = "words"
+
+	// TagsPageCategories shows curated categories.
+	TagsPageCategories TagsPageMode = "categories"
+
+	// TagsPageBoth shows both curated categories and word tags.
+	TagsPageBoth TagsPageMode = "both"
+)
+
+// RetentionAction specifies what is done with events older than the retention period.
+type RetentionAction string
+
+// Retention actions.
+const (
+	// RetentionKeep keeps old events.
+	RetentionKeep RetentionAction = ""
+
+	// RetentionArchive returns old events to drafts.
+	RetentionArchive RetentionAction = "archive"
+
+	// RetentionDelete deletes old events.
+	RetentionDelete RetentionAction = "delete"
+)
+
 // Settings is the settings domain model.
 type Settings struct {
 	Title       string
 	Description string
+	TagsPage    TagsPageMode
+	TagLanguage string
+
+	// Locale is the default UI language code.
+	Locale string
+
+	// Timezone is the default IANA time zone of events
+	// when neither the location nor the author time zone is known.
+	Timezone string
+
+	// AccentColor is the #rrggbb theme color. Empty means the default amber.
+	AccentColor string
+
+	// Footer is the page footer text in markdown.
+	Footer       string
+	ContactEmail string
+
+	// LogoHash and FaviconHash are content hashes of the uploaded
+	// logo and favicon. Empty means not uploaded.
+	LogoHash    string
+	FaviconHash string
+
+	// PublicSubmission allows visitors to submit events without logging in.
+	PublicSubmission bool
+
+	// Moderation requires admin approval before events of non-admins are published.
+	Moderation bool
+
+	// TagsPageEnabled shows the tags page.
+	TagsPageEnabled bool
+
+	// AnonymousRSVP allows visitors to RSVP to events with their name and email.
+	AnonymousRSVP bool
+
+	// RetentionDays is the number of days after their start events are kept.
+	// Zero means forever.
+	RetentionDays   int
+	RetentionAction RetentionAction
+
+	// EmbedOrigins are the origins of partner sites allowed to show
+	// the embedded event list in a frame.
+	EmbedOrigins []string
+}
+
+// GetRetentionCutoff returns the start time before which events are archived or deleted.
+// It returns zero time when old events are kept.
+func (s *Settings) GetRetentionCutoff(now time.Time) time.Time {
+	if s.RetentionDays <= 0 || s.RetentionAction == RetentionKeep {
+		return time.Time{}
+	}
+
+	return now.AddDate(0, 0, -s.RetentionDays)
+}
+
+// ShowsWords reports whether the tags page shows word tags.
+func (s *Settings) ShowsWords() bool {
+	return s.TagsPage != TagsPageCategories
+}
+
+// ShowsCategories reports whether the tags page shows curated categories.
+func (s *Settings) ShowsCategories() bool {
+	return s.TagsPage == TagsPageCategories || s.TagsPage == TagsPageBoth
 }
 
 // NewDefaultSettings creates new default settings.
 func NewDefaultSettings() *Settings {
 	return &Settings{
-		Title:       "My Awesome Events",
-		Description: "All the awesome events in one place!",
+		Title:           "My Awesome Events",
+		Description:     "All the awesome events in one place!",
+		TagsPage:        TagsPageWords,
+		TagsPageEnabled: true,
 	}
 }
+
+// DefaultAccentColor is the theme color used when no accent color is set.
+const DefaultAccentColor = "#d97706"
@@ -0,0 +1,10 @@
+package domain
+
+// Snippet is an excerpt of event text matching a search.
+type Snippet []SnippetFragment
+
+// SnippetFragment is a part of a snippet.
+type SnippetFragment struct {
+	Text  string
+	Match bool
+}
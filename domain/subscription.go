@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// DigestFrequency is how often a subscription digest is sent.
+type DigestFrequency string
+
+// Digest frequencies.
+const (
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// IsValid returns whether the frequency is known.
+func (f DigestFrequency) IsValid() bool {
+	return f == DigestDaily || f == DigestWeekly
+}
+
+// Period returns the time between digests.
+// A digest lists the events starting within a period.
+func (f DigestFrequency) Period() time.Duration {
+	if f == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// Subscription is a visitor's email digest subscription.
+type Subscription struct {
+	ID        snowflake.ID
+	Email     string
+	Frequency DigestFrequency
+
+	// Category and Tag optionally filter the digest events.
+	Category string
+	Tag      string
+
+	// Language is the language of the digest.
+	Language string
+
+	// Token confirms and cancels the subscription.
+	Token uuid.UUID
+
+	// ConfirmedAt is zero until the email address is confirmed.
+	ConfirmedAt time.Time
+	LastSentAt  time.Time
+}
+
+// GetCreatedAt returns the subscription's created at time.
+func (s *Subscription) GetCreatedAt() time.Time {
+	return snowflake.ParseTime(s.ID.Int64())
+}
+
+// IsConfirmed returns whether the subscription is confirmed.
+func (s *Subscription) IsConfirmed() bool {
+	return !s.ConfirmedAt.IsZero()
+}
+
+// IsDue returns whether a digest is due at now.
+// The first digest is due right after confirming.
+func (s *Subscription) IsDue(now time.Time) bool {
+	return s.IsConfirmed() && !now.Before(s.LastSentAt.Add(s.Frequency.Period()))
+}
+
+// SubscriptionStats are subscriber counts.
+type SubscriptionStats struct {
+	Daily   int
+	Weekly  int
+	Pending int
+
+	// Filters are the confirmed subscriber counts by filter.
+	Filters []SubscriptionFilterCount
+}
+
+// SubscriptionFilterCount is the number of confirmed subscribers with a filter.
+type SubscriptionFilterCount struct {
+	Category string
+	Tag      string
+	Count    int
+}
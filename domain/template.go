@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// TemplateDatePlaceholder is replaced with the event start date in template titles.
+const TemplateDatePlaceholder = "{date}"
+
+// EventTemplate is the event template domain model.
+type EventTemplate struct {
+	ID snowflake.ID
+
+	// UserID is the owner of the template. Site-wide templates have no owner.
+	UserID snowflake.ID
+
+	Name        string
+	Title       string
+	Description string
+	URL         string
+	VenueID     snowflake.ID
+	Location    string
+	OSMType     string
+	OSMID       uint64
+	Latitude    float64
+	Longitude   float64
+	Categories  []string
+	Language    string
+}
+
+// IsSiteWide reports whether the template is available to all users.
+func (t *EventTemplate) IsSiteWide() bool {
+	return t.UserID == 0
+}
+
+// CanDelete reports whether the user may delete the template.
+// Site-wide templates can only be deleted by admins.
+func (t *EventTemplate) CanDelete(user *User) bool {
+	if t.IsSiteWide() {
+		return user.Role == Admin
+	}
+
+	return user.ID == t.UserID
+}
+
+// FormatTemplateTitle returns the title pattern with the date placeholder replaced.
+func FormatTemplateTitle(title, date string) string {
+	return strings.ReplaceAll(title, TemplateDatePlaceholder, date)
+}
@@ -0,0 +1,28 @@
+package domain_test
+
+import (
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("event templates", func() {
+	Specify("date placeholder is replaced in title", func() {
+		Expect(domain.FormatTemplateTitle("Jam session {date}", "2026-10-19")).To(Equal("Jam session 2026-10-19"))
+		Expect(domain.FormatTemplateTitle("Jam session", "2026-10-19")).To(Equal("Jam session"))
+	})
+
+	Specify("site-wide templates can only be deleted by admins", func() {
+		admin := &domain.User{ID: snowflake.Generate(), Role: domain.Admin}
+		author := &domain.User{ID: snowflake.Generate(), Role: domain.Author}
+
+		site := &domain.EventTemplate{}
+		own := &domain.EventTemplate{UserID: author.ID}
+
+		Expect(site.CanDelete(admin)).To(BeTrue())
+		Expect(site.CanDelete(author)).To(BeFalse())
+		Expect(own.CanDelete(author)).To(BeTrue())
+		Expect(own.CanDelete(admin)).To(BeFalse())
+	})
+})
@@ -25,6 +25,9 @@ const (
 type User struct {
 	ID       snowflake.ID
 	Username string
+
+	// Email is optional and used for notifications and password resets.
+	Email    string
 	Password []byte
 	Role     Role
 }
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// Venue is the venue domain model.
+type Venue struct {
+	ID            snowflake.ID
+	Name          string
+	Address       string
+	OSMType       string
+	OSMID         uint64
+	Latitude      float64
+	Longitude     float64
+	URL           string
+	Accessibility string
+
+	// EventCount is set when listing venues.
+	EventCount uint64
+}
+
+// GetLocation returns the event location text for the venue.
+func (v *Venue) GetLocation() string {
+	if v.Address == "" || v.Address == v.Name {
+		return v.Name
+	}
+
+	return v.Name + ", " + v.Address
+}
+
+// Apply sets the venue location on the event.
+func (v *Venue) Apply(ev *Event) {
+	ev.VenueID = v.ID
+	ev.Location = v.GetLocation()
+	ev.OSMType = v.OSMType
+	ev.OSMID = v.OSMID
+	ev.Latitude = v.Latitude
+	ev.Longitude = v.Longitude
+}
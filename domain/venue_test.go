@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	"github.com/mgnsk/calendar/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("venue location", func() {
+	Specify("address is appended to name", func() {
+		v := &domain.Venue{Name: "Sveta", Address: "Telliskivi 60a, Tallinn"}
+
+		Expect(v.GetLocation()).To(Equal("Sveta, Telliskivi 60a, Tallinn"))
+	})
+
+	Specify("empty address is omitted", func() {
+		v := &domain.Venue{Name: "Sveta"}
+
+		Expect(v.GetLocation()).To(Equal("Sveta"))
+	})
+
+	Specify("venue is applied to event", func() {
+		v := &domain.Venue{
+			Name:      "Sveta",
+			OSMType:   "node",
+			OSMID:     1,
+			Latitude:  59.44,
+			Longitude: 24.73,
+		}
+		ev := &domain.Event{Location: "sveta baar"}
+
+		v.Apply(ev)
+
+		Expect(ev.Location).To(Equal("Sveta"))
+		Expect(ev.OSMType).To(Equal("node"))
+		Expect(ev.OSMID).To(Equal(uint64(1)))
+		Expect(ev.Latitude).To(Equal(59.44))
+		Expect(ev.Longitude).To(Equal(24.73))
+	})
+})
@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+)
+
+// WebhookEventType is the type of an event lifecycle change.
+type WebhookEventType string
+
+// Webhook event types.
+const (
+	// WebhookPublished is sent when an event is published.
+	WebhookPublished WebhookEventType = "event.published"
+
+	// WebhookUpdated is sent when a published event is updated.
+	WebhookUpdated WebhookEventType = "event.updated"
+
+	// WebhookUnpublished is sent when a published event is withdrawn to drafts.
+	WebhookUnpublished WebhookEventType = "event.unpublished"
+
+	// WebhookDeleted is sent when a published event is deleted.
+	WebhookDeleted WebhookEventType = "event.deleted"
+
+	// WebhookPing is sent by the test button.
+	WebhookPing WebhookEventType = "ping"
+)
+
+// WebhookEventTypes are the event types webhooks can subscribe to.
+var WebhookEventTypes = []WebhookEventType{
+	WebhookPublished,
+	WebhookUpdated,
+	WebhookUnpublished,
+	WebhookDeleted,
+}
+
+// IsValid returns whether the event type can be subscribed to.
+func (t WebhookEventType) IsValid() bool {
+	return slices.Contains(WebhookEventTypes, t)
+}
+
+// Webhook is an admin-configured endpoint which receives event lifecycle changes.
+type Webhook struct {
+	ID         snowflake.ID
+	URL        string
+	Secret     string
+	EventTypes []WebhookEventType
+}
+
+// Accepts returns whether the webhook receives the event type.
+// Pings are always accepted.
+func (w *Webhook) Accepts(t WebhookEventType) bool {
+	return t == WebhookPing || slices.Contains(w.EventTypes, t)
+}
+
+// Sign returns the HMAC-SHA256 signature of a payload in the form sha256=<hex>.
+func (w *Webhook) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDeliveryStatus is the status of a webhook delivery.
+type WebhookDeliveryStatus string
+
+// Webhook delivery statuses.
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// MaxWebhookAttempts is the number of times a webhook delivery is attempted.
+const MaxWebhookAttempts = 10
+
+// WebhookDelivery is a queued or finished webhook request.
+type WebhookDelivery struct {
+	ID        snowflake.ID
+	WebhookID snowflake.ID
+	EventType WebhookEventType
+	Payload   []byte
+	Status    WebhookDeliveryStatus
+
+	Attempts      int
+	NextAttemptAt time.Time
+
+	// ResponseCode is the HTTP status of the last attempt. Zero means no response.
+	ResponseCode int
+	LastError    string
+	UpdatedAt    time.Time
+}
+
+// SetDelivered records a successful attempt.
+func (d *WebhookDelivery) SetDelivered(code int, now time.Time) {
+	d.Attempts++
+	d.Status = WebhookDeliveryDelivered
+	d.ResponseCode = code
+	d.LastError = ""
+	d.UpdatedAt = now
+}
+
+// SetFailed records a failed attempt and schedules the next one.
+// The delivery fails when it runs out of attempts.
+func (d *WebhookDelivery) SetFailed(code int, err error, now time.Time) {
+	d.NextAttemptAt = now.Add(retryBackoff(d.Attempts))
+	d.Attempts++
+	d.ResponseCode = code
+	d.LastError = err.Error()
+	d.UpdatedAt = now
+
+	if d.Attempts >= MaxWebhookAttempts {
+		d.Status = WebhookDeliveryFailed
+	}
+}
+
+// WebhookPayload is the JSON body of a webhook request.
+type WebhookPayload struct {
+	Type       WebhookEventType `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Event      *WebhookEvent    `json:"event,omitempty"`
+}
+
+// WebhookEvent describes an event in a webhook payload.
+type WebhookEvent struct {
+	// ID is a string since snowflake IDs exceed the JSON safe integer range.
+	ID string `json:"id"`
+
+	// Path is the path of the event page on the site.
+	Path         string               `json:"path"`
+	StartAt      time.Time            `json:"start_at"`
+	Timezone     string               `json:"timezone"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	URL          string               `json:"url,omitempty"`
+	Location     string               `json:"location,omitempty"`
+	Latitude     float64              `json:"latitude,omitempty"`
+	Longitude    float64              `json:"longitude,omitempty"`
+	Categories   []string             `json:"categories"`
+	Capacity     int                  `json:"capacity,omitempty"`
+	Language     string               `json:"language,omitempty"`
+	Translations []WebhookTranslation `json:"translations,omitempty"`
+}
+
+// WebhookTranslation is an event translation in a webhook payload.
+type WebhookTranslation struct {
+	Language    string `json:"language"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// NewWebhookPayload creates a payload describing an event lifecycle change.
+// The event is nil for pings.
+func NewWebhookPayload(t WebhookEventType, ev *Event, now time.Time) *WebhookPayload {
+	p := &WebhookPayload{
+		Type:       t,
+		OccurredAt: now.UTC().Truncate(time.Second),
+	}
+
+	if ev == nil {
+		return p
+	}
+
+	p.Event = &WebhookEvent{
+		ID:          ev.ID.String(),
+		Path:        fmt.Sprintf("/event/%d", ev.ID),
+		StartAt:     ev.StartAt,
+		Timezone:    ev.GetTimezoneName(),
+		Title:       ev.Title,
+		Description: ev.Description,
+		URL:         ev.URL,
+		Location:    ev.Location,
+		Latitude:    ev.Latitude,
+		Longitude:   ev.Longitude,
+		Categories:  slices.Clone(ev.Categories),
+		Capacity:    ev.Capacity,
+		Language:    ev.Language,
+	}
+
+	if p.Event.Categories == nil {
+		p.Event.Categories = []string{}
+	}
+
+	for _, tr := range ev.Translations {
+		p.Event.Translations = append(p.Event.Translations, WebhookTranslation(tr))
+	}
+
+	return p
+}
@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("webhooks", func() {
+	webhook := &domain.Webhook{
+		Secret:     "It's a Secret to Everybody",
+		EventTypes: []domain.WebhookEventType{domain.WebhookPublished},
+	}
+
+	Specify("payload is signed with HMAC-SHA256", func() {
+		Expect(webhook.Sign([]byte("Hello, World!"))).To(Equal("sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"))
+	})
+
+	Specify("pings are always accepted", func() {
+		Expect(webhook.Accepts(domain.WebhookPublished)).To(BeTrue())
+		Expect(webhook.Accepts(domain.WebhookPing)).To(BeTrue())
+		Expect(webhook.Accepts(domain.WebhookDeleted)).To(BeFalse())
+	})
+
+	Specify("event is described in the payload", func() {
+		ev := &domain.Event{
+			ID:      1234567890123456789,
+			StartAt: time.Date(2030, 1, 2, 18, 0, 0, 0, time.UTC),
+			Title:   "Concert",
+		}
+
+		payload := Must(json.Marshal(domain.NewWebhookPayload(domain.WebhookPublished, ev, ev.StartAt)))
+
+		Expect(payload).To(MatchJSON(`{
+			"type": "event.published",
+			"occurred_at": "2030-01-02T18:00:00Z",
+			"event": {
+				"id": "1234567890123456789",
+				"path": "/event/1234567890123456789",
+				"start_at": "2030-01-02T18:00:00Z",
+				"timezone": "UTC",
+				"title": "Concert",
+				"description": "",
+				"categories": []
+			}
+		}`))
+	})
+
+	Specify("delivery fails after the last attempt", func() {
+		d := &domain.WebhookDelivery{Status: domain.WebhookDeliveryPending}
+
+		for range domain.MaxWebhookAttempts - 1 {
+			d.SetFailed(500, errors.New("unexpected status"), time.Now())
+			Expect(d.Status).To(Equal(domain.WebhookDeliveryPending))
+		}
+
+		d.SetFailed(500, errors.New("unexpected status"), time.Now())
+		Expect(d.Status).To(Equal(domain.WebhookDeliveryFailed))
+		Expect(d.ResponseCode).To(Equal(500))
+	})
+})
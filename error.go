@@ -20,6 +20,7 @@ var (
 	NotFound           = wreck.New("not_found").With(KeyHTTPCode, http.StatusNotFound)
 	Timeout            = wreck.New("timeout").With(KeyHTTPCode, http.StatusRequestTimeout)
 	Forbidden          = wreck.New("forbidden").With(KeyHTTPCode, http.StatusForbidden)
+	Unauthorized       = wreck.New("unauthorized").With(KeyHTTPCode, http.StatusUnauthorized)
 
 	Internal = wreck.New("internal").With(KeyHTTPCode, http.StatusInternalServerError)
 )
@@ -8,9 +8,11 @@ require (
 	github.com/arran4/golang-ical v0.3.5
 	github.com/aybabtme/uniplot v0.0.0-20151203143629-039c559e5e7e
 	github.com/bwmarrin/snowflake v0.3.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/golang-migrate/migrate/v4 v4.19.1
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/feeds v1.2.0
+	github.com/kljensen/snowball v0.10.0
 	github.com/labstack/echo/v4 v4.15.1
 	github.com/mgnsk/wreck v0.0.0-20250814111550-d27b8a6e5d5b
 	github.com/mmcdole/gofeed v1.3.0
@@ -18,12 +20,16 @@ require (
 	github.com/onsi/gomega v1.40.0
 	github.com/ringsaturn/tzf v1.1.1
 	github.com/samber/lo v1.53.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/uptrace/bun v1.2.18
 	github.com/uptrace/bun/dialect/sqlitedialect v1.2.18
 	github.com/uptrace/bun/extra/bundebug v1.2.18
 	github.com/yuin/goldmark v1.8.2
 	golang.org/x/crypto v0.50.0
+	golang.org/x/image v0.37.0
 	golang.org/x/sync v0.20.0
+	golang.org/x/text v0.36.0
+	golang.org/x/time v0.14.0
 	maragu.dev/gomponents v1.3.0
 	maragu.dev/gomponents-htmx v0.6.1
 	modernc.org/sqlite v1.50.0
@@ -90,8 +96,6 @@ require (
 	golang.org/x/mod v0.35.0 // indirect
 	golang.org/x/net v0.53.0 // indirect
 	golang.org/x/sys v0.43.0 // indirect
-	golang.org/x/text v0.36.0 // indirect
-	golang.org/x/time v0.14.0 // indirect
 	golang.org/x/tools v0.44.0 // indirect
 	google.golang.org/protobuf v1.36.11 // indirect
 	modernc.org/libc v1.72.0 // indirect
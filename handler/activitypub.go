@@ -0,0 +1,518 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/activitypub"
+	"github.com/mgnsk/calendar/pkg/httpsig"
+	"github.com/mgnsk/calendar/pkg/markdown"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+)
+
+// ActivityPubUsername is the username of the site actor, as in @events@example.com.
+const ActivityPubUsername = "events"
+
+// ActivityPubHandler handles ActivityPub federation. The site is a single
+// actor which publishes its events to followers.
+type ActivityPubHandler struct {
+	db      *bun.DB
+	client  *http.Client
+	baseURL string
+}
+
+// WebFinger handles actor discovery.
+func (h *ActivityPubHandler) WebFinger(c *server.Context) error {
+	req := contract.WebFingerRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(h.baseURL)
+	if err != nil {
+		return err
+	}
+
+	actorID := activityPubActorID(h.baseURL)
+	subject := fmt.Sprintf("acct:%s@%s", ActivityPubUsername, u.Host)
+
+	if req.Resource != subject && req.Resource != actorID {
+		return calendar.NotFound.New("Not found")
+	}
+
+	c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, "*")
+
+	return writeActivityJSON(c, "application/jrd+json", &activitypub.WebFinger{
+		Subject: subject,
+		Aliases: []string{actorID},
+		Links: []activitypub.WebFingerLink{
+			{
+				Rel:  "self",
+				Type: activitypub.ContentType,
+				Href: actorID,
+			},
+			{
+				Rel:  "http://webfinger.net/rel/profile-page",
+				Type: "text/html",
+				Href: h.baseURL + "/",
+			},
+		},
+	})
+}
+
+// Actor handles the actor document of the site.
+func (h *ActivityPubHandler) Actor(c *server.Context) error {
+	key, err := model.GetActorKey(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	actorID := activityPubActorID(h.baseURL)
+
+	actor := &activitypub.Actor{
+		Context:           activitypub.Context,
+		ID:                actorID,
+		Type:              "Application",
+		PreferredUsername: ActivityPubUsername,
+		Name:              c.Settings.Title,
+		Summary:           c.Settings.Description,
+		URL:               h.baseURL + "/",
+		Inbox:             h.baseURL + "/activitypub/inbox",
+		Outbox:            h.baseURL + "/activitypub/outbox",
+		Followers:         h.baseURL + "/activitypub/followers",
+		Endpoints: &activitypub.Endpoints{
+			SharedInbox: h.baseURL + "/activitypub/inbox",
+		},
+		PublicKey: &activitypub.PublicKey{
+			ID:           activityPubKeyID(h.baseURL),
+			Owner:        actorID,
+			PublicKeyPem: httpsig.EncodePublicKey(&key.PublicKey),
+		},
+	}
+
+	if c.Settings.LogoHash != "" {
+		actor.Icon = &activitypub.Image{
+			Type: "Image",
+			URL:  h.baseURL + "/site/logo?v=" + c.Settings.LogoHash,
+		}
+	}
+
+	return writeActivityJSON(c, activitypub.ContentType, actor)
+}
+
+// Outbox handles the outbox of the site with the latest published events.
+// Older events are not paginated.
+func (h *ActivityPubHandler) Outbox(c *server.Context) error {
+	events, err := model.NewEventsQuery().
+		WithOrder(0, model.OrderCreatedAtDesc).
+		WithLimit(20).
+		List(c.Request().Context(), h.db)
+	if err != nil {
+		if !errors.Is(err, calendar.NotFound) {
+			return err
+		}
+	}
+
+	items := make([]any, 0, len(events))
+	for _, ev := range events {
+		items = append(items, newEventActivity(h.baseURL, domain.ActivityCreate, ev, activityPubObjectID(h.baseURL, ev)+"#create"))
+	}
+
+	return writeActivityJSON(c, activitypub.ContentType, &activitypub.OrderedCollection{
+		Context:      activitypub.Context,
+		ID:           h.baseURL + "/activitypub/outbox",
+		Type:         "OrderedCollection",
+		TotalItems:   len(items),
+		OrderedItems: items,
+	})
+}
+
+// Followers handles the followers collection. Only the count is public.
+func (h *ActivityPubHandler) Followers(c *server.Context) error {
+	total, err := model.CountFollowers(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	return writeActivityJSON(c, activitypub.ContentType, &activitypub.OrderedCollection{
+		Context:    activitypub.Context,
+		ID:         h.baseURL + "/activitypub/followers",
+		Type:       "OrderedCollection",
+		TotalItems: total,
+	})
+}
+
+// Event handles the object of a published event.
+func (h *ActivityPubHandler) Event(c *server.Context) error {
+	req := contract.EventRequest{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	ev, err := model.GetEvent(c.Request().Context(), h.db, req.EventID)
+	if err != nil {
+		return err
+	}
+
+	if ev.IsDraft {
+		return calendar.NotFound.New("Not found")
+	}
+
+	obj := newEventObject(h.baseURL, ev)
+	obj.Context = activitypub.Context
+
+	return writeActivityJSON(c, activitypub.ContentType, obj)
+}
+
+// Inbox handles activities sent to the site. Requests must be signed by the
+// actor of the activity. Follows are accepted and unfollows delete the follower.
+// Other activities are ignored.
+func (h *ActivityPubHandler) Inbox(c *server.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	activity := &activitypub.Activity{}
+	if err := json.Unmarshal(body, activity); err != nil {
+		return calendar.InvalidValue.New("Invalid activity", err)
+	}
+
+	// Deleted remote accounts can no longer be verified.
+	if activity.Type == "Delete" {
+		return c.NoContent(http.StatusAccepted)
+	}
+
+	actor, err := h.verify(c, activity, body)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	actorID := activityPubActorID(h.baseURL)
+
+	switch activity.Type {
+	case "Follow":
+		if activity.ObjectID() != actorID {
+			return calendar.InvalidValue.New("Only the site actor can be followed")
+		}
+
+		if err := h.db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+			f, err := model.PutFollower(ctx, db, &domain.Follower{
+				ActorID:  actor.ID,
+				Inbox:    actor.SharedInbox(),
+				FollowID: activity.ID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return model.InsertActivityDelivery(ctx, db, &domain.ActivityDelivery{
+				Inbox:      actor.Inbox,
+				Type:       domain.ActivityAccept,
+				FollowerID: f.ID,
+			})
+		}); err != nil {
+			return err
+		}
+
+	case "Undo":
+		if activity.ObjectType() == "Follow" {
+			if err := model.DeleteFollower(ctx, h.db, actor.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// verify verifies the signature of an activity and returns its actor.
+func (h *ActivityPubHandler) verify(c *server.Context, activity *activitypub.Activity, body []byte) (*activitypub.Actor, error) {
+	keyID, err := httpsig.KeyID(c.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newActivityPubClient(c.Request().Context(), h.db, h.client, h.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := client.FetchActor(c.Request().Context(), keyID)
+	if err != nil {
+		return nil, calendar.Forbidden.New("Unable to fetch the signing key", err)
+	}
+
+	if actor.PublicKey == nil || actor.PublicKey.ID != keyID || actor.PublicKey.Owner != actor.ID {
+		return nil, calendar.Forbidden.New("Unknown signing key")
+	}
+
+	if actor.ID != activity.Actor {
+		return nil, calendar.Forbidden.New("Activity must be signed by its actor")
+	}
+
+	key, err := httpsig.DecodePublicKey(actor.PublicKey.PublicKeyPem)
+	if err != nil {
+		return nil, calendar.Forbidden.New("Invalid signing key", err)
+	}
+
+	if err := httpsig.Verify(c.Request(), key, body, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return actor, nil
+}
+
+// Register the handler.
+func (h *ActivityPubHandler) Register(g *echo.Group) {
+	g.GET("/.well-known/webfinger", server.Wrap(h.db, nil, h.WebFinger))
+
+	g.GET("/activitypub/actor", server.Wrap(h.db, nil, h.Actor))
+	g.GET("/activitypub/outbox", server.Wrap(h.db, nil, h.Outbox))
+	g.GET("/activitypub/followers", server.Wrap(h.db, nil, h.Followers))
+	g.GET("/activitypub/event/:event_id", server.Wrap(h.db, nil, h.Event))
+	g.POST("/activitypub/inbox", server.Wrap(h.db, nil, h.Inbox))
+}
+
+// NewActivityPubHandler creates a new ActivityPub handler.
+// Object IDs are relative to baseURL.
+func NewActivityPubHandler(db *bun.DB, client *http.Client, baseURL string) *ActivityPubHandler {
+	return &ActivityPubHandler{
+		db:      db,
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// ProcessActivityPub delivers the queued activities which are due at now.
+// Failed deliveries are retried with backoff until they run out of attempts.
+// Activities of events and followers which no longer exist are dropped.
+// It returns the number of delivered activities.
+func ProcessActivityPub(ctx context.Context, db *bun.DB, client *http.Client, baseURL string, now time.Time) (int, error) {
+	deliveries, err := model.ListDueActivityDeliveries(ctx, db, now, 100)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	ap, err := newActivityPubClient(ctx, db, client, baseURL)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+
+	for _, d := range deliveries {
+		activity, err := newQueuedActivity(ctx, db, baseURL, d)
+		if err != nil {
+			if !errors.Is(err, calendar.NotFound) {
+				return delivered, err
+			}
+
+			if err := model.DeleteActivityDelivery(ctx, db, d.ID); err != nil {
+				return delivered, err
+			}
+
+			continue
+		}
+
+		postCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err = ap.Post(postCtx, d.Inbox, activity)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return delivered, nil
+			}
+
+			d.SetFailed(err, now)
+
+			slog.Warn("error delivering activity",
+				slog.String("id", d.ID.String()),
+				slog.String("inbox", d.Inbox),
+				slog.Int("attempts", d.Attempts),
+				slog.String("error", err.Error()),
+			)
+
+			if err := model.UpdateActivityDelivery(ctx, db, d); err != nil {
+				return delivered, err
+			}
+
+			continue
+		}
+
+		if err := model.DeleteActivityDelivery(ctx, db, d.ID); err != nil {
+			return delivered, err
+		}
+
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// newQueuedActivity builds the activity of a queued delivery.
+func newQueuedActivity(ctx context.Context, db bun.IDB, baseURL string, d *domain.ActivityDelivery) (*activitypub.Activity, error) {
+	actorID := activityPubActorID(baseURL)
+	activityID := fmt.Sprintf("%s/activitypub/activity/%d", baseURL, d.ID)
+
+	switch d.Type {
+	case domain.ActivityAccept:
+		f, err := model.GetFollower(ctx, db, d.FollowerID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &activitypub.Activity{
+			Context: activitypub.Context,
+			ID:      activityID,
+			Type:    string(domain.ActivityAccept),
+			Actor:   actorID,
+			To:      []string{f.ActorID},
+			Object: &activitypub.Activity{
+				ID:     f.FollowID,
+				Type:   "Follow",
+				Actor:  f.ActorID,
+				Object: actorID,
+			},
+		}, nil
+
+	case domain.ActivityDelete:
+		objectID := fmt.Sprintf("%s/activitypub/event/%d", baseURL, d.EventID)
+
+		return &activitypub.Activity{
+			Context: activitypub.Context,
+			ID:      activityID,
+			Type:    string(domain.ActivityDelete),
+			Actor:   actorID,
+			To:      []string{activitypub.Public},
+			Cc:      []string{baseURL + "/activitypub/followers"},
+			Object: &activitypub.Tombstone{
+				ID:   objectID,
+				Type: "Tombstone",
+			},
+		}, nil
+
+	default:
+		ev, err := model.GetEvent(ctx, db, d.EventID)
+		if err != nil {
+			return nil, err
+		}
+
+		if ev.IsDraft {
+			// Withdrawn after queueing, followed by a Delete.
+			return nil, calendar.NotFound.New("Event was withdrawn")
+		}
+
+		return newEventActivity(baseURL, d.Type, ev, activityID), nil
+	}
+}
+
+// newEventActivity wraps an event object in an activity.
+func newEventActivity(baseURL string, t domain.ActivityType, ev *domain.Event, activityID string) *activitypub.Activity {
+	obj := newEventObject(baseURL, ev)
+	published := obj.Published
+
+	return &activitypub.Activity{
+		Context:   activitypub.Context,
+		ID:        activityID,
+		Type:      string(t),
+		Actor:     obj.AttributedTo,
+		Published: &published,
+		To:        obj.To,
+		Cc:        obj.Cc,
+		Object:    obj,
+	}
+}
+
+// newEventObject converts an event to an ActivityPub Event object.
+func newEventObject(baseURL string, ev *domain.Event) *activitypub.Event {
+	var content strings.Builder
+	if err := markdown.Convert(&content, ev.Description); err != nil {
+		content.Reset()
+		content.WriteString(ev.Description)
+	}
+
+	obj := &activitypub.Event{
+		ID:           activityPubObjectID(baseURL, ev),
+		Type:         "Event",
+		Name:         ev.Title,
+		Content:      content.String(),
+		MediaType:    "text/html",
+		URL:          fmt.Sprintf("%s/event/%d", baseURL, ev.ID),
+		StartTime:    ev.StartAt,
+		Timezone:     ev.GetTimezoneName(),
+		Published:    ev.GetCreatedAt().UTC(),
+		AttributedTo: activityPubActorID(baseURL),
+		To:           []string{activitypub.Public},
+		Cc:           []string{baseURL + "/activitypub/followers"},
+		Language:     ev.Language,
+	}
+
+	if ev.Location != "" {
+		obj.Location = &activitypub.Place{
+			Type:      "Place",
+			Name:      ev.Location,
+			Latitude:  ev.Latitude,
+			Longitude: ev.Longitude,
+		}
+	}
+
+	for _, category := range ev.Categories {
+		obj.Tag = append(obj.Tag, activitypub.Tag{
+			Type: "Hashtag",
+			Name: "#" + strings.ReplaceAll(category, " ", ""),
+		})
+	}
+
+	return obj
+}
+
+// newActivityPubClient creates a client which signs requests with the site actor key.
+func newActivityPubClient(ctx context.Context, db bun.IDB, client *http.Client, baseURL string) (*activitypub.Client, error) {
+	key, err := model.GetActorKey(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return activitypub.NewClient(client, activityPubKeyID(baseURL), key), nil
+}
+
+func activityPubActorID(baseURL string) string {
+	return baseURL + "/activitypub/actor"
+}
+
+func activityPubKeyID(baseURL string) string {
+	return activityPubActorID(baseURL) + "#main-key"
+}
+
+func activityPubObjectID(baseURL string, ev *domain.Event) string {
+	return fmt.Sprintf("%s/activitypub/event/%d", baseURL, ev.ID)
+}
+
+func writeActivityJSON(c *server.Context, contentType string, v any) error {
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return json.NewEncoder(c.Response()).Encode(v)
+}
@@ -0,0 +1,302 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/activitypub"
+	"github.com/mgnsk/calendar/pkg/httpsig"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ActivityPub federation", func() {
+	type delivery struct {
+		req  *http.Request
+		body []byte
+	}
+
+	var (
+		ts         *httptest.Server
+		remote     *httptest.Server
+		remoteKey  *rsa.PrivateKey
+		mu         sync.Mutex
+		deliveries []delivery
+	)
+
+	aliceID := func() string {
+		return remote.URL + "/users/alice"
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+
+		deliveries = nil
+		remoteKey = Must(rsa.GenerateKey(rand.Reader, 2048))
+
+		// A fake remote server with a single actor and inboxes.
+		remote = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/users/mallory":
+				// An actor document claiming to be an actor of another server.
+				w.Header().Set("Content-Type", activitypub.ContentType)
+				_ = json.NewEncoder(w).Encode(&activitypub.Actor{
+					ID:    "https://mastodon.testing/users/alice",
+					Type:  "Person",
+					Inbox: remote.URL + "/inbox",
+					PublicKey: &activitypub.PublicKey{
+						ID:           remote.URL + "/users/mallory#main-key",
+						Owner:        "https://mastodon.testing/users/alice",
+						PublicKeyPem: httpsig.EncodePublicKey(&remoteKey.PublicKey),
+					},
+				})
+
+			case r.Method == http.MethodGet && r.URL.Path == "/users/alice":
+				w.Header().Set("Content-Type", activitypub.ContentType)
+				_ = json.NewEncoder(w).Encode(&activitypub.Actor{
+					ID:    aliceID(),
+					Type:  "Person",
+					Inbox: aliceID() + "/inbox",
+					Endpoints: &activitypub.Endpoints{
+						SharedInbox: remote.URL + "/inbox",
+					},
+					PublicKey: &activitypub.PublicKey{
+						ID:           aliceID() + "#main-key",
+						Owner:        aliceID(),
+						PublicKeyPem: httpsig.EncodePublicKey(&remoteKey.PublicKey),
+					},
+				})
+
+			case r.Method == http.MethodPost:
+				mu.Lock()
+				defer mu.Unlock()
+
+				deliveries = append(deliveries, delivery{
+					req:  r.Clone(context.Background()),
+					body: Must(io.ReadAll(r.Body)),
+				})
+				w.WriteHeader(http.StatusAccepted)
+
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(remote.Close)
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+
+		h := handler.NewActivityPubHandler(db, http.DefaultClient, ts.URL)
+		h.Register(e.Group(""))
+	})
+
+	getJSON := func(path string, v any) {
+		GinkgoHelper()
+
+		r := Must(ts.Client().Get(ts.URL + path))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(json.NewDecoder(r.Body).Decode(v)).To(Succeed())
+	}
+
+	postInbox := func(activity *activitypub.Activity, sign bool) int {
+		GinkgoHelper()
+
+		body := Must(json.Marshal(activity))
+		req := Must(http.NewRequest(http.MethodPost, ts.URL+"/activitypub/inbox", bytes.NewReader(body)))
+		req.Header.Set("Content-Type", activitypub.ContentType)
+
+		if sign {
+			Expect(httpsig.Sign(req, aliceID()+"#main-key", remoteKey, body)).To(Succeed())
+		}
+
+		r := Must(ts.Client().Do(req))
+		defer r.Body.Close()
+
+		return r.StatusCode
+	}
+
+	follow := func() {
+		GinkgoHelper()
+
+		Expect(postInbox(&activitypub.Activity{
+			ID:     aliceID() + "/follows/1",
+			Type:   "Follow",
+			Actor:  aliceID(),
+			Object: ts.URL + "/activitypub/actor",
+		}, true)).To(Equal(http.StatusAccepted))
+	}
+
+	// received delivers the queued activities and returns the received ones
+	// after verifying their signatures.
+	received := func(ctx SpecContext) []*activitypub.Activity {
+		GinkgoHelper()
+
+		_ = Must(handler.ProcessActivityPub(ctx, db, http.DefaultClient, ts.URL, time.Now()))
+
+		actor := &activitypub.Actor{}
+		getJSON("/activitypub/actor", actor)
+		key := Must(httpsig.DecodePublicKey(actor.PublicKey.PublicKeyPem))
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var activities []*activitypub.Activity
+		for _, d := range deliveries {
+			Expect(httpsig.Verify(d.req, key, d.body, time.Now())).To(Succeed())
+
+			activity := &activitypub.Activity{}
+			Expect(json.Unmarshal(d.body, activity)).To(Succeed())
+			activities = append(activities, activity)
+		}
+
+		deliveries = nil
+
+		return activities
+	}
+
+	Specify("WebFinger resolves the actor", func() {
+		u := Must(url.Parse(ts.URL))
+
+		jrd := &activitypub.WebFinger{}
+		getJSON("/.well-known/webfinger?resource="+url.QueryEscape("acct:events@"+u.Host), jrd)
+
+		Expect(jrd.Links).To(ContainElement(activitypub.WebFingerLink{
+			Rel:  "self",
+			Type: activitypub.ContentType,
+			Href: ts.URL + "/activitypub/actor",
+		}))
+
+		r := Must(ts.Client().Get(ts.URL + "/.well-known/webfinger?resource=" + url.QueryEscape("acct:other@"+u.Host)))
+		r.Body.Close()
+		Expect(r.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	Specify("follow is accepted and events are delivered", func(ctx SpecContext) {
+		follow()
+
+		accept := received(ctx)
+		Expect(accept).To(HaveExactElements(SatisfyAll(
+			HaveField("Type", "Accept"),
+			HaveField("Actor", ts.URL+"/activitypub/actor"),
+			HaveField("ObjectID()", aliceID()+"/follows/1"),
+		)))
+
+		followers := &activitypub.OrderedCollection{}
+		getJSON("/activitypub/followers", followers)
+		Expect(followers.TotalItems).To(Equal(1))
+
+		By("publishing an event")
+		Expect(model.InsertEvent(ctx, db, event1)).To(Succeed())
+
+		create := received(ctx)
+		Expect(create).To(HaveExactElements(SatisfyAll(
+			HaveField("Type", "Create"),
+			HaveField("ObjectType()", "Event"),
+			HaveField("ObjectID()", ts.URL+"/activitypub/event/"+event1.ID.String()),
+			HaveField("Object", HaveKeyWithValue("name", "Event 1")),
+		)))
+
+		By("fetching the event object")
+		obj := &activitypub.Event{}
+		getJSON("/activitypub/event/"+event1.ID.String(), obj)
+		Expect(obj.Name).To(Equal("Event 1"))
+		Expect(obj.Location).To(HaveField("Name", "Loc 1"))
+
+		By("deleting the event")
+		Expect(model.DeleteEvent(ctx, db, event1)).To(Succeed())
+
+		Expect(received(ctx)).To(HaveExactElements(SatisfyAll(
+			HaveField("Type", "Delete"),
+			HaveField("ObjectType()", "Tombstone"),
+			HaveField("ObjectID()", ts.URL+"/activitypub/event/"+event1.ID.String()),
+		)))
+	})
+
+	Specify("unfollowing stops deliveries", func(ctx SpecContext) {
+		follow()
+		Expect(received(ctx)).To(HaveLen(1))
+
+		Expect(postInbox(&activitypub.Activity{
+			ID:    aliceID() + "/follows/1/undo",
+			Type:  "Undo",
+			Actor: aliceID(),
+			Object: map[string]any{
+				"id":     aliceID() + "/follows/1",
+				"type":   "Follow",
+				"actor":  aliceID(),
+				"object": ts.URL + "/activitypub/actor",
+			},
+		}, true)).To(Equal(http.StatusAccepted))
+
+		Expect(model.InsertEvent(ctx, db, event1)).To(Succeed())
+		Expect(received(ctx)).To(BeEmpty())
+	})
+
+	Specify("unsigned activity is rejected", func(ctx SpecContext) {
+		Expect(postInbox(&activitypub.Activity{
+			ID:     aliceID() + "/follows/1",
+			Type:   "Follow",
+			Actor:  aliceID(),
+			Object: ts.URL + "/activitypub/actor",
+		}, false)).To(Equal(http.StatusForbidden))
+
+		Expect(Must(model.CountFollowers(ctx, db))).To(BeZero())
+	})
+
+	Specify("activity signed by another actor is rejected", func(ctx SpecContext) {
+		Expect(postInbox(&activitypub.Activity{
+			ID:     remote.URL + "/users/bob/follows/1",
+			Type:   "Follow",
+			Actor:  remote.URL + "/users/bob",
+			Object: ts.URL + "/activitypub/actor",
+		}, true)).To(Equal(http.StatusForbidden))
+
+		Expect(Must(model.CountFollowers(ctx, db))).To(BeZero())
+	})
+
+	Specify("actor of another server is rejected", func(ctx SpecContext) {
+		body := Must(json.Marshal(&activitypub.Activity{
+			ID:     "https://mastodon.testing/users/alice/follows/1",
+			Type:   "Follow",
+			Actor:  "https://mastodon.testing/users/alice",
+			Object: ts.URL + "/activitypub/actor",
+		}))
+
+		req := Must(http.NewRequest(http.MethodPost, ts.URL+"/activitypub/inbox", bytes.NewReader(body)))
+		req.Header.Set("Content-Type", activitypub.ContentType)
+		Expect(httpsig.Sign(req, remote.URL+"/users/mallory#main-key", remoteKey, body)).To(Succeed())
+
+		r := Must(ts.Client().Do(req))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(Must(model.CountFollowers(ctx, db))).To(BeZero())
+	})
+
+	Specify("public client refuses to connect to internal addresses", func() {
+		client := activitypub.NewPublicHTTPClient(time.Second)
+
+		_, err := client.Get(remote.URL + "/users/alice")
+		Expect(err).To(MatchError(ContainSubstring("refusing to connect to non-public address 127.0.0.1")))
+	})
+})
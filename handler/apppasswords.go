@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+	hxhttp "maragu.dev/gomponents-htmx/http"
+)
+
+// AppPasswordsHandler handles the app passwords of the current user.
+type AppPasswordsHandler struct {
+	db *bun.DB
+	sm *scs.SessionManager
+}
+
+// AppPasswords handles the app password list and adding app passwords.
+// A new password is shown once on the page rendered after adding it.
+func (h *AppPasswordsHandler) AppPasswords(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	form := contract.AppPasswordForm{}
+	var password string
+
+	if c.Request().Method == http.MethodPost {
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		if errs := form.Validate(); len(errs) > 0 {
+			return h.render(c, "", form, errs)
+		}
+
+		p, plain := domain.NewAppPassword(c.User.ID, form.Name)
+
+		if err := model.InsertAppPassword(c.Request().Context(), h.db, p); err != nil {
+			return err
+		}
+
+		form = contract.AppPasswordForm{}
+		password = plain
+	}
+
+	return h.render(c, password, form, nil)
+}
+
+// Delete handles deleting app passwords.
+func (h *AppPasswordsHandler) Delete(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		req := contract.AppPasswordRequest{}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+
+		if err := model.DeleteAppPassword(c.Request().Context(), h.db, c.User.ID, req.AppPasswordID); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "App password deleted")
+
+		hxhttp.SetRefresh(c.Response().Header())
+
+		return nil
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+func (h *AppPasswordsHandler) render(c *server.Context, password string, form contract.AppPasswordForm, errs url.Values) error {
+	passwords, err := model.ListAppPasswords(c.Request().Context(), h.db, c.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.AppPasswordsMain(c.Locale, c.User, absoluteURL(c, "/caldav/"), passwords, password, form, errs, c.CSRF),
+	)
+}
+
+// Register the handler.
+func (h *AppPasswordsHandler) Register(g *echo.Group) {
+	g.GET("/app-passwords", server.Wrap(h.db, h.sm, h.AppPasswords))
+	g.POST("/app-passwords", server.Wrap(h.db, h.sm, h.AppPasswords))
+	g.POST("/app-passwords/delete", server.Wrap(h.db, h.sm, h.Delete))
+}
+
+// NewAppPasswordsHandler creates a new app passwords handler.
+func NewAppPasswordsHandler(db *bun.DB, sm *scs.SessionManager) *AppPasswordsHandler {
+	return &AppPasswordsHandler{
+		db: db,
+		sm: sm,
+	}
+}
@@ -3,14 +3,17 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/alexedwards/scs/v2"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/mgnsk/calendar"
 	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/html"
 	"github.com/mgnsk/calendar/model"
 	"github.com/mgnsk/calendar/server"
@@ -19,8 +22,10 @@ import (
 
 // AuthenticationHandler handles user login and logout.
 type AuthenticationHandler struct {
-	db *bun.DB
-	sm *scs.SessionManager
+	db          *bun.DB
+	sm          *scs.SessionManager
+	mailEnabled bool
+	baseURL     string
 }
 
 // Login handles login page.
@@ -32,7 +37,7 @@ func (h *AuthenticationHandler) Login(c *server.Context) error {
 	switch c.Request().Method {
 	case http.MethodGet:
 		return server.RenderPage(c, h.sm,
-			html.LoginMain(contract.LoginForm{}, nil, c.CSRF),
+			html.LoginMain(c.Locale, contract.LoginForm{}, nil, h.mailEnabled, c.CSRF),
 		)
 
 	case http.MethodPost:
@@ -43,7 +48,7 @@ func (h *AuthenticationHandler) Login(c *server.Context) error {
 
 		if errs := req.Validate(); len(errs) > 0 {
 			return server.RenderPage(c, h.sm,
-				html.LoginMain(contract.LoginForm{}, errs, c.CSRF),
+				html.LoginMain(c.Locale, contract.LoginForm{}, errs, h.mailEnabled, c.CSRF),
 			)
 		}
 
@@ -62,7 +67,7 @@ func (h *AuthenticationHandler) Login(c *server.Context) error {
 				errs.Set("password", "Invalid username or password")
 
 				return server.RenderPage(c, h.sm,
-					html.LoginMain(contract.LoginForm{}, errs, c.CSRF),
+					html.LoginMain(c.Locale, contract.LoginForm{}, errs, h.mailEnabled, c.CSRF),
 				)
 			}
 			return err
@@ -77,7 +82,7 @@ func (h *AuthenticationHandler) Login(c *server.Context) error {
 				errs.Set("password", "Invalid username or password")
 
 				return server.RenderPage(c, h.sm,
-					html.LoginMain(contract.LoginForm{}, errs, c.CSRF),
+					html.LoginMain(c.Locale, contract.LoginForm{}, errs, h.mailEnabled, c.CSRF),
 				)
 			}
 			return err
@@ -98,6 +103,148 @@ func (h *AuthenticationHandler) Login(c *server.Context) error {
 	}
 }
 
+// ForgotPassword handles requesting a password reset link by email.
+// The response does not reveal whether an account with the email exists.
+func (h *AuthenticationHandler) ForgotPassword(c *server.Context) error {
+	if !h.mailEnabled {
+		return calendar.NotFound.New("Not found")
+	}
+
+	if c.User != nil {
+		return c.Redirect(http.StatusSeeOther, "/")
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		return server.RenderPage(c, h.sm,
+			html.ForgotPasswordMain(c.Locale, contract.ForgotPasswordForm{}, nil, false, c.CSRF),
+		)
+
+	case http.MethodPost:
+		form := contract.ForgotPasswordForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.ForgotPasswordMain(c.Locale, form, errs, false, c.CSRF),
+			)
+		}
+
+		user, err := model.GetUserByEmail(c.Request().Context(), h.db, form.Email)
+		if err != nil && !errors.Is(err, calendar.NotFound) {
+			return err
+		}
+
+		if user != nil {
+			token := uuid.New()
+
+			if err := h.db.RunInTx(c.Request().Context(), nil, func(ctx context.Context, db bun.Tx) error {
+				if err := model.InsertPasswordReset(ctx, db, &domain.PasswordReset{
+					Token:      token,
+					UserID:     user.ID,
+					ValidUntil: time.Now().Add(time.Hour),
+				}); err != nil {
+					return err
+				}
+
+				return queueEmail(ctx, db, user.Email, html.PasswordResetEmail(
+					c.Locale,
+					c.Settings.Title,
+					user.Username,
+					baseURLPath(h.baseURL, fmt.Sprintf("/reset-password/%s", token)),
+				))
+			}); err != nil {
+				return err
+			}
+		}
+
+		return server.RenderPage(c, h.sm,
+			html.ForgotPasswordMain(c.Locale, form, nil, true, c.CSRF),
+		)
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// ResetPassword handles setting a new password with a password reset link.
+func (h *AuthenticationHandler) ResetPassword(c *server.Context) error {
+	if c.User != nil {
+		return c.Redirect(http.StatusSeeOther, "/")
+	}
+
+	req := contract.ResetPasswordRequest{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	reset, err := model.GetPasswordReset(c.Request().Context(), h.db, req.Token)
+	if err != nil {
+		return err
+	}
+
+	if !reset.IsValid() {
+		return calendar.NotFound.New("Not found")
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		return server.RenderPage(c, h.sm,
+			html.ResetPasswordMain(c.Locale, contract.ResetPasswordForm{}, nil, c.CSRF),
+		)
+
+	case http.MethodPost:
+		form := contract.ResetPasswordForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.ResetPasswordMain(c.Locale, form, errs, c.CSRF),
+			)
+		}
+
+		user, err := model.GetUser(c.Request().Context(), h.db, reset.UserID)
+		if err != nil {
+			return err
+		}
+
+		if err := user.SetPassword(form.Password1); err != nil {
+			if errors.Is(err, calendar.InvalidValue) {
+				errs := url.Values{}
+				errs.Set("password1", err.Error())
+				errs.Set("password2", err.Error())
+
+				return server.RenderPage(c, h.sm,
+					html.ResetPasswordMain(c.Locale, form, errs, c.CSRF),
+				)
+			}
+
+			return err
+		}
+
+		if err := h.db.RunInTx(c.Request().Context(), nil, func(ctx context.Context, db bun.Tx) error {
+			if err := model.DeletePasswordResets(ctx, db, user.ID); err != nil {
+				return err
+			}
+
+			return model.UpdateUser(ctx, db, user)
+		}); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Password changed, you can now log in")
+
+		return c.Redirect(http.StatusSeeOther, "/login")
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
 // Logout handles logout page.
 func (h *AuthenticationHandler) Logout(c *server.Context) error {
 	if err := h.sm.Destroy(c.Request().Context()); err != nil {
@@ -112,12 +259,21 @@ func (h *AuthenticationHandler) Register(g *echo.Group) {
 	g.POST("/login", server.Wrap(h.db, h.sm, h.Login))
 
 	g.GET("/logout", server.Wrap(h.db, h.sm, h.Logout))
+
+	g.GET("/forgot-password", server.Wrap(h.db, h.sm, h.ForgotPassword))
+	g.POST("/forgot-password", server.Wrap(h.db, h.sm, h.ForgotPassword))
+
+	g.GET("/reset-password/:token", server.Wrap(h.db, h.sm, h.ResetPassword))
+	g.POST("/reset-password/:token", server.Wrap(h.db, h.sm, h.ResetPassword))
 }
 
 // NewAuthenticationHandler creates a new authentication handler.
-func NewAuthenticationHandler(db *bun.DB, sm *scs.SessionManager) *AuthenticationHandler {
+// Password resets are available when mail is enabled. Reset links point to baseURL.
+func NewAuthenticationHandler(db *bun.DB, sm *scs.SessionManager, mailEnabled bool, baseURL string) *AuthenticationHandler {
 	return &AuthenticationHandler{
-		db: db,
-		sm: sm,
+		db:          db,
+		sm:          sm,
+		mailEnabled: mailEnabled,
+		baseURL:     baseURL,
 	}
 }
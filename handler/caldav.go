@@ -0,0 +1,949 @@
+package handler
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/blobstore"
+	"github.com/mgnsk/calendar/pkg/caldav"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/timestamp"
+	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// CalDAV methods.
+const (
+	methodPropfind = "PROPFIND"
+	methodReport   = "REPORT"
+)
+
+const (
+	calendarObjectContentType = "text/calendar; charset=utf-8; component=vevent"
+	syncTokenPrefix           = "urn:x-calendar:sync:"
+)
+
+// CalDAVHandler serves the events of users to calendar clients.
+// Users sign in with app passwords. Each user has a single calendar
+// of the events they can edit: their own events or all events for admins.
+type CalDAVHandler struct {
+	db     *bun.DB
+	finder TimezoneFinder
+	store  *blobstore.Store
+}
+
+// WellKnown redirects calendar clients to the CalDAV root.
+func (h *CalDAVHandler) WellKnown(c *server.Context) error {
+	return c.Redirect(http.StatusMovedPermanently, "/caldav/")
+}
+
+// Options advertises the CalDAV capabilities.
+func (h *CalDAVHandler) Options(c *server.Context) error {
+	c.Response().Header().Set("DAV", "1, 3, calendar-access")
+	c.Response().Header().Set(echo.HeaderAllow, "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+
+	return c.NoContent(http.StatusOK)
+}
+
+// Root handles PROPFIND on the CalDAV root. Clients discover the principal of the user here.
+func (h *CalDAVHandler) Root(c *server.Context) error {
+	user, err := h.authenticate(c)
+	if err != nil {
+		return err
+	}
+
+	p, err := caldav.ParsePropfind(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	return writeMultistatus(c, &caldav.Multistatus{
+		Responses: []caldav.Response{
+			caldav.NewResponse("/caldav/", []caldav.Property{
+				caldav.NewParentProperty(caldav.ResourceType, caldav.NewElement(caldav.NamespaceDAV, "collection")),
+				caldav.NewParentProperty(caldav.CurrentUserPrincipal, caldav.Href(principalPath(user))),
+			}, p.Names()),
+		},
+	})
+}
+
+// Principal handles PROPFIND on the principal of the user.
+func (h *CalDAVHandler) Principal(c *server.Context) error {
+	user, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+
+	p, err := caldav.ParsePropfind(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	return writeMultistatus(c, &caldav.Multistatus{
+		Responses: []caldav.Response{
+			caldav.NewResponse(principalPath(user), []caldav.Property{
+				caldav.NewParentProperty(caldav.ResourceType, caldav.NewElement(caldav.NamespaceDAV, "principal")),
+				caldav.NewProperty(caldav.DisplayName, user.Username),
+				caldav.NewParentProperty(caldav.CurrentUserPrincipal, caldav.Href(principalPath(user))),
+				caldav.NewParentProperty(caldav.PrincipalURL, caldav.Href(principalPath(user))),
+				caldav.NewParentProperty(caldav.CalendarHomeSet, caldav.Href(homePath(user))),
+			}, p.Names()),
+		},
+	})
+}
+
+// Home handles PROPFIND on the calendar home of the user.
+func (h *CalDAVHandler) Home(c *server.Context) error {
+	user, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+
+	p, err := caldav.ParsePropfind(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	ms := &caldav.Multistatus{
+		Responses: []caldav.Response{
+			caldav.NewResponse(homePath(user), []caldav.Property{
+				caldav.NewParentProperty(caldav.ResourceType, caldav.NewElement(caldav.NamespaceDAV, "collection")),
+				caldav.NewProperty(caldav.DisplayName, user.Username),
+				caldav.NewParentProperty(caldav.CurrentUserPrincipal, caldav.Href(principalPath(user))),
+				caldav.NewParentProperty(caldav.Owner, caldav.Href(principalPath(user))),
+			}, p.Names()),
+		},
+	}
+
+	if getDepth(c) > 0 {
+		props, err := h.calendarProps(c, user)
+		if err != nil {
+			return err
+		}
+
+		ms.Responses = append(ms.Responses, caldav.NewResponse(calendarPath(user), props, p.Names()))
+	}
+
+	return writeMultistatus(c, ms)
+}
+
+// Calendar handles PROPFIND on the calendar of the user.
+func (h *CalDAVHandler) Calendar(c *server.Context) error {
+	user, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+
+	p, err := caldav.ParsePropfind(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	props, err := h.calendarProps(c, user)
+	if err != nil {
+		return err
+	}
+
+	ms := &caldav.Multistatus{
+		Responses: []caldav.Response{
+			caldav.NewResponse(calendarPath(user), props, p.Names()),
+		},
+	}
+
+	if getDepth(c) > 0 {
+		events, err := h.newEventsQuery(user).List(c.Request().Context(), h.db)
+		if err != nil {
+			return err
+		}
+
+		for _, ev := range events {
+			ms.Responses = append(ms.Responses, newObjectResponse(c, user, ev, p.Names()))
+		}
+	}
+
+	return writeMultistatus(c, ms)
+}
+
+// Report handles calendar-query, calendar-multiget and sync-collection reports on the calendar of the user.
+func (h *CalDAVHandler) Report(c *server.Context) error {
+	user, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+
+	report, err := caldav.ParseReport(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	switch report.XMLName {
+	case caldav.CalendarQuery:
+		return h.calendarQuery(c, user, report)
+	case caldav.CalendarMultiget:
+		return h.calendarMultiget(c, user, report)
+	default:
+		return h.syncCollection(c, user, report)
+	}
+}
+
+// Object handles PROPFIND on an event.
+func (h *CalDAVHandler) Object(c *server.Context) error {
+	user, ev, err := h.getObject(c)
+	if err != nil {
+		return err
+	}
+
+	p, err := caldav.ParsePropfind(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	return writeMultistatus(c, &caldav.Multistatus{
+		Responses: []caldav.Response{
+			newObjectResponse(c, user, ev, p.Names()),
+		},
+	})
+}
+
+// Get handles downloading an event.
+func (h *CalDAVHandler) Get(c *server.Context) error {
+	_, ev, err := h.getObject(c)
+	if err != nil {
+		return err
+	}
+
+	data := serializeEvent(c, ev)
+
+	c.Response().Header().Set(echo.HeaderContentType, calendarObjectContentType)
+	c.Response().Header().Set("ETag", getETag(data))
+
+	return c.String(http.StatusOK, data)
+}
+
+// Put handles creating and updating an event.
+func (h *CalDAVHandler) Put(c *server.Context) error {
+	user, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+
+	uid, err := getObjectUID(c)
+	if err != nil {
+		return err
+	}
+
+	ev, err := model.GetEventByUID(c.Request().Context(), h.db, uid)
+	if err != nil {
+		if !errors.Is(err, calendar.NotFound) {
+			return err
+		}
+	}
+
+	if ev != nil && user.Role != domain.Admin && user.ID != ev.UserID {
+		return calendar.Forbidden.New("Non-admin users can only edit own events")
+	}
+
+	if err := checkPreconditions(c, ev); err != nil {
+		return err
+	}
+
+	vevent, err := parseCalendarObject(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	form, startAt, err := h.getEventForm(c, vevent, ev)
+	if err != nil {
+		return err
+	}
+
+	// Drafts are tentative events.
+	status := vevent.GetProperty(ics.ComponentPropertyStatus)
+	isDraft := status != nil && status.Value == string(ics.ObjectStatusTentative)
+
+	// Publishing a draft of a non-admin user requires approval when moderation is enabled.
+	// Drafts awaiting approval stay in moderation until published.
+	isPending := (!isDraft && c.Settings.Moderation && user.Role != domain.Admin && (ev == nil || ev.IsDraft)) ||
+		(isDraft && ev != nil && ev.IsPending)
+
+	if ev != nil {
+		if form.Location != ev.Location {
+			// The venue no longer matches the location.
+			ev.VenueID = 0
+			ev.OSMType = ""
+			ev.OSMID = 0
+		}
+
+		ev.StartAt = startAt
+		ev.Title = form.Title
+		ev.Description = form.Description
+		ev.URL = form.URL
+		ev.Location = form.Location
+		ev.Latitude = form.Latitude
+		ev.Longitude = form.Longitude
+		ev.Categories = form.Categories
+		ev.IsDraft = isDraft || isPending
+		ev.IsPending = isPending
+
+		if err := model.UpdateEvent(c.Request().Context(), h.db, ev); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	if err := model.InsertEvent(c.Request().Context(), h.db, &domain.Event{
+		ID:          snowflake.Generate(),
+		UID:         uid,
+		StartAt:     startAt,
+		Title:       form.Title,
+		Description: form.Description,
+		URL:         form.URL,
+		Location:    form.Location,
+		Latitude:    form.Latitude,
+		Longitude:   form.Longitude,
+		IsDraft:     isDraft || isPending,
+		IsPending:   isPending,
+		UserID:      user.ID,
+		Categories:  form.Categories,
+	}); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// Delete handles deleting an event.
+func (h *CalDAVHandler) Delete(c *server.Context) error {
+	_, ev, err := h.getObject(c)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPreconditions(c, ev); err != nil {
+		return err
+	}
+
+	if err := model.DeleteEvent(c.Request().Context(), h.db, ev); err != nil {
+		return err
+	}
+
+	if err := deleteUnreferencedFiles(c.Request().Context(), h.db, h.store, attachmentHashes(ev.Attachments)); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Register the handler.
+func (h *CalDAVHandler) Register(g *echo.Group) {
+	g.GET("/.well-known/caldav", server.Wrap(h.db, nil, h.WellKnown))
+	g.Add(methodPropfind, "/.well-known/caldav", server.Wrap(h.db, nil, h.WellKnown))
+
+	for _, p := range []string{
+		"/caldav/",
+		"/caldav/principals/:username/",
+		"/caldav/calendars/:username/",
+		"/caldav/calendars/:username/events/",
+		"/caldav/calendars/:username/events/:name",
+	} {
+		g.OPTIONS(p, server.Wrap(h.db, nil, h.Options))
+	}
+
+	g.Add(methodPropfind, "/caldav/", server.Wrap(h.db, nil, h.Root))
+	g.Add(methodPropfind, "/caldav/principals/:username/", server.Wrap(h.db, nil, h.Principal))
+	g.Add(methodPropfind, "/caldav/calendars/:username/", server.Wrap(h.db, nil, h.Home))
+	g.Add(methodPropfind, "/caldav/calendars/:username/events/", server.Wrap(h.db, nil, h.Calendar))
+	g.Add(methodReport, "/caldav/calendars/:username/events/", server.Wrap(h.db, nil, h.Report))
+
+	g.Add(methodPropfind, "/caldav/calendars/:username/events/:name", server.Wrap(h.db, nil, h.Object))
+	g.GET("/caldav/calendars/:username/events/:name", server.Wrap(h.db, nil, h.Get))
+	g.PUT("/caldav/calendars/:username/events/:name", server.Wrap(h.db, nil, h.Put))
+	g.DELETE("/caldav/calendars/:username/events/:name", server.Wrap(h.db, nil, h.Delete))
+}
+
+// NewCalDAVHandler creates a new CalDAV handler.
+func NewCalDAVHandler(db *bun.DB, finder TimezoneFinder, store *blobstore.Store) *CalDAVHandler {
+	return &CalDAVHandler{
+		db:     db,
+		finder: finder,
+		store:  store,
+	}
+}
+
+// calendarQuery reports the events in the requested time range.
+func (h *CalDAVHandler) calendarQuery(c *server.Context, user *domain.User, report *caldav.Report) error {
+	start, end, err := report.TimeRange()
+	if err != nil {
+		return err
+	}
+
+	query := h.newEventsQuery(user)
+
+	// Events last an hour.
+	if !start.IsZero() {
+		query = query.WithStartAtFrom(start.Add(-time.Hour + time.Second))
+	}
+
+	if !end.IsZero() {
+		query = query.WithStartAtUntil(end.Add(-time.Second))
+	}
+
+	events, err := query.List(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	return writeMultistatus(c, &caldav.Multistatus{
+		Responses: lo.Map(events, func(ev *domain.Event, _ int) caldav.Response {
+			return newObjectResponse(c, user, ev, report.Names())
+		}),
+	})
+}
+
+// calendarMultiget reports the requested events.
+func (h *CalDAVHandler) calendarMultiget(c *server.Context, user *domain.User, report *caldav.Report) error {
+	ms := &caldav.Multistatus{}
+
+	for _, href := range report.Hrefs {
+		ev, err := h.getHref(c, user, href)
+		if err != nil {
+			if !errors.Is(err, calendar.NotFound) {
+				return err
+			}
+
+			ms.Responses = append(ms.Responses, caldav.Response{
+				Href:   href,
+				Status: caldav.Status(http.StatusNotFound),
+			})
+
+			continue
+		}
+
+		ms.Responses = append(ms.Responses, newObjectResponse(c, user, ev, report.Names()))
+	}
+
+	return writeMultistatus(c, ms)
+}
+
+// syncCollection reports the events changed since the sync token.
+// All events are reported when the token is empty.
+func (h *CalDAVHandler) syncCollection(c *server.Context, user *domain.User, report *caldav.Report) error {
+	seq, err := model.GetLastEventChange(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	since, err := parseSyncToken(report.SyncToken, seq)
+	if err != nil {
+		return writeXML(c, http.StatusForbidden, &caldav.Error{
+			Condition: caldav.NewElement(caldav.NamespaceDAV, "valid-sync-token"),
+		})
+	}
+
+	ms := &caldav.Multistatus{
+		SyncToken: formatSyncToken(seq),
+	}
+
+	if since == 0 {
+		events, err := h.newEventsQuery(user).List(c.Request().Context(), h.db)
+		if err != nil {
+			return err
+		}
+
+		for _, ev := range events {
+			ms.Responses = append(ms.Responses, newObjectResponse(c, user, ev, report.Names()))
+		}
+
+		return writeMultistatus(c, ms)
+	}
+
+	var userID snowflake.ID
+	if user.Role != domain.Admin {
+		userID = user.ID
+	}
+
+	changes, err := model.ListEventChanges(c.Request().Context(), h.db, userID, since)
+	if err != nil {
+		return err
+	}
+
+	var (
+		deleted []*domain.EventChange
+		changed = map[snowflake.ID]*domain.EventChange{}
+	)
+
+	for _, change := range changes {
+		if change.Deleted {
+			deleted = append(deleted, change)
+		} else {
+			changed[change.EventID] = change
+		}
+	}
+
+	if len(changed) > 0 {
+		events, err := h.newEventsQuery(user).WithIDs(lo.Keys(changed)...).List(c.Request().Context(), h.db)
+		if err != nil {
+			return err
+		}
+
+		for _, ev := range events {
+			ms.Responses = append(ms.Responses, newObjectResponse(c, user, ev, report.Names()))
+			delete(changed, ev.ID)
+		}
+
+		// Events which are no longer in the calendar, such as
+		// events moved to another owner, are deleted on the client.
+		deleted = append(deleted, lo.Values(changed)...)
+	}
+
+	slices.SortFunc(deleted, func(a, b *domain.EventChange) int {
+		return cmp.Compare(a.Seq, b.Seq)
+	})
+
+	deletedResponses := lo.Map(deleted, func(change *domain.EventChange, _ int) caldav.Response {
+		return caldav.Response{
+			Href:   objectPath(user, change.GetUID()),
+			Status: caldav.Status(http.StatusNotFound),
+		}
+	})
+
+	ms.Responses = append(deletedResponses, ms.Responses...)
+
+	return writeMultistatus(c, ms)
+}
+
+// calendarProps returns the properties of the calendar of a user.
+func (h *CalDAVHandler) calendarProps(c *server.Context, user *domain.User) ([]caldav.Property, error) {
+	seq, err := model.GetLastEventChange(c.Request().Context(), h.db)
+	if err != nil {
+		return nil, err
+	}
+
+	privilege := func(name string) caldav.Property {
+		return caldav.NewElement(caldav.NamespaceDAV, "privilege", caldav.NewElement(caldav.NamespaceDAV, name))
+	}
+
+	report := func(name xml.Name) caldav.Property {
+		return caldav.NewElement(caldav.NamespaceDAV, "supported-report",
+			caldav.NewElement(caldav.NamespaceDAV, "report", caldav.NewParentProperty(name)),
+		)
+	}
+
+	return []caldav.Property{
+		caldav.NewParentProperty(caldav.ResourceType,
+			caldav.NewElement(caldav.NamespaceDAV, "collection"),
+			caldav.NewElement(caldav.NamespaceCalDAV, "calendar"),
+		),
+		caldav.NewProperty(caldav.DisplayName, c.Settings.Title),
+		caldav.NewProperty(caldav.GetCTag, formatSyncToken(seq)),
+		caldav.NewProperty(caldav.SyncToken, formatSyncToken(seq)),
+		caldav.NewParentProperty(caldav.SupportedCalendarComponentSet, caldav.Property{
+			XMLName: xml.Name{Space: caldav.NamespaceCalDAV, Local: "comp"},
+			Attrs:   []xml.Attr{{Name: xml.Name{Local: "name"}, Value: "VEVENT"}},
+		}),
+		caldav.NewParentProperty(caldav.SupportedReportSet,
+			report(caldav.CalendarQuery),
+			report(caldav.CalendarMultiget),
+			report(caldav.SyncCollection),
+		),
+		caldav.NewParentProperty(caldav.CurrentUserPrivilegeSet,
+			privilege("read"),
+			privilege("write"),
+			privilege("write-content"),
+			privilege("bind"),
+			privilege("unbind"),
+		),
+		caldav.NewParentProperty(caldav.CurrentUserPrincipal, caldav.Href(principalPath(user))),
+		caldav.NewParentProperty(caldav.Owner, caldav.Href(principalPath(user))),
+	}, nil
+}
+
+// getEventForm returns the event fields of a calendar object and the start time of the event.
+// The fields are validated like the edit event form.
+func (h *CalDAVHandler) getEventForm(c *server.Context, vevent *ics.VEvent, ev *domain.Event) (*contract.EditEventForm, time.Time, error) {
+	text := func(p ics.ComponentProperty) string {
+		if prop := vevent.GetProperty(p); prop != nil {
+			return strings.TrimSpace(prop.Value)
+		}
+		return ""
+	}
+
+	form := &contract.EditEventForm{
+		Title:       text(ics.ComponentPropertySummary),
+		Description: text(ics.ComponentPropertyDescription),
+		URL:         text(ics.ComponentPropertyUrl),
+		Location:    text(ics.ComponentPropertyLocation),
+	}
+
+	for _, prop := range vevent.GetProperties(ics.ComponentPropertyCategories) {
+		for name := range strings.SplitSeq(prop.Value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				form.Categories = append(form.Categories, name)
+			}
+		}
+	}
+
+	if geo := text(ics.ComponentPropertyGeo); geo != "" {
+		lat, lng, err := parseGeo(geo)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		form.Latitude = lat
+		form.Longitude = lng
+	} else if ev != nil && form.Location == ev.Location {
+		// Keep the coordinates of an unchanged location.
+		form.Latitude = ev.Latitude
+		form.Longitude = ev.Longitude
+	}
+
+	startAt, err := h.parseStartAt(c, vevent, form)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// The start time format is validated with the form.
+	form.StartAt = startAt.Format(contract.FormDateTimeLayout)
+
+	if errs := form.Validate(); len(errs) > 0 {
+		fields := lo.Keys(errs)
+		slices.Sort(fields)
+
+		return nil, time.Time{}, calendar.InvalidValue.New(fmt.Sprintf("Invalid event fields: %s", strings.Join(fields, ", ")))
+	}
+
+	return form, startAt, nil
+}
+
+// parseGeo parses the latitude and longitude of a GEO property.
+func parseGeo(value string) (float64, float64, error) {
+	latStr, lngStr, ok := strings.Cut(value, ";")
+	if !ok {
+		return 0, 0, calendar.InvalidValue.New("Invalid GEO, use the format GEO:lat;lng")
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return 0, 0, calendar.InvalidValue.New("Invalid GEO latitude", err)
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil || math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return 0, 0, calendar.InvalidValue.New("Invalid GEO longitude", err)
+	}
+
+	return lat, lng, nil
+}
+
+// parseStartAt parses the start time of a calendar object.
+// Floating times and dates are in the time zone of the location.
+func (h *CalDAVHandler) parseStartAt(c *server.Context, vevent *ics.VEvent, form *contract.EditEventForm) (time.Time, error) {
+	prop := vevent.GetProperty(ics.ComponentPropertyDtStart)
+	if prop == nil {
+		return time.Time{}, calendar.InvalidValue.New("Missing DTSTART")
+	}
+
+	loc := time.UTC
+
+	if tzid, ok := prop.ICalParameters[string(ics.ParameterTzid)]; ok && len(tzid) > 0 {
+		l, err := timestamp.LoadLocation(tzid[0])
+		if err != nil {
+			return time.Time{}, calendar.InvalidValue.New("Unknown time zone", err)
+		}
+		loc = l
+	} else if name := cmp.Or(h.finder.GetTimezoneName(form.Longitude, form.Latitude), c.Settings.Timezone); name != "" {
+		if l, err := timestamp.LoadLocation(name); err == nil {
+			loc = l
+		}
+	}
+
+	var (
+		startAt time.Time
+		err     error
+	)
+
+	switch value := prop.Value; {
+	case len(value) == len("20060102"):
+		startAt, err = time.ParseInLocation("20060102", value, loc)
+	case strings.HasSuffix(value, "Z"):
+		startAt, err = time.Parse("20060102T150405Z", value)
+		startAt = startAt.In(loc)
+	default:
+		startAt, err = time.ParseInLocation(icalLocalTimeLayout, value, loc)
+	}
+
+	if err != nil {
+		return time.Time{}, calendar.InvalidValue.New("Invalid DTSTART", err)
+	}
+
+	return startAt, nil
+}
+
+// newEventsQuery creates a query of the events in the calendar of a user.
+func (h *CalDAVHandler) newEventsQuery(user *domain.User) model.EventsQueryBuilder {
+	query := model.NewEventsQuery().
+		WithIncludeDrafts().
+		WithOrder(0, model.OrderCreatedAtAsc)
+
+	if user.Role != domain.Admin {
+		query = query.WithUserID(user.ID)
+	}
+
+	return query
+}
+
+// getObject returns the current user and the requested event.
+func (h *CalDAVHandler) getObject(c *server.Context) (*domain.User, *domain.Event, error) {
+	user, err := h.authorize(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uid, err := getObjectUID(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ev, err := model.GetEventByUID(c.Request().Context(), h.db, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user.Role != domain.Admin && user.ID != ev.UserID {
+		return nil, nil, calendar.Forbidden.New("Non-admin users can only edit own events")
+	}
+
+	return user, ev, nil
+}
+
+// getHref returns the event of an href in the calendar of a user.
+func (h *CalDAVHandler) getHref(c *server.Context, user *domain.User, href string) (*domain.Event, error) {
+	if u, err := url.Parse(href); err == nil {
+		href = u.EscapedPath()
+	}
+
+	dir, name := path.Split(href)
+	if dir != calendarPath(user) || !strings.HasSuffix(name, ".ics") {
+		return nil, calendar.NotFound.New("Not found")
+	}
+
+	uid, err := url.PathUnescape(strings.TrimSuffix(name, ".ics"))
+	if err != nil {
+		return nil, calendar.NotFound.New("Not found", err)
+	}
+
+	ev, err := model.GetEventByUID(c.Request().Context(), h.db, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role != domain.Admin && user.ID != ev.UserID {
+		return nil, calendar.NotFound.New("Not found")
+	}
+
+	return ev, nil
+}
+
+// authorize authenticates the user and checks that the requested resource belongs to the user.
+func (h *CalDAVHandler) authorize(c *server.Context) (*domain.User, error) {
+	user, err := h.authenticate(c)
+	if err != nil {
+		return nil, err
+	}
+
+	req := contract.CalDAVRequest{}
+	if err := (&echo.DefaultBinder{}).BindPathParams(c, &req); err != nil {
+		return nil, err
+	}
+
+	if username, err := url.PathUnescape(req.Username); err != nil || username != user.Username {
+		return nil, calendar.Forbidden.New("Users can only access their own calendar")
+	}
+
+	return user, nil
+}
+
+// authenticate authenticates the user with an app password.
+func (h *CalDAVHandler) authenticate(c *server.Context) (*domain.User, error) {
+	username, password, ok := c.Request().BasicAuth()
+	if ok {
+		user, err := model.GetAppPasswordUser(c.Request().Context(), h.db, username, password, time.Now())
+		if err == nil {
+			return user, nil
+		}
+
+		if !errors.Is(err, calendar.NotFound) {
+			return nil, err
+		}
+	}
+
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="CalDAV", charset="UTF-8"`)
+
+	return nil, calendar.Unauthorized.New("Invalid credentials")
+}
+
+// newObjectResponse creates the multi-status response of an event.
+// The calendar data is only included when requested.
+func newObjectResponse(c *server.Context, user *domain.User, ev *domain.Event, names []xml.Name) caldav.Response {
+	data := serializeEvent(c, ev)
+
+	props := []caldav.Property{
+		caldav.NewParentProperty(caldav.ResourceType),
+		caldav.NewProperty(caldav.GetETag, getETag(data)),
+		caldav.NewProperty(caldav.GetContentType, calendarObjectContentType),
+	}
+
+	if slices.Contains(names, caldav.CalendarData) {
+		props = append(props, caldav.NewProperty(caldav.CalendarData, data))
+	}
+
+	return caldav.NewResponse(objectPath(user, ev.GetUID()), props, names)
+}
+
+// serializeEvent returns the calendar object of an event.
+func serializeEvent(c *server.Context, ev *domain.Event) string {
+	cal := ics.NewCalendar()
+	cal.SetProductId(icalProductID)
+
+	addEvents(c, cal, []*domain.Event{ev}, nil)
+
+	return cal.Serialize()
+}
+
+// parseCalendarObject parses the single event of a calendar object.
+func parseCalendarObject(r io.Reader) (*ics.VEvent, error) {
+	cal, err := ics.ParseCalendar(r)
+	if err != nil {
+		return nil, calendar.InvalidValue.New("Invalid calendar data", err)
+	}
+
+	events := cal.Events()
+	if len(events) != 1 {
+		return nil, calendar.InvalidValue.New("Calendar data must contain a single event")
+	}
+
+	if events[0].GetProperty(ics.ComponentPropertyRrule) != nil {
+		return nil, calendar.InvalidValue.New("Recurring events are not supported")
+	}
+
+	return events[0], nil
+}
+
+// checkPreconditions checks the If-Match and If-None-Match headers against the current event.
+func checkPreconditions(c *server.Context, ev *domain.Event) error {
+	var etag string
+	if ev != nil {
+		etag = getETag(serializeEvent(c, ev))
+	}
+
+	if ifMatch := c.Request().Header.Get("If-Match"); ifMatch != "" {
+		if ev == nil || (ifMatch != "*" && ifMatch != etag) {
+			return calendar.PreconditionFailed.New("Event has been changed")
+		}
+	}
+
+	if ifNoneMatch := c.Request().Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if ev != nil && (ifNoneMatch == "*" || ifNoneMatch == etag) {
+			return calendar.PreconditionFailed.New("Event already exists")
+		}
+	}
+
+	return nil
+}
+
+// getObjectUID returns the UID of the requested calendar object resource.
+func getObjectUID(c *server.Context) (string, error) {
+	req := contract.CalDAVRequest{}
+	if err := (&echo.DefaultBinder{}).BindPathParams(c, &req); err != nil {
+		return "", err
+	}
+
+	uid, err := url.PathUnescape(req.Name)
+	if err != nil || !strings.HasSuffix(uid, ".ics") || uid == ".ics" {
+		return "", calendar.NotFound.New("Not found", err)
+	}
+
+	return strings.TrimSuffix(uid, ".ics"), nil
+}
+
+// getDepth returns the Depth header. Infinite depth is treated as 1.
+func getDepth(c *server.Context) int {
+	if c.Request().Header.Get("Depth") == "0" {
+		return 0
+	}
+
+	return 1
+}
+
+// getETag returns the entity tag of calendar data.
+func getETag(data string) string {
+	return fmt.Sprintf(`"%x"`, sha256.Sum256([]byte(data)))
+}
+
+func formatSyncToken(seq int64) string {
+	return syncTokenPrefix + strconv.FormatInt(seq, 10)
+}
+
+// parseSyncToken parses a sync token issued before the current position.
+// An empty token returns zero.
+func parseSyncToken(token string, current int64) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	seq, err := strconv.ParseInt(strings.TrimPrefix(token, syncTokenPrefix), 10, 64)
+	if err != nil || !strings.HasPrefix(token, syncTokenPrefix) || seq < 0 || seq > current {
+		return 0, calendar.InvalidValue.New("Invalid sync token", err)
+	}
+
+	return seq, nil
+}
+
+func principalPath(user *domain.User) string {
+	return "/caldav/principals/" + url.PathEscape(user.Username) + "/"
+}
+
+func homePath(user *domain.User) string {
+	return "/caldav/calendars/" + url.PathEscape(user.Username) + "/"
+}
+
+func calendarPath(user *domain.User) string {
+	return homePath(user) + "events/"
+}
+
+func objectPath(user *domain.User, uid string) string {
+	return calendarPath(user) + url.PathEscape(uid) + ".ics"
+}
+
+func writeMultistatus(c *server.Context, ms *caldav.Multistatus) error {
+	return writeXML(c, http.StatusMultiStatus, ms)
+}
+
+func writeXML(c *server.Context, code int, v any) error {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return c.Blob(code, "application/xml; charset=utf-8", append([]byte(xml.Header), b...))
+}
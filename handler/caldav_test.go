@@ -0,0 +1,310 @@
+package handler_test
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/blobstore"
+	"github.com/mgnsk/calendar/pkg/caldav"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/pkg/timestamp"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fixedTimezoneFinder string
+
+func (f fixedTimezoneFinder) GetTimezoneName(_, _ float64) string {
+	return string(f)
+}
+
+var _ = Describe("CalDAV", func() {
+	const calendarPath = "/caldav/calendars/alice/events/"
+
+	var (
+		ts            *httptest.Server
+		alice, bob    *domain.User
+		alicePassword string
+	)
+
+	type response struct {
+		code   int
+		header http.Header
+		body   string
+	}
+
+	do := func(method, path, password, body string, header http.Header) response {
+		GinkgoHelper()
+
+		req := Must(http.NewRequest(method, ts.URL+path, strings.NewReader(body)))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req.SetBasicAuth("alice", password)
+
+		r := Must(ts.Client().Do(req))
+		defer r.Body.Close()
+
+		return response{
+			code:   r.StatusCode,
+			header: r.Header,
+			body:   string(Must(io.ReadAll(r.Body))),
+		}
+	}
+
+	multistatus := func(r response) *caldav.Multistatus {
+		GinkgoHelper()
+
+		Expect(r.code).To(Equal(http.StatusMultiStatus))
+
+		ms := &caldav.Multistatus{}
+		Expect(xml.Unmarshal([]byte(r.body), ms)).To(Succeed())
+
+		return ms
+	}
+
+	// put puts an event with optional extra VEVENT properties.
+	put := func(name, summary string, header http.Header, props ...string) response {
+		GinkgoHelper()
+
+		lines := []string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//Testing//EN",
+			"BEGIN:VEVENT",
+			"UID:" + strings.TrimSuffix(name, ".ics"),
+			"DTSTAMP:20300101T000000Z",
+			"DTSTART;TZID=Europe/Tallinn:20300102T190000",
+			"SUMMARY:" + summary,
+			"DESCRIPTION:Description",
+			"LOCATION:Venue",
+		}
+		lines = append(lines, props...)
+		lines = append(lines, "END:VEVENT", "END:VCALENDAR", "")
+
+		return do(http.MethodPut, calendarPath+name, alicePassword, strings.Join(lines, "\r\n"), header)
+	}
+
+	report := func(body string) *caldav.Multistatus {
+		GinkgoHelper()
+
+		return multistatus(do("REPORT", calendarPath, alicePassword, body, http.Header{"Depth": {"1"}}))
+	}
+
+	hrefs := func(ms *caldav.Multistatus) []string {
+		var hrefs []string
+		for _, resp := range ms.Responses {
+			hrefs = append(hrefs, resp.Href)
+		}
+
+		return hrefs
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+
+		alice = &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "alice",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		}
+
+		bob = &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "bob",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		}
+
+		Expect(model.InsertUser(ctx, db, alice)).To(Succeed())
+		Expect(model.InsertUser(ctx, db, bob)).To(Succeed())
+
+		var p *domain.AppPassword
+		p, alicePassword = domain.NewAppPassword(alice.ID, "Phone")
+		Expect(model.InsertAppPassword(ctx, db, p)).To(Succeed())
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+
+		h := handler.NewCalDAVHandler(db, fixedTimezoneFinder(""), blobstore.New(GinkgoT().TempDir()))
+		h.Register(e.Group(""))
+	})
+
+	Specify("invalid credentials are rejected", func() {
+		r := do("PROPFIND", "/caldav/", "password", "", nil)
+
+		Expect(r.code).To(Equal(http.StatusUnauthorized))
+		Expect(r.header.Get("WWW-Authenticate")).To(HavePrefix("Basic"))
+	})
+
+	Specify("calendar is discovered", func() {
+		root := multistatus(do("PROPFIND", "/caldav/", alicePassword, "", http.Header{"Depth": {"0"}}))
+		Expect(root.Responses).To(HaveExactElements(
+			HaveField("Propstat", ContainElement(HaveField("Prop.Properties", ContainElement(SatisfyAll(
+				HaveField("XMLName", caldav.CurrentUserPrincipal),
+				HaveField("Children", HaveExactElements(HaveField("Text", "/caldav/principals/alice/"))),
+			))))),
+		))
+
+		home := multistatus(do("PROPFIND", "/caldav/calendars/alice/", alicePassword, "", http.Header{"Depth": {"1"}}))
+		Expect(hrefs(home)).To(HaveExactElements("/caldav/calendars/alice/", calendarPath))
+	})
+
+	Specify("calendar of another user is forbidden", func() {
+		r := do("PROPFIND", "/caldav/calendars/bob/events/", alicePassword, "", http.Header{"Depth": {"1"}})
+		Expect(r.code).To(Equal(http.StatusForbidden))
+	})
+
+	Specify("events of other users are forbidden", func(ctx SpecContext) {
+		ev := *event1
+		ev.UserID = bob.ID
+		Expect(model.InsertEvent(ctx, db, &ev)).To(Succeed())
+
+		name := ev.ID.String() + ".ics"
+
+		Expect(do(http.MethodGet, calendarPath+name, alicePassword, "", nil).code).To(Equal(http.StatusForbidden))
+		Expect(put(name, "Changed", nil).code).To(Equal(http.StatusForbidden))
+		Expect(do(http.MethodDelete, calendarPath+name, alicePassword, "", nil).code).To(Equal(http.StatusForbidden))
+
+		Expect(Must(model.GetEvent(ctx, db, ev.ID))).To(HaveField("Title", "Event 1"))
+	})
+
+	Specify("events are created, updated and deleted", func(ctx SpecContext) {
+		Expect(put("new.ics", "New event", http.Header{"If-None-Match": {"*"}}).code).To(Equal(http.StatusCreated))
+		Expect(put("new.ics", "New event", http.Header{"If-None-Match": {"*"}}).code).To(Equal(http.StatusPreconditionFailed))
+
+		tallinn := Must(timestamp.LoadLocation("Europe/Tallinn"))
+
+		ev := Must(model.GetEventByUID(ctx, db, "new"))
+		Expect(ev).To(SatisfyAll(
+			HaveField("UserID", alice.ID),
+			HaveField("Title", "New event"),
+			HaveField("StartAt", BeTemporally("==", Must(time.ParseInLocation("2006-01-02 15:04", "2030-01-02 19:00", tallinn)))),
+		))
+
+		r := do(http.MethodGet, calendarPath+"new.ics", alicePassword, "", nil)
+		Expect(r.code).To(Equal(http.StatusOK))
+		Expect(r.body).To(ContainSubstring("SUMMARY:New event"))
+		etag := r.header.Get("ETag")
+		Expect(etag).NotTo(BeEmpty())
+
+		By("updating with a stale ETag")
+		Expect(put("new.ics", "Updated", http.Header{"If-Match": {`"stale"`}}).code).To(Equal(http.StatusPreconditionFailed))
+
+		By("updating with the current ETag")
+		Expect(put("new.ics", "Updated", http.Header{"If-Match": {etag}}).code).To(Equal(http.StatusNoContent))
+		Expect(Must(model.GetEvent(ctx, db, ev.ID))).To(HaveField("Title", "Updated"))
+
+		By("deleting the event")
+		Expect(do(http.MethodDelete, calendarPath+"new.ics", alicePassword, "", nil).code).To(Equal(http.StatusNoContent))
+		Expect(do(http.MethodGet, calendarPath+"new.ics", alicePassword, "", nil).code).To(Equal(http.StatusNotFound))
+	})
+
+	Specify("invalid coordinates are rejected", func(ctx SpecContext) {
+		Expect(put("new.ics", "New event", nil, "GEO:59.437;24.7536").code).To(Equal(http.StatusCreated))
+
+		for _, geo := range []string{"GEO:foo;bar", "GEO:NaN;NaN", "GEO:91;24.7536", "GEO:59.437;Inf", "GEO:59.437"} {
+			Expect(put("new.ics", "Updated", nil, geo).code).To(Equal(http.StatusBadRequest), geo)
+		}
+
+		Expect(Must(model.GetEventByUID(ctx, db, "new"))).To(SatisfyAll(
+			HaveField("Title", "New event"),
+			HaveField("Latitude", 59.437),
+			HaveField("Longitude", 24.7536),
+		))
+	})
+
+	Specify("events are fetched with calendar-multiget", func() {
+		Expect(put("new.ics", "New event", nil).code).To(Equal(http.StatusCreated))
+
+		ms := report(`<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <D:href>` + calendarPath + `new.ics</D:href>
+  <D:href>` + calendarPath + `missing.ics</D:href>
+</C:calendar-multiget>`)
+
+		Expect(ms.Responses).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("Href", calendarPath+"new.ics"),
+				HaveField("Propstat", HaveExactElements(HaveField("Prop.Properties", ContainElement(SatisfyAll(
+					HaveField("XMLName", caldav.CalendarData),
+					HaveField("Text", ContainSubstring("SUMMARY:New event")),
+				))))),
+			),
+			SatisfyAll(
+				HaveField("Href", calendarPath+"missing.ics"),
+				HaveField("Status", caldav.Status(http.StatusNotFound)),
+			),
+		))
+	})
+
+	Specify("changes are synced with sync-collection", func(ctx SpecContext) {
+		const syncReport = `<?xml version="1.0" encoding="utf-8"?>
+<D:sync-collection xmlns:D="DAV:">
+  <D:sync-token>%s</D:sync-token>
+  <D:sync-level>1</D:sync-level>
+  <D:prop><D:getetag/></D:prop>
+</D:sync-collection>`
+
+		Expect(put("first.ics", "First", nil).code).To(Equal(http.StatusCreated))
+
+		initial := report(strings.Replace(syncReport, "%s", "", 1))
+		Expect(hrefs(initial)).To(HaveExactElements(calendarPath + "first.ics"))
+		Expect(initial.SyncToken).NotTo(BeEmpty())
+
+		By("changing events of the user and others")
+		Expect(put("second.ics", "Second", nil).code).To(Equal(http.StatusCreated))
+		Expect(do(http.MethodDelete, calendarPath+"first.ics", alicePassword, "", nil).code).To(Equal(http.StatusNoContent))
+
+		ev := *event1
+		ev.UserID = bob.ID
+		Expect(model.InsertEvent(ctx, db, &ev)).To(Succeed())
+
+		changes := report(strings.Replace(syncReport, "%s", initial.SyncToken, 1))
+		Expect(changes.Responses).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("Href", calendarPath+"first.ics"),
+				HaveField("Status", caldav.Status(http.StatusNotFound)),
+			),
+			HaveField("Href", calendarPath+"second.ics"),
+		))
+		Expect(changes.SyncToken).NotTo(Equal(initial.SyncToken))
+
+		By("syncing with the latest token")
+		Expect(report(strings.Replace(syncReport, "%s", changes.SyncToken, 1)).Responses).To(BeEmpty())
+
+		By("moving an event to another owner")
+		second := Must(model.GetEventByUID(ctx, db, "second"))
+		_ = Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action: model.BulkChangeOwner,
+			UserID: bob.ID,
+		}, second.ID))
+
+		Expect(report(strings.Replace(syncReport, "%s", changes.SyncToken, 1)).Responses).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("Href", calendarPath+"second.ics"),
+				HaveField("Status", caldav.Status(http.StatusNotFound)),
+			),
+		))
+
+		By("syncing with an invalid token")
+		r := do("REPORT", calendarPath, alicePassword, strings.Replace(syncReport, "%s", "invalid", 1), nil)
+		Expect(r.code).To(Equal(http.StatusForbidden))
+		Expect(r.body).To(ContainSubstring("valid-sync-token"))
+	})
+})
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bufio"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// CategoriesHandler handles category pages.
+type CategoriesHandler struct {
+	db *bun.DB
+	sm *scs.SessionManager
+}
+
+// Categories renders the categories form page.
+func (h *CategoriesHandler) Categories(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can edit categories")
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		categories, err := model.ListCategories(c.Request().Context(), h.db, time.Time{})
+		if err != nil {
+			return err
+		}
+
+		names := lo.Map(categories, func(category *domain.Category, _ int) string {
+			return category.Name
+		})
+
+		return server.RenderPage(c, h.sm,
+			html.CategoriesMain(c.Locale, names, c.Settings.TagsPage, nil, c.CSRF),
+		)
+
+	case http.MethodPost:
+		form := contract.EditCategoriesForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		var names []string
+
+		scanner := bufio.NewScanner(strings.NewReader(form.Categories))
+		for scanner.Scan() {
+			names = append(names, scanner.Text())
+		}
+
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.CategoriesMain(c.Locale, names, domain.TagsPageMode(form.TagsPage), errs, c.CSRF),
+			)
+		}
+
+		if err := model.SetCategories(c.Request().Context(), h.db, domain.NewCategoryList(names...)); err != nil {
+			return err
+		}
+
+		c.Settings.TagsPage = domain.TagsPageMode(form.TagsPage)
+
+		if err := model.UpdateSettings(c.Request().Context(), h.db, c.Settings); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Categories saved")
+
+		return c.Redirect(http.StatusSeeOther, "/categories")
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// Register the handler.
+func (h *CategoriesHandler) Register(g *echo.Group) {
+	g.GET("/categories", server.Wrap(h.db, h.sm, h.Categories))
+	g.POST("/categories", server.Wrap(h.db, h.sm, h.Categories))
+}
+
+// NewCategoriesHandler creates a new categories handler.
+func NewCategoriesHandler(db *bun.DB, sm *scs.SessionManager) *CategoriesHandler {
+	return &CategoriesHandler{
+		db: db,
+		sm: sm,
+	}
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/alexedwards/scs/v2"
@@ -12,9 +14,12 @@ import (
 	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/blobstore"
 	"github.com/mgnsk/calendar/pkg/snowflake"
 	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 	hxhttp "maragu.dev/gomponents-htmx/http"
 )
@@ -24,16 +29,23 @@ type TimezoneFinder interface {
 	GetTimezoneName(lng, lat float64) string
 }
 
+// Geocoder finds locations by free-text query.
+type Geocoder interface {
+	Search(ctx context.Context, query, language string) ([]*domain.Place, error)
+}
+
 // EditEventHandler handles adding and editing events.
 type EditEventHandler struct {
-	db     *bun.DB
-	sm     *scs.SessionManager
-	finder TimezoneFinder
+	db       *bun.DB
+	sm       *scs.SessionManager
+	finder   TimezoneFinder
+	geocoder Geocoder
+	store    *blobstore.Store
 }
 
 // Edit handles adding and editing events.
 func (h *EditEventHandler) Edit(c *server.Context) error {
-	if c.User == nil {
+	if !canSubmitEvents(c) {
 		return calendar.Forbidden.New("Must be logged in")
 	}
 
@@ -49,6 +61,10 @@ func (h *EditEventHandler) Edit(c *server.Context) error {
 	var ev *domain.Event
 
 	if req.EventID > 0 {
+		if c.User == nil {
+			return calendar.Forbidden.New("Must be logged in")
+		}
+
 		event, err := model.GetEvent(c.Request().Context(), h.db, req.EventID)
 		if err != nil {
 			return err
@@ -59,93 +75,199 @@ func (h *EditEventHandler) Edit(c *server.Context) error {
 		}
 
 		ev = event
+		req.Attachments = ev.Attachments
+	} else if req.DuplicateOf > 0 {
+		source, err := h.getDuplicateSource(c, req.DuplicateOf)
+		if err != nil {
+			return err
+		}
+
+		// The copy shares the stored files of the source event.
+		req.Attachments = source.Attachments
+
+		if c.Request().Method == http.MethodGet {
+			h.setForm(&req, source)
+
+			// The copy is a new event with its own date.
+			req.StartAt = ""
+			req.PublishAt = ""
+			req.IsDraft = false
+			req.IsPending = false
+		}
 	}
 
 	switch c.Request().Method {
 	case http.MethodGet:
-		if ev != nil {
-			req.Title = ev.Title
-			req.IsDraft = ev.IsDraft
-			req.Description = ev.Description
-			req.URL = ev.URL
-			req.StartAt = ev.StartAt.Format(contract.FormDateTimeLayout)
-			req.Location = ev.Location
-			req.OSMType = ev.OSMType
-			req.OSMID = ev.OSMID
-			req.Latitude = ev.Latitude
-			req.Longitude = ev.Longitude
-			_, offset := ev.StartAt.Zone()
-			req.TimezoneOffset = offset
+		switch {
+		case ev != nil:
+			h.setForm(&req, ev)
+
+		case req.TemplateID > 0 && req.DuplicateOf == 0:
+			t, err := getEventTemplate(c, h.db, req.TemplateID)
+			if err != nil {
+				return err
+			}
+
+			if err := setFormFromTemplate(c, h.db, &req, t); err != nil {
+				return err
+			}
 		}
 
-		return server.RenderPage(c, h.sm,
-			html.EditEventMain(req, nil, c.CSRF),
-		)
+		if req.Language == "" {
+			req.Language = i18n.Get(c.Settings.Locale).Code()
+		}
+
+		return h.render(c, req, nil)
 
 	case http.MethodPost:
-		if errs := req.Validate(); len(errs) > 0 {
-			return server.RenderPage(c, h.sm,
-				html.EditEventMain(req, errs, c.CSRF),
-			)
+		if req.VenueID > 0 {
+			// The venue location takes precedence over the form location.
+			venue, err := model.GetVenue(c.Request().Context(), h.db, req.VenueID)
+			if err != nil {
+				return err
+			}
+
+			req.SetVenue(venue)
 		}
 
-		startAt, err := h.parseStartAt(req)
+		errs := req.Validate()
+
+		files, err := getUploadedFiles(c, "attachments")
+		if err != nil {
+			return err
+		}
+
+		attachments := lo.Filter(req.Attachments, func(a domain.Attachment, _ int) bool {
+			return slices.Contains(req.KeepAttachments, a.Hash)
+		})
+
+		if len(attachments)+len(files) > contract.MaxAttachments {
+			errs.Set("attachments", "Too many attachments")
+		}
+
+		if len(errs) > 0 {
+			return h.render(c, req, errs)
+		}
+
+		if req.Latitude == 0 && req.Longitude == 0 {
+			// Location was typed without picking a search result.
+			if place := geocodeFirst(c, h.db, h.geocoder, req.Location); place != nil {
+				req.OSMType = place.OSMType
+				req.OSMID = place.OSMID
+				req.Latitude = place.Latitude
+				req.Longitude = place.Longitude
+			}
+		}
+
+		startAt, err := h.parseStartAt(req, c.Settings)
 		if err != nil {
 			errs := url.Values{}
 			errs.Set("start_at", "Invalid start_at value")
-			return server.RenderPage(c, h.sm,
-				html.EditEventMain(req, errs, c.CSRF),
-			)
+			return h.render(c, req, errs)
+		}
+
+		var publishAt time.Time
+		if req.IsDraft && req.PublishAt != "" {
+			// The format is validated. Publish time is in the event time zone.
+			publishAt, _ = time.ParseInLocation(contract.FormDateTimeLayout, req.PublishAt, startAt.Location())
 		}
 
+		if ev == nil && req.TemplateID > 0 {
+			// Fill in the date of titles from templates.
+			req.Title = domain.FormatTemplateTitle(req.Title, startAt.Format(time.DateOnly))
+		}
+
+		uploaded, err := saveAttachments(h.store, files, errs)
+		if err != nil {
+			return err
+		}
+
+		if len(errs) > 0 {
+			return h.render(c, req, errs)
+		}
+
+		attachments = append(attachments, uploaded...)
+
+		// Publishing or scheduling a draft of a non-admin user requires approval when moderation is enabled.
+		isPending := (!req.IsDraft || !publishAt.IsZero()) && c.Settings.Moderation &&
+			(c.User == nil || c.User.Role != domain.Admin) &&
+			(ev == nil || ev.IsDraft)
+
 		if ev != nil {
+			removed := lo.Without(attachmentHashes(ev.Attachments), attachmentHashes(attachments)...)
+
 			ev.StartAt = startAt
+			ev.TimezoneOverride = req.Timezone != ""
 			ev.Title = req.Title
-			ev.IsDraft = req.IsDraft
+			ev.IsDraft = req.IsDraft || isPending
+			ev.IsPending = isPending
+			ev.PublishAt = publishAt
+			ev.Capacity = req.Capacity
 			ev.Description = req.Description
 			ev.URL = req.URL
+			ev.VenueID = req.VenueID
 			ev.Location = req.Location
 			ev.OSMType = req.OSMType
 			ev.OSMID = req.OSMID
 			ev.Latitude = req.Latitude
 			ev.Longitude = req.Longitude
+			ev.Categories = req.Categories
+			ev.Language = req.Language
+			ev.Translations = req.GetTranslations()
+			ev.Attachments = attachments
 
 			if err := model.UpdateEvent(c.Request().Context(), h.db, ev); err != nil {
 				return err
 			}
 
-			if req.IsDraft {
-				h.sm.Put(c.Request().Context(), "flash-success", "Draft saved")
-			} else {
-				h.sm.Put(c.Request().Context(), "flash-success", "Event published")
+			if err := deleteUnreferencedFiles(c.Request().Context(), h.db, h.store, removed); err != nil {
+				return err
 			}
 
+			h.sm.Put(c.Request().Context(), "flash-success", savedMessage(req.IsDraft, isPending, !publishAt.IsZero()))
+
 			return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/edit/%d", ev.ID))
 		}
 
+		var userID snowflake.ID
+		if c.User != nil {
+			userID = c.User.ID
+		}
+
 		eventID := snowflake.Generate()
 
 		if err := model.InsertEvent(c.Request().Context(), h.db, &domain.Event{
-			ID:          eventID,
-			StartAt:     startAt,
-			Title:       req.Title,
-			Description: req.Description,
-			URL:         req.URL,
-			Location:    req.Location,
-			OSMType:     req.OSMType,
-			OSMID:       req.OSMID,
-			Latitude:    req.Latitude,
-			Longitude:   req.Longitude,
-			IsDraft:     req.IsDraft,
-			UserID:      c.User.ID,
+			ID:           eventID,
+			StartAt:      startAt,
+			Title:        req.Title,
+			Description:  req.Description,
+			URL:          req.URL,
+			Location:     req.Location,
+			OSMType:      req.OSMType,
+			OSMID:        req.OSMID,
+			Latitude:     req.Latitude,
+			Longitude:    req.Longitude,
+			VenueID:      req.VenueID,
+			IsDraft:      req.IsDraft || isPending,
+			IsPending:    isPending,
+			PublishAt:    publishAt,
+			Capacity:     req.Capacity,
+			UserID:       userID,
+			Categories:   req.Categories,
+			Language:     req.Language,
+			Translations: req.GetTranslations(),
+			Attachments:  attachments,
+
+			TimezoneOverride: req.Timezone != "",
 		}); err != nil {
 			return err
 		}
 
-		if req.IsDraft {
-			h.sm.Put(c.Request().Context(), "flash-success", "Draft saved")
-		} else {
-			h.sm.Put(c.Request().Context(), "flash-success", "Event published")
+		h.sm.Put(c.Request().Context(), "flash-success", savedMessage(req.IsDraft, isPending, !publishAt.IsZero()))
+
+		if c.User == nil {
+			// Anonymous submitters can't edit the event afterwards.
+			return c.Redirect(http.StatusSeeOther, "/")
 		}
 
 		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/edit/%d", eventID))
@@ -155,6 +277,81 @@ func (h *EditEventHandler) Edit(c *server.Context) error {
 	}
 }
 
+// render renders the edit form page.
+func (h *EditEventHandler) render(c *server.Context, req contract.EditEventForm, errs url.Values) error {
+	categories, err := model.ListCategories(c.Request().Context(), h.db, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	venues, err := model.ListVenues(c.Request().Context(), h.db, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	var templates []*domain.EventTemplate
+
+	if c.User != nil && req.EventID == 0 && req.DuplicateOf == 0 {
+		templates, err = model.ListEventTemplates(c.Request().Context(), h.db, c.User.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.EditEventMain(c.Locale, c.User, req, categories, venues, templates, errs, c.CSRF),
+	)
+}
+
+// setForm sets the event fields on the form.
+func (h *EditEventHandler) setForm(req *contract.EditEventForm, ev *domain.Event) {
+	req.Title = ev.Title
+	req.IsDraft = ev.IsDraft
+	req.IsPending = ev.IsPending
+	req.Description = ev.Description
+	req.URL = ev.URL
+	req.StartAt = ev.StartAt.Format(contract.FormDateTimeLayout)
+	if !ev.PublishAt.IsZero() {
+		req.PublishAt = ev.PublishAt.In(ev.StartAt.Location()).Format(contract.FormDateTimeLayout)
+	}
+	req.Capacity = ev.Capacity
+	req.VenueID = ev.VenueID
+	req.Location = ev.Location
+	req.OSMType = ev.OSMType
+	req.OSMID = ev.OSMID
+	req.Latitude = ev.Latitude
+	req.Longitude = ev.Longitude
+	req.Categories = ev.Categories
+	if ev.TimezoneOverride {
+		// Zones resolved from the location or fallbacks are resolved again on save.
+		req.Timezone = ev.GetTimezoneName()
+	}
+	req.Language = ev.Language
+	req.SetTranslations(ev.Translations)
+	req.KeepAttachments = lo.Map(ev.Attachments, func(a domain.Attachment, _ int) string {
+		return a.Hash
+	})
+}
+
+// getDuplicateSource returns the event to copy into a new event.
+// Drafts can only be copied by admins and their authors.
+func (h *EditEventHandler) getDuplicateSource(c *server.Context, id snowflake.ID) (*domain.Event, error) {
+	if c.User == nil {
+		return nil, calendar.Forbidden.New("Must be logged in")
+	}
+
+	ev, err := model.GetEvent(c.Request().Context(), h.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ev.IsDraft && c.User.Role != domain.Admin && c.User.ID != ev.UserID {
+		return nil, calendar.NotFound.New("Not found")
+	}
+
+	return ev, nil
+}
+
 // Delete handles deleting events.
 func (h *EditEventHandler) Delete(c *server.Context) error {
 	if c.User == nil {
@@ -180,6 +377,10 @@ func (h *EditEventHandler) Delete(c *server.Context) error {
 			return err
 		}
 
+		if err := deleteUnreferencedFiles(c.Request().Context(), h.db, h.store, attachmentHashes(ev.Attachments)); err != nil {
+			return err
+		}
+
 		h.sm.Put(c.Request().Context(), "flash-success", "Event deleted")
 
 		hxhttp.SetRefresh(c.Response().Header())
@@ -192,7 +393,7 @@ func (h *EditEventHandler) Delete(c *server.Context) error {
 
 // Preview returns a preview of the event.
 func (h *EditEventHandler) Preview(c *server.Context) error {
-	if c.User == nil {
+	if !canSubmitEvents(c) {
 		return calendar.Forbidden.New("Must be logged in")
 	}
 
@@ -205,27 +406,31 @@ func (h *EditEventHandler) Preview(c *server.Context) error {
 		return err
 	}
 
-	startAt, _ := h.parseStartAt(req)
+	startAt, _ := h.parseStartAt(req, c.Settings)
 
 	ev := &domain.Event{
-		StartAt:     startAt,
-		Title:       req.Title,
-		Description: req.Description,
-		URL:         req.URL,
-		Location:    req.Location,
-		OSMType:     req.OSMType,
-		OSMID:       req.OSMID,
-		Latitude:    req.Latitude,
-		Longitude:   req.Longitude,
-		IsDraft:     req.IsDraft,
+		StartAt:      startAt,
+		Title:        req.Title,
+		Description:  req.Description,
+		URL:          req.URL,
+		Location:     req.Location,
+		OSMType:      req.OSMType,
+		OSMID:        req.OSMID,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		IsDraft:      req.IsDraft,
+		Categories:   req.Categories,
+		Language:     req.Language,
+		Translations: req.GetTranslations(),
 	}
 
 	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
 	c.Response().WriteHeader(200)
-	return html.EventCard(nil, ev, c.CSRF).Render(c.Response())
+	return html.EventCard(c.Locale, nil, c.Timezone, ev, c.CSRF).Render(c.Response())
 }
 
-// Register the handler.
+// Register the handler. The group must allow request bodies up to
+// contract.AttachmentBodyLimit with server.BodyLimit.
 func (h *EditEventHandler) Register(g *echo.Group) {
 	g.GET("/edit/:event_id", server.Wrap(h.db, h.sm, h.Edit))
 	g.POST("/edit/:event_id", server.Wrap(h.db, h.sm, h.Edit))
@@ -235,18 +440,27 @@ func (h *EditEventHandler) Register(g *echo.Group) {
 	g.POST("/preview", server.Wrap(h.db, h.sm, h.Preview))
 }
 
-func (h *EditEventHandler) parseStartAt(req contract.EditEventForm) (time.Time, error) {
-	ianaTimezone := h.finder.GetTimezoneName(req.Longitude, req.Latitude)
+func (h *EditEventHandler) parseStartAt(req contract.EditEventForm, settings *domain.Settings) (time.Time, error) {
+	ianaTimezone := req.Timezone
+
+	if ianaTimezone == "" {
+		ianaTimezone = h.finder.GetTimezoneName(req.Longitude, req.Latitude)
+	}
 
 	if ianaTimezone == "" {
 		// If timezone not found, fall back to user timezone.
 		ianaTimezone = req.UserTimezone
 	}
 
+	if ianaTimezone == "" {
+		// If user timezone also not found, fall back to the site default.
+		ianaTimezone = settings.Timezone
+	}
+
 	var loc *time.Location
 
 	if ianaTimezone == "" {
-		// if user timezone also not found, fall back to UTC.
+		// If no default is configured, fall back to UTC.
 		loc = time.UTC
 	} else {
 		l, err := time.LoadLocation(ianaTimezone)
@@ -266,11 +480,31 @@ func (h *EditEventHandler) parseStartAt(req contract.EditEventForm) (time.Time,
 	return startAt, nil
 }
 
+// canSubmitEvents reports whether the current user may add events.
+func canSubmitEvents(c *server.Context) bool {
+	return c.User != nil || c.Settings.PublicSubmission
+}
+
+func savedMessage(isDraft, isPending, isScheduled bool) string {
+	switch {
+	case isPending:
+		return "Event submitted for review"
+	case isScheduled:
+		return "Draft saved and scheduled for publishing"
+	case isDraft:
+		return "Draft saved"
+	default:
+		return "Event published"
+	}
+}
+
 // NewEditEventHandler creates a new edit event handler.
-func NewEditEventHandler(db *bun.DB, sm *scs.SessionManager, finder TimezoneFinder) *EditEventHandler {
+func NewEditEventHandler(db *bun.DB, sm *scs.SessionManager, finder TimezoneFinder, geocoder Geocoder, store *blobstore.Store) *EditEventHandler {
 	return &EditEventHandler{
-		db:     db,
-		sm:     sm,
-		finder: finder,
+		db:       db,
+		sm:       sm,
+		finder:   finder,
+		geocoder: geocoder,
+		store:    store,
 	}
 }
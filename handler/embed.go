@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// EmbedHandler handles the event list embedded on partner sites.
+type EmbedHandler struct {
+	db *bun.DB
+	sm *scs.SessionManager
+}
+
+// Embed renders the embedded list of upcoming events.
+// Only the sites in the settings may show the list in a frame.
+func (h *EmbedHandler) Embed(c *server.Context) error {
+	req, events, locale, err := h.getEvents(c)
+	if err != nil {
+		return err
+	}
+
+	server.AllowFraming(c, c.Settings.EmbedOrigins...)
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return html.EmbedPage(locale, c.Settings, events, absoluteURL(c, ""), req.Theme != contract.EmbedThemeNone).Render(c.Response())
+}
+
+// Events renders the embedded list of upcoming events as JSON for custom renderers.
+func (h *EmbedHandler) Events(c *server.Context) error {
+	_, events, _, err := h.getEvents(c)
+	if err != nil {
+		return err
+	}
+
+	list := embedEventList{
+		Title: c.Settings.Title,
+		URL:   absoluteURL(c, "/"),
+		Events: lo.Map(events, func(ev *domain.Event, _ int) embedEvent {
+			e := embedEvent{
+				ID:          ev.ID.String(),
+				Title:       ev.Title,
+				Description: ev.Description,
+				StartAt:     ev.StartAt.Format(time.RFC3339),
+				Timezone:    ev.GetTimezoneName(),
+				Location:    ev.Location,
+				Latitude:    ev.Latitude,
+				Longitude:   ev.Longitude,
+				URL:         ev.URL,
+				EventURL:    absoluteURL(c, fmt.Sprintf("/event/%d", ev.ID)),
+				Categories:  ev.Categories,
+			}
+
+			if poster := ev.GetPoster(); poster != nil {
+				e.Image = absoluteURL(c, poster.GetURL())
+			}
+
+			return e
+		}),
+	}
+
+	// Allow partner sites to render the events themselves.
+	c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, "*")
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return json.NewEncoder(c.Response()).Encode(list)
+}
+
+// Script serves the script which shows the embedded list on partner sites.
+func (h *EmbedHandler) Script(c *server.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=3600")
+
+	return c.Blob(http.StatusOK, "text/javascript; charset=utf-8", []byte(html.EmbedScript))
+}
+
+// Snippet renders the embed snippet generator.
+func (h *EmbedHandler) Snippet(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can embed events")
+	}
+
+	req := contract.EmbedRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return err
+	}
+
+	categories, err := model.ListCategories(c.Request().Context(), h.db, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	venues, err := model.ListVenues(c.Request().Context(), h.db, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.EmbedSnippetMain(c.Locale, c.Settings, categories, venues, req, absoluteURL(c, "")),
+	)
+}
+
+// getEvents lists the upcoming events of an embed request translated to the requested language.
+func (h *EmbedHandler) getEvents(c *server.Context) (*contract.EmbedRequest, []*domain.Event, *i18n.Locale, error) {
+	req := &contract.EmbedRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, req); err != nil {
+		return nil, nil, nil, err
+	}
+
+	locale := c.Locale
+	if i18n.IsSupported(req.Language) {
+		locale = i18n.Get(req.Language)
+	}
+
+	query := model.NewEventsQuery().
+		WithStartAtFrom(time.Now()).
+		WithOrder(0, model.OrderStartAtAsc).
+		WithLimit(req.GetLimit())
+
+	if req.Category != "" {
+		query = query.WithCategory(req.Category)
+	}
+
+	if req.Venue > 0 {
+		query = query.WithVenue(req.Venue)
+	}
+
+	if len(req.Tags) > 0 {
+		query = query.WithTags(model.ParseTagMatch(req.TagMatch), req.Tags...)
+	}
+
+	events, err := query.List(c.Request().Context(), h.db)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for i, ev := range events {
+		events[i] = ev.Translate(locale.Code())
+	}
+
+	return req, events, locale, nil
+}
+
+// Register the handler.
+func (h *EmbedHandler) Register(g *echo.Group) {
+	g.GET("/embed", server.Wrap(h.db, nil, h.Embed))
+	g.GET("/embed.js", server.Wrap(h.db, nil, h.Script))
+	g.GET("/embed/events.json", server.Wrap(h.db, nil, h.Events))
+	g.GET("/embed/snippet", server.Wrap(h.db, h.sm, h.Snippet))
+}
+
+// NewEmbedHandler creates a new embed handler.
+func NewEmbedHandler(db *bun.DB, sm *scs.SessionManager) *EmbedHandler {
+	return &EmbedHandler{
+		db: db,
+		sm: sm,
+	}
+}
+
+type embedEventList struct {
+	Title  string       `json:"title"`
+	URL    string       `json:"url"`
+	Events []embedEvent `json:"events"`
+}
+
+type embedEvent struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	StartAt     string   `json:"start_at"`
+	Timezone    string   `json:"timezone"`
+	Location    string   `json:"location,omitempty"`
+	Latitude    float64  `json:"latitude,omitempty"`
+	Longitude   float64  `json:"longitude,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	EventURL    string   `json:"event_url"`
+	Categories  []string `json:"categories"`
+	Image       string   `json:"image,omitempty"`
+}
@@ -0,0 +1,123 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("embedded event list", func() {
+	var ts *httptest.Server
+
+	get := func(path string) (*http.Response, string) {
+		GinkgoHelper()
+
+		r := Must(ts.Client().Get(ts.URL + path))
+		defer r.Body.Close()
+
+		return r, string(Must(io.ReadAll(r.Body)))
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		settings := domain.NewDefaultSettings()
+		settings.EmbedOrigins = []string{"https://partner.testing"}
+
+		Expect(model.InsertSettings(ctx, db, settings)).To(Succeed())
+		Expect(model.SetCategories(ctx, db, domain.CategoryList{"Music"})).To(Succeed())
+
+		ev := *event1
+		ev.Categories = []string{"Music"}
+
+		Expect(model.InsertEvent(ctx, db, &ev)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, event2)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, event3)).To(Succeed())
+
+		e := server.NewServer()
+
+		h := handler.NewEmbedHandler(db, nil)
+		h.Register(e.Group(""))
+
+		h2 := handler.NewFeedHandler(db)
+		h2.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("list can be framed by the allowed origins only", func() {
+		r, body := get("/embed")
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(r.Header).NotTo(HaveKey(echo.HeaderXFrameOptions))
+		Expect(r.Header.Get(echo.HeaderContentSecurityPolicy)).To(HaveSuffix("; frame-ancestors 'self' https://partner.testing"))
+
+		Expect(body).To(SatisfyAll(
+			ContainSubstring("Event 1"),
+			ContainSubstring("Event 2"),
+			ContainSubstring("Event 3"),
+			ContainSubstring("app.css"),
+		))
+
+		By("asserting other pages can't be framed")
+		r, _ = get("/feed")
+		Expect(r.Header.Get(echo.HeaderXFrameOptions)).To(Equal("SAMEORIGIN"))
+		Expect(r.Header.Get(echo.HeaderContentSecurityPolicy)).NotTo(ContainSubstring("frame-ancestors"))
+	})
+
+	Specify("list is filtered and limited", func() {
+		_, body := get("/embed?category=Music&theme=none")
+		Expect(body).To(SatisfyAll(
+			ContainSubstring("Event 1"),
+			Not(ContainSubstring("Event 2")),
+			Not(ContainSubstring("app.css")),
+		))
+
+		_, body = get("/embed?limit=1")
+		Expect(body).To(SatisfyAll(
+			ContainSubstring("Event 3"),
+			Not(ContainSubstring("Event 2")),
+		))
+	})
+
+	Specify("events are listed as JSON for other sites", func() {
+		r, body := get("/embed/events.json?limit=2&lang=et")
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(r.Header.Get(echo.HeaderAccessControlAllowOrigin)).To(Equal("*"))
+
+		var list struct {
+			Title  string
+			Events []struct {
+				Title    string `json:"title"`
+				EventURL string `json:"event_url"`
+			}
+		}
+		Expect(json.Unmarshal([]byte(body), &list)).To(Succeed())
+
+		Expect(list.Title).To(Equal("My Awesome Events"))
+		Expect(list.Events).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("Title", "Event 3"),
+				HaveField("EventURL", ts.URL+"/event/"+event3.ID.String()),
+			),
+			HaveField("Title", "Event 2"),
+		))
+	})
+
+	Specify("script is served", func() {
+		r, body := get("/embed.js")
+
+		Expect(r.Header.Get(echo.HeaderContentType)).To(HavePrefix("text/javascript"))
+		Expect(body).To(ContainSubstring("data-calendar-embed"))
+	})
+})
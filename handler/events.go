@@ -3,6 +3,7 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
 	"time"
@@ -15,6 +16,8 @@ import (
 	"github.com/mgnsk/calendar/html"
 	"github.com/mgnsk/calendar/model"
 	"github.com/mgnsk/calendar/server"
+	"github.com/mgnsk/wreck"
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 	hxhttp "maragu.dev/gomponents-htmx/http"
 )
@@ -56,24 +59,90 @@ func (h *EventsHandler) MyEvents(c *server.Context) error {
 	)
 }
 
+// Event renders a single event with all its images.
+// Drafts are only shown to admins and their authors.
+func (h *EventsHandler) Event(c *server.Context) error {
+	req := contract.EventRequest{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	ev, err := model.GetEvent(c.Request().Context(), h.db, req.EventID)
+	if err != nil {
+		return err
+	}
+
+	if ev.IsDraft && (c.User == nil || (c.User.Role != domain.Admin && c.User.ID != ev.UserID)) {
+		return calendar.NotFound.New("Not found")
+	}
+
+	return renderEvent(c, h.db, h.sm, ev, contract.RSVPForm{}, nil)
+}
+
+// renderEvent renders the event page with the RSVP form.
+func renderEvent(c *server.Context, db *bun.DB, sm *scs.SessionManager, ev *domain.Event, form contract.RSVPForm, errs url.Values) error {
+	var rsvp *domain.RSVP
+
+	if c.User != nil {
+		r, err := model.GetUserRSVP(c.Request().Context(), db, ev.ID, c.User.ID)
+		if err != nil && !errors.Is(err, calendar.NotFound) {
+			return err
+		}
+		rsvp = r
+	}
+
+	return server.RenderPage(c, sm,
+		html.EventMain(c.Locale, c.User, c.Timezone, ev, rsvp, c.Settings.AnonymousRSVP, form, errs, c.CSRF),
+	)
+}
+
 // Tags handles tags.
 func (h *EventsHandler) Tags(c *server.Context) error {
+	if !c.Settings.TagsPageEnabled {
+		return calendar.NotFound.New("Not found")
+	}
+
 	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
-		tags, err := model.ListTags(c.Request().Context(), h.db, time.Now(), 500)
-		if err != nil {
-			if !errors.Is(err, calendar.NotFound) {
+		var (
+			tags       []*domain.Tag
+			categories []*domain.Category
+		)
+
+		if c.Settings.ShowsWords() {
+			result, err := model.ListTags(c.Request().Context(), h.db, time.Now(), 500)
+			if err != nil {
+				if !errors.Is(err, calendar.NotFound) {
+					return err
+				}
+			}
+
+			slices.SortFunc(result, func(a, b *domain.Tag) int {
+				return strings.Compare(a.Name, b.Name)
+			})
+
+			tags = result
+		}
+
+		if c.Settings.ShowsCategories() {
+			result, err := model.ListCategories(c.Request().Context(), h.db, time.Now())
+			if err != nil {
 				return err
 			}
+
+			categories = lo.Filter(result, func(category *domain.Category, _ int) bool {
+				return category.EventCount > 0
+			})
 		}
 
-		slices.SortFunc(tags, func(a, b *domain.Tag) int {
-			return strings.Compare(a.Name, b.Name)
-		})
+		req := contract.TagsRequest{}
+		if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+			return err
+		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
 		c.Response().WriteHeader(200)
 
-		return html.TagListPartial(tags, c.CSRF).Render(c.Response())
+		return html.TagListPartial(c.Locale, tags, categories, req.Tags, req.TagMatch).Render(c.Response())
 	}
 
 	return server.RenderPage(c, h.sm,
@@ -88,6 +157,10 @@ func (h *EventsHandler) events(c *server.Context, query model.EventsQueryBuilder
 			return err
 		}
 
+		if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+			return err
+		}
+
 		var cursor int64
 
 		switch c.Path() {
@@ -101,26 +174,48 @@ func (h *EventsHandler) events(c *server.Context, query model.EventsQueryBuilder
 			return calendar.NotFound.New("Not found")
 		}
 
+		near, err := contract.ParseNear(req.Near, req.Radius)
+		if err != nil {
+			return renderEventListError(c, err)
+		}
+
+		switch {
+		case req.Sort == contract.SortRelevance && req.Search != "":
+			order = model.OrderRelevance
+			cursor = req.Offset
+
+		case req.Sort == contract.SortDistance && near != nil:
+			order = model.OrderDistance
+			cursor = req.Offset
+		}
+
 		query = query.
 			WithOrder(cursor, order).
 			WithLimit(contract.EventLimitPerPage).
-			WithSearchText(req.Search)
+			WithSearchText(req.Search, c.Location())
 
-		var (
-			events []*domain.Event
-			err    error
-		)
+		if req.Category != "" {
+			query = query.WithCategory(req.Category)
+		}
+
+		if len(req.Tags) > 0 {
+			query = query.WithTags(model.ParseTagMatch(req.TagMatch), req.Tags...)
+		}
+
+		if near != nil {
+			query = query.WithinRadius(near.Latitude, near.Longitude, near.RadiusKM)
+		}
 
-		events, err = query.List(c.Request().Context(), h.db)
+		events, err := query.List(c.Request().Context(), h.db)
 		if err != nil {
 			if !errors.Is(err, calendar.NotFound) {
-				return err
+				return renderEventListError(c, err)
 			}
 		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
 		c.Response().WriteHeader(200)
-		return html.EventListPartial(c.User, cursor, events, c.CSRF).Render(c.Response())
+		return html.EventListPartial(c.Locale, c.User, c.Timezone, cursor, events, c.CSRF).Render(c.Response())
 	}
 
 	return server.RenderPage(c, h.sm,
@@ -128,6 +223,20 @@ func (h *EventsHandler) events(c *server.Context, query model.EventsQueryBuilder
 	)
 }
 
+// renderEventListError renders invalid value errors such as invalid search queries
+// in place of the event list. Other errors are returned.
+func renderEventListError(c *server.Context, err error) error {
+	var werr *wreck.Error
+	if !errors.Is(err, calendar.InvalidValue) || !errors.As(err, &werr) {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(200)
+
+	return html.EventListErrorPartial(c.Locale, werr.Message()).Render(c.Response())
+}
+
 // Register the handler.
 func (h *EventsHandler) Register(g *echo.Group) {
 	g.GET("/", server.Wrap(h.db, h.sm, h.Upcoming))
@@ -141,6 +250,8 @@ func (h *EventsHandler) Register(g *echo.Group) {
 
 	g.GET("/my-events", server.Wrap(h.db, h.sm, h.MyEvents))
 	g.POST("/my-events", server.Wrap(h.db, h.sm, h.MyEvents)) // For htmx.
+
+	g.GET("/event/:event_id", server.Wrap(h.db, h.sm, h.Event))
 }
 
 // NewEventsHandler creates a new events handler.
@@ -1,19 +1,26 @@
 package handler
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	ics "github.com/arran4/golang-ical"
 	"github.com/gorilla/feeds"
 	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/timestamp"
 	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 )
 
@@ -29,19 +36,116 @@ func (h *FeedHandler) HandleRSS(c *server.Context) error {
 
 // HandleICal handles iCal feeds.
 func (h *FeedHandler) HandleICal(c *server.Context) error {
-	events, err := h.getEvents(c)
+	events, _, err := h.getEvents(c)
 	if err != nil {
 		return err
 	}
 
+	cal := newCalendar(c, events, nil)
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="calendar.ics"`)
+
+	c.Response().WriteHeader(http.StatusOK)
+
+	return cal.SerializeTo(c.Response())
+}
+
+// HandlePersonalICal handles the personal calendar feed of a user.
+// It contains the events the user has responded to with their own attendance
+// and the events of the user with all attendees.
+func (h *FeedHandler) HandlePersonalICal(c *server.Context) error {
+	req := contract.PersonalCalendarRequest{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	user, err := model.GetCalendarTokenUser(c.Request().Context(), h.db, req.Token)
+	if err != nil {
+		return err
+	}
+
+	own, err := model.NewEventsQuery().
+		WithUserID(user.ID).
+		WithOrder(0, model.OrderCreatedAtAsc).
+		List(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	attendees := map[snowflake.ID][]*domain.RSVP{}
+
+	ownAttendees, err := model.ListRSVPs(c.Request().Context(), h.db, lo.Map(own, func(ev *domain.Event, _ int) snowflake.ID {
+		return ev.ID
+	})...)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range ownAttendees {
+		attendees[r.EventID] = append(attendees[r.EventID], r)
+	}
+
+	rsvps, err := model.ListUserRSVPs(c.Request().Context(), h.db, user.ID)
+	if err != nil {
+		return err
+	}
+
+	var ids []snowflake.ID
+
+	for _, r := range rsvps {
+		if r.Status == domain.RSVPNotGoing {
+			continue
+		}
+
+		if _, ok := attendees[r.EventID]; !ok {
+			attendees[r.EventID] = []*domain.RSVP{r}
+			ids = append(ids, r.EventID)
+		}
+	}
+
+	events := own
+
+	if len(ids) > 0 {
+		attending, err := model.NewEventsQuery().
+			WithIDs(ids...).
+			WithOrder(0, model.OrderCreatedAtAsc).
+			List(c.Request().Context(), h.db)
+		if err != nil {
+			return err
+		}
+
+		events = append(events, attending...)
+	}
+
+	cal := newCalendar(c, events, attendees)
+	cal.SetName(fmt.Sprintf("%s - %s", c.Settings.Title, user.Username))
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="calendar.ics"`)
+
+	c.Response().WriteHeader(http.StatusOK)
+
+	return cal.SerializeTo(c.Response())
+}
+
+// newCalendar creates a calendar of events with optional attendees by event.
+func newCalendar(c *server.Context, events []*domain.Event, attendees map[snowflake.ID][]*domain.RSVP) *ics.Calendar {
 	cal := ics.NewCalendar()
-	cal.SetProductId("Calendar - github.com/mgnsk/calendar")
+	cal.SetProductId(icalProductID)
 	cal.SetMethod(ics.MethodPublish)
 	cal.SetName(c.Settings.Title)
 	cal.SetDescription(c.Settings.Description)
 
+	addEvents(c, cal, events, attendees)
+
+	return cal
+}
+
+// addEvents adds events with optional attendees by event to a calendar.
+func addEvents(c *server.Context, cal *ics.Calendar, events []*domain.Event, attendees map[snowflake.ID][]*domain.RSVP) {
 	for _, ev := range events {
-		event := cal.AddEvent(ev.ID.String())
+		event := cal.AddEvent(ev.GetUID())
 
 		event.SetLocation(ev.Location)
 		event.SetGeo(ev.Latitude, ev.Longitude)
@@ -50,25 +154,246 @@ func (h *FeedHandler) HandleICal(c *server.Context) error {
 		event.SetModifiedAt(ev.GetCreatedAt())
 		event.SetDtStampTime(ev.GetCreatedAt())
 
-		event.SetStartAt(ev.StartAt)
 		// Default to 1 hour event duration.
-		event.SetEndAt(ev.StartAt.Add(time.Hour))
+		if name := ev.GetTimezoneName(); name != "" && name != "UTC" {
+			event.SetProperty(ics.ComponentPropertyDtStart, ev.StartAt.Format(icalLocalTimeLayout), ics.WithTZID(name))
+			event.SetProperty(ics.ComponentPropertyDtEnd, ev.StartAt.Add(time.Hour).Format(icalLocalTimeLayout), ics.WithTZID(name))
+		} else {
+			event.SetStartAt(ev.StartAt)
+			event.SetEndAt(ev.StartAt.Add(time.Hour))
+		}
 
 		event.SetSummary(ev.Title)
 		event.SetURL(ev.URL)
 		event.SetDescription(ev.Description)
+
+		if ev.IsDraft {
+			event.SetStatus(ics.ObjectStatusTentative)
+		}
+
+		for _, category := range ev.Categories {
+			event.AddCategory(category)
+		}
+
+		for _, a := range ev.Attachments {
+			event.AddAttachmentURL(absoluteURL(c, a.GetURL()), a.ContentType)
+		}
+
+		for _, r := range attendees[ev.ID] {
+			event.AddProperty(ics.ComponentPropertyAttendee, attendeeAddress(c, r),
+				ics.WithCN(r.Name),
+				attendeeStatus(r),
+				ics.WithRSVP(false),
+			)
+		}
 	}
 
-	c.Response().Header().Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
-	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="calendar.ics"`)
+	addTimezones(cal, events)
+}
+
+// attendeeAddress returns the calendar user address of an attendee.
+// Users have no email address so they are identified by a URI on this site.
+func attendeeAddress(c *server.Context, r *domain.RSVP) string {
+	if r.Email != "" {
+		return "mailto:" + r.Email
+	}
+
+	return absoluteURL(c, fmt.Sprintf("/users/%d", r.UserID))
+}
+
+// attendeeStatus returns the participation status of an attendee.
+// Waitlisted attendees are tentative.
+func attendeeStatus(r *domain.RSVP) ics.ParticipationStatus {
+	switch {
+	case r.Waitlisted:
+		return ics.ParticipationStatusTentative
+	case r.Status == domain.RSVPGoing:
+		return ics.ParticipationStatusAccepted
+	case r.Status == domain.RSVPMaybe:
+		return ics.ParticipationStatusTentative
+	default:
+		return ics.ParticipationStatusDeclined
+	}
+}
+
+const (
+	icalProductID       = "Calendar - github.com/mgnsk/calendar"
+	icalLocalTimeLayout = "20060102T150405"
+)
+
+// addTimezones adds VTIMEZONE components for the time zones used by events.
+// Only the transitions spanning the event start and end times are included.
+func addTimezones(cal *ics.Calendar, events []*domain.Event) {
+	type span struct {
+		loc         *time.Location
+		from, until time.Time
+	}
+
+	var names []string
+	spans := map[string]*span{}
+
+	for _, ev := range events {
+		name := ev.GetTimezoneName()
+		if name == "" || name == "UTC" {
+			continue
+		}
+
+		endAt := ev.StartAt.Add(time.Hour)
+
+		if s, ok := spans[name]; ok {
+			if ev.StartAt.Before(s.from) {
+				s.from = ev.StartAt
+			}
+			if endAt.After(s.until) {
+				s.until = endAt
+			}
+			continue
+		}
+
+		names = append(names, name)
+		spans[name] = &span{
+			loc:   ev.StartAt.Location(),
+			from:  ev.StartAt,
+			until: endAt,
+		}
+	}
+
+	for _, name := range names {
+		s := spans[name]
+		tz := cal.AddTimezone(name)
 
+		for _, t := range timestamp.Transitions(s.loc, s.from, s.until) {
+			var c *ics.ComponentBase
+
+			if t.IsDST {
+				daylight := &ics.Daylight{}
+				tz.Components = append(tz.Components, daylight)
+				c = &daylight.ComponentBase
+			} else {
+				c = &tz.AddStandard().ComponentBase
+			}
+
+			if t.At.IsZero() {
+				c.SetProperty(ics.ComponentPropertyDtStart, "19700101T000000")
+			} else {
+				c.SetProperty(ics.ComponentPropertyDtStart, t.At.In(time.FixedZone("", t.OffsetFrom)).Format(icalLocalTimeLayout))
+			}
+
+			c.SetProperty(ics.ComponentProperty(ics.PropertyTzoffsetfrom), formatUTCOffset(t.OffsetFrom))
+			c.SetProperty(ics.ComponentProperty(ics.PropertyTzoffsetto), formatUTCOffset(t.OffsetTo))
+			if t.Name != "" {
+				c.SetProperty(ics.ComponentProperty(ics.PropertyTzname), t.Name)
+			}
+		}
+	}
+}
+
+// formatUTCOffset formats a UTC offset in seconds as an iCal UTC-OFFSET value.
+func formatUTCOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+
+	if secs := offset % 60; secs != 0 {
+		return fmt.Sprintf("%c%02d%02d%02d", sign, offset/3600, offset%3600/60, secs)
+	}
+
+	return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// HandleGeoJSON renders events with coordinates as a GeoJSON feature collection.
+func (h *FeedHandler) HandleGeoJSON(c *server.Context) error {
+	req := contract.MapRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return err
+	}
+
+	near, err := contract.ParseNear(req.Near, req.Radius)
+	if err != nil {
+		return err
+	}
+
+	bbox, err := contract.ParseBBox(req.BBox)
+	if err != nil {
+		return err
+	}
+
+	query := model.NewEventsQuery().
+		WithLimit(contract.MapEventLimit).
+		WithSearchText(req.Search, c.Location())
+
+	if req.Past {
+		query = query.
+			WithStartAtUntil(time.Now()).
+			WithOrder(0, model.OrderStartAtDesc)
+	} else {
+		query = query.
+			WithStartAtFrom(time.Now()).
+			WithOrder(0, model.OrderStartAtAsc)
+	}
+
+	if req.Category != "" {
+		query = query.WithCategory(req.Category)
+	}
+
+	if len(req.Tags) > 0 {
+		query = query.WithTags(model.ParseTagMatch(req.TagMatch), req.Tags...)
+	}
+
+	if near != nil {
+		query = query.WithinRadius(near.Latitude, near.Longitude, near.RadiusKM)
+	}
+
+	if bbox == nil {
+		// Only events with coordinates.
+		bbox = &contract.BBoxFilter{
+			MinLatitude:  -90,
+			MinLongitude: -180,
+			MaxLatitude:  90,
+			MaxLongitude: 180,
+		}
+	}
+
+	query = query.WithinBBox(bbox.MinLatitude, bbox.MinLongitude, bbox.MaxLatitude, bbox.MaxLongitude)
+
+	events, err := query.List(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	collection := featureCollection{
+		Type: "FeatureCollection",
+		Features: lo.Map(events, func(ev *domain.Event, _ int) feature {
+			return feature{
+				Type: "Feature",
+				ID:   ev.ID.String(),
+				Geometry: geometry{
+					Type:        "Point",
+					Coordinates: [2]float64{ev.Longitude, ev.Latitude},
+				},
+				Properties: featureProperties{
+					Title:      ev.Translate(c.Locale.Code()).Title,
+					StartAt:    ev.StartAt.Format(time.RFC3339),
+					Location:   ev.Location,
+					URL:        ev.URL,
+					Categories: ev.Categories,
+				},
+			}
+		}),
+	}
+
+	// Allow other sites to show our events on their maps.
+	c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, "*")
+	c.Response().Header().Set(echo.HeaderContentType, "application/geo+json; charset=utf-8")
 	c.Response().WriteHeader(http.StatusOK)
 
-	return cal.SerializeTo(c.Response())
+	return json.NewEncoder(c.Response()).Encode(collection)
 }
 
 func (h *FeedHandler) handleRSSFeed(c *server.Context, _ string) error {
-	events, err := h.getEvents(c)
+	events, locale, err := h.getEvents(c)
 	if err != nil {
 		return err
 	}
@@ -80,19 +405,29 @@ func (h *FeedHandler) handleRSSFeed(c *server.Context, _ string) error {
 
 	for _, ev := range events {
 		var htmlContent strings.Builder
-		if err := html.EventCard(nil, ev, "").Render(&htmlContent); err != nil {
+		if err := html.EventCard(locale, nil, nil, ev, "").Render(&htmlContent); err != nil {
 			return err
 		}
 
+		var enclosure *feeds.Enclosure
+		if a := getEnclosure(ev); a != nil {
+			enclosure = &feeds.Enclosure{
+				Url:    absoluteURL(c, a.GetURL()),
+				Length: strconv.FormatInt(a.Size, 10),
+				Type:   a.ContentType,
+			}
+		}
+
 		feed.Add(&feeds.Item{
 			Title:       ev.Title,
 			Link:        &feeds.Link{Href: ev.URL},
-			Description: fmt.Sprintf("%s\n\n%s", ev.GetDateString(), ev.Description),
+			Description: fmt.Sprintf("%s\n\n%s", locale.FormatDateTime(ev.StartAt), ev.Description),
 			Content:     htmlContent.String(),
 			Id:          ev.ID.String(),
 			IsPermaLink: "false",
 			Updated:     ev.GetCreatedAt(),
 			Created:     ev.GetCreatedAt(),
+			Enclosure:   enclosure,
 		})
 	}
 
@@ -103,6 +438,7 @@ func (h *FeedHandler) handleRSSFeed(c *server.Context, _ string) error {
 
 	rss := (&feeds.Rss{Feed: feed}).RssFeed()
 	rss.Generator = "Calendar - github.com/mgnsk/calendar"
+	rss.Language = locale.Code()
 	x := rss.FeedXml()
 
 	// write default xml header, without the newline
@@ -116,16 +452,97 @@ func (h *FeedHandler) handleRSSFeed(c *server.Context, _ string) error {
 	return e.Encode(x)
 }
 
-func (h *FeedHandler) getEvents(c *server.Context) ([]*domain.Event, error) {
-	return model.NewEventsQuery().
-		WithOrder(0, model.OrderCreatedAtAsc).
-		List(c.Request().Context(), h.db)
+// getEnclosure returns the poster or the first attachment of the event.
+// RSS items can have only one enclosure.
+func getEnclosure(ev *domain.Event) *domain.Attachment {
+	if poster := ev.GetPoster(); poster != nil {
+		return poster
+	}
+
+	if len(ev.Attachments) > 0 {
+		return &ev.Attachments[0]
+	}
+
+	return nil
+}
+
+// absoluteURL returns the absolute URL of a path on this site.
+// The host comes from the request, so don't use it in links sent elsewhere.
+func absoluteURL(c *server.Context, path string) string {
+	return c.Scheme() + "://" + c.Request().Host + path
+}
+
+// baseURLPath returns the absolute URL of a path on the configured public address of the site.
+func baseURLPath(baseURL, path string) string {
+	return strings.TrimSuffix(baseURL, "/") + path
+}
+
+// publicURL returns the absolute URL of a path on the configured public address
+// of the site or on the request host when the address is not configured.
+func publicURL(c *server.Context, baseURL, path string) string {
+	if baseURL == "" {
+		return absoluteURL(c, path)
+	}
+
+	return baseURLPath(baseURL, path)
+}
+
+// getEvents lists the feed events translated to the feed language.
+func (h *FeedHandler) getEvents(c *server.Context) ([]*domain.Event, *i18n.Locale, error) {
+	req := contract.FeedRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return nil, nil, err
+	}
+
+	// Feeds are shared between readers so they use the site language
+	// unless a language is requested.
+	locale := i18n.Get(c.Settings.Locale)
+	if i18n.IsSupported(req.Language) {
+		locale = i18n.Get(req.Language)
+	}
+
+	query := model.NewEventsQuery().
+		WithOrder(0, model.OrderCreatedAtAsc)
+
+	if req.Category != "" {
+		query = query.WithCategory(req.Category)
+	}
+
+	if req.Venue > 0 {
+		query = query.WithVenue(req.Venue)
+	}
+
+	if len(req.Tags) > 0 {
+		query = query.WithTags(model.ParseTagMatch(req.TagMatch), req.Tags...)
+	}
+
+	near, err := contract.ParseNear(req.Near, req.Radius)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if near != nil {
+		query = query.WithinRadius(near.Latitude, near.Longitude, near.RadiusKM)
+	}
+
+	events, err := query.List(c.Request().Context(), h.db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, ev := range events {
+		events[i] = ev.Translate(locale.Code())
+	}
+
+	return events, locale, nil
 }
 
 // Register the handler.
 func (h *FeedHandler) Register(g *echo.Group) {
 	g.GET("/feed", server.Wrap(h.db, nil, h.HandleRSS))
 	g.GET("/calendar.ics", server.Wrap(h.db, nil, h.HandleICal))
+	g.GET("/rsvps/:token/calendar.ics", server.Wrap(h.db, nil, h.HandlePersonalICal))
+	g.GET("/events.geojson", server.Wrap(h.db, nil, h.HandleGeoJSON))
 }
 
 // NewFeedHandler creates a new feed handler.
@@ -134,3 +551,28 @@ func NewFeedHandler(db *bun.DB) *FeedHandler {
 		db: db,
 	}
 }
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	Geometry   geometry          `json:"geometry"`
+	Properties featureProperties `json:"properties"`
+}
+
+type geometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type featureProperties struct {
+	Title      string   `json:"title"`
+	StartAt    string   `json:"start_at"`
+	Location   string   `json:"location"`
+	URL        string   `json:"url"`
+	Categories []string `json:"categories,omitempty"`
+}
@@ -1,6 +1,7 @@
 package handler_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,8 +11,11 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
 	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
 	"github.com/mmcdole/gofeed"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -59,6 +63,7 @@ var _ = Describe("RSS feed output", func() {
 					"FeedType":    Equal(string(feedType)),
 					"Title":       Equal("My Awesome Events"),
 					"Description": Equal("All the awesome events in one place!"),
+					"Language":    Equal("en"),
 					"Items":       BeEmpty(),
 				}
 
@@ -104,10 +109,11 @@ var _ = Describe("RSS feed output", func() {
 					"FeedType":    Equal(string(feedType)),
 					"Title":       Equal("My Awesome Events"),
 					"Description": Equal("All the awesome events in one place!"),
+					"Language":    Equal("en"),
 					"Items": HaveExactElements(
 						PointTo(MatchFields(IgnoreExtras, Fields{
 							"Title":           Equal(event1.Title),
-							"Description":     Equal(fmt.Sprintf("%s\n\n%s", event1.GetDateString(), event1.Description)),
+							"Description":     Equal(fmt.Sprintf("%s\n\n%s", i18n.English.FormatDateTime(event1.StartAt), event1.Description)),
 							"Content":         Not(BeEmpty()),
 							"PublishedParsed": PointTo(BeTemporally("~", event1.GetCreatedAt(), time.Second)),
 							"GUID":            Equal(event1.ID.String()),
@@ -115,7 +121,7 @@ var _ = Describe("RSS feed output", func() {
 						})),
 						PointTo(MatchFields(IgnoreExtras, Fields{
 							"Title":           Equal(event2.Title),
-							"Description":     Equal(fmt.Sprintf("%s\n\n%s", event2.GetDateString(), event2.Description)),
+							"Description":     Equal(fmt.Sprintf("%s\n\n%s", i18n.English.FormatDateTime(event2.StartAt), event2.Description)),
 							"Content":         Not(BeEmpty()),
 							"PublishedParsed": PointTo(BeTemporally("~", event2.GetCreatedAt(), time.Second)),
 							"GUID":            Equal(event2.ID.String()),
@@ -123,7 +129,7 @@ var _ = Describe("RSS feed output", func() {
 						})),
 						PointTo(MatchFields(IgnoreExtras, Fields{
 							"Title":           Equal(event3.Title),
-							"Description":     Equal(fmt.Sprintf("%s\n\n%s", event3.GetDateString(), event3.Description)),
+							"Description":     Equal(fmt.Sprintf("%s\n\n%s", i18n.English.FormatDateTime(event3.StartAt), event3.Description)),
 							"Content":         Not(BeEmpty()),
 							"PublishedParsed": PointTo(BeTemporally("~", event3.GetCreatedAt(), time.Second)),
 							"GUID":            Equal(event3.ID.String()),
@@ -241,7 +247,7 @@ var _ = Describe("iCal feed output", func() {
 
 			for _, target := range []*domain.Event{event1, event2, event3} {
 				matchers = append(matchers, MakeMatcher(func(ev *ics.VEvent) (bool, error) {
-					Expect(Must(ev.GetLastModifiedAt())).To(BeTemporally("~", time.Now(), time.Second))
+					Expect(Must(ev.GetLastModifiedAt())).To(BeTemporally("~", target.GetCreatedAt(), time.Second))
 					Expect(Must(ev.GetStartAt())).To(BeTemporally("~", target.StartAt, time.Second))
 					Expect(Must(ev.GetEndAt())).To(BeTemporally("~", target.StartAt.Add(time.Hour), time.Second))
 
@@ -268,3 +274,606 @@ var _ = Describe("iCal feed output", func() {
 		})
 	})
 })
+
+var _ = Describe("filtering feeds by category", func() {
+	var (
+		server *httptest.Server
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		By("creating categories", func() {
+			Expect(model.SetCategories(ctx, db, domain.NewCategoryList("Music", "Theatre"))).To(Succeed())
+		})
+
+		By("inserting events", func() {
+			ev1 := *event1
+			ev1.Categories = []string{"Music"}
+			Expect(model.InsertEvent(ctx, db, &ev1)).To(Succeed())
+
+			ev2 := *event2
+			ev2.Categories = []string{"Music", "Theatre"}
+			Expect(model.InsertEvent(ctx, db, &ev2)).To(Succeed())
+
+			Expect(model.InsertEvent(ctx, db, event3)).To(Succeed())
+		})
+
+		e := echo.New()
+		h := handler.NewFeedHandler(db)
+		h.Register(e.Group(""))
+
+		server = httptest.NewServer(e)
+		DeferCleanup(server.Close)
+	})
+
+	Specify("RSS feed contains only events in category", func() {
+		r := Must(server.Client().Get(server.URL + "/feed?category=Theatre"))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		feed := Must(gofeed.NewParser().Parse(r.Body))
+
+		Expect(feed.Items).To(HaveExactElements(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Title": Equal(event2.Title),
+			})),
+		))
+	})
+
+	Specify("iCal feed contains categories", func() {
+		r := Must(server.Client().Get(server.URL + "/calendar.ics?category=Music"))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		cal := Must(ics.ParseCalendar(r.Body))
+
+		Expect(cal.Events()).To(HaveExactElements(
+			MakeMatcher(func(ev *ics.VEvent) (bool, error) {
+				categories := ev.GetProperties(ics.ComponentPropertyCategories)
+				Expect(categories).To(HaveExactElements(
+					HaveField("Value", "Music"),
+				))
+
+				return true, nil
+			}),
+			MakeMatcher(func(ev *ics.VEvent) (bool, error) {
+				categories := ev.GetProperties(ics.ComponentPropertyCategories)
+				Expect(categories).To(HaveExactElements(
+					HaveField("Value", "Music"),
+					HaveField("Value", "Theatre"),
+				))
+
+				return true, nil
+			}),
+		))
+	})
+})
+
+var _ = Describe("filtering feeds by distance", func() {
+	var (
+		ts *httptest.Server
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		By("inserting events", func() {
+			Expect(model.InsertEvent(ctx, db, event1)).To(Succeed())
+
+			// Tartu is about 160 km from the other events.
+			ev2 := *event2
+			ev2.Latitude = 58.3780
+			ev2.Longitude = 26.7290
+			Expect(model.InsertEvent(ctx, db, &ev2)).To(Succeed())
+		})
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+		h := handler.NewFeedHandler(db)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("RSS feed contains only events within radius", func() {
+		r := Must(ts.Client().Get(ts.URL + "/feed?near=59.4370,24.7536&radius=10km"))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		feed := Must(gofeed.NewParser().Parse(r.Body))
+
+		Expect(feed.Items).To(HaveExactElements(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Title": Equal(event1.Title),
+			})),
+		))
+	})
+
+	DescribeTable("invalid near filter is rejected",
+		func(query string) {
+			r := Must(ts.Client().Get(ts.URL + "/feed?" + query))
+			Expect(r.StatusCode).To(Equal(http.StatusBadRequest))
+		},
+		Entry("unknown radius", "near=59.4370,24.7536&radius=far"),
+		Entry("NaN radius", "near=59.4370,24.7536&radius=NaN"),
+		Entry("infinite radius", "near=59.4370,24.7536&radius=Inf"),
+		Entry("NaN coordinates", "near=NaN,NaN"),
+		Entry("infinite coordinates", "near=Inf,-Inf"),
+		Entry("latitude out of range", "near=91,24.7536"),
+	)
+})
+
+var _ = Describe("GeoJSON output", func() {
+	var (
+		ts *httptest.Server
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		By("inserting events", func() {
+			Expect(model.InsertEvent(ctx, db, event1)).To(Succeed())
+
+			// Tartu is about 160 km from the other events.
+			ev2 := *event2
+			ev2.Latitude = 58.3780
+			ev2.Longitude = 26.7290
+			Expect(model.InsertEvent(ctx, db, &ev2)).To(Succeed())
+
+			// Online event without coordinates.
+			ev3 := *event3
+			ev3.Latitude = 0
+			ev3.Longitude = 0
+			Expect(model.InsertEvent(ctx, db, &ev3)).To(Succeed())
+		})
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+		h := handler.NewFeedHandler(db)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	type feature struct {
+		Geometry struct {
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			Title string `json:"title"`
+			URL   string `json:"url"`
+		} `json:"properties"`
+	}
+
+	type featureCollection struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}
+
+	get := func(path string) (*http.Response, featureCollection) {
+		r := Must(ts.Client().Get(ts.URL + path))
+		defer r.Body.Close()
+
+		var fc featureCollection
+		if r.StatusCode == http.StatusOK {
+			Expect(json.NewDecoder(r.Body).Decode(&fc)).To(Succeed())
+		}
+
+		return r, fc
+	}
+
+	Specify("events with coordinates are listed as points", func() {
+		r, fc := get("/events.geojson")
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(r.Header.Get("Content-Type")).To(HavePrefix("application/geo+json"))
+		Expect(r.Header.Get("Access-Control-Allow-Origin")).To(Equal("*"))
+
+		Expect(fc.Type).To(Equal("FeatureCollection"))
+		Expect(fc.Features).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{
+				"Geometry": MatchFields(IgnoreExtras, Fields{
+					"Type":        Equal("Point"),
+					"Coordinates": HaveExactElements(BeNumerically("~", event1.Longitude, 1e-6), BeNumerically("~", event1.Latitude, 1e-6)),
+				}),
+				"Properties": MatchFields(IgnoreExtras, Fields{
+					"Title": Equal(event1.Title),
+					"URL":   Equal(event1.URL),
+				}),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Properties": MatchFields(IgnoreExtras, Fields{
+					"Title": Equal(event2.Title),
+				}),
+			}),
+		))
+	})
+
+	Specify("bounding box restricts events", func() {
+		r, fc := get("/events.geojson?bbox=24.5,59.3,25.0,59.6")
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		Expect(fc.Features).To(HaveExactElements(
+			MatchFields(IgnoreExtras, Fields{
+				"Properties": MatchFields(IgnoreExtras, Fields{
+					"Title": Equal(event1.Title),
+				}),
+			}),
+		))
+	})
+
+	DescribeTable("invalid bounding box is rejected",
+		func(bbox string) {
+			r, _ := get("/events.geojson?bbox=" + bbox)
+			Expect(r.StatusCode).To(Equal(http.StatusBadRequest))
+		},
+		Entry("missing value", "1,2,3"),
+		Entry("NaN values", "NaN,NaN,NaN,NaN"),
+		Entry("infinite values", "-Inf,-Inf,Inf,Inf"),
+		Entry("minimum exceeds maximum", "25.0,59.6,24.5,59.3"),
+		Entry("latitude out of range", "24.5,-91,25.0,59.6"),
+		Entry("longitude out of range", "-181,59.3,25.0,59.6"),
+	)
+})
+
+var _ = Describe("filtering feeds by venue", func() {
+	var (
+		ts    *httptest.Server
+		venue *domain.Venue
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		By("creating a venue", func() {
+			venue = &domain.Venue{
+				ID:        snowflake.Generate(),
+				Name:      "Sveta",
+				Latitude:  59.4400,
+				Longitude: 24.7300,
+			}
+			Expect(model.InsertVenue(ctx, db, venue)).To(Succeed())
+		})
+
+		By("inserting events", func() {
+			ev1 := *event1
+			venue.Apply(&ev1)
+			Expect(model.InsertEvent(ctx, db, &ev1)).To(Succeed())
+
+			Expect(model.InsertEvent(ctx, db, event2)).To(Succeed())
+		})
+
+		e := echo.New()
+		h := handler.NewFeedHandler(db)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("RSS feed contains only events at venue", func() {
+		r := Must(ts.Client().Get(ts.URL + "/feed?venue=" + venue.ID.String()))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		feed := Must(gofeed.NewParser().Parse(r.Body))
+
+		Expect(feed.Items).To(HaveExactElements(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Title": Equal(event1.Title),
+			})),
+		))
+	})
+
+	Specify("iCal feed contains venue location", func() {
+		r := Must(ts.Client().Get(ts.URL + "/calendar.ics?venue=" + venue.ID.String()))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		cal := Must(ics.ParseCalendar(r.Body))
+
+		Expect(cal.Events()).To(HaveExactElements(
+			MakeMatcher(func(ev *ics.VEvent) (bool, error) {
+				return ev.GetProperty(ics.ComponentPropertyLocation).Value == "Sveta", nil
+			}),
+		))
+	})
+})
+
+var _ = Describe("iCal feed time zones", func() {
+	var (
+		ts *httptest.Server
+		ev domain.Event
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		By("inserting an event with a time zone", func() {
+			ev = *event1
+			ev.ID = snowflake.Generate()
+			ev.StartAt = time.Now().Add(24 * time.Hour).In(Must(time.LoadLocation("Europe/Tallinn"))).Truncate(time.Minute)
+			Expect(model.InsertEvent(ctx, db, &ev)).To(Succeed())
+		})
+
+		e := echo.New()
+		h := handler.NewFeedHandler(db)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("event times reference the time zone", func() {
+		r := Must(ts.Client().Get(ts.URL + "/calendar.ics"))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		cal := Must(ics.ParseCalendar(r.Body))
+
+		Expect(cal.Timezones()).To(HaveExactElements(
+			MakeMatcher(func(tz *ics.VTimezone) (bool, error) {
+				return tz.GetProperty(ics.ComponentPropertyTzid).Value == "Europe/Tallinn" && len(tz.Components) > 0, nil
+			}),
+		))
+
+		Expect(cal.Events()).To(HaveExactElements(
+			MakeMatcher(func(event *ics.VEvent) (bool, error) {
+				start := event.GetProperty(ics.ComponentPropertyDtStart)
+				if start.ICalParameters["TZID"][0] != "Europe/Tallinn" {
+					return false, nil
+				}
+
+				startAt, err := event.GetStartAt()
+				if err != nil {
+					return false, err
+				}
+
+				return startAt.Equal(ev.StartAt), nil
+			}),
+		))
+	})
+})
+
+var _ = Describe("translated feeds", func() {
+	var ts *httptest.Server
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		By("inserting a translated event", func() {
+			ev := *event1
+			ev.Language = "en"
+			ev.Translations = []domain.EventTranslation{
+				{Language: "et", Title: "Sündmus", Description: "Kirjeldus"},
+			}
+			Expect(model.InsertEvent(ctx, db, &ev)).To(Succeed())
+		})
+
+		e := echo.New()
+		h := handler.NewFeedHandler(db)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	DescribeTable("RSS feed language",
+		func(query, language, title string) {
+			r := Must(ts.Client().Get(ts.URL + "/feed" + query))
+			Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+			feed := Must(gofeed.NewParser().Parse(r.Body))
+
+			Expect(feed.Language).To(Equal(language))
+			Expect(feed.Items).To(HaveExactElements(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Title": Equal(title),
+				})),
+			))
+		},
+		Entry("site language", "", "en", event1.Title),
+		Entry("requested language", "?lang=et", "et", "Sündmus"),
+		Entry("unsupported language", "?lang=xx", "en", event1.Title),
+	)
+
+	Specify("iCal feed uses requested language", func() {
+		r := Must(ts.Client().Get(ts.URL + "/calendar.ics?lang=et"))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		cal := Must(ics.ParseCalendar(r.Body))
+
+		Expect(cal.Events()).To(HaveExactElements(
+			MakeMatcher(func(ev *ics.VEvent) (bool, error) {
+				return ev.GetProperty(ics.ComponentPropertySummary).Value == "Sündmus", nil
+			}),
+		))
+	})
+})
+
+var _ = Describe("feed attachments", func() {
+	var ts *httptest.Server
+
+	program := domain.Attachment{
+		Hash:        "program",
+		Name:        "program.pdf",
+		ContentType: "application/pdf",
+		Size:        2000,
+	}
+
+	poster := domain.Attachment{
+		Hash:          "poster",
+		ThumbnailHash: "poster-thumb",
+		Name:          "poster.jpg",
+		ContentType:   "image/jpeg",
+		Size:          1000,
+		Width:         800,
+		Height:        600,
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		By("inserting an event with attachments", func() {
+			ev := *event1
+			ev.Attachments = []domain.Attachment{program, poster}
+			Expect(model.InsertEvent(ctx, db, &ev)).To(Succeed())
+		})
+
+		e := echo.New()
+		h := handler.NewFeedHandler(db)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("RSS item encloses the poster", func() {
+		r := Must(ts.Client().Get(ts.URL + "/feed"))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		feed := Must(gofeed.NewParser().Parse(r.Body))
+
+		Expect(feed.Items).To(HaveExactElements(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Enclosures": HaveExactElements(PointTo(MatchAllFields(Fields{
+					"URL":    Equal(ts.URL + "/uploads/poster/poster.jpg"),
+					"Length": Equal("1000"),
+					"Type":   Equal("image/jpeg"),
+				}))),
+			})),
+		))
+	})
+
+	Specify("iCal event has all attachments", func() {
+		r := Must(ts.Client().Get(ts.URL + "/calendar.ics"))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		cal := Must(ics.ParseCalendar(r.Body))
+
+		Expect(cal.Events()).To(HaveExactElements(
+			MakeMatcher(func(ev *ics.VEvent) (bool, error) {
+				attachments := ev.GetProperties(ics.ComponentPropertyAttach)
+				if len(attachments) != 2 {
+					return false, nil
+				}
+
+				return attachments[0].Value == ts.URL+"/uploads/program/program.pdf" &&
+					attachments[0].ICalParameters["FMTTYPE"][0] == "application/pdf" &&
+					attachments[1].Value == ts.URL+"/uploads/poster/poster.jpg", nil
+			}),
+		))
+	})
+})
+
+var _ = Describe("personal iCal feed", func() {
+	var (
+		ts               *httptest.Server
+		user             *domain.User
+		own, attending   domain.Event
+		notGoing, others domain.Event
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		By("creating a user", func() {
+			user = &domain.User{
+				ID:       snowflake.Generate(),
+				Username: "attendee",
+				Password: []byte("password"),
+				Role:     domain.Author,
+			}
+			Expect(model.InsertUser(ctx, db, user)).To(Succeed())
+		})
+
+		By("inserting events", func() {
+			for _, ev := range []*domain.Event{&own, &attending, &notGoing, &others} {
+				*ev = *event1
+				ev.ID = snowflake.Generate()
+				if ev == &own {
+					ev.UserID = user.ID
+				}
+				Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+			}
+		})
+
+		By("responding to events", func() {
+			Expect(model.SetRSVP(ctx, db, &domain.RSVP{
+				EventID: own.ID,
+				Name:    "Visitor",
+				Email:   "visitor@calendar.testing",
+				Status:  domain.RSVPMaybe,
+			})).To(Succeed())
+
+			Expect(model.SetRSVP(ctx, db, &domain.RSVP{
+				EventID: attending.ID,
+				UserID:  user.ID,
+				Name:    user.Username,
+				Status:  domain.RSVPGoing,
+			})).To(Succeed())
+
+			Expect(model.SetRSVP(ctx, db, &domain.RSVP{
+				EventID: notGoing.ID,
+				UserID:  user.ID,
+				Name:    user.Username,
+				Status:  domain.RSVPNotGoing,
+			})).To(Succeed())
+		})
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+		h := handler.NewFeedHandler(db)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("own events and attended events are listed with attendees", func(ctx SpecContext) {
+		token := Must(model.GetCalendarToken(ctx, db, user.ID))
+
+		r := Must(ts.Client().Get(fmt.Sprintf("%s/rsvps/%s/calendar.ics", ts.URL, token)))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		cal := Must(ics.ParseCalendar(r.Body))
+
+		attendee := func(id snowflake.ID, address, cn string, status ics.ParticipationStatus) OmegaMatcher {
+			return MakeMatcher(func(event *ics.VEvent) (bool, error) {
+				attendees := event.Attendees()
+				if event.Id() != id.String() || len(attendees) != 1 {
+					return false, nil
+				}
+
+				a := attendees[0]
+
+				return a.Value == address &&
+					a.ICalParameters["CN"][0] == cn &&
+					a.ParticipationStatus() == status, nil
+			})
+		}
+
+		Expect(cal.Events()).To(ConsistOf(
+			attendee(own.ID, "mailto:visitor@calendar.testing", "Visitor", ics.ParticipationStatusTentative),
+			attendee(attending.ID, fmt.Sprintf("%s/users/%d", ts.URL, user.ID), "attendee", ics.ParticipationStatusAccepted),
+		))
+	})
+
+	Specify("unknown token is not found", func() {
+		r := Must(ts.Client().Get(ts.URL + "/rsvps/00000000-0000-0000-0000-000000000000/calendar.ics"))
+		Expect(r.StatusCode).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// GeocodeHandler handles location search.
+type GeocodeHandler struct {
+	db       *bun.DB
+	sm       *scs.SessionManager
+	geocoder Geocoder
+}
+
+// Search handles location search for the edit forms.
+func (h *GeocodeHandler) Search(c *server.Context) error {
+	if !canSubmitEvents(c) {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	req := contract.GeocodeRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return err
+	}
+
+	req.Query = strings.TrimSpace(req.Query)
+
+	places := []*domain.Place{}
+
+	if utf8.RuneCountInString(req.Query) >= contract.GeocodeMinQueryLength {
+		result, err := geocode(c.Request().Context(), h.db, h.geocoder, req.Query, preferredLanguage(c.Request()))
+		if err != nil {
+			return err
+		}
+
+		places = result
+	}
+
+	return c.JSON(http.StatusOK, lo.Map(places, func(p *domain.Place, _ int) placeResult {
+		return placeResult{
+			Label:     p.Label,
+			OSMType:   p.OSMType,
+			OSMID:     p.OSMID,
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+		}
+	}))
+}
+
+// Register the handler.
+func (h *GeocodeHandler) Register(g *echo.Group) {
+	g.GET("/geocode", server.Wrap(h.db, h.sm, h.Search))
+}
+
+// NewGeocodeHandler creates a new geocode handler.
+func NewGeocodeHandler(db *bun.DB, sm *scs.SessionManager, geocoder Geocoder) *GeocodeHandler {
+	return &GeocodeHandler{
+		db:       db,
+		sm:       sm,
+		geocoder: geocoder,
+	}
+}
+
+type placeResult struct {
+	Label     string  `json:"label"`
+	OSMType   string  `json:"osm_type"`
+	OSMID     uint64  `json:"osm_id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// geocode searches locations, using cached results when available.
+func geocode(ctx context.Context, db *bun.DB, geocoder Geocoder, query, language string) ([]*domain.Place, error) {
+	places, err := model.GetGeocodeResults(ctx, db, query, language)
+	if err == nil {
+		return places, nil
+	}
+
+	if !errors.Is(err, calendar.NotFound) {
+		return nil, err
+	}
+
+	places, err = geocoder.Search(ctx, query, language)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := model.SetGeocodeResults(ctx, db, query, language, places); err != nil {
+		return nil, err
+	}
+
+	return places, nil
+}
+
+// geocodeFirst returns the first search result for a typed location.
+// Errors are logged and nil is returned so that forms can be saved without coordinates.
+func geocodeFirst(c *server.Context, db *bun.DB, geocoder Geocoder, query string) *domain.Place {
+	if utf8.RuneCountInString(strings.TrimSpace(query)) < contract.GeocodeMinQueryLength {
+		return nil
+	}
+
+	places, err := geocode(c.Request().Context(), db, geocoder, query, preferredLanguage(c.Request()))
+	if err != nil {
+		server.Logger(c).Warn("error geocoding location", slog.String("error", err.Error()))
+		return nil
+	}
+
+	if len(places) == 0 {
+		return nil
+	}
+
+	return places[0]
+}
+
+// preferredLanguage returns the primary language subtag of the most preferred
+// Accept-Language entry, so that cached results are shared between regions.
+func preferredLanguage(r *http.Request) string {
+	lang, _, _ := strings.Cut(r.Header.Get("Accept-Language"), ",")
+	lang, _, _ = strings.Cut(lang, ";")
+	lang, _, _ = strings.Cut(lang, "-")
+
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "*" {
+		return ""
+	}
+
+	return lang
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/blobstore"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/server"
+	"github.com/mgnsk/wreck"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+var manageOrders = map[string]model.EventOrder{
+	contract.ManageSortStartAt:       model.OrderStartAtAsc,
+	"-" + contract.ManageSortStartAt: model.OrderStartAtDesc,
+	contract.ManageSortTitle:         model.OrderTitleAsc,
+	"-" + contract.ManageSortTitle:   model.OrderTitleDesc,
+}
+
+// ManageHandler handles the event management table.
+// Admins manage all events and authors their own events.
+type ManageHandler struct {
+	db    *bun.DB
+	sm    *scs.SessionManager
+	store *blobstore.Store
+}
+
+// Manage renders the management table and applies bulk actions.
+func (h *ManageHandler) Manage(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	req := contract.ManageEventsRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return err
+	}
+
+	if _, ok := manageOrders[req.Sort]; !ok {
+		req.Sort = "-" + contract.ManageSortStartAt
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		return h.render(c, req, nil, nil)
+
+	case http.MethodPost:
+		form := contract.BulkEventsForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		if form.Action == contract.BulkChangeOwner && c.User.Role != domain.Admin {
+			return calendar.Forbidden.New("Only admins can change event owners")
+		}
+
+		errs := form.Validate()
+
+		if err := h.validateBulkTarget(c, form, errs); err != nil {
+			return err
+		}
+
+		if len(errs) > 0 {
+			return h.render(c, req, errs, nil)
+		}
+
+		summary, err := h.apply(c, form)
+		if err != nil {
+			return err
+		}
+
+		return h.render(c, req, nil, summary)
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// validateBulkTarget validates the new owner and category of the bulk action.
+func (h *ManageHandler) validateBulkTarget(c *server.Context, form contract.BulkEventsForm, errs url.Values) error {
+	switch form.Action {
+	case contract.BulkChangeOwner:
+		if form.Owner == 0 {
+			return nil
+		}
+
+		if _, err := model.GetUser(c.Request().Context(), h.db, form.Owner); err != nil {
+			if !errors.Is(err, calendar.NotFound) {
+				return err
+			}
+			errs.Set("new_owner", "Unknown user")
+		}
+
+	case contract.BulkAddCategory, contract.BulkRemoveCategory:
+		if form.Category == "" {
+			return nil
+		}
+
+		categories, err := model.ListCategories(c.Request().Context(), h.db, time.Time{})
+		if err != nil {
+			return err
+		}
+
+		if !slices.ContainsFunc(categories, func(category *domain.Category) bool {
+			return strings.EqualFold(category.Name, form.Category)
+		}) {
+			errs.Set("bulk_category", "Unknown category")
+		}
+	}
+
+	return nil
+}
+
+// apply applies the bulk action to the events the user may edit.
+func (h *ManageHandler) apply(c *server.Context, form contract.BulkEventsForm) (*contract.BulkSummary, error) {
+	ids := lo.Uniq(form.EventIDs)
+
+	events, err := model.NewEventsQuery().WithIncludeDrafts().WithIDs(ids...).List(c.Request().Context(), h.db)
+	if err != nil && !errors.Is(err, calendar.NotFound) {
+		return nil, err
+	}
+
+	summary := &contract.BulkSummary{}
+
+	forbidden := lo.FilterMap(events, func(ev *domain.Event, _ int) (snowflake.ID, bool) {
+		return ev.ID, c.User.Role != domain.Admin && c.User.ID != ev.UserID
+	})
+
+	for _, ev := range events {
+		if slices.Contains(forbidden, ev.ID) {
+			summary.Failures = append(summary.Failures, contract.BulkFailure{
+				EventID: ev.ID,
+				Title:   ev.Title,
+				Reason:  "Non-admin users can only edit own events",
+			})
+		}
+	}
+
+	results, err := model.ApplyBulkOperation(c.Request().Context(), h.db, model.BulkOperation{
+		Action: model.BulkAction(form.Action),
+		// Publishing a draft of a non-admin user requires approval when moderation is enabled.
+		RequireReview: c.Settings.Moderation && c.User.Role != domain.Admin,
+		UserID:        form.Owner,
+		Category:      form.Category,
+		Days:          form.Days,
+	}, lo.Without(ids, forbidden...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []domain.Attachment
+
+	for _, result := range results {
+		if result.Err == nil {
+			summary.Succeeded++
+
+			if form.Action == contract.BulkDelete {
+				deleted = append(deleted, result.Event.Attachments...)
+			}
+
+			continue
+		}
+
+		failure := contract.BulkFailure{
+			EventID: result.EventID,
+			Reason:  "Something went wrong",
+		}
+
+		if result.Event != nil {
+			failure.Title = result.Event.Title
+		}
+
+		var werr *wreck.Error
+		if errors.As(result.Err, &werr) && werr.Message() != "" {
+			failure.Reason = werr.Message()
+		} else {
+			server.Logger(c).Error("bulk action failed", "event_id", result.EventID, "error", result.Err)
+		}
+
+		summary.Failures = append(summary.Failures, failure)
+	}
+
+	if err := deleteUnreferencedFiles(c.Request().Context(), h.db, h.store, attachmentHashes(deleted)); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (h *ManageHandler) render(c *server.Context, req contract.ManageEventsRequest, errs url.Values, summary *contract.BulkSummary) error {
+	query := model.NewEventsQuery().
+		WithOrder(req.Offset, manageOrders[req.Sort]).
+		WithLimit(contract.ManageEventsLimit).
+		WithTitleContains(req.Title)
+
+	switch req.Status {
+	case contract.StatusPublished:
+		// Drafts are excluded by default.
+
+	case contract.StatusDraft:
+		query = query.WithDraftsOnly()
+
+	case contract.StatusPending:
+		query = query.WithPending()
+
+	default:
+		query = query.WithIncludeDrafts()
+	}
+
+	if req.Category != "" {
+		query = query.WithCategory(req.Category)
+	}
+
+	var users []*domain.User
+
+	if c.User.Role == domain.Admin {
+		if req.Owner > 0 {
+			query = query.WithUserID(req.Owner)
+		}
+
+		result, err := model.ListUsers(c.Request().Context(), h.db)
+		if err != nil {
+			return err
+		}
+
+		users = result
+	} else {
+		query = query.WithUserID(c.User.ID)
+	}
+
+	events, err := query.List(c.Request().Context(), h.db)
+	if err != nil && !errors.Is(err, calendar.NotFound) {
+		return err
+	}
+
+	categories, err := model.ListCategories(c.Request().Context(), h.db, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.ManageEventsMain(c.Locale, c.User, req, events, categories, users, errs, summary, c.CSRF),
+	)
+}
+
+// Register the handler.
+func (h *ManageHandler) Register(g *echo.Group) {
+	g.GET("/manage", server.Wrap(h.db, h.sm, h.Manage))
+	g.POST("/manage", server.Wrap(h.db, h.sm, h.Manage))
+}
+
+// NewManageHandler creates a new manage handler.
+func NewManageHandler(db *bun.DB, sm *scs.SessionManager, store *blobstore.Store) *ManageHandler {
+	return &ManageHandler{
+		db:    db,
+		sm:    sm,
+		store: store,
+	}
+}
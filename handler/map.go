@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// MapHandler handles the event map.
+type MapHandler struct {
+	db    *bun.DB
+	sm    *scs.SessionManager
+	tiles html.MapTiles
+}
+
+// Map renders the map page.
+func (h *MapHandler) Map(c *server.Context) error {
+	req := contract.MapRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.MapMain(c.Locale, h.tiles, req.Past, c.QueryParams()),
+	)
+}
+
+// Popup renders the compact event cards for a map marker.
+func (h *MapHandler) Popup(c *server.Context) error {
+	req := contract.MapPopupRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return err
+	}
+
+	var events []*domain.Event
+
+	if len(req.IDs) > 0 {
+		ids := lo.Map(req.IDs, func(id int64, _ int) snowflake.ID {
+			return snowflake.ID(id)
+		})
+
+		result, err := model.NewEventsQuery().
+			WithIDs(ids...).
+			WithOrder(0, model.OrderStartAtAsc).
+			WithLimit(contract.EventLimitPerPage).
+			List(c.Request().Context(), h.db)
+		if err != nil {
+			return err
+		}
+
+		events = result
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return html.MapPopupPartial(c.Locale, c.Timezone, events).Render(c.Response())
+}
+
+// Register the handler.
+func (h *MapHandler) Register(g *echo.Group) {
+	g.GET("/map", server.Wrap(h.db, h.sm, h.Map))
+	g.GET("/map/popup", server.Wrap(h.db, h.sm, h.Popup))
+}
+
+// NewMapHandler creates a new map handler.
+func NewMapHandler(db *bun.DB, sm *scs.SessionManager, tiles html.MapTiles) *MapHandler {
+	return &MapHandler{
+		db:    db,
+		sm:    sm,
+		tiles: tiles,
+	}
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+	hxhttp "maragu.dev/gomponents-htmx/http"
+)
+
+// ModerationHandler handles reviewing submitted events.
+type ModerationHandler struct {
+	db          *bun.DB
+	sm          *scs.SessionManager
+	mailEnabled bool
+	baseURL     string
+}
+
+// Moderation renders the events awaiting review.
+func (h *ModerationHandler) Moderation(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can moderate events")
+	}
+
+	events, err := model.NewEventsQuery().
+		WithPending().
+		WithOrder(0, model.OrderCreatedAtAsc).
+		List(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.ModerationMain(c.Locale, c.User, c.Timezone, events, c.CSRF),
+	)
+}
+
+// Approve publishes a pending event.
+func (h *ModerationHandler) Approve(c *server.Context) error {
+	ev, err := h.getPendingEvent(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		// Scheduled events are published by the scheduler when due.
+		ev.IsDraft = ev.PublishAt.After(time.Now())
+		ev.IsPending = false
+
+		if err := model.UpdateEvent(c.Request().Context(), h.db, ev); err != nil {
+			return err
+		}
+
+		if err := h.notifyOwner(c, ev, true); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Event approved")
+
+		hxhttp.SetRefresh(c.Response().Header())
+
+		return nil
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+// Reject rejects a pending event. Anonymous submissions are deleted,
+// events of registered users are returned to their drafts.
+func (h *ModerationHandler) Reject(c *server.Context) error {
+	ev, err := h.getPendingEvent(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		if ev.UserID == 0 {
+			if err := model.DeleteEvent(c.Request().Context(), h.db, ev); err != nil {
+				return err
+			}
+		} else {
+			ev.IsPending = false
+
+			if err := model.UpdateEvent(c.Request().Context(), h.db, ev); err != nil {
+				return err
+			}
+
+			if err := h.notifyOwner(c, ev, false); err != nil {
+				return err
+			}
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Event rejected")
+
+		hxhttp.SetRefresh(c.Response().Header())
+
+		return nil
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+// notifyOwner emails the moderation decision to the event owner
+// when mail is enabled and the owner has an email address.
+func (h *ModerationHandler) notifyOwner(c *server.Context, ev *domain.Event, approved bool) error {
+	if !h.mailEnabled || ev.UserID == 0 {
+		return nil
+	}
+
+	owner, err := model.GetUser(c.Request().Context(), h.db, ev.UserID)
+	if err != nil {
+		if errors.Is(err, calendar.NotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if owner.Email == "" {
+		return nil
+	}
+
+	return queueEmail(c.Request().Context(), h.db, owner.Email, html.ModerationEmail(
+		i18n.Get(c.Settings.Locale),
+		c.Settings.Title,
+		ev,
+		approved,
+		baseURLPath(h.baseURL, fmt.Sprintf("/event/%d", ev.ID)),
+	))
+}
+
+func (h *ModerationHandler) getPendingEvent(c *server.Context) (*domain.Event, error) {
+	if c.User == nil {
+		return nil, calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return nil, calendar.Forbidden.New("Only admins can moderate events")
+	}
+
+	req := contract.ModerateEventRequest{}
+	if err := c.Bind(&req); err != nil {
+		return nil, err
+	}
+
+	ev, err := model.GetEvent(c.Request().Context(), h.db, req.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ev.IsPending {
+		return nil, calendar.PreconditionFailed.New("Event is not awaiting review")
+	}
+
+	return ev, nil
+}
+
+// Register the handler.
+func (h *ModerationHandler) Register(g *echo.Group) {
+	g.GET("/moderation", server.Wrap(h.db, h.sm, h.Moderation))
+
+	g.POST("/moderation/approve/:event_id", server.Wrap(h.db, h.sm, h.Approve))
+	g.POST("/moderation/reject/:event_id", server.Wrap(h.db, h.sm, h.Reject))
+}
+
+// NewModerationHandler creates a new moderation handler.
+// Event owners are notified of decisions by email when mail is enabled.
+// Links in the emails point to baseURL.
+func NewModerationHandler(db *bun.DB, sm *scs.SessionManager, mailEnabled bool, baseURL string) *ModerationHandler {
+	return &ModerationHandler{
+		db:          db,
+		sm:          sm,
+		mailEnabled: mailEnabled,
+		baseURL:     baseURL,
+	}
+}
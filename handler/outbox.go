@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/uptrace/bun"
+)
+
+// Mailer sends email.
+type Mailer interface {
+	Send(ctx context.Context, email *domain.Email) error
+}
+
+// queueEmail renders an email and adds it to the outbox.
+func queueEmail(ctx context.Context, db bun.IDB, to string, email html.Email) error {
+	var b strings.Builder
+
+	if err := email.Body.Render(&b); err != nil {
+		return err
+	}
+
+	return model.InsertEmail(ctx, db, &domain.Email{
+		To:      to,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    b.String(),
+	})
+}
+
+// ProcessOutbox sends the queued emails which are due at now.
+// Failed emails are retried with backoff until they run out of attempts.
+// It returns the number of sent emails.
+func ProcessOutbox(ctx context.Context, db *bun.DB, mailer Mailer, now time.Time) (int, error) {
+	emails, err := model.ListDueEmails(ctx, db, now, 100)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+
+	for _, email := range emails {
+		sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err := mailer.Send(sendCtx, email)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return sent, nil
+			}
+
+			email.SetFailed(err, now)
+
+			slog.Warn("error sending email",
+				slog.String("id", email.ID.String()),
+				slog.Int("attempts", email.Attempts),
+				slog.String("error", err.Error()),
+			)
+
+			if err := model.UpdateEmail(ctx, db, email); err != nil {
+				return sent, err
+			}
+
+			continue
+		}
+
+		if err := model.DeleteEmail(ctx, db, email.ID); err != nil {
+			return sent, err
+		}
+
+		sent++
+	}
+
+	return sent, nil
+}
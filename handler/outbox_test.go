@@ -0,0 +1,198 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/mailer"
+	"github.com/mgnsk/calendar/pkg/mailer/smtptest"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type smtpMailer struct {
+	client *mailer.Client
+}
+
+func (m *smtpMailer) Send(ctx context.Context, email *domain.Email) error {
+	return m.client.Send(ctx, mailer.Message{
+		To:      email.To,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	})
+}
+
+// readTextPart returns the decoded text part of a received message.
+func readTextPart(msg smtptest.Message) string {
+	GinkgoHelper()
+
+	m := Must(mail.ReadMessage(bytes.NewReader(msg.Data)))
+
+	_, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	Expect(err).NotTo(HaveOccurred())
+
+	part := Must(multipart.NewReader(m.Body, params["boundary"]).NextPart())
+
+	return string(Must(io.ReadAll(part)))
+}
+
+var _ = Describe("password reset by email", func() {
+	var (
+		ts     *httptest.Server
+		client *http.Client
+		smtp   *smtptest.Server
+		m      *smtpMailer
+		user   *domain.User
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+
+		user = &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "author",
+			Email:    "author@calendar.testing",
+			Role:     domain.Author,
+		}
+		Expect(user.SetPassword("old password")).To(Succeed())
+		Expect(model.InsertUser(ctx, db, user)).To(Succeed())
+
+		smtp = smtptest.NewServer()
+		DeferCleanup(smtp.Close)
+
+		m = &smtpMailer{
+			client: mailer.NewClient(mailer.Config{
+				Host: smtp.Host,
+				Port: smtp.Port,
+				From: "calendar@calendar.testing",
+			}),
+		}
+
+		sm := scs.New()
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+		h := handler.NewAuthenticationHandler(db, sm, true, "https://calendar.testing/")
+		h.Register(e.Group("", server.NewSessionMiddleware(sm)))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+
+		client = ts.Client()
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	})
+
+	requestReset := func(email string) {
+		GinkgoHelper()
+
+		r := Must(client.PostForm(ts.URL+"/forgot-password", url.Values{"email": {email}}))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(string(Must(io.ReadAll(r.Body)))).To(ContainSubstring("a password reset link was sent"))
+	}
+
+	Specify("reset link is emailed and sets a new password", func(ctx SpecContext) {
+		requestReset("Author@calendar.testing")
+
+		Expect(smtp.Messages()).To(BeEmpty(), "emails are sent in the background")
+		Expect(Must(handler.ProcessOutbox(ctx, db, m, time.Now()))).To(Equal(1))
+
+		messages := smtp.Messages()
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0].To).To(HaveExactElements("author@calendar.testing"))
+
+		link := regexp.MustCompile(`https://calendar\.testing(/reset-password/[0-9a-f-]{36})`).FindStringSubmatch(readTextPart(messages[0]))
+		Expect(link).To(HaveLen(2))
+		match := link[1]
+
+		By("opening the link", func() {
+			r := Must(client.Get(ts.URL + match))
+			defer r.Body.Close()
+
+			Expect(r.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		By("setting a new password", func() {
+			r := Must(client.PostForm(ts.URL+match, url.Values{
+				"password1": {"new password"},
+				"password2": {"new password"},
+			}))
+			defer r.Body.Close()
+
+			Expect(r.StatusCode).To(Equal(http.StatusSeeOther))
+			Expect(r.Header.Get(echo.HeaderLocation)).To(Equal("/login"))
+
+			Expect(Must(model.GetUser(ctx, db, user.ID)).VerifyPassword("new password")).To(Succeed())
+		})
+
+		By("the link is used up", func() {
+			r := Must(client.Get(ts.URL + match))
+			defer r.Body.Close()
+
+			Expect(r.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Specify("reset link does not use the request host", func(ctx SpecContext) {
+		req := Must(http.NewRequest(http.MethodPost, ts.URL+"/forgot-password", strings.NewReader(url.Values{"email": {user.Email}}.Encode())))
+		req.Host = "attacker.testing"
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+		r := Must(client.Do(req))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(Must(handler.ProcessOutbox(ctx, db, m, time.Now()))).To(Equal(1))
+
+		messages := smtp.Messages()
+		Expect(messages).To(HaveLen(1))
+		Expect(readTextPart(messages[0])).To(SatisfyAll(
+			ContainSubstring("https://calendar.testing/reset-password/"),
+			Not(ContainSubstring("attacker.testing")),
+		))
+	})
+
+	Specify("unknown email gets the same response without an email", func(ctx SpecContext) {
+		requestReset("nobody@calendar.testing")
+
+		Expect(Must(model.ListDueEmails(ctx, db, time.Now(), 10))).To(BeEmpty())
+	})
+
+	Specify("failed email is kept in the outbox for a retry", func(ctx SpecContext) {
+		requestReset("author@calendar.testing")
+
+		smtp.SetFailing(true)
+
+		now := time.Now()
+		Expect(Must(handler.ProcessOutbox(ctx, db, m, now))).To(BeZero())
+		Expect(Must(model.ListDueEmails(ctx, db, now, 10))).To(BeEmpty())
+
+		smtp.SetFailing(false)
+
+		later := now.Add(time.Minute)
+		Expect(Must(handler.ProcessOutbox(ctx, db, m, later))).To(Equal(1))
+		Expect(smtp.Messages()).To(HaveLen(1))
+	})
+})
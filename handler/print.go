@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/server"
+	"github.com/skip2/go-qrcode"
+	"github.com/uptrace/bun"
+)
+
+// PrintHandler handles the printed program of events.
+type PrintHandler struct {
+	db      *bun.DB
+	baseURL string
+}
+
+// Print renders the print-optimized program of events grouped by day.
+func (h *PrintHandler) Print(c *server.Context) error {
+	p, err := h.getProgram(c)
+	if err != nil {
+		return err
+	}
+
+	qrCodes := map[snowflake.ID]string{}
+	for id, png := range p.qrCodes {
+		qrCodes[id] = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return html.PrintPage(c.Locale, c.Settings, p.days, p.req, p.from, p.until, qrCodes).Render(c.Response())
+}
+
+// PDF renders the program of events grouped by day as a PDF document.
+func (h *PrintHandler) PDF(c *server.Context) error {
+	p, err := h.getProgram(c)
+	if err != nil {
+		return err
+	}
+
+	const (
+		margin     = 15.0
+		lineHeight = 6.0
+		timeWidth  = 25.0
+		qrSize     = 20.0
+	)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(c.Settings.Title, true)
+	pdf.AddPage()
+
+	// Core fonts only support cp1252.
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, pageHeight := pdf.GetPageSize()
+	contentWidth := pageWidth - 2*margin
+
+	if c.Settings.LogoHash != "" {
+		logo, err := model.GetSiteAsset(c.Request().Context(), h.db, domain.AssetLogo)
+		if err != nil {
+			return err
+		}
+
+		if imageType := pdfImageType(logo.ContentType); imageType != "" {
+			opts := fpdf.ImageOptions{ImageType: imageType}
+			pdf.RegisterImageOptionsReader("logo", opts, bytes.NewReader(logo.Data))
+			pdf.ImageOptions("logo", margin, pdf.GetY(), 0, 20, true, opts, 0, "")
+			pdf.Ln(2)
+		}
+	}
+
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.MultiCell(contentWidth, 9, tr(c.Settings.Title), "", "L", false)
+
+	if c.Settings.Description != "" {
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(contentWidth, lineHeight, tr(c.Settings.Description), "", "L", false)
+	}
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.MultiCell(contentWidth, lineHeight, tr(fmt.Sprintf("%s - %s",
+		c.Locale.FormatDate(p.from),
+		c.Locale.FormatDate(p.until.AddDate(0, 0, -1)),
+	)), "", "L", false)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(4)
+
+	if len(p.days) == 0 {
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(contentWidth, lineHeight, tr(c.Locale.T("No events")), "", "C", false)
+	}
+
+	for _, day := range p.days {
+		// Keep the day heading together with its first event.
+		if pdf.GetY()+2*lineHeight+qrSize > pageHeight-margin {
+			pdf.AddPage()
+		}
+
+		pdf.Ln(2)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(contentWidth, 8, tr(c.Locale.FormatDate(day.Date)), "B", 1, "L", false, 0, "")
+		pdf.Ln(1)
+
+		for _, ev := range day.Events {
+			rowHeight := lineHeight
+			if p.req.QR {
+				rowHeight = qrSize + 2
+			}
+
+			if pdf.GetY()+rowHeight > pageHeight-margin {
+				pdf.AddPage()
+			}
+
+			top := pdf.GetY()
+			textWidth := contentWidth - timeWidth
+			if png, ok := p.qrCodes[ev.ID]; ok {
+				textWidth -= qrSize + 2
+
+				name := "qr-" + ev.ID.String()
+				opts := fpdf.ImageOptions{ImageType: "PNG"}
+				pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(png))
+				pdf.ImageOptions(name, pageWidth-margin-qrSize, top, qrSize, qrSize, false, opts, 0, h.eventURL(c, ev))
+			}
+
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.CellFormat(timeWidth, lineHeight, tr(c.Locale.FormatTime(ev.StartAt)), "", 0, "L", false, 0, "")
+			pdf.MultiCell(textWidth, lineHeight, tr(ev.Title), "", "L", false)
+
+			if ev.Location != "" {
+				pdf.SetFont("Helvetica", "", 10)
+				pdf.SetX(margin + timeWidth)
+				pdf.MultiCell(textWidth, lineHeight-1, tr(ev.Location), "", "L", false)
+			}
+
+			pdf.SetY(max(pdf.GetY(), top+rowHeight))
+		}
+	}
+
+	if pdf.Err() {
+		return pdf.Error()
+	}
+
+	// Render fully before writing headers so that errors are not sent as a truncated document.
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="program.pdf"`)
+
+	return c.Blob(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+type program struct {
+	req     contract.PrintRequest
+	from    time.Time
+	until   time.Time
+	days    []domain.EventDay
+	qrCodes map[snowflake.ID][]byte
+}
+
+// getProgram lists the events of a print request grouped by day.
+// The date range is in the site time zone.
+func (h *PrintHandler) getProgram(c *server.Context) (*program, error) {
+	p := &program{
+		qrCodes: map[snowflake.ID][]byte{},
+	}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &p.req); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	p.from, p.until, err = p.req.ParseDateRange(time.Now().In(c.SiteLocation()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Query one more to detect programs with too many events.
+	query := model.NewEventsQuery().
+		WithStartAtFrom(p.from).
+		WithStartAtUntil(p.until.Add(-time.Second)).
+		WithOrder(0, model.OrderStartAtAsc).
+		WithLimit(contract.PrintMaxEvents + 1)
+
+	if p.req.Category != "" {
+		query = query.WithCategory(p.req.Category)
+	}
+
+	if p.req.Venue > 0 {
+		query = query.WithVenue(p.req.Venue)
+	}
+
+	events, err := query.List(c.Request().Context(), h.db)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) > contract.PrintMaxEvents {
+		return nil, calendar.InvalidValue.New("Too many events, choose a shorter date range")
+	}
+
+	for i, ev := range events {
+		events[i] = ev.Translate(c.Locale.Code())
+
+		if p.req.QR {
+			png, err := qrcode.Encode(h.eventURL(c, ev), qrcode.Medium, 256)
+			if err != nil {
+				return nil, err
+			}
+
+			p.qrCodes[ev.ID] = png
+		}
+	}
+
+	p.days = domain.GroupEventsByDay(events)
+
+	return p, nil
+}
+
+// Register the handler.
+func (h *PrintHandler) Register(g *echo.Group) {
+	g.GET("/print", server.Wrap(h.db, nil, h.Print))
+	g.GET("/print.pdf", server.Wrap(h.db, nil, h.PDF))
+}
+
+// NewPrintHandler creates a new print handler.
+// QR codes link to event pages on baseURL when configured.
+func NewPrintHandler(db *bun.DB, baseURL string) *PrintHandler {
+	return &PrintHandler{
+		db:      db,
+		baseURL: baseURL,
+	}
+}
+
+// eventURL returns the address of the event page.
+func (h *PrintHandler) eventURL(c *server.Context, ev *domain.Event) string {
+	return publicURL(c, h.baseURL, fmt.Sprintf("/event/%d", ev.ID))
+}
+
+// pdfImageType returns the PDF image type of a content type or an empty string if not supported.
+func pdfImageType(contentType string) string {
+	switch strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]) {
+	case "image/png":
+		return "PNG"
+	case "image/jpeg":
+		return "JPG"
+	case "image/gif":
+		return "GIF"
+	default:
+		return ""
+	}
+}
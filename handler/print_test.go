@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("printed program", func() {
+	var (
+		ts             *httptest.Server
+		first, second  *domain.Event
+		outsideOfRange *domain.Event
+	)
+
+	get := func(path string) (*http.Response, string) {
+		GinkgoHelper()
+
+		r := Must(ts.Client().Get(ts.URL + path))
+		defer r.Body.Close()
+
+		return r, string(Must(io.ReadAll(r.Body)))
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+
+		first = &domain.Event{
+			ID:       snowflake.Generate(),
+			StartAt:  time.Date(2030, 6, 1, 18, 0, 0, 0, time.UTC),
+			Title:    "First day event",
+			Location: "Main hall",
+		}
+
+		second = &domain.Event{
+			ID:       snowflake.Generate(),
+			StartAt:  time.Date(2030, 6, 2, 19, 30, 0, 0, time.UTC),
+			Title:    "Second day event",
+			Location: "Small hall",
+		}
+
+		outsideOfRange = &domain.Event{
+			ID:      snowflake.Generate(),
+			StartAt: time.Date(2030, 7, 1, 18, 0, 0, 0, time.UTC),
+			Title:   "Next month event",
+		}
+
+		Expect(model.InsertEvent(ctx, db, first)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, second)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, outsideOfRange)).To(Succeed())
+
+		e := server.NewServer()
+
+		h := handler.NewPrintHandler(db, "https://calendar.testing")
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("events in the date range are grouped by day", func() {
+		r, body := get("/print?from=2030-06-01&until=2030-06-30")
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(body).To(SatisfyAll(
+			ContainSubstring("My Awesome Events"),
+			MatchRegexp(`Saturday, June 1, 2030.*6PM.*First day event.*Main hall.*Sunday, June 2, 2030.*7:30PM.*Second day event`),
+			Not(ContainSubstring("Next month event")),
+			Not(ContainSubstring("data:image/png")),
+			Not(ContainSubstring("/logout")),
+		))
+	})
+
+	Specify("QR codes link to the event pages", func() {
+		_, body := get("/print?from=2030-06-01&until=2030-06-01&qr=true")
+
+		Expect(body).To(SatisfyAll(
+			ContainSubstring("First day event"),
+			ContainSubstring("data:image/png;base64,"),
+			Not(ContainSubstring("Second day event")),
+		))
+	})
+
+	Specify("program is exported as PDF", func() {
+		r, body := get("/print.pdf?from=2030-06-01&until=2030-06-30&qr=true")
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(r.Header.Get(echo.HeaderContentType)).To(Equal("application/pdf"))
+		Expect(r.Header.Get(echo.HeaderContentDisposition)).To(ContainSubstring("program.pdf"))
+		Expect(body).To(SatisfyAll(
+			HavePrefix("%PDF"),
+			ContainSubstring("https://calendar.testing/event/"+first.ID.String()),
+		))
+	})
+
+	Specify("program with too many events is rejected", func(ctx SpecContext) {
+		for i := range contract.PrintMaxEvents {
+			Expect(model.InsertEvent(ctx, db, &domain.Event{
+				ID:      snowflake.Generate(),
+				StartAt: time.Date(2030, 6, 3, 0, i, 0, 0, time.UTC),
+				Title:   "Crowded event",
+			})).To(Succeed())
+		}
+
+		r, _ := get("/print.pdf?from=2030-06-01&until=2030-06-30&qr=true")
+		Expect(r.StatusCode).To(Equal(http.StatusBadRequest))
+
+		r, _ = get("/print?from=2030-06-01&until=2030-06-02")
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	Specify("invalid date range is rejected", func() {
+		r, _ := get("/print?from=2030-06-30&until=2030-06-01")
+		Expect(r.StatusCode).To(Equal(http.StatusBadRequest))
+
+		r, _ = get("/print.pdf?from=June")
+		Expect(r.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/textfilter"
+	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+	hxhttp "maragu.dev/gomponents-htmx/http"
+)
+
+// RSVPHandler handles event RSVPs and attendee lists.
+type RSVPHandler struct {
+	db *bun.DB
+	sm *scs.SessionManager
+}
+
+// RSVP handles responding to an event.
+// Visitors can respond with their name and email when anonymous RSVPs are enabled.
+func (h *RSVPHandler) RSVP(c *server.Context) error {
+	form := contract.RSVPForm{}
+	if err := c.Bind(&form); err != nil {
+		return err
+	}
+
+	ev, err := model.GetEvent(c.Request().Context(), h.db, form.EventID)
+	if err != nil {
+		return err
+	}
+
+	if ev.IsDraft {
+		return calendar.NotFound.New("Not found")
+	}
+
+	errs := form.Validate()
+
+	if c.User == nil {
+		if !c.Settings.AnonymousRSVP {
+			return calendar.Forbidden.New("Must be logged in")
+		}
+
+		errs = form.ValidateAnonymous()
+	}
+
+	if ev.StartAt.Before(time.Now()) {
+		errs.Set("status", "The event has already started")
+	}
+
+	if len(errs) > 0 {
+		return renderEvent(c, h.db, h.sm, ev, form, errs)
+	}
+
+	rsvp := &domain.RSVP{
+		EventID: ev.ID,
+		Name:    form.Name,
+		Email:   form.Email,
+		Status:  domain.RSVPStatus(form.Status),
+	}
+
+	if c.User != nil {
+		rsvp.UserID = c.User.ID
+		rsvp.Name = c.User.Username
+		rsvp.Email = c.User.Email
+	}
+
+	if err := model.SetRSVP(c.Request().Context(), h.db, rsvp); err != nil {
+		if errors.Is(err, calendar.AlreadyExists) {
+			errs.Set("email", "This email has already responded")
+			return renderEvent(c, h.db, h.sm, ev, form, errs)
+		}
+
+		return err
+	}
+
+	if rsvp.Waitlisted {
+		h.sm.Put(c.Request().Context(), "flash-success", "The event is full, you were added to the waitlist")
+	} else {
+		h.sm.Put(c.Request().Context(), "flash-success", "Your response was saved")
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/event/%d", ev.ID))
+}
+
+// Attendees renders the attendee list of an event.
+func (h *RSVPHandler) Attendees(c *server.Context) error {
+	ev, rsvps, err := h.getAttendees(c)
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.AttendeesMain(c.Locale, ev, rsvps, c.CSRF),
+	)
+}
+
+// AttendeesCSV exports the attendee list of an event as CSV.
+func (h *RSVPHandler) AttendeesCSV(c *server.Context) error {
+	ev, rsvps, err := h.getAttendees(c)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="attendees-%d.csv"`, ev.ID))
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(c.Response())
+
+	if err := w.Write([]string{"name", "email", "status", "waitlisted", "guest", "responded_at"}); err != nil {
+		return err
+	}
+
+	for _, r := range rsvps {
+		if err := w.Write([]string{
+			textfilter.EscapeFormula(r.Name),
+			textfilter.EscapeFormula(r.Email),
+			string(r.Status),
+			strconv.FormatBool(r.Waitlisted),
+			strconv.FormatBool(r.IsAnonymous()),
+			r.UpdatedAt.In(ev.StartAt.Location()).Format(time.RFC3339),
+		}); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+// RemoveAttendee handles removing an RSVP from an event.
+// The first waitlisted attendee takes the free place.
+func (h *RSVPHandler) RemoveAttendee(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		req := contract.RemoveAttendeeRequest{}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+
+		ev, err := h.getOwnEvent(c, req.EventID)
+		if err != nil {
+			return err
+		}
+
+		rsvp, err := model.GetRSVP(c.Request().Context(), h.db, req.RSVPID)
+		if err != nil {
+			return err
+		}
+
+		if rsvp.EventID != ev.ID {
+			return calendar.NotFound.New("Not found")
+		}
+
+		if err := model.DeleteRSVP(c.Request().Context(), h.db, rsvp); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Attendee removed")
+
+		hxhttp.SetRefresh(c.Response().Header())
+
+		return nil
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+// MyRSVPs renders the events the current user has responded to.
+func (h *RSVPHandler) MyRSVPs(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	rsvps, err := model.ListUserRSVPs(c.Request().Context(), h.db, c.User.ID)
+	if err != nil {
+		return err
+	}
+
+	var events []*domain.Event
+
+	if len(rsvps) > 0 {
+		events, err = model.NewEventsQuery().
+			WithIDs(lo.Map(rsvps, func(r *domain.RSVP, _ int) snowflake.ID {
+				return r.EventID
+			})...).
+			WithOrder(0, model.OrderStartAtDesc).
+			List(c.Request().Context(), h.db)
+		if err != nil {
+			return err
+		}
+	}
+
+	token, err := model.GetCalendarToken(c.Request().Context(), h.db, c.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.MyRSVPsMain(
+			c.Locale,
+			c.Timezone,
+			events,
+			lo.KeyBy(rsvps, func(r *domain.RSVP) snowflake.ID {
+				return r.EventID
+			}),
+			absoluteURL(c, fmt.Sprintf("/rsvps/%s/calendar.ics", token)),
+		),
+	)
+}
+
+// getAttendees returns an event of the current user and its RSVPs.
+func (h *RSVPHandler) getAttendees(c *server.Context) (*domain.Event, []*domain.RSVP, error) {
+	req := contract.AttendeesRequest{}
+	if err := c.Bind(&req); err != nil {
+		return nil, nil, err
+	}
+
+	ev, err := h.getOwnEvent(c, req.EventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rsvps, err := model.ListRSVPs(c.Request().Context(), h.db, ev.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ev, rsvps, nil
+}
+
+// getOwnEvent returns an event owned by the current user.
+// Admins can access all events.
+func (h *RSVPHandler) getOwnEvent(c *server.Context, id snowflake.ID) (*domain.Event, error) {
+	if c.User == nil {
+		return nil, calendar.Forbidden.New("Must be logged in")
+	}
+
+	ev, err := model.GetEvent(c.Request().Context(), h.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.User.Role != domain.Admin && c.User.ID != ev.UserID {
+		return nil, calendar.Forbidden.New("Only the event owner can see the attendees")
+	}
+
+	return ev, nil
+}
+
+// Register the handler.
+func (h *RSVPHandler) Register(g *echo.Group) {
+	g.POST("/event/:event_id/rsvp", server.Wrap(h.db, h.sm, h.RSVP))
+
+	g.GET("/event/:event_id/attendees", server.Wrap(h.db, h.sm, h.Attendees))
+	g.GET("/event/:event_id/attendees.csv", server.Wrap(h.db, h.sm, h.AttendeesCSV))
+	g.POST("/event/:event_id/attendees/remove", server.Wrap(h.db, h.sm, h.RemoveAttendee))
+
+	g.GET("/rsvps", server.Wrap(h.db, h.sm, h.MyRSVPs))
+}
+
+// NewRSVPHandler creates a new RSVP handler.
+func NewRSVPHandler(db *bun.DB, sm *scs.SessionManager) *RSVPHandler {
+	return &RSVPHandler{
+		db: db,
+		sm: sm,
+	}
+}
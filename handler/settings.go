@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+)
+
+// maxSiteAssetSize is the maximum size of an uploaded logo or favicon.
+const maxSiteAssetSize = 256 << 10
+
+var siteAssetContentTypes = map[string][]string{
+	domain.AssetLogo:    {"image/png", "image/jpeg", "image/gif", "image/webp"},
+	domain.AssetFavicon: {"image/png", "image/jpeg", "image/gif", "image/webp", "image/x-icon"},
+}
+
+// SettingsHandler handles the site settings page.
+type SettingsHandler struct {
+	db *bun.DB
+	sm *scs.SessionManager
+}
+
+// Settings renders the settings form page.
+func (h *SettingsHandler) Settings(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can edit settings")
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		form := contract.SettingsForm{
+			Title:            c.Settings.Title,
+			Description:      c.Settings.Description,
+			Locale:           c.Settings.Locale,
+			Timezone:         c.Settings.Timezone,
+			AccentColor:      c.Settings.AccentColor,
+			Footer:           c.Settings.Footer,
+			ContactEmail:     c.Settings.ContactEmail,
+			PublicSubmission: c.Settings.PublicSubmission,
+			Moderation:       c.Settings.Moderation,
+			TagsPageEnabled:  c.Settings.TagsPageEnabled,
+			AnonymousRSVP:    c.Settings.AnonymousRSVP,
+			RetentionDays:    c.Settings.RetentionDays,
+			RetentionAction:  string(c.Settings.RetentionAction),
+			EmbedOrigins:     strings.Join(c.Settings.EmbedOrigins, "\n"),
+		}
+
+		return server.RenderPage(c, h.sm,
+			html.SettingsMain(c.Locale, form, c.Settings, nil, c.CSRF),
+		)
+
+	case http.MethodPost:
+		form := contract.SettingsForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		errs := form.Validate()
+
+		logo, err := readSiteAsset(c, domain.AssetLogo, errs)
+		if err != nil {
+			return err
+		}
+
+		favicon, err := readSiteAsset(c, domain.AssetFavicon, errs)
+		if err != nil {
+			return err
+		}
+
+		if len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.SettingsMain(c.Locale, form, c.Settings, errs, c.CSRF),
+			)
+		}
+
+		settings := c.Settings
+		settings.Title = form.Title
+		settings.Description = form.Description
+		settings.Locale = form.Locale
+		settings.Timezone = form.Timezone
+		settings.AccentColor = form.AccentColor
+		settings.Footer = form.Footer
+		settings.ContactEmail = form.ContactEmail
+		settings.PublicSubmission = form.PublicSubmission
+		settings.Moderation = form.Moderation
+		settings.TagsPageEnabled = form.TagsPageEnabled
+		settings.AnonymousRSVP = form.AnonymousRSVP
+		settings.RetentionDays = form.RetentionDays
+		settings.RetentionAction = domain.RetentionAction(form.RetentionAction)
+		settings.EmbedOrigins = strings.Fields(form.EmbedOrigins)
+
+		if settings.AccentColor == domain.DefaultAccentColor {
+			// Keep following the default.
+			settings.AccentColor = ""
+		}
+
+		if err := h.db.RunInTx(c.Request().Context(), nil, func(ctx context.Context, tx bun.Tx) error {
+			hash, err := setSiteAsset(ctx, tx, domain.AssetLogo, logo, form.RemoveLogo, settings.LogoHash)
+			if err != nil {
+				return err
+			}
+			settings.LogoHash = hash
+
+			hash, err = setSiteAsset(ctx, tx, domain.AssetFavicon, favicon, form.RemoveFavicon, settings.FaviconHash)
+			if err != nil {
+				return err
+			}
+			settings.FaviconHash = hash
+
+			return model.UpdateSettings(ctx, tx, settings)
+		}); err != nil {
+			return err
+		}
+
+		model.InvalidateSettings()
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Settings saved")
+
+		return c.Redirect(http.StatusSeeOther, "/settings")
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// Asset serves the site logo and favicon.
+func (h *SettingsHandler) Asset(c *server.Context) error {
+	name := c.Param("name")
+	if _, ok := siteAssetContentTypes[name]; !ok {
+		return calendar.NotFound.New("Not found")
+	}
+
+	asset, err := model.GetSiteAsset(c.Request().Context(), h.db, name)
+	if err != nil {
+		return err
+	}
+
+	// The URL contains the content hash.
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=31536000, immutable")
+
+	return c.Blob(http.StatusOK, asset.ContentType, asset.Data)
+}
+
+// Register the handler.
+func (h *SettingsHandler) Register(g *echo.Group) {
+	g.GET("/settings", server.Wrap(h.db, h.sm, h.Settings))
+	g.POST("/settings", server.Wrap(h.db, h.sm, h.Settings))
+
+	g.GET("/site/:name", server.Wrap(h.db, nil, h.Asset))
+}
+
+// readSiteAsset reads an uploaded site asset.
+// It returns nil if the file was not uploaded or is invalid, in which case errs is updated.
+func readSiteAsset(c *server.Context, name string, errs url.Values) (*domain.SiteAsset, error) {
+	fh, err := c.FormFile(name)
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if fh.Size > maxSiteAssetSize {
+		errs.Set(name, "File is too large")
+		return nil, nil
+	}
+
+	data, err := readFormFile(fh)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(siteAssetContentTypes[name], contentType) {
+		errs.Set(name, "Unsupported image format")
+		return nil, nil
+	}
+
+	return &domain.SiteAsset{
+		Name:        name,
+		ContentType: contentType,
+		Data:        data,
+	}, nil
+}
+
+func readFormFile(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// setSiteAsset stores or removes a site asset and returns its new hash.
+func setSiteAsset(ctx context.Context, db bun.IDB, name string, asset *domain.SiteAsset, remove bool, hash string) (string, error) {
+	switch {
+	case asset != nil:
+		if err := model.PutSiteAsset(ctx, db, asset); err != nil {
+			return "", err
+		}
+
+		sum := sha256.Sum256(asset.Data)
+
+		return hex.EncodeToString(sum[:])[:16], nil
+
+	case remove:
+		if err := model.DeleteSiteAsset(ctx, db, name); err != nil && !errors.Is(err, calendar.PreconditionFailed) {
+			return "", err
+		}
+
+		return "", nil
+
+	default:
+		return hash, nil
+	}
+}
+
+// NewSettingsHandler creates a new settings handler.
+func NewSettingsHandler(db *bun.DB, sm *scs.SessionManager) *SettingsHandler {
+	return &SettingsHandler{
+		db: db,
+		sm: sm,
+	}
+}
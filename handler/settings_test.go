@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("site assets", func() {
+	var (
+		ts *httptest.Server
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+		h := handler.NewSettingsHandler(db, nil)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	When("logo is not uploaded", func() {
+		Specify("not found is returned", func() {
+			r := Must(ts.Client().Get(ts.URL + "/site/logo"))
+			Expect(r.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	When("logo is uploaded", func() {
+		BeforeEach(func(ctx SpecContext) {
+			Expect(model.PutSiteAsset(ctx, db, &domain.SiteAsset{
+				Name:        domain.AssetLogo,
+				ContentType: "image/png",
+				Data:        []byte("logo data"),
+			})).To(Succeed())
+		})
+
+		Specify("logo is served with long-lived caching", func() {
+			r := Must(ts.Client().Get(ts.URL + "/site/logo?v=abc"))
+			defer r.Body.Close()
+
+			Expect(r.StatusCode).To(Equal(http.StatusOK))
+			Expect(r.Header).To(SatisfyAll(
+				HaveKeyWithValue(echo.HeaderContentType, HaveExactElements("image/png")),
+				HaveKeyWithValue(echo.HeaderCacheControl, HaveExactElements("public, max-age=31536000, immutable")),
+			))
+			Expect(string(Must(io.ReadAll(r.Body)))).To(Equal("logo data"))
+		})
+	})
+
+	Specify("unknown assets are not served", func() {
+		r := Must(ts.Client().Get(ts.URL + "/site/other"))
+		Expect(r.StatusCode).To(Equal(http.StatusNotFound))
+	})
+})
@@ -38,10 +38,11 @@ func (h *SetupHandler) Setup(c *server.Context) error {
 		form := contract.SetupForm{
 			Title:       c.Settings.Title,
 			Description: c.Settings.Description,
+			Locale:      c.Locale.Code(),
 		}
 
 		return server.RenderPage(c, h.sm,
-			html.SetupMain(form, nil, c.CSRF),
+			html.SetupMain(c.Locale, form, nil, c.CSRF),
 		)
 
 	case http.MethodPost:
@@ -52,12 +53,13 @@ func (h *SetupHandler) Setup(c *server.Context) error {
 
 		if errs := form.Validate(); len(errs) > 0 {
 			return server.RenderPage(c, h.sm,
-				html.SetupMain(form, errs, c.CSRF),
+				html.SetupMain(c.Locale, form, errs, c.CSRF),
 			)
 		}
 
 		c.Settings.Title = form.Title
 		c.Settings.Description = form.Description
+		c.Settings.Locale = form.Locale
 
 		user := &domain.User{
 			ID:       snowflake.Generate(),
@@ -72,7 +74,7 @@ func (h *SetupHandler) Setup(c *server.Context) error {
 				errs.Set("password2", err.Error())
 
 				return server.RenderPage(c, h.sm,
-					html.SetupMain(form, errs, c.CSRF),
+					html.SetupMain(c.Locale, form, errs, c.CSRF),
 				)
 			}
 
@@ -89,6 +91,8 @@ func (h *SetupHandler) Setup(c *server.Context) error {
 			return err
 		}
 
+		model.InvalidateSettings()
+
 		// First renew the session token.
 		if err := h.sm.RenewToken(c.Request().Context()); err != nil {
 			return err
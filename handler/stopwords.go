@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bufio"
+	"context"
 	"net/http"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/html"
 	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/textfilter"
 	"github.com/mgnsk/calendar/server"
 	"github.com/uptrace/bun"
 )
@@ -40,7 +42,7 @@ func (h *StopWordsHandler) StopWords(c *server.Context) error {
 		}
 
 		return server.RenderPage(c, h.sm,
-			html.StopWordsMain(words, c.CSRF),
+			html.StopWordsMain(c.Locale, words, c.Settings.TagLanguage, nil, c.CSRF),
 		)
 
 	case http.MethodPost:
@@ -60,10 +62,32 @@ func (h *StopWordsHandler) StopWords(c *server.Context) error {
 			return err
 		}
 
-		if err := model.SetStopWords(c.Request().Context(), h.db, domain.NewStopWordList(words...)); err != nil {
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.StopWordsMain(c.Locale, words, form.TagLanguage, errs, c.CSRF),
+			)
+		}
+
+		c.Settings.TagLanguage = form.TagLanguage
+
+		if err := h.db.RunInTx(c.Request().Context(), nil, func(ctx context.Context, tx bun.Tx) error {
+			if err := model.SetStopWords(ctx, tx, domain.NewStopWordList(words...)); err != nil {
+				return err
+			}
+
+			if err := model.UpdateSettings(ctx, tx, c.Settings); err != nil {
+				return err
+			}
+
+			return model.RetagEvents(ctx, tx)
+		}); err != nil {
 			return err
 		}
 
+		model.InvalidateSettings()
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Stop words saved")
+
 		return c.Redirect(http.StatusSeeOther, "/stopwords")
 
 	default:
@@ -71,10 +95,57 @@ func (h *StopWordsHandler) StopWords(c *server.Context) error {
 	}
 }
 
+// LoadPreset adds stop words from a bundled preset.
+func (h *StopWordsHandler) LoadPreset(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can edit stopwords")
+	}
+
+	form := contract.LoadStopWordPresetForm{}
+	if err := c.Bind(&form); err != nil {
+		return err
+	}
+
+	words, err := model.ListStopWords(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	if errs := form.Validate(); len(errs) > 0 {
+		return server.RenderPage(c, h.sm,
+			html.StopWordsMain(c.Locale, words, c.Settings.TagLanguage, errs, c.CSRF),
+		)
+	}
+
+	preset, err := textfilter.LoadStopWordPreset(form.Preset)
+	if err != nil {
+		return err
+	}
+
+	if err := h.db.RunInTx(c.Request().Context(), nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.SetStopWords(ctx, tx, domain.NewStopWordList(append(words, preset...)...)); err != nil {
+			return err
+		}
+
+		return model.RetagEvents(ctx, tx)
+	}); err != nil {
+		return err
+	}
+
+	h.sm.Put(c.Request().Context(), "flash-success", "Stop words added")
+
+	return c.Redirect(http.StatusSeeOther, "/stopwords")
+}
+
 // Register the handler.
 func (h *StopWordsHandler) Register(g *echo.Group) {
 	g.GET("/stopwords", server.Wrap(h.db, h.sm, h.StopWords))
 	g.POST("/stopwords", server.Wrap(h.db, h.sm, h.StopWords))
+	g.POST("/stopwords/preset", server.Wrap(h.db, h.sm, h.LoadPreset))
 }
 
 // NewStopWordsHandler creates a new stop words handler.
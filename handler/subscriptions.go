@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+)
+
+// SubscriptionsHandler handles visitor digest subscriptions.
+type SubscriptionsHandler struct {
+	db          *bun.DB
+	sm          *scs.SessionManager
+	mailEnabled bool
+	baseURL     string
+}
+
+// Subscribe handles the subscribe page. The RSS feed of the filter is always
+// offered, email digests require mail to be enabled.
+func (h *SubscriptionsHandler) Subscribe(c *server.Context) error {
+	categories, err := model.ListCategories(c.Request().Context(), h.db, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		req := contract.SubscribeRequest{}
+		if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+			return err
+		}
+
+		form := contract.SubscribeForm{
+			Frequency: string(domain.DigestWeekly),
+			Category:  req.Category,
+		}
+
+		if len(req.Tags) > 0 {
+			form.Tag = req.Tags[0]
+		}
+
+		return server.RenderPage(c, h.sm,
+			html.SubscribeMain(c.Locale, categories, form, nil, h.feedURL(c, form), h.mailEnabled, c.CSRF),
+		)
+
+	case http.MethodPost:
+		if !h.mailEnabled {
+			return calendar.NotFound.New("Not found")
+		}
+
+		form := contract.SubscribeForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		form.Tag = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(form.Tag), "#")))
+
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.SubscribeMain(c.Locale, categories, form, errs, h.feedURL(c, form), h.mailEnabled, c.CSRF),
+			)
+		}
+
+		if err := h.db.RunInTx(c.Request().Context(), nil, func(ctx context.Context, db bun.Tx) error {
+			s, err := model.InsertSubscription(ctx, db, &domain.Subscription{
+				ID:        snowflake.Generate(),
+				Email:     form.Email,
+				Frequency: domain.DigestFrequency(form.Frequency),
+				Category:  form.Category,
+				Tag:       form.Tag,
+				Language:  c.Locale.Code(),
+				Token:     uuid.New(),
+			})
+			if err != nil {
+				return err
+			}
+
+			// Confirmed subscribers get the same response without an email
+			// so the page does not reveal who is subscribed.
+			if s.IsConfirmed() {
+				return nil
+			}
+
+			return queueEmail(ctx, db, s.Email, html.SubscriptionConfirmEmail(
+				c.Locale,
+				c.Settings.Title,
+				s,
+				baseURLPath(h.baseURL, fmt.Sprintf("/subscribe/confirm/%s", s.Token)),
+			))
+		}); err != nil {
+			return err
+		}
+
+		return server.RenderPage(c, h.sm,
+			html.SubscriptionMessageMain(c.Locale, "Check your inbox to confirm the subscription."),
+		)
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// Confirm handles the double opt-in confirmation link.
+func (h *SubscriptionsHandler) Confirm(c *server.Context) error {
+	s, err := h.getSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	if !s.IsConfirmed() {
+		if err := model.ConfirmSubscription(c.Request().Context(), h.db, s, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.SubscriptionMessageMain(c.Locale, "Your subscription is confirmed."),
+	)
+}
+
+// Unsubscribe handles the unsubscribe link. The subscription is deleted
+// on form submission so that link previews do not unsubscribe.
+func (h *SubscriptionsHandler) Unsubscribe(c *server.Context) error {
+	s, err := h.getSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		return server.RenderPage(c, h.sm,
+			html.UnsubscribeMain(c.Locale, s, c.CSRF),
+		)
+
+	case http.MethodPost:
+		if err := model.DeleteSubscription(c.Request().Context(), h.db, s.ID); err != nil {
+			return err
+		}
+
+		return server.RenderPage(c, h.sm,
+			html.SubscriptionMessageMain(c.Locale, "You have been unsubscribed."),
+		)
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// Subscribers renders the subscriber counts.
+func (h *SubscriptionsHandler) Subscribers(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can view subscribers")
+	}
+
+	stats, err := model.GetSubscriptionStats(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.SubscribersMain(c.Locale, stats),
+	)
+}
+
+func (h *SubscriptionsHandler) getSubscription(c *server.Context) (*domain.Subscription, error) {
+	if !h.mailEnabled {
+		return nil, calendar.NotFound.New("Not found")
+	}
+
+	req := contract.SubscriptionRequest{}
+	if err := c.Bind(&req); err != nil {
+		return nil, err
+	}
+
+	return model.GetSubscription(c.Request().Context(), h.db, req.Token)
+}
+
+// feedURL returns the RSS feed address of the subscription filter.
+// The address is on the base URL when configured.
+func (h *SubscriptionsHandler) feedURL(c *server.Context, form contract.SubscribeForm) string {
+	path := "/feed"
+	query := url.Values{}
+
+	if form.Category != "" {
+		query.Set("category", form.Category)
+	}
+
+	if form.Tag != "" {
+		query.Set("tag", form.Tag)
+	}
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	return publicURL(c, h.baseURL, path)
+}
+
+// Register the handler.
+func (h *SubscriptionsHandler) Register(g *echo.Group) {
+	g.GET("/subscribe", server.Wrap(h.db, h.sm, h.Subscribe))
+	g.POST("/subscribe", server.Wrap(h.db, h.sm, h.Subscribe))
+
+	g.GET("/subscribe/confirm/:token", server.Wrap(h.db, h.sm, h.Confirm))
+
+	g.GET("/unsubscribe/:token", server.Wrap(h.db, h.sm, h.Unsubscribe))
+	g.POST("/unsubscribe/:token", server.Wrap(h.db, h.sm, h.Unsubscribe))
+
+	g.GET("/subscribers", server.Wrap(h.db, h.sm, h.Subscribers))
+}
+
+// NewSubscriptionsHandler creates a new subscriptions handler.
+// Email digests are available when mail is enabled. Links in the emails point to baseURL.
+func NewSubscriptionsHandler(db *bun.DB, sm *scs.SessionManager, mailEnabled bool, baseURL string) *SubscriptionsHandler {
+	return &SubscriptionsHandler{
+		db:          db,
+		sm:          sm,
+		mailEnabled: mailEnabled,
+		baseURL:     baseURL,
+	}
+}
+
+// SendDigests queues the digests which are due at now. A digest lists the events
+// starting within the subscription period and is skipped when there are none.
+// Links are relative to baseURL. It returns the number of queued digests.
+func SendDigests(ctx context.Context, db *bun.DB, baseURL string, now time.Time) (int, error) {
+	settings, err := model.GetCachedSettings(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions, err := model.ListDueSubscriptions(ctx, db, now)
+	if err != nil {
+		return 0, err
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	queued := 0
+
+	for _, s := range subscriptions {
+		query := model.NewEventsQuery().
+			WithStartAtFrom(now).
+			WithStartAtUntil(now.Add(s.Frequency.Period())).
+			WithOrder(0, model.OrderStartAtAsc)
+
+		if s.Category != "" {
+			query = query.WithCategory(s.Category)
+		}
+
+		if s.Tag != "" {
+			query = query.WithTags(model.TagMatchAll, s.Tag)
+		}
+
+		events, err := query.List(ctx, db)
+		if err != nil {
+			return queued, err
+		}
+
+		if err := db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+			// Keep the digests at the same hour with an hourly schedule.
+			if err := model.SetSubscriptionSent(ctx, db, s, now.Truncate(time.Hour)); err != nil {
+				return err
+			}
+
+			if len(events) == 0 {
+				return nil
+			}
+
+			return queueEmail(ctx, db, s.Email, html.DigestEmail(
+				i18n.Get(s.Language),
+				settings.Title,
+				s,
+				events,
+				baseURL,
+				fmt.Sprintf("%s/unsubscribe/%s", baseURL, s.Token),
+			))
+		}); err != nil {
+			if !errors.Is(err, calendar.PreconditionFailed) {
+				return queued, err
+			}
+
+			// Unsubscribed during the run.
+			slog.Info("skipping digest of deleted subscription",
+				slog.String("id", s.ID.String()),
+			)
+
+			continue
+		}
+
+		if len(events) > 0 {
+			queued++
+		}
+	}
+
+	return queued, nil
+}
@@ -0,0 +1,234 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/uptrace/bun"
+)
+
+var _ = Describe("digest subscriptions", func() {
+	var ts *httptest.Server
+
+	BeforeEach(func(ctx SpecContext) {
+		Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		Expect(model.SetCategories(ctx, db, domain.NewCategoryList("Music", "Theatre"))).To(Succeed())
+
+		sm := scs.New()
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+		h := handler.NewSubscriptionsHandler(db, sm, true, "https://calendar.testing")
+		h.Register(e.Group("", server.NewSessionMiddleware(sm)))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	get := func(path string) string {
+		GinkgoHelper()
+
+		r := Must(ts.Client().Get(ts.URL + path))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		return string(Must(io.ReadAll(r.Body)))
+	}
+
+	post := func(path string, form url.Values) string {
+		GinkgoHelper()
+
+		r := Must(ts.Client().PostForm(ts.URL+path, form))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		return string(Must(io.ReadAll(r.Body)))
+	}
+
+	// dueEmails returns the queued emails and clears the outbox.
+	dueEmails := func(ctx SpecContext) []*domain.Email {
+		GinkgoHelper()
+
+		emails := Must(model.ListDueEmails(ctx, db, time.Now(), 100))
+		for _, email := range emails {
+			Expect(model.DeleteEmail(ctx, db, email.ID)).To(Succeed())
+		}
+
+		return emails
+	}
+
+	Specify("subscribe page offers the feed of the filter", func() {
+		body := get("/subscribe?category=Music")
+
+		Expect(body).To(ContainSubstring("https://calendar.testing/feed?category=Music"))
+		Expect(body).To(ContainSubstring(`<option value="Music" selected>`))
+	})
+
+	Specify("confirmed subscriber receives filtered digests until unsubscribing", func(ctx SpecContext) {
+		now := time.Now()
+
+		music := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     now.Add(48 * time.Hour),
+			Title:       "Jazz concert",
+			Description: "Desc",
+			Categories:  []string{"Music"},
+		}
+		play := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     now.Add(48 * time.Hour),
+			Title:       "Hamlet",
+			Description: "Desc",
+			Categories:  []string{"Theatre"},
+		}
+		later := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     now.Add(8 * 24 * time.Hour),
+			Title:       "Late concert",
+			Description: "Desc",
+			Categories:  []string{"Music"},
+		}
+
+		for _, ev := range []*domain.Event{music, play, later} {
+			Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+		}
+
+		Expect(post("/subscribe", url.Values{
+			"email":     {"visitor@calendar.testing"},
+			"frequency": {"weekly"},
+			"category":  {"Music"},
+		})).To(ContainSubstring("Check your inbox"))
+
+		By("digests are not sent before confirming", func() {
+			Expect(Must(handler.SendDigests(ctx, db, "https://calendar.testing", now))).To(BeZero())
+		})
+
+		emails := dueEmails(ctx)
+		Expect(emails).To(HaveExactElements(HaveField("To", "visitor@calendar.testing")))
+
+		link := regexp.MustCompile(`https://calendar\.testing(/subscribe/confirm/[0-9a-f-]{36})`).FindStringSubmatch(emails[0].Text)
+		Expect(link).To(HaveLen(2))
+		confirm := link[1]
+		Expect(get(confirm)).To(ContainSubstring("Your subscription is confirmed."))
+
+		By("subscribing again does not send another confirmation", func() {
+			post("/subscribe", url.Values{
+				"email":     {"Visitor@calendar.testing"},
+				"frequency": {"weekly"},
+				"category":  {"Music"},
+			})
+			Expect(dueEmails(ctx)).To(BeEmpty())
+		})
+
+		Expect(Must(handler.SendDigests(ctx, db, "https://calendar.testing", now))).To(Equal(1))
+
+		emails = dueEmails(ctx)
+		Expect(emails).To(HaveLen(1))
+		Expect(emails[0].Text).To(SatisfyAll(
+			ContainSubstring("Jazz concert"),
+			ContainSubstring("https://calendar.testing/event/"+music.ID.String()),
+			Not(ContainSubstring("Hamlet")),
+			Not(ContainSubstring("Late concert")),
+		))
+
+		By("the next digest is sent after a week", func() {
+			Expect(Must(handler.SendDigests(ctx, db, "https://calendar.testing", now.Add(24*time.Hour)))).To(BeZero())
+		})
+
+		unsubscribe := regexp.MustCompile(`https://calendar.testing(/unsubscribe/[0-9a-f-]{36})`).FindStringSubmatch(emails[0].Text)
+		Expect(unsubscribe).To(HaveLen(2))
+
+		Expect(get(unsubscribe[1])).To(ContainSubstring("visitor@calendar.testing"))
+		Expect(post(unsubscribe[1], nil)).To(ContainSubstring("You have been unsubscribed."))
+
+		Expect(Must(model.ListDueSubscriptions(ctx, db, now.Add(8*24*time.Hour)))).To(BeEmpty())
+	})
+
+	Specify("confirmation link does not use the request host", func(ctx SpecContext) {
+		form := url.Values{
+			"email":     {"visitor@calendar.testing"},
+			"frequency": {"weekly"},
+		}
+
+		req := Must(http.NewRequest(http.MethodPost, ts.URL+"/subscribe", strings.NewReader(form.Encode())))
+		req.Host = "attacker.testing"
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+		r := Must(ts.Client().Do(req))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+
+		emails := dueEmails(ctx)
+		Expect(emails).To(HaveLen(1))
+		Expect(emails[0].Text).To(SatisfyAll(
+			ContainSubstring("https://calendar.testing/subscribe/confirm/"),
+			Not(ContainSubstring("attacker.testing")),
+		))
+	})
+
+	Specify("subscription deleted during the run is skipped", func(ctx SpecContext) {
+		now := time.Now()
+
+		Expect(model.InsertEvent(ctx, db, &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     now.Add(48 * time.Hour),
+			Title:       "Jazz concert",
+			Description: "Desc",
+		})).To(Succeed())
+
+		var subscriptions []*domain.Subscription
+		for _, email := range []string{"first@calendar.testing", "second@calendar.testing"} {
+			s := Must(model.InsertSubscription(ctx, db, &domain.Subscription{
+				ID:        snowflake.Generate(),
+				Email:     email,
+				Frequency: domain.DigestWeekly,
+				Token:     uuid.New(),
+			}))
+			Expect(model.ConfirmSubscription(ctx, db, s, now.Add(-time.Hour))).To(Succeed())
+			subscriptions = append(subscriptions, s)
+		}
+
+		By("unsubscribing when the digest events of the first subscription are listed")
+		unsubscribed := false
+		db.AddQueryHook(beforeQueryHook(func(ctx context.Context, event *bun.QueryEvent) {
+			if !unsubscribed && strings.Contains(event.Query, `FROM "events"`) {
+				unsubscribed = true
+				Expect(model.DeleteSubscription(ctx, db, subscriptions[0].ID)).To(Succeed())
+			}
+		}))
+
+		Expect(Must(handler.SendDigests(ctx, db, "https://calendar.testing", now))).To(Equal(1))
+		Expect(unsubscribed).To(BeTrue())
+		Expect(dueEmails(ctx)).To(HaveExactElements(HaveField("To", "second@calendar.testing")))
+	})
+})
+
+// beforeQueryHook runs a function before each query.
+type beforeQueryHook func(ctx context.Context, event *bun.QueryEvent)
+
+func (h beforeQueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	h(ctx, event)
+	return ctx
+}
+
+func (h beforeQueryHook) AfterQuery(context.Context, *bun.QueryEvent) {}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+	hxhttp "maragu.dev/gomponents-htmx/http"
+)
+
+// TemplatesHandler handles event templates.
+type TemplatesHandler struct {
+	db *bun.DB
+	sm *scs.SessionManager
+}
+
+// Templates renders the site-wide and own templates of the user.
+func (h *TemplatesHandler) Templates(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	templates, err := model.ListEventTemplates(c.Request().Context(), h.db, c.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.TemplatesMain(c.Locale, c.User, templates, c.CSRF),
+	)
+}
+
+// Save saves the posted edit event form as a template.
+// The result is rendered in place so that the form is kept.
+func (h *TemplatesHandler) Save(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		req := contract.EditEventForm{}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+
+		form := contract.SaveTemplateForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		if form.SiteWide && c.User.Role != domain.Admin {
+			return calendar.Forbidden.New("Only admins can save site-wide templates")
+		}
+
+		if form.Name == "" {
+			form.Name = req.Title
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+		c.Response().WriteHeader(200)
+
+		if form.Name == "" {
+			return html.SaveTemplatePartial(c.Locale, "Template name is required", false).Render(c.Response())
+		}
+
+		t := &domain.EventTemplate{
+			ID:          snowflake.Generate(),
+			UserID:      c.User.ID,
+			Name:        form.Name,
+			Title:       req.Title,
+			Description: req.Description,
+			URL:         req.URL,
+			VenueID:     req.VenueID,
+			Location:    req.Location,
+			OSMType:     req.OSMType,
+			OSMID:       req.OSMID,
+			Latitude:    req.Latitude,
+			Longitude:   req.Longitude,
+			Categories:  req.Categories,
+			Language:    req.Language,
+		}
+
+		if form.SiteWide {
+			t.UserID = 0
+		}
+
+		if err := model.InsertEventTemplate(c.Request().Context(), h.db, t); err != nil {
+			return err
+		}
+
+		return html.SaveTemplatePartial(c.Locale, "Template saved", true).Render(c.Response())
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+// Delete handles deleting templates.
+// Site-wide templates can only be deleted by admins.
+func (h *TemplatesHandler) Delete(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		req := contract.DeleteTemplateRequest{}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+
+		t, err := getEventTemplate(c, h.db, req.TemplateID)
+		if err != nil {
+			return err
+		}
+
+		if !t.CanDelete(c.User) {
+			return calendar.Forbidden.New("Only admins can delete site-wide templates")
+		}
+
+		if err := model.DeleteEventTemplate(c.Request().Context(), h.db, t.ID); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Template deleted")
+
+		hxhttp.SetRefresh(c.Response().Header())
+
+		return nil
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+// Register the handler.
+func (h *TemplatesHandler) Register(g *echo.Group) {
+	g.GET("/templates", server.Wrap(h.db, h.sm, h.Templates))
+	g.POST("/templates/new", server.Wrap(h.db, h.sm, h.Save))
+
+	g.POST("/templates/delete", server.Wrap(h.db, h.sm, h.Delete))
+}
+
+// getEventTemplate returns a template available to the current user.
+func getEventTemplate(c *server.Context, db bun.IDB, id snowflake.ID) (*domain.EventTemplate, error) {
+	if c.User == nil {
+		return nil, calendar.Forbidden.New("Must be logged in")
+	}
+
+	t, err := model.GetEventTemplate(c.Request().Context(), db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsSiteWide() && t.UserID != c.User.ID {
+		return nil, calendar.NotFound.New("Not found")
+	}
+
+	return t, nil
+}
+
+// setFormFromTemplate sets the template fields on the form.
+// The current venue location is used if the venue still exists.
+func setFormFromTemplate(c *server.Context, db bun.IDB, req *contract.EditEventForm, t *domain.EventTemplate) error {
+	req.Title = t.Title
+	req.Description = t.Description
+	req.URL = t.URL
+	req.Location = t.Location
+	req.OSMType = t.OSMType
+	req.OSMID = t.OSMID
+	req.Latitude = t.Latitude
+	req.Longitude = t.Longitude
+	req.Categories = t.Categories
+	req.Language = t.Language
+
+	if t.VenueID > 0 {
+		venue, err := model.GetVenue(c.Request().Context(), db, t.VenueID)
+		if err != nil {
+			if !errors.Is(err, calendar.NotFound) {
+				return err
+			}
+			return nil
+		}
+
+		req.SetVenue(venue)
+	}
+
+	return nil
+}
+
+// NewTemplatesHandler creates a new templates handler.
+func NewTemplatesHandler(db *bun.DB, sm *scs.SessionManager) *TemplatesHandler {
+	return &TemplatesHandler{
+		db: db,
+		sm: sm,
+	}
+}
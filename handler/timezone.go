@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+)
+
+// TimezoneHandler handles the viewer time zone preference.
+type TimezoneHandler struct {
+	db *bun.DB
+	sm *scs.SessionManager
+}
+
+// SetTimezone sets the viewer time zone cookie.
+func (h *TimezoneHandler) SetTimezone(c *server.Context) error {
+	form := contract.TimezoneForm{}
+	if err := c.Bind(&form); err != nil {
+		return err
+	}
+
+	if errs := form.Validate(); len(errs) > 0 {
+		return calendar.InvalidValue.New(errs.Get("timezone"))
+	}
+
+	cookie := &http.Cookie{
+		Name:     server.TimezoneCookieName,
+		Value:    form.Timezone,
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+	}
+
+	if form.Timezone == "" {
+		// Let the browser detect the time zone again.
+		cookie.MaxAge = -1
+	}
+
+	c.SetCookie(cookie)
+
+	return c.Redirect(http.StatusSeeOther, localReferer(c.Request()))
+}
+
+// Register the handler.
+func (h *TimezoneHandler) Register(g *echo.Group) {
+	g.POST("/timezone", server.Wrap(h.db, h.sm, h.SetTimezone))
+}
+
+// NewTimezoneHandler creates a new time zone handler.
+func NewTimezoneHandler(db *bun.DB, sm *scs.SessionManager) *TimezoneHandler {
+	return &TimezoneHandler{
+		db: db,
+		sm: sm,
+	}
+}
+
+// localReferer returns the path and query of the referring page or the root path.
+func localReferer(r *http.Request) string {
+	u, err := url.Parse(r.Referer())
+	if err != nil || !strings.HasPrefix(u.Path, "/") || strings.HasPrefix(u.Path, "//") {
+		return "/"
+	}
+
+	return (&url.URL{Path: u.Path, RawQuery: u.RawQuery}).String()
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/blobstore"
+	"github.com/mgnsk/calendar/pkg/imaging"
+	"github.com/mgnsk/calendar/server"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// Image sizes in pixels.
+const (
+	imageMaxSize     = 1600
+	thumbnailMaxSize = 400
+)
+
+var attachmentContentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+}
+
+// UploadsHandler serves uploaded files.
+type UploadsHandler struct {
+	db    *bun.DB
+	store *blobstore.Store
+}
+
+// File serves an uploaded file. The name in the URL is only used for downloads.
+func (h *UploadsHandler) File(c *server.Context) error {
+	f, err := h.store.Open(c.Param("hash"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, blobstore.ErrInvalidHash) {
+			return calendar.NotFound.New("Not found")
+		}
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Detect the content type from content since the name is user provided.
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if !slices.Contains(attachmentContentTypes, contentType) {
+		contentType = echo.MIMEOctetStream
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// The URL contains the content hash.
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=31536000, immutable")
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+
+	http.ServeContent(c.Response(), c.Request(), "", info.ModTime(), f)
+
+	return nil
+}
+
+// Register the handler.
+func (h *UploadsHandler) Register(g *echo.Group) {
+	g.GET("/uploads/:hash/:name", server.Wrap(h.db, nil, h.File))
+}
+
+// NewUploadsHandler creates a new uploads handler.
+func NewUploadsHandler(db *bun.DB, store *blobstore.Store) *UploadsHandler {
+	return &UploadsHandler{
+		db:    db,
+		store: store,
+	}
+}
+
+// getUploadedFiles returns the files uploaded with a form field.
+func getUploadedFiles(c *server.Context, name string) ([]*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		if errors.Is(err, http.ErrNotMultipart) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return form.File[name], nil
+}
+
+// saveAttachments validates and stores uploaded files. Images are resized
+// and re-encoded without metadata. Validation errors are set on errs.
+func saveAttachments(store *blobstore.Store, files []*multipart.FileHeader, errs url.Values) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+
+	for _, fh := range files {
+		if fh.Size > contract.MaxAttachmentSize {
+			errs.Set("attachments", "File is too large")
+			return nil, nil
+		}
+
+		data, err := readFormFile(fh)
+		if err != nil {
+			return nil, err
+		}
+
+		contentType := http.DetectContentType(data)
+		if !slices.Contains(attachmentContentTypes, contentType) {
+			errs.Set("attachments", "Unsupported file type")
+			return nil, nil
+		}
+
+		name := filepath.Base(fh.Filename)
+
+		if contentType == "application/pdf" {
+			hash, err := store.Put(data)
+			if err != nil {
+				return nil, err
+			}
+
+			attachments = append(attachments, domain.Attachment{
+				Hash:        hash,
+				Name:        name,
+				ContentType: contentType,
+				Size:        int64(len(data)),
+			})
+
+			continue
+		}
+
+		img, err := imaging.Resize(data, imageMaxSize)
+		if err != nil {
+			errs.Set("attachments", "Invalid image")
+			return nil, nil
+		}
+
+		thumb, err := imaging.Resize(data, thumbnailMaxSize)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := store.Put(img.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		thumbHash, err := store.Put(thumb.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, domain.Attachment{
+			Hash:          hash,
+			ThumbnailHash: thumbHash,
+			Name:          withImageExtension(name, img.ContentType),
+			ContentType:   img.ContentType,
+			Size:          int64(len(img.Data)),
+			Width:         img.Width,
+			Height:        img.Height,
+		})
+	}
+
+	return attachments, nil
+}
+
+// withImageExtension replaces the file name extension to match the re-encoded image.
+func withImageExtension(name, contentType string) string {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+
+	if contentType == "image/png" {
+		return base + ".png"
+	}
+
+	return base + ".jpg"
+}
+
+// attachmentHashes returns the stored file hashes of attachments.
+func attachmentHashes(attachments []domain.Attachment) []string {
+	var hashes []string
+
+	for _, a := range attachments {
+		hashes = append(hashes, a.Hash)
+		if a.ThumbnailHash != "" {
+			hashes = append(hashes, a.ThumbnailHash)
+		}
+	}
+
+	return lo.Uniq(hashes)
+}
+
+// deleteUnreferencedFiles deletes the stored files of hashes which are no longer referenced by events.
+func deleteUnreferencedFiles(ctx context.Context, db bun.IDB, store *blobstore.Store, hashes []string) error {
+	for _, chunk := range lo.Chunk(hashes, 500) {
+		referenced, err := model.ListReferencedHashes(ctx, db, chunk...)
+		if err != nil {
+			return err
+		}
+
+		for _, hash := range chunk {
+			if slices.Contains(referenced, hash) {
+				continue
+			}
+
+			if err := store.Delete(hash); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteOrphanedFiles deletes stored files older than minAge which are not referenced by events.
+// The age check leaves time for uploads to be saved with the event.
+func DeleteOrphanedFiles(ctx context.Context, db bun.IDB, store *blobstore.Store, minAge time.Duration) error {
+	blobs, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	hashes := lo.FilterMap(blobs, func(b blobstore.Blob, _ int) (string, bool) {
+		return b.Hash, time.Since(b.ModTime) > minAge
+	})
+
+	return deleteUnreferencedFiles(ctx, db, store, hashes)
+}
@@ -0,0 +1,88 @@
+package handler_test
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/blobstore"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("serving uploads", func() {
+	var (
+		ts    *httptest.Server
+		store *blobstore.Store
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		By("creating settings", func() {
+			Expect(model.InsertSettings(ctx, db, domain.NewDefaultSettings())).To(Succeed())
+		})
+
+		store = blobstore.New(GinkgoT().TempDir())
+
+		e := echo.New()
+		e.HTTPErrorHandler = server.ErrorHandler()
+		h := handler.NewUploadsHandler(db, store)
+		h.Register(e.Group(""))
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("content type is detected from content", func() {
+		hash := Must(store.Put([]byte("%PDF-1.4 program")))
+
+		r := Must(ts.Client().Get(ts.URL + "/uploads/" + hash + "/program.html"))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(r.Header).To(SatisfyAll(
+			HaveKeyWithValue(echo.HeaderContentType, HaveExactElements("application/pdf")),
+			HaveKeyWithValue(echo.HeaderCacheControl, HaveExactElements("public, max-age=31536000, immutable")),
+		))
+		Expect(string(Must(io.ReadAll(r.Body)))).To(Equal("%PDF-1.4 program"))
+	})
+
+	Specify("unexpected content is served as binary", func() {
+		hash := Must(store.Put([]byte("<html><script>alert(1)</script></html>")))
+
+		r := Must(ts.Client().Get(ts.URL + "/uploads/" + hash + "/page.html"))
+		Expect(r.StatusCode).To(Equal(http.StatusOK))
+		Expect(r.Header).To(HaveKeyWithValue(echo.HeaderContentType, HaveExactElements(echo.MIMEOctetStream)))
+	})
+
+	DescribeTable("missing files are not found",
+		func(hash string) {
+			r := Must(ts.Client().Get(ts.URL + "/uploads/" + hash + "/file.pdf"))
+			Expect(r.StatusCode).To(Equal(http.StatusNotFound))
+		},
+		Entry("unknown hash", "293b9207228b7854bc3ccb2959ebea1583e066d41983124a5b381d6fdf6575f8"),
+		Entry("invalid hash", "invalid"),
+	)
+
+	Specify("orphaned files are deleted", func(ctx SpecContext) {
+		referenced := Must(store.Put([]byte("poster")))
+		orphaned := Must(store.Put([]byte("orphan")))
+
+		ev := *event1
+		ev.Attachments = []domain.Attachment{{Hash: referenced, Name: "poster.jpg", ContentType: "image/jpeg"}}
+		Expect(model.InsertEvent(ctx, db, &ev)).To(Succeed())
+
+		Expect(handler.DeleteOrphanedFiles(ctx, db, store, 0)).To(Succeed())
+
+		Expect(store.List()).To(HaveExactElements(HaveField("Hash", referenced)))
+
+		_, err := store.Open(orphaned)
+		Expect(err).To(MatchError(fs.ErrNotExist))
+	})
+})
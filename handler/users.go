@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/mgnsk/calendar/model"
 	"github.com/mgnsk/calendar/pkg/snowflake"
 	"github.com/mgnsk/calendar/server"
@@ -23,8 +25,10 @@ import (
 
 // UsersHandler handles users pages.
 type UsersHandler struct {
-	db *bun.DB
-	sm *scs.SessionManager
+	db          *bun.DB
+	sm          *scs.SessionManager
+	mailEnabled bool
+	baseURL     string
 }
 
 // Users handles users page.
@@ -43,11 +47,12 @@ func (h *UsersHandler) Users(c *server.Context) error {
 	}
 
 	return server.RenderPage(c, h.sm,
-		html.UsersMain(c.User, users, c.CSRF),
+		html.UsersMain(c.Locale, c.User, users, h.mailEnabled, c.CSRF),
 	)
 }
 
 // Invite handles invite link generation.
+// The link is emailed when an email address is given.
 func (h *UsersHandler) Invite(c *server.Context) error {
 	if c.User == nil {
 		return calendar.Forbidden.New("Must be logged in")
@@ -58,18 +63,44 @@ func (h *UsersHandler) Invite(c *server.Context) error {
 	}
 
 	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		form := contract.InviteForm{}
+
+		if h.mailEnabled {
+			if err := c.Bind(&form); err != nil {
+				return err
+			}
+
+			if errs := form.Validate(); len(errs) > 0 {
+				return html.InviteFormPartial(c.Locale, form, errs, c.CSRF).Render(c.Response())
+			}
+		}
+
 		token := uuid.New()
 
-		if err := model.InsertInvite(c.Request().Context(), h.db, &domain.Invite{
-			Token:      token,
-			ValidUntil: time.Now().Add(72 * time.Hour),
-			CreatedBy:  c.User.ID,
+		if err := h.db.RunInTx(c.Request().Context(), nil, func(ctx context.Context, db bun.Tx) error {
+			if err := model.InsertInvite(ctx, db, &domain.Invite{
+				Token:      token,
+				ValidUntil: time.Now().Add(72 * time.Hour),
+				CreatedBy:  c.User.ID,
+			}); err != nil {
+				return err
+			}
+
+			if form.Email == "" {
+				return nil
+			}
+
+			// The recipient's language is not known.
+			return queueEmail(ctx, db, form.Email, html.InviteEmail(
+				i18n.Get(c.Settings.Locale),
+				c.Settings.Title,
+				baseURLPath(h.baseURL, fmt.Sprintf("/register/%s", token)),
+			))
 		}); err != nil {
 			return err
-
 		}
 
-		return html.InviteLinkPartial(token).Render(c.Response())
+		return html.InviteLinkPartial(c.Locale, token, form.Email).Render(c.Response())
 	}
 
 	return calendar.NotFound.New("Not found")
@@ -100,7 +131,7 @@ func (h *UsersHandler) RegisterUser(c *server.Context) error {
 		form := contract.RegisterForm{}
 
 		return server.RenderPage(c, h.sm,
-			html.RegisterMain(form, nil, c.CSRF),
+			html.RegisterMain(c.Locale, form, nil, h.mailEnabled, c.CSRF),
 		)
 
 	case http.MethodPost:
@@ -111,13 +142,14 @@ func (h *UsersHandler) RegisterUser(c *server.Context) error {
 
 		if errs := form.Validate(); len(errs) > 0 {
 			return server.RenderPage(c, h.sm,
-				html.RegisterMain(form, errs, c.CSRF),
+				html.RegisterMain(c.Locale, form, errs, h.mailEnabled, c.CSRF),
 			)
 		}
 
 		newUser := &domain.User{
 			ID:       snowflake.Generate(),
 			Username: form.Username,
+			Email:    form.Email,
 			Role:     domain.Author,
 		}
 
@@ -128,7 +160,7 @@ func (h *UsersHandler) RegisterUser(c *server.Context) error {
 				errs.Set("password2", err.Error())
 
 				return server.RenderPage(c, h.sm,
-					html.RegisterMain(form, errs, c.CSRF),
+					html.RegisterMain(c.Locale, form, errs, h.mailEnabled, c.CSRF),
 				)
 			}
 
@@ -144,10 +176,15 @@ func (h *UsersHandler) RegisterUser(c *server.Context) error {
 		}); err != nil {
 			if errors.Is(err, calendar.AlreadyExists) {
 				errs := url.Values{}
-				errs.Set("username", "User already exists")
+				if form.Email == "" {
+					errs.Set("username", "User already exists")
+				} else {
+					errs.Set("username", "Username or email already in use")
+					errs.Set("email", "Username or email already in use")
+				}
 
 				return server.RenderPage(c, h.sm,
-					html.RegisterMain(form, errs, c.CSRF),
+					html.RegisterMain(c.Locale, form, errs, h.mailEnabled, c.CSRF),
 				)
 			}
 
@@ -169,6 +206,58 @@ func (h *UsersHandler) RegisterUser(c *server.Context) error {
 	}
 }
 
+// Account handles the account settings page of the current user.
+func (h *UsersHandler) Account(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		form := contract.AccountForm{
+			Email: c.User.Email,
+		}
+
+		return server.RenderPage(c, h.sm,
+			html.AccountMain(c.Locale, c.User, form, nil, c.CSRF),
+		)
+
+	case http.MethodPost:
+		form := contract.AccountForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.AccountMain(c.Locale, c.User, form, errs, c.CSRF),
+			)
+		}
+
+		c.User.Email = form.Email
+
+		if err := model.UpdateUser(c.Request().Context(), h.db, c.User); err != nil {
+			if errors.Is(err, calendar.AlreadyExists) {
+				errs := url.Values{}
+				errs.Set("email", "Email already in use")
+
+				return server.RenderPage(c, h.sm,
+					html.AccountMain(c.Locale, c.User, form, errs, c.CSRF),
+				)
+			}
+
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Account saved")
+
+		return c.Redirect(http.StatusSeeOther, "/account")
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
 // Delete a user.
 func (h *UsersHandler) Delete(c *server.Context) error {
 	if c.User == nil {
@@ -254,12 +343,19 @@ func (h *UsersHandler) Register(g *echo.Group) {
 
 	g.GET("/register/:token", server.Wrap(h.db, h.sm, h.RegisterUser))
 	g.POST("/register/:token", server.Wrap(h.db, h.sm, h.RegisterUser))
+
+	g.GET("/account", server.Wrap(h.db, h.sm, h.Account))
+	g.POST("/account", server.Wrap(h.db, h.sm, h.Account))
 }
 
 // NewUsersHandler creates a new users handler.
-func NewUsersHandler(db *bun.DB, sm *scs.SessionManager) *UsersHandler {
+// Invite links are emailed through the outbox when mail is enabled
+// and point to baseURL.
+func NewUsersHandler(db *bun.DB, sm *scs.SessionManager, mailEnabled bool, baseURL string) *UsersHandler {
 	return &UsersHandler{
-		db: db,
-		sm: sm,
+		db:          db,
+		sm:          sm,
+		mailEnabled: mailEnabled,
+		baseURL:     baseURL,
 	}
 }
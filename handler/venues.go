@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+	hxhttp "maragu.dev/gomponents-htmx/http"
+)
+
+// VenuesHandler handles venue pages.
+type VenuesHandler struct {
+	db       *bun.DB
+	sm       *scs.SessionManager
+	geocoder Geocoder
+}
+
+// Venues renders the venue directory.
+func (h *VenuesHandler) Venues(c *server.Context) error {
+	venues, err := model.ListVenues(c.Request().Context(), h.db, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.VenuesMain(c.Locale, c.User, venues, c.CSRF),
+	)
+}
+
+// Venue renders a venue page with upcoming events.
+func (h *VenuesHandler) Venue(c *server.Context) error {
+	req := contract.VenueRequest{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	venue, err := model.GetVenue(c.Request().Context(), h.db, req.VenueID)
+	if err != nil {
+		return err
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		events, err := model.NewEventsQuery().
+			WithVenue(venue.ID).
+			WithStartAtFrom(time.Now()).
+			WithOrder(req.Offset, model.OrderStartAtAsc).
+			WithLimit(contract.EventLimitPerPage).
+			List(c.Request().Context(), h.db)
+		if err != nil {
+			if !errors.Is(err, calendar.NotFound) {
+				return err
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+		c.Response().WriteHeader(200)
+		return html.EventListPartial(c.Locale, c.User, c.Timezone, req.Offset, events, c.CSRF).Render(c.Response())
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.VenueMain(c.Locale, c.User, venue, c.CSRF),
+	)
+}
+
+// Edit handles adding and editing venues.
+func (h *VenuesHandler) Edit(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	form := contract.EditVenueForm{}
+	if err := c.Bind(&form); err != nil {
+		return err
+	}
+
+	var venue *domain.Venue
+
+	if form.VenueID > 0 {
+		if c.User.Role != domain.Admin {
+			return calendar.Forbidden.New("Only admins can edit venues")
+		}
+
+		v, err := model.GetVenue(c.Request().Context(), h.db, form.VenueID)
+		if err != nil {
+			return err
+		}
+
+		venue = v
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		if venue != nil {
+			form.Name = venue.Name
+			form.Address = venue.Address
+			form.URL = venue.URL
+			form.Accessibility = venue.Accessibility
+			form.OSMType = venue.OSMType
+			form.OSMID = venue.OSMID
+			form.Latitude = venue.Latitude
+			form.Longitude = venue.Longitude
+		}
+
+		return server.RenderPage(c, h.sm,
+			html.EditVenueMain(c.Locale, form, nil, c.CSRF),
+		)
+
+	case http.MethodPost:
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.EditVenueMain(c.Locale, form, errs, c.CSRF),
+			)
+		}
+
+		if form.Latitude == 0 && form.Longitude == 0 {
+			// Address was typed without picking a search result.
+			if place := geocodeFirst(c, h.db, h.geocoder, form.Address); place != nil {
+				form.OSMType = place.OSMType
+				form.OSMID = place.OSMID
+				form.Latitude = place.Latitude
+				form.Longitude = place.Longitude
+			}
+		}
+
+		if venue == nil {
+			venue = &domain.Venue{
+				ID: snowflake.Generate(),
+			}
+		}
+
+		venue.Name = form.Name
+		venue.Address = form.Address
+		venue.URL = form.URL
+		venue.Accessibility = form.Accessibility
+		venue.OSMType = form.OSMType
+		venue.OSMID = form.OSMID
+		venue.Latitude = form.Latitude
+		venue.Longitude = form.Longitude
+
+		if form.VenueID > 0 {
+			if err := model.UpdateVenue(c.Request().Context(), h.db, venue); err != nil {
+				return err
+			}
+		} else {
+			if err := model.InsertVenue(c.Request().Context(), h.db, venue); err != nil {
+				return err
+			}
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Venue saved")
+
+		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/venue/%d", venue.ID))
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// Delete handles deleting venues.
+func (h *VenuesHandler) Delete(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can delete venues")
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		req := contract.DeleteVenueRequest{}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+
+		if err := model.DeleteVenue(c.Request().Context(), h.db, req.VenueID); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Venue deleted")
+
+		hxhttp.SetRefresh(c.Response().Header())
+
+		return nil
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+// Merge handles merging duplicate venues.
+func (h *VenuesHandler) Merge(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can merge venues")
+	}
+
+	venues, err := model.ListVenues(c.Request().Context(), h.db, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	form := contract.MergeVenuesForm{}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		return server.RenderPage(c, h.sm,
+			html.MergeVenuesMain(c.Locale, form, venues, nil, c.CSRF),
+		)
+
+	case http.MethodPost:
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.MergeVenuesMain(c.Locale, form, venues, errs, c.CSRF),
+			)
+		}
+
+		if err := model.MergeVenues(c.Request().Context(), h.db, form.TargetID, form.SourceIDs...); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Venues merged")
+
+		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/venue/%d", form.TargetID))
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// Register the handler.
+func (h *VenuesHandler) Register(g *echo.Group) {
+	g.GET("/venues", server.Wrap(h.db, h.sm, h.Venues))
+
+	g.GET("/venue/:venue_id", server.Wrap(h.db, h.sm, h.Venue))
+	g.POST("/venue/:venue_id", server.Wrap(h.db, h.sm, h.Venue)) // For htmx.
+
+	g.GET("/venues/edit/:venue_id", server.Wrap(h.db, h.sm, h.Edit))
+	g.POST("/venues/edit/:venue_id", server.Wrap(h.db, h.sm, h.Edit))
+
+	g.POST("/venues/delete", server.Wrap(h.db, h.sm, h.Delete))
+
+	g.GET("/venues/merge", server.Wrap(h.db, h.sm, h.Merge))
+	g.POST("/venues/merge", server.Wrap(h.db, h.sm, h.Merge))
+}
+
+// NewVenuesHandler creates a new venues handler.
+func NewVenuesHandler(db *bun.DB, sm *scs.SessionManager, geocoder Geocoder) *VenuesHandler {
+	return &VenuesHandler{
+		db:       db,
+		sm:       sm,
+		geocoder: geocoder,
+	}
+}
@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/server"
+	"github.com/uptrace/bun"
+	hxhttp "maragu.dev/gomponents-htmx/http"
+)
+
+// webhookUserAgent identifies webhook requests.
+const webhookUserAgent = "Calendar-Webhook - github.com/mgnsk/calendar"
+
+// WebhooksHandler handles webhook configuration.
+type WebhooksHandler struct {
+	db     *bun.DB
+	sm     *scs.SessionManager
+	client *http.Client
+}
+
+// Webhooks handles the webhook list and adding webhooks.
+func (h *WebhooksHandler) Webhooks(c *server.Context) error {
+	if err := h.checkAdmin(c); err != nil {
+		return err
+	}
+
+	webhooks, err := model.ListWebhooks(c.Request().Context(), h.db)
+	if err != nil {
+		return err
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		form := contract.WebhookForm{
+			EventTypes: []string{string(domain.WebhookPublished)},
+		}
+
+		return server.RenderPage(c, h.sm,
+			html.WebhooksMain(c.Locale, webhooks, form, nil, c.CSRF),
+		)
+
+	case http.MethodPost:
+		form := contract.WebhookForm{}
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+
+		if errs := form.Validate(); len(errs) > 0 {
+			return server.RenderPage(c, h.sm,
+				html.WebhooksMain(c.Locale, webhooks, form, errs, c.CSRF),
+			)
+		}
+
+		w := &domain.Webhook{
+			ID:     snowflake.Generate(),
+			URL:    form.URL,
+			Secret: newWebhookSecret(),
+		}
+
+		for _, t := range form.EventTypes {
+			w.EventTypes = append(w.EventTypes, domain.WebhookEventType(t))
+		}
+
+		if err := model.InsertWebhook(c.Request().Context(), h.db, w); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Webhook added")
+
+		return c.Redirect(http.StatusSeeOther, "/webhooks")
+
+	default:
+		return calendar.NotFound.New("Not found")
+	}
+}
+
+// Delete handles deleting webhooks.
+func (h *WebhooksHandler) Delete(c *server.Context) error {
+	if err := h.checkAdmin(c); err != nil {
+		return err
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		req := contract.WebhookRequest{}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+
+		if err := model.DeleteWebhook(c.Request().Context(), h.db, req.WebhookID); err != nil {
+			return err
+		}
+
+		h.sm.Put(c.Request().Context(), "flash-success", "Webhook deleted")
+
+		hxhttp.SetRefresh(c.Response().Header())
+
+		return nil
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+// Test handles sending a ping to a webhook. The ping is sent immediately
+// and is not retried. The result is recorded in the delivery log.
+func (h *WebhooksHandler) Test(c *server.Context) error {
+	if err := h.checkAdmin(c); err != nil {
+		return err
+	}
+
+	if c.Request().Method == http.MethodPost && hxhttp.IsRequest(c.Request().Header) {
+		req := contract.WebhookRequest{}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+
+		w, err := model.GetWebhook(c.Request().Context(), h.db, req.WebhookID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		payload, err := json.Marshal(domain.NewWebhookPayload(domain.WebhookPing, nil, now))
+		if err != nil {
+			return err
+		}
+
+		d := &domain.WebhookDelivery{
+			ID:            snowflake.Generate(),
+			WebhookID:     w.ID,
+			EventType:     domain.WebhookPing,
+			Payload:       payload,
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		}
+
+		if code, err := deliverWebhook(c.Request().Context(), h.client, w, d); err != nil {
+			d.SetFailed(code, err, now)
+			d.Status = domain.WebhookDeliveryFailed
+
+			h.sm.Put(c.Request().Context(), "flash-error", "Test delivery failed, see the delivery log")
+		} else {
+			d.SetDelivered(code, now)
+
+			h.sm.Put(c.Request().Context(), "flash-success", "Test delivered")
+		}
+
+		if err := model.InsertWebhookDelivery(c.Request().Context(), h.db, d); err != nil {
+			return err
+		}
+
+		hxhttp.SetRefresh(c.Response().Header())
+
+		return nil
+	}
+
+	return calendar.NotFound.New("Not found")
+}
+
+// Deliveries renders the delivery log of a webhook.
+func (h *WebhooksHandler) Deliveries(c *server.Context) error {
+	if err := h.checkAdmin(c); err != nil {
+		return err
+	}
+
+	req := contract.WebhookDeliveriesRequest{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	w, err := model.GetWebhook(c.Request().Context(), h.db, req.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := model.ListWebhookDeliveries(c.Request().Context(), h.db, w.ID, 100)
+	if err != nil {
+		return err
+	}
+
+	return server.RenderPage(c, h.sm,
+		html.WebhookDeliveriesMain(c.Locale, w, deliveries),
+	)
+}
+
+func (h *WebhooksHandler) checkAdmin(c *server.Context) error {
+	if c.User == nil {
+		return calendar.Forbidden.New("Must be logged in")
+	}
+
+	if c.User.Role != domain.Admin {
+		return calendar.Forbidden.New("Only admins can manage webhooks")
+	}
+
+	return nil
+}
+
+// Register the handler.
+func (h *WebhooksHandler) Register(g *echo.Group) {
+	g.GET("/webhooks", server.Wrap(h.db, h.sm, h.Webhooks))
+	g.POST("/webhooks", server.Wrap(h.db, h.sm, h.Webhooks))
+
+	g.POST("/webhooks/delete", server.Wrap(h.db, h.sm, h.Delete))
+	g.POST("/webhooks/test", server.Wrap(h.db, h.sm, h.Test))
+
+	g.GET("/webhooks/:webhook_id/deliveries", server.Wrap(h.db, h.sm, h.Deliveries))
+}
+
+// NewWebhooksHandler creates a new webhooks handler.
+func NewWebhooksHandler(db *bun.DB, sm *scs.SessionManager, client *http.Client) *WebhooksHandler {
+	return &WebhooksHandler{
+		db:     db,
+		sm:     sm,
+		client: client,
+	}
+}
+
+// ProcessWebhooks sends the queued webhook deliveries which are due at now.
+// Failed deliveries are retried with backoff until they run out of attempts.
+// It returns the number of delivered requests.
+func ProcessWebhooks(ctx context.Context, db *bun.DB, client *http.Client, now time.Time) (int, error) {
+	deliveries, err := model.ListDueWebhookDeliveries(ctx, db, now, 100)
+	if err != nil {
+		return 0, err
+	}
+
+	// Webhooks are nil once they have been deleted during the run.
+	webhooks := map[snowflake.ID]*domain.Webhook{}
+	delivered := 0
+
+	for _, d := range deliveries {
+		w, ok := webhooks[d.WebhookID]
+		if !ok {
+			w, err = model.GetWebhook(ctx, db, d.WebhookID)
+			if err != nil {
+				if !errors.Is(err, calendar.NotFound) {
+					return delivered, err
+				}
+
+				logWebhookDeleted(d)
+			}
+
+			webhooks[d.WebhookID] = w
+		}
+
+		if w == nil {
+			continue
+		}
+
+		code, err := deliverWebhook(ctx, client, w, d)
+		if err != nil {
+			if ctx.Err() != nil {
+				return delivered, nil
+			}
+
+			d.SetFailed(code, err, now)
+
+			slog.Warn("error delivering webhook",
+				slog.String("id", d.ID.String()),
+				slog.String("url", w.URL),
+				slog.Int("attempts", d.Attempts),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			d.SetDelivered(code, now)
+		}
+
+		if err := model.UpdateWebhookDelivery(ctx, db, d); err != nil {
+			if !errors.Is(err, calendar.PreconditionFailed) {
+				return delivered, err
+			}
+
+			// The webhook was deleted along with its deliveries.
+			logWebhookDeleted(d)
+			webhooks[d.WebhookID] = nil
+
+			continue
+		}
+
+		if d.Status == domain.WebhookDeliveryDelivered {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// logWebhookDeleted logs a skipped delivery of a deleted webhook.
+func logWebhookDeleted(d *domain.WebhookDelivery) {
+	slog.Info("skipping delivery of deleted webhook",
+		slog.String("id", d.ID.String()),
+		slog.String("webhook_id", d.WebhookID.String()),
+	)
+}
+
+// deliverWebhook posts a delivery to a webhook. It returns the response
+// status code, which is zero when there was no response.
+// Responses other than 2xx are errors.
+func deliverWebhook(ctx context.Context, client *http.Client, w *domain.Webhook, d *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-Calendar-Event", string(d.EventType))
+	req.Header.Set("X-Calendar-Delivery", d.ID.String())
+	req.Header.Set("X-Calendar-Signature", w.Sign(d.Payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.New(resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// newWebhookSecret returns a random signing secret.
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,152 @@
+package handler_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/handler"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("webhook deliveries", func() {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+
+	var (
+		ts        *httptest.Server
+		webhook   *domain.Webhook
+		mu        sync.Mutex
+		requests  []request
+		status    int
+		onRequest func(r *http.Request)
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		requests = nil
+		status = http.StatusNoContent
+		onRequest = nil
+
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			requests = append(requests, request{
+				header: r.Header.Clone(),
+				body:   Must(io.ReadAll(r.Body)),
+			})
+
+			if onRequest != nil {
+				onRequest(r)
+			}
+
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(ts.Close)
+
+		webhook = &domain.Webhook{
+			ID:         snowflake.Generate(),
+			URL:        ts.URL + "/hook",
+			Secret:     "secret",
+			EventTypes: []domain.WebhookEventType{domain.WebhookPublished},
+		}
+
+		Expect(model.InsertWebhook(ctx, db, webhook)).To(Succeed())
+	})
+
+	Specify("published event is delivered with a signature", func(ctx SpecContext) {
+		Expect(model.InsertEvent(ctx, db, event1)).To(Succeed())
+
+		Expect(Must(handler.ProcessWebhooks(ctx, db, ts.Client(), time.Now()))).To(Equal(1))
+
+		Expect(requests).To(HaveLen(1))
+		req := requests[0]
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(req.body)
+
+		Expect(req.header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(req.header.Get("X-Calendar-Event")).To(Equal("event.published"))
+		Expect(req.header.Get("X-Calendar-Signature")).To(Equal("sha256=" + hex.EncodeToString(mac.Sum(nil))))
+
+		payload := domain.WebhookPayload{}
+		Expect(json.Unmarshal(req.body, &payload)).To(Succeed())
+		Expect(payload.Type).To(Equal(domain.WebhookPublished))
+		Expect(payload.Event).To(SatisfyAll(
+			HaveField("ID", Equal(event1.ID.String())),
+			HaveField("Title", Equal(event1.Title)),
+		))
+
+		Expect(Must(model.ListWebhookDeliveries(ctx, db, webhook.ID, 10))).To(HaveExactElements(SatisfyAll(
+			HaveField("Status", Equal(domain.WebhookDeliveryDelivered)),
+			HaveField("ResponseCode", Equal(http.StatusNoContent)),
+		)))
+	})
+
+	Specify("failed delivery is retried with backoff", func(ctx SpecContext) {
+		Expect(model.InsertEvent(ctx, db, event1)).To(Succeed())
+
+		now := time.Now()
+		status = http.StatusInternalServerError
+
+		Expect(Must(handler.ProcessWebhooks(ctx, db, ts.Client(), now))).To(BeZero())
+		Expect(Must(model.ListWebhookDeliveries(ctx, db, webhook.ID, 10))).To(HaveExactElements(SatisfyAll(
+			HaveField("Status", Equal(domain.WebhookDeliveryPending)),
+			HaveField("Attempts", Equal(1)),
+			HaveField("ResponseCode", Equal(http.StatusInternalServerError)),
+		)))
+
+		By("not retrying before the backoff")
+		Expect(Must(handler.ProcessWebhooks(ctx, db, ts.Client(), now))).To(BeZero())
+		Expect(requests).To(HaveLen(1))
+
+		By("retrying after the backoff")
+		status = http.StatusOK
+		Expect(Must(handler.ProcessWebhooks(ctx, db, ts.Client(), now.Add(time.Minute)))).To(Equal(1))
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].body).To(Equal(requests[0].body))
+
+		Expect(Must(model.ListWebhookDeliveries(ctx, db, webhook.ID, 10))).To(HaveExactElements(SatisfyAll(
+			HaveField("Status", Equal(domain.WebhookDeliveryDelivered)),
+			HaveField("Attempts", Equal(2)),
+			HaveField("ResponseCode", Equal(http.StatusOK)),
+		)))
+	})
+
+	Specify("filtered event types are not delivered", func(ctx SpecContext) {
+		Expect(model.InsertEvent(ctx, db, event1)).To(Succeed())
+		Expect(model.DeleteEvent(ctx, db, event1)).To(Succeed())
+
+		Expect(Must(handler.ProcessWebhooks(ctx, db, ts.Client(), time.Now()))).To(Equal(1))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].header.Get("X-Calendar-Event")).To(Equal("event.published"))
+	})
+
+	Specify("deliveries of a webhook deleted during the run are skipped", func(ctx SpecContext) {
+		Expect(model.InsertEvent(ctx, db, event1)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, event2)).To(Succeed())
+
+		onRequest = func(r *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(model.DeleteWebhook(r.Context(), db, webhook.ID)).To(Succeed())
+		}
+
+		Expect(Must(handler.ProcessWebhooks(ctx, db, ts.Client(), time.Now()))).To(BeZero())
+		Expect(requests).To(HaveLen(1))
+		Expect(Must(model.ListWebhookDeliveries(ctx, db, webhook.ID, 10))).To(BeEmpty())
+	})
+})
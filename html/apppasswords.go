@@ -0,0 +1,90 @@
+package html
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	hx "maragu.dev/gomponents-htmx"
+	. "maragu.dev/gomponents/html"
+)
+
+// AppPasswordsMain renders the app passwords of the current user and the add app password form.
+// The password is the plain text of a newly created app password or empty.
+func AppPasswordsMain(l *i18n.Locale, user *domain.User, caldavURL string, passwords []*domain.AppPassword, password string, form contract.AppPasswordForm, errs url.Values, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			Div(Class("px-3 py-4 text-center"),
+				P(Text(l.T("Calendar apps such as Thunderbird and DAVx5 can add and edit your events over CalDAV. Sign in with your username and an app password."))),
+				P(Class("font-semibold break-all"), Text(caldavURL)),
+				P(Text(l.T("Username: %s", user.Username))),
+			),
+
+			If(password != "",
+				Div(Class("px-3 py-4 text-center"),
+					P(Text(l.T("Copy the app password now. It will not be shown again."))),
+					P(Class("font-mono font-semibold text-lg"), Text(password)),
+				),
+			),
+
+			If(len(passwords) == 0,
+				Div(Class("px-3 py-4 text-center"),
+					P(Text(l.T("no app passwords found"))),
+				),
+			),
+
+			Iff(len(passwords) > 0, func() Node {
+				return Table(Class("table-fixed w-full text-sm"),
+					THead(
+						Tr(
+							Th(Class("text-left"), Text(l.T("Name"))),
+							Th(Class("text-left"), Text(l.T("Created"))),
+							Th(Class("text-left"), Text(l.T("Last used"))),
+							Th(Class("text-left"), Text(l.T("Actions"))),
+						),
+					),
+					TBody(
+						Map(passwords, func(p *domain.AppPassword) Node {
+							return Tr(
+								Td(Class("py-1 break-all"), Text(p.Name)),
+								Td(Text(l.FormatDateTime(p.GetCreatedAt()))),
+								Td(
+									If(p.LastUsedAt.IsZero(), Text(l.T("Never"))),
+									If(!p.LastUsedAt.IsZero(), Text(l.FormatDateTime(p.LastUsedAt))),
+								),
+								Td(
+									A(Class("hover:underline text-accent font-semibold px-1"),
+										hx.Post("/app-passwords/delete"),
+										hx.Confirm(l.T("Calendar apps using the password will be signed out. Are you sure?")),
+										hx.Vals(string(must(json.Marshal(map[string]string{
+											"csrf":            csrf,
+											"app_password_id": p.ID.String(),
+										})))),
+										Href("#"),
+										Text(l.T("DELETE")),
+									),
+								),
+							)
+						}),
+					),
+				)
+			}),
+
+			Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+				Method("POST"),
+				Action("/app-passwords"),
+
+				Label(Class("block w-full pt-2"), For("name"), Text(l.T("App name"))),
+				components.InputElement("name", "text", l.T("Phone"), form.Name, l.T(errs.Get("name")), true, false),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				components.SubmitButtonElement(l.T("Add app password")),
+			),
+		),
+	)
+}
@@ -0,0 +1,58 @@
+package html
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// CategoriesMain renders the categories form.
+func CategoriesMain(l *i18n.Locale, categories []string, tagsPage domain.TagsPageMode, errs url.Values, csrf string) Node {
+	type option struct {
+		Value domain.TagsPageMode
+		Text  string
+	}
+
+	options := []option{
+		{Value: domain.TagsPageWords, Text: l.T("Word tags")},
+		{Value: domain.TagsPageCategories, Text: l.T("Categories")},
+		{Value: domain.TagsPageBoth, Text: l.T("Categories and word tags")},
+	}
+
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			Form(Class("text-center w-full  px-3 py-4 mx-auto"),
+				Method("POST"),
+
+				Label(Class("block w-full pb-2"), For("categories"), Text(l.T("Categories authors can pick from. One category per line."))),
+
+				components.TextareaElement("categories",
+					strings.Join(categories, "\n"),
+					"",
+					20,
+					false,
+					false,
+				),
+
+				Label(Class("block w-full pb-2"), For("tags_page"), Text(l.T("Tags page shows"))),
+
+				Select(components.BaseFormElementClasses(),
+					Name("tags_page"),
+					Map(options, func(o option) Node {
+						return Option(Value(string(o.Value)), If(o.Value == tagsPage, Selected()), Text(o.Text))
+					}),
+				),
+				If(errs.Get("tags_page") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("tags_page"))))),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				components.SubmitButtonElement(l.T("Save")),
+			),
+		),
+	)
+}
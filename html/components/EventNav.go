@@ -1,9 +1,16 @@
 package components
 
 import (
+	"cmp"
 	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
 
+	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
 	. "maragu.dev/gomponents"
 	hx "maragu.dev/gomponents-htmx"
 	. "maragu.dev/gomponents/components"
@@ -11,7 +18,7 @@ import (
 )
 
 // EventNav renders the event navigation.
-func EventNav(user *domain.User, currentPath, csrf string) Node {
+func EventNav(l *i18n.Locale, settings *domain.Settings, user *domain.User, currentPath string, query url.Values, csrf string) Node {
 	type eventNavLink struct {
 		Text   string
 		URL    string
@@ -20,30 +27,61 @@ func EventNav(user *domain.User, currentPath, csrf string) Node {
 
 	links := []eventNavLink{
 		{
-			Text:   "Upcoming",
+			Text:   l.T("Upcoming"),
 			URL:    "/",
 			Active: currentPath == "/",
 		},
 		{
-			Text:   "Past",
+			Text:   l.T("Past"),
 			URL:    "/past",
 			Active: currentPath == "/past",
 		},
-		{
-			Text:   "Tags",
+	}
+
+	if settings == nil || settings.TagsPageEnabled {
+		links = append(links, eventNavLink{
+			Text:   l.T("Tags"),
 			URL:    "/tags",
 			Active: currentPath == "/tags",
-		},
+		})
 	}
 
 	if user != nil {
 		links = append(links, eventNavLink{
-			Text:   "My events",
+			Text:   l.T("My events"),
 			URL:    "/my-events",
 			Active: currentPath == "/my-events",
 		})
 	}
 
+	filter := url.Values{}
+	if category := query.Get("category"); category != "" {
+		filter.Set("category", category)
+	}
+
+	tags := query["tag"]
+	for _, tag := range tags {
+		filter.Add("tag", tag)
+	}
+
+	tagMatch := query.Get("tag_match")
+	if tagMatch != "" && len(tags) > 1 {
+		filter.Set("tag_match", tagMatch)
+	}
+
+	near := query.Get("near")
+	if near != "" {
+		filter.Set("near", near)
+		if radius := query.Get("radius"); radius != "" {
+			filter.Set("radius", radius)
+		}
+	}
+
+	mapFilter := maps.Clone(filter)
+	if currentPath == "/past" {
+		mapFilter.Set("past", "1")
+	}
+
 	return Div(Class("max-w-3xl mx-auto"),
 		Ul(Class("flex border-b border-gray-200"),
 			Map(links, func(link eventNavLink) Node {
@@ -67,16 +105,16 @@ func EventNav(user *domain.User, currentPath, csrf string) Node {
 							"px-2":                 true,
 							"md:px-4":              true,
 							"text-gray-400":        !link.Active,
-							"hover:text-amber-600": !link.Active,
-							"text-amber-600":       link.Active,
+							"hover:text-accent":    !link.Active,
+							"text-accent":          link.Active,
 							"font-semibold":        true,
 							"hover:cursor-pointer": true,
 						},
-						hx.Post(link.URL),
+						hx.Post(withQuery(link.URL, filter)), // Keep filters when clicking tabs.
 						hx.Trigger("click"),
 						If(link.URL == "/tags", hx.On("click", "changeTab(this); setSearch('')")), // Clear search when clicking tags tab.
 						If(link.URL != "/tags", hx.On("click", "changeTab(this)")),                // Keep search query when clicking event tabs.
-						If(link.URL != "/tags", hx.Include("[name='search']")),                    // Keep search query when clicking event tabs.
+						If(link.URL != "/tags", hx.Include("[name='search'], [name='sort']")),     // Keep search query when clicking event tabs.
 						hx.Target("#event-list"),
 						hx.Swap("innerHTML"),
 						hx.PushURL("true"),
@@ -89,7 +127,33 @@ func EventNav(user *domain.User, currentPath, csrf string) Node {
 					),
 				)
 			}),
+			Li(Class("flex items-baseline mr-1"),
+				A(Class("inline-block py-2 px-2 md:px-4 text-gray-400 hover:text-accent font-semibold"),
+					Href(withQuery("/map", mapFilter)),
+					Title(l.T("Show events on a map")),
+					Attr("onclick", "openMap(this); return false;"), // Keep search query.
+					I(Class("fa fa-map pr-1"), Aria("hidden", "true")),
+					Text(l.T("Map")),
+				),
+			),
 			Li(Class("flex items-baseline ml-auto border-l border-t border-r border-gray-200 rounded-t"),
+				If(currentPath != "/tags", Select(Class("py-2 px-1 text-sm text-gray-500 bg-white"),
+					ID("sort"),
+					Name("sort"),
+					Title(l.T("Sort search results")),
+					hx.Post(""), // Post to current URL.
+					hx.Trigger("change"),
+					hx.Include("[name='search']"),
+					hx.Target("#event-list"),
+					hx.Swap("innerHTML"),
+					hx.Indicator("#loading-spinner"),
+					hx.Vals(string(must(json.Marshal(map[string]string{
+						"csrf": csrf,
+					})))),
+					Option(Value(""), Text(l.T("By date"))),
+					Option(Value("relevance"), Text(l.T("By relevance"))),
+					If(near != "", Option(Value("distance"), Text(l.T("By distance")))),
+				)),
 				Div(Class("relative"),
 					Input(Classes{
 						"block":   true,
@@ -102,9 +166,11 @@ func EventNav(user *domain.User, currentPath, csrf string) Node {
 						ID("search"),
 						Name("search"),
 						Type("text"),
-						Placeholder("Filter..."),
+						Placeholder(l.T("Filter...")),
+						Title(l.T(`Search words or "exact phrases". Narrow down with title:, location:, description:, after:YYYY-MM-DD and before:YYYY-MM-DD.`)),
 						hx.Post(""), // Post to current URL.
 						hx.Trigger("input delay:0.2s"),
+						hx.Include("[name='sort']"),
 						hx.Target("#event-list"),
 						hx.Swap("innerHTML"),
 						hx.Indicator("#search-spinner, #loading-spinner"),
@@ -118,5 +184,74 @@ func EventNav(user *domain.User, currentPath, csrf string) Node {
 				),
 			),
 		),
+		Iff(currentPath != "/tags", func() Node {
+			return Div(Class("flex flex-wrap gap-2 py-2"),
+				Iff(near == "", func() Node {
+					return Chip(l.T("Near me"), "#",
+						Title(l.T("Show events near your location")),
+						Attr("onclick", "nearMe(); return false;"),
+						I(Class("fa fa-location-crosshairs pl-1"), Aria("hidden", "true")),
+					)
+				}),
+				Iff(near != "", func() Node {
+					return RemovableChip(l, l.T("Within %s of you", cmp.Or(filter.Get("radius"), fmt.Sprintf("%dkm", contract.DefaultRadiusKM))), withoutQuery(currentPath, filter, "near", "radius"))
+				}),
+				Iff(filter.Get("category") != "", func() Node {
+					return RemovableChip(l, l.T("Category: %s", filter.Get("category")), withoutQuery(currentPath, filter, "category"))
+				}),
+				Map(tags, func(tag string) Node {
+					return RemovableChip(l, "#"+tag, withoutTag(currentPath, filter, tag))
+				}),
+				Chip(l.T("Subscribe"), withQuery("/subscribe", filter),
+					Title(l.T("Get these events by email or in a feed reader")),
+					I(Class("fa fa-envelope pl-1"), Aria("hidden", "true")),
+				),
+				Iff(len(tags) > 1, func() Node {
+					q := maps.Clone(filter)
+
+					if tagMatch == "any" {
+						q.Set("tag_match", "all")
+						return Chip(l.T("Matching any tag"), withQuery(currentPath, q), Title(l.T("Match all tags")))
+					}
+
+					q.Set("tag_match", "any")
+					return Chip(l.T("Matching all tags"), withQuery(currentPath, q), Title(l.T("Match any tag")))
+				}),
+			)
+		}),
 	)
 }
+
+// withQuery returns path with query string appended.
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+
+	return path + "?" + query.Encode()
+}
+
+// withoutQuery returns path with query string appended, excluding keys.
+func withoutQuery(path string, query url.Values, keys ...string) string {
+	q := maps.Clone(query)
+	for _, key := range keys {
+		q.Del(key)
+	}
+
+	return withQuery(path, q)
+}
+
+// withoutTag returns path with query string appended, excluding a single tag.
+func withoutTag(path string, query url.Values, tag string) string {
+	q := maps.Clone(query)
+	q["tag"] = slices.DeleteFunc(slices.Clone(q["tag"]), func(v string) bool {
+		return v == tag
+	})
+
+	if len(q["tag"]) < 2 {
+		// Tag match is irrelevant for less than 2 tags.
+		q.Del("tag_match")
+	}
+
+	return withQuery(path, q)
+}
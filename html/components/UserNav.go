@@ -2,20 +2,30 @@ package components
 
 import (
 	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
 	. "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/html"
 )
 
 // UserNav renders the user navigation.
-func UserNav(user *domain.User, children Node) Node {
+// Settings is nil before setup.
+func UserNav(l *i18n.Locale, settings *domain.Settings, user *domain.User, children Node) Node {
 	return Nav(Class("sticky top-0 bg-white max-w-3xl mx-auto z-1"),
 		Ul(Class("flex justify-between font-semibold flex-row space-x-8 mb-5"),
 			// TODO: find better icons
 			Li(Class("justify-self-start align-start"),
-				A(Class("inline-block p-2"), Href("/"), Text("Home")),
-				A(Class("inline-block p-2"), Title("RSS feed"), Href("/feed"), rssIcon()),
-				A(Class("inline-block p-2"), Title("iCal URL"), ID("ical-link"), calendarIcon()),
-				A(Class("inline-block p-2"), Title("Add to Google Calendar"), ID("google-calendar-link"), Target("_blank"), calendarIcon()),
+				Iff(settings != nil && settings.LogoHash != "", func() Node {
+					return A(Class("inline-block p-2"), Href("/"),
+						Img(Class("inline h-6"), Src("/site/logo?v="+settings.LogoHash), Alt(settings.Title)),
+					)
+				}),
+				If(settings == nil || settings.LogoHash == "",
+					A(Class("inline-block p-2"), Href("/"), Text(l.T("Home"))),
+				),
+				A(Class("inline-block p-2"), Href("/venues"), Text(l.T("Venues"))),
+				A(Class("inline-block p-2"), Title(l.T("RSS feed")), Href("/feed"), rssIcon()),
+				A(Class("inline-block p-2"), Title(l.T("iCal URL")), ID("ical-link"), calendarIcon()),
+				A(Class("inline-block p-2"), Title(l.T("Add to Google Calendar")), ID("google-calendar-link"), Target("_blank"), calendarIcon()),
 				Script(Raw(`window.webcalURL = "webcal://" + window.location.host + "/calendar.ics"`)),
 				Script(Raw(`document.getElementById("ical-link").setAttribute("href", window.webcalURL)`)),
 				Script(Raw(`document.getElementById("google-calendar-link").setAttribute("href", "https://calendar.google.com/calendar/render?cid=" + window.webcalURL)`)),
@@ -24,19 +34,35 @@ func UserNav(user *domain.User, children Node) Node {
 			Iff(user != nil, func() Node {
 				return Group{
 					Li(Class("justify-self-end"),
-						A(Class("inline-block p-2"), Href("/edit/0"), Text("Add event")),
+						A(Class("inline-block p-2"), Href("/edit/0"), Text(l.T("Add event"))),
+						A(Class("inline-block p-2"), Href("/templates"), Text(l.T("Templates")), Title(l.T("Event templates"))),
+						A(Class("inline-block p-2"), Href("/manage"), Text(l.T("Manage")), Title(l.T("Manage events in bulk"))),
+						A(Class("inline-block p-2"), Href("/rsvps"), Text(l.T("RSVPs")), Title(l.T("Events you have responded to"))),
+						A(Class("inline-block p-2"), Href("/print"), Text(l.T("Print")), Title(l.T("Printable program"))),
 						If(user.Role == domain.Admin, Group{
-							A(Class("inline-block p-2"), Href("/stopwords"), Text("Stop words"), Title("Configure tag cloud stop words")),
-							A(Class("inline-block p-2"), Href("/users"), Text("Users"), Title("Manage users")),
+							If(settings != nil && settings.Moderation,
+								A(Class("inline-block p-2"), Href("/moderation"), Text(l.T("Moderation")), Title(l.T("Review submitted events"))),
+							),
+							A(Class("inline-block p-2"), Href("/settings"), Text(l.T("Settings")), Title(l.T("Configure the site"))),
+							A(Class("inline-block p-2"), Href("/stopwords"), Text(l.T("Stop words")), Title(l.T("Configure tag cloud stop words"))),
+							A(Class("inline-block p-2"), Href("/categories"), Text(l.T("Categories")), Title(l.T("Configure event categories"))),
+							A(Class("inline-block p-2"), Href("/users"), Text(l.T("Users")), Title(l.T("Manage users"))),
+							A(Class("inline-block p-2"), Href("/subscribers"), Text(l.T("Subscribers")), Title(l.T("Digest subscriber counts"))),
+							A(Class("inline-block p-2"), Href("/webhooks"), Text(l.T("Webhooks")), Title(l.T("Notify other services of event changes"))),
+							A(Class("inline-block p-2"), Href("/embed/snippet"), Text(l.T("Embed")), Title(l.T("Show events on partner sites"))),
 						}),
-						A(Class("inline-block p-2"), Href("/logout"), Text("Logout")),
+						A(Class("inline-block p-2"), Href("/account"), Text(l.T("Account")), Title(l.T("Account settings"))),
+						A(Class("inline-block p-2"), Href("/logout"), Text(l.T("Logout"))),
 					),
 				}
 			}),
 
 			Iff(user == nil, func() Node {
 				return Li(Class("justify-self-end"),
-					A(Class("inline-block p-2"), Href("/login"), Text("Login")),
+					If(settings != nil && settings.PublicSubmission,
+						A(Class("inline-block p-2"), Href("/edit/0"), Text(l.T("Submit event"))),
+					),
+					A(Class("inline-block p-2"), Href("/login"), Text(l.T("Login"))),
 				)
 			}),
 		),
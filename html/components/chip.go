@@ -0,0 +1,29 @@
+package components
+
+import (
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// Chip renders a small rounded link.
+func Chip(text, href string, children ...Node) Node {
+	nodes := []Node{
+		Class("inline-block rounded-full border border-accent/30 bg-accent/5 px-3 py-0.5 text-xs font-semibold text-accent hover:underline"),
+		Href(href),
+		Text(text),
+	}
+	nodes = append(nodes, children...)
+
+	return A(
+		nodes...,
+	)
+}
+
+// RemovableChip renders a chip which links to a page without the filter it represents.
+func RemovableChip(l *i18n.Locale, text, href string) Node {
+	return Chip(text, href,
+		Title(l.T("Remove filter")),
+		I(Class("fa fa-xmark pl-1"), Aria("hidden", "true")),
+	)
+}
@@ -53,6 +53,20 @@ func InputElement(name, typ, placeholder string, value, err string, required, au
 	)
 }
 
+// DataListInputElement is a text input element with suggestions from a datalist.
+func DataListInputElement(name, placeholder string, value, err, list string) Node {
+	return withErrors(err,
+		Input(baseInputClasses(err != ""),
+			Name(name),
+			Type("text"),
+			Placeholder(placeholder),
+			Value(value),
+			Attr("list", list),
+			AutoComplete("off"),
+		),
+	)
+}
+
 // TextareaElement is a textarea element.
 func TextareaElement(name string, value, err string, rows uint64, required, autocomplete bool) Node {
 	return withErrors(err,
@@ -78,3 +92,16 @@ func DateTimeLocalInput(name string, value, err string, required, autocomplete b
 		),
 	)
 }
+
+// CheckboxElement is a labeled checkbox element.
+func CheckboxElement(name, value, label string, checked bool) Node {
+	return Label(Class("inline-flex items-center gap-1 hover:cursor-pointer"),
+		Input(
+			Name(name),
+			Type("checkbox"),
+			Value(value),
+			If(checked, Checked()),
+		),
+		Text(label),
+	)
+}
@@ -46,7 +46,7 @@ func buttonClasses() Classes {
 		"mt-3":                 true,
 		"font-bold":            true,
 		"hover:cursor-pointer": true,
-		"hover:bg-amber-600/5": true,
+		"hover:bg-accent/5":    true,
 	})
 
 	return classes
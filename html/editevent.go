@@ -3,38 +3,73 @@ package html
 import (
 	"fmt"
 	"net/url"
+	"slices"
 	"strconv"
 
 	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/pkg/snowflake"
 	. "maragu.dev/gomponents"
+	hx "maragu.dev/gomponents-htmx"
+	. "maragu.dev/gomponents/components"
 	. "maragu.dev/gomponents/html"
 )
 
 // EditEventMain render the edit event page main content.
-func EditEventMain(form contract.EditEventForm, errs url.Values, csrf string) Node {
+// Templates are offered when starting a new event.
+func EditEventMain(l *i18n.Locale, user *domain.User, form contract.EditEventForm, categories []*domain.Category, venues []*domain.Venue, templates []*domain.EventTemplate, errs url.Values, csrf string) Node {
 	return Main(
 		Div(Class("max-w-3xl mx-auto"),
+			Iff(len(templates) > 0, func() Node {
+				return templatePicker(l, form.TemplateID, templates)
+			}),
+
 			Form(ID("edit-form"), Class("w-full px-3 py-4 mx-auto"),
 				Method("POST"),
+				EncType("multipart/form-data"),
 
 				// TODO: refactor this usage of classes
 				H1(components.BaseFormElementClasses(),
-					Text("Status: "),
+					Text(l.T("Status: ")),
 					B(Text(func() string {
+						if form.IsPending {
+							return l.T("pending review")
+						}
+						if form.IsDraft && form.PublishAt != "" {
+							return l.T("scheduled")
+						}
 						if form.IsDraft || form.EventID == 0 {
-							return "draft"
+							return l.T("draft")
 						}
-						return "published"
+						return l.T("published")
 					}())),
 				),
 
-				components.InputElement("title", "text", "Title", form.Title, errs.Get("title"), true, false),
-				components.InputElement("url", "url", "URL", form.URL, errs.Get("url"), false, false),
-				components.DateTimeLocalInput("start_at", form.StartAt, errs.Get("start_at"), true, false),
+				components.InputElement("title", "text", l.T("Title"), form.Title, l.T(errs.Get("title")), true, false),
+
+				Label(Class("block w-full pb-2"), For("language"), Text(l.T("Language of title and description"))),
+				localeSelect("language", form.Language),
+				If(errs.Get("language") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("language"))))),
+
+				components.InputElement("url", "url", l.T("URL"), form.URL, l.T(errs.Get("url")), false, false),
+				components.DateTimeLocalInput("start_at", form.StartAt, l.T(errs.Get("start_at")), true, false),
+				Iff(user != nil && form.IsDraftOrNew(), func() Node {
+					return Group{
+						Label(Class("block w-full pb-2"), For("publish_at"), Text(l.T("Publish the draft at (optional)"))),
+						components.DateTimeLocalInput("publish_at", form.PublishAt, l.T(errs.Get("publish_at")), false, false),
+					}
+				}),
+				components.DataListInputElement("timezone", l.T("Timezone (automatic from location)"), form.Timezone, l.T(errs.Get("timezone")), "timezones"),
+				DataList(ID("timezones"), Data("timezones", "")),
+
+				Iff(len(venues) > 0, func() Node {
+					return venuePicker(l, form.VenueID, venues)
+				}),
 
 				Div(Class("relative"),
-					components.InputElement("location", "text", "Location", form.Location, errs.Get("location"), true, false),
+					components.InputElement("location", "text", l.T("Location"), form.Location, l.T(errs.Get("location")), true, false),
 					Input(Type("hidden"), Name("osm_type"), Value(form.OSMType)),
 					Input(Type("hidden"), Name("osm_id"), Value(strconv.FormatUint(form.OSMID, 10))),
 					Div(ID("location-spinner"), Class("opacity-0 absolute top-0 right-0 h-full flex items-center mr-2"),
@@ -42,43 +77,191 @@ func EditEventMain(form contract.EditEventForm, errs url.Values, csrf string) No
 					),
 				),
 
-				components.TextareaElement("desc", form.Description, errs.Get("desc"), 3, true, false),
+				components.TextareaElement("desc", form.Description, l.T(errs.Get("desc")), 3, true, false),
+
+				// Anonymous submitters can't manage attendees.
+				Iff(user != nil, func() Node {
+					return Group{
+						Label(Class("block w-full pb-2"), For("capacity"), Text(l.T("Maximum number of attendees (0 means unlimited)"))),
+						components.InputElement("capacity", "number", l.T("Capacity"), strconv.Itoa(form.Capacity), l.T(errs.Get("capacity")), false, false),
+					}
+				}),
+
+				eventTranslations(l, form, errs),
+
+				eventAttachmentsInput(l, form, errs),
+
+				Iff(len(categories) > 0, func() Node {
+					return FieldSet(components.BaseFormElementClasses(),
+						Legend(Class("font-semibold"), Text(l.T("Categories"))),
+						Div(Class("flex flex-wrap gap-x-4 gap-y-1"),
+							Map(categories, func(c *domain.Category) Node {
+								return components.CheckboxElement("categories", c.Name, c.Name, slices.Contains(form.Categories, c.Name))
+							}),
+						),
+					)
+				}),
 
 				Input(Type("hidden"), Name("csrf"), Value(csrf)),
 				Input(Type("hidden"), Name("easymde_cache_key"), Value(form.EventID.String())),
+				If(form.DuplicateOf > 0, Input(Type("hidden"), Name("duplicate"), Value(form.DuplicateOf.String()))),
+				If(form.TemplateID > 0, Input(Type("hidden"), Name("template"), Value(form.TemplateID.String()))),
 				Input(Type("hidden"), Name("latitude"), Value(strconv.FormatFloat(form.Latitude, 'f', -1, 64))),
 				Input(Type("hidden"), Name("longitude"), Value(strconv.FormatFloat(form.Longitude, 'f', -1, 64))),
 
-				Input(Type("hidden"), Name("timezone_offset"), Value(strconv.FormatInt(int64(form.TimezoneOffset), 10))),
 				Input(Type("hidden"), Name("user_timezone")),
 				Script(Raw(`document.querySelector('[name="user_timezone"]').value = Intl.DateTimeFormat().resolvedOptions().timeZone`)),
 
+				// Anonymous submission.
+				Iff(user == nil, func() Node {
+					return components.SubmitButtonElement(l.T("Submit"),
+						FormAction("/edit/0?draft=0"),
+					)
+				}),
+
 				// Draft or new event.
-				Iff(form.IsDraftOrNew(), func() Node {
+				Iff(user != nil && form.IsDraftOrNew(), func() Node {
 					return Group{
-						components.SubmitButtonElement("Save Draft",
+						components.SubmitButtonElement(l.T("Save Draft"),
 							FormAction(fmt.Sprintf("/edit/%s?draft=1", form.EventID.String())),
 						),
-						components.SubmitButtonElement("Publish",
-							Attr("onclick", "return confirm('Confirm publishing this event')"),
+						components.SubmitButtonElement(l.T("Publish"),
+							Attr("onclick", fmt.Sprintf("return confirm(%q)", l.T("Confirm publishing this event"))),
 							FormAction(fmt.Sprintf("/edit/%s?draft=0", form.EventID.String())),
 						),
 					}
 				}),
 
 				// Already published event.
-				Iff(!form.IsDraftOrNew(), func() Node {
+				Iff(user != nil && !form.IsDraftOrNew(), func() Node {
 					return Group{
-						components.SubmitButtonElement("Save",
+						components.SubmitButtonElement(l.T("Save"),
 							FormAction(fmt.Sprintf("/edit/%s?draft=0", form.EventID.String())),
 						),
-						components.SubmitButtonElement("Unpublish",
-							Attr("onclick", "return confirm('Confirm unpublishing this event')"),
+						components.SubmitButtonElement(l.T("Unpublish"),
+							Attr("onclick", fmt.Sprintf("return confirm(%q)", l.T("Confirm unpublishing this event"))),
 							FormAction(fmt.Sprintf("/edit/%s?draft=1", form.EventID.String())),
 						),
 					}
 				}),
+
+				Iff(user != nil, func() Node {
+					return saveTemplateInput(l, user)
+				}),
 			),
 		),
 	)
 }
+
+// eventTranslations renders title and description translations with a language switcher.
+// The tab of the event language is hidden.
+func eventTranslations(l *i18n.Locale, form contract.EditEventForm, errs url.Values) Node {
+	active := ""
+	for _, locale := range i18n.Locales {
+		if locale.Code() != form.Language {
+			active = locale.Code()
+			break
+		}
+	}
+
+	return FieldSet(ID("translations"), components.BaseFormElementClasses(),
+		Legend(Class("font-semibold"), Text(l.T("Translations"))),
+		If(errs.Get("translation_language") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("translation_language"))))),
+
+		Div(Class("flex flex-wrap gap-2 pb-2"), Role("tablist"),
+			Map(i18n.Locales, func(locale *i18n.Locale) Node {
+				return translationTab(locale, locale.Code() == active, locale.Code() == form.Language)
+			}),
+		),
+
+		Map(i18n.Locales, func(locale *i18n.Locale) Node {
+			t := form.GetTranslation(locale.Code())
+
+			return Div(Role("tabpanel"), Data("language", locale.Code()),
+				If(locale.Code() != active, Class("hidden")),
+				Input(Type("hidden"), Name("translation_language"), Value(locale.Code())),
+				components.InputElement("translation_title", "text", l.T("Title"), t.Title, "", false, false),
+				components.TextareaElement("translation_desc", t.Description, "", 3, false, false),
+			)
+		}),
+	)
+}
+
+func translationTab(locale *i18n.Locale, active, hidden bool) Node {
+	return Button(Classes{
+		"py-1":              true,
+		"px-3":              true,
+		"rounded-full":      true,
+		"font-semibold":     true,
+		"text-white":        active,
+		"bg-accent":         active,
+		"text-gray-400":     !active,
+		"hover:text-accent": !active,
+		"hidden":            hidden,
+	},
+		Type("button"),
+		Role("tab"),
+		Data("language", locale.Code()),
+		Aria("selected", strconv.FormatBool(active)),
+		Text(locale.Name()),
+	)
+}
+
+// eventAttachmentsInput renders the current attachments with keep checkboxes and a file input for new ones.
+func eventAttachmentsInput(l *i18n.Locale, form contract.EditEventForm, errs url.Values) Node {
+	return FieldSet(components.BaseFormElementClasses(),
+		Legend(Class("font-semibold"), Text(l.T("Images and documents"))),
+		If(errs.Get("attachments") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("attachments"))))),
+
+		Iff(len(form.Attachments) > 0, func() Node {
+			return Div(Class("flex flex-wrap gap-4 pb-2"),
+				Map(form.Attachments, func(a domain.Attachment) Node {
+					return Label(Class("flex items-center gap-2"),
+						Input(Type("checkbox"), Name("keep_attachment"), Value(a.Hash), If(slices.Contains(form.KeepAttachments, a.Hash), Checked())),
+						If(a.IsImage(), Img(Class("h-12"), Src(a.GetThumbnailURL()), Alt(a.Name))),
+						Text(a.Name),
+					)
+				}),
+			)
+		}),
+
+		Input(Class("py-2"), ID("attachments"), Name("attachments"), Type("file"), Multiple(),
+			Accept("image/jpeg,image/png,image/gif,image/webp,application/pdf"),
+		),
+		P(Class("text-sm text-gray-500"),
+			Text(l.T("Posters and PDF programs, up to %d files of %d MB each.", contract.MaxAttachments, contract.MaxAttachmentSize>>20)),
+		),
+	)
+}
+
+// templatePicker renders links to start a new event from a template.
+func templatePicker(l *i18n.Locale, current snowflake.ID, templates []*domain.EventTemplate) Node {
+	return Div(Class("flex flex-wrap items-center gap-2 px-3 pt-4"),
+		Span(Class("font-semibold"), Text(l.T("Start from a template:"))),
+		Map(templates, func(t *domain.EventTemplate) Node {
+			return components.Chip(t.Name, fmt.Sprintf("/edit/0?template=%d", t.ID),
+				If(t.ID == current, Aria("current", "true")),
+			)
+		}),
+	)
+}
+
+// saveTemplateInput renders the fields to save the form as a template.
+// The form is posted with htmx and the result is rendered in place.
+func saveTemplateInput(l *i18n.Locale, user *domain.User) Node {
+	return FieldSet(components.BaseFormElementClasses(),
+		Legend(Class("font-semibold"), Text(l.T("Template"))),
+		P(Class("text-sm text-gray-500 pb-2"),
+			Text(l.T("Save the title, description, location and categories for future events. %s in the title is replaced with the event date.", domain.TemplateDatePlaceholder)),
+		),
+		components.InputElement("template_name", "text", l.T("Template name"), "", "", false, false),
+		If(user.Role == domain.Admin,
+			Div(Class("pb-2"), components.CheckboxElement("template_site_wide", "true", l.T("Available to all users"), false)),
+		),
+		components.ButtonElement(l.T("Save as template"),
+			hx.Post("/templates/new"),
+			hx.Target("#save-template-result"),
+		),
+		Div(ID("save-template-result")),
+	)
+}
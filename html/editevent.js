@@ -1,8 +1,14 @@
 /* global EasyMDE */
-/* global GeoSearch */
 /* global $ */
 
 document.addEventListener("DOMContentLoaded", () => {
+  setupEditor();
+  setupLocationSearch();
+  setupVenuePicker();
+  setupTranslations();
+});
+
+function setupEditor() {
   const el = document.querySelector('textarea[name="desc"]');
   const cacheKeyInput = document.querySelector('[name="easymde_cache_key"]');
 
@@ -66,8 +72,11 @@ document.addEventListener("DOMContentLoaded", () => {
       return "Loading...";
     },
   });
+}
 
-  $('[name="location"]').autocomplete({
+function setupLocationSearch() {
+  // Event location or venue address.
+  $('[name="location"], [name="address"]').autocomplete({
     source: function (request, response) {
       const query = request.term.trim();
       if (!query) {
@@ -77,17 +86,22 @@ document.addEventListener("DOMContentLoaded", () => {
 
       $("#location-spinner").css("opacity", "1");
 
-      const providerform = new GeoSearch.OpenStreetMapProvider({
-        params: {
-          limit: 5,
-          // TODO: configure i18n in the calendar application.
-          "accept-language": "en",
-        },
-      });
-      return providerform
-        .search({ query })
+      // Searched on the server which caches results and respects provider rate limits.
+      return fetch(`/geocode?${new URLSearchParams({ q: query })}`)
+        .then(function (res) {
+          if (!res.ok) {
+            throw new Error(`${res.status} - ${res.statusText}`);
+          }
+          return res.json();
+        })
         .then(function (results) {
-          response(results);
+          response(
+            results.map((place) => ({
+              label: place.label,
+              value: place.label,
+              place: place,
+            })),
+          );
         })
         .catch(function (error) {
           response([]);
@@ -98,10 +112,99 @@ document.addEventListener("DOMContentLoaded", () => {
         });
     },
     select: function (_, ui) {
-      document.querySelector('[name="osm_type"]').value = ui.item.raw.osm_type;
-      document.querySelector('[name="osm_id"]').value = ui.item.raw.osm_id;
+      const form = this.form;
+      form.elements["osm_type"].value = ui.item.place.osm_type;
+      form.elements["osm_id"].value = ui.item.place.osm_id;
+      form.elements["latitude"].value = ui.item.place.latitude;
+      form.elements["longitude"].value = ui.item.place.longitude;
+
+      // A searched location replaces the venue.
+      if (form.elements["venue_id"]) {
+        form.elements["venue_id"].value = "0";
+      }
     },
     delay: 1000,
     minLength: 3,
   });
-});
+
+  // Typed locations are geocoded on the server when saved.
+  $('[name="location"], [name="address"]').on("input", function () {
+    const form = this.form;
+    form.elements["osm_type"].value = "";
+    form.elements["osm_id"].value = "0";
+    form.elements["latitude"].value = "0";
+    form.elements["longitude"].value = "0";
+  });
+}
+
+function setupVenuePicker() {
+  const picker = document.querySelector('select[name="venue_id"]');
+  if (!picker) {
+    return;
+  }
+
+  const location = picker.form.elements["location"];
+
+  picker.addEventListener("change", () => {
+    const option = picker.selectedOptions[0];
+    if (!option || option.value === "0") {
+      return;
+    }
+
+    const form = picker.form;
+    location.value = option.dataset.location;
+    form.elements["osm_type"].value = option.dataset.osmType;
+    form.elements["osm_id"].value = option.dataset.osmId;
+    form.elements["latitude"].value = option.dataset.latitude;
+    form.elements["longitude"].value = option.dataset.longitude;
+  });
+
+  // Typing a different location detaches the event from the venue.
+  location.addEventListener("input", () => {
+    picker.value = "0";
+  });
+}
+
+function setupTranslations() {
+  const fieldset = document.getElementById("translations");
+  if (!fieldset) {
+    return;
+  }
+
+  const language = fieldset.form.elements["language"];
+  const tabs = fieldset.querySelectorAll('[role="tab"]');
+  const panels = fieldset.querySelectorAll('[role="tabpanel"]');
+
+  const activeClasses = ["text-white", "bg-accent"];
+  const inactiveClasses = ["text-gray-400", "hover:text-accent"];
+
+  function activate(code) {
+    tabs.forEach((tab) => {
+      const active = tab.dataset.language === code;
+      tab.setAttribute("aria-selected", active);
+      tab.classList.remove(...(active ? inactiveClasses : activeClasses));
+      tab.classList.add(...(active ? activeClasses : inactiveClasses));
+    });
+
+    panels.forEach((panel) => {
+      panel.classList.toggle("hidden", panel.dataset.language !== code);
+    });
+  }
+
+  tabs.forEach((tab) => {
+    tab.addEventListener("click", () => activate(tab.dataset.language));
+  });
+
+  // The event language has no translation.
+  language.addEventListener("change", () => {
+    tabs.forEach((tab) => {
+      tab.classList.toggle("hidden", tab.dataset.language === language.value);
+    });
+
+    const selected = fieldset.querySelector('[role="tab"][aria-selected="true"]');
+    if (!selected || selected.dataset.language === language.value) {
+      const first = fieldset.querySelector('[role="tab"]:not(.hidden)');
+      activate(first ? first.dataset.language : "");
+    }
+  });
+}
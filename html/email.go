@@ -0,0 +1,108 @@
+package html
+
+import (
+	"strings"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// Email is an email with text and HTML alternatives.
+type Email struct {
+	Subject string
+	Text    string
+	Body    Node
+}
+
+// InviteEmail renders an invitation to register.
+func InviteEmail(l *i18n.Locale, siteTitle, link string) Email {
+	intro := l.T("You have been invited to add events to %s.", siteTitle)
+	action := l.T("Register with this one-time link:")
+	validity := l.T("The link is valid for 72 hours.")
+
+	return Email{
+		Subject: l.T("Invitation to %s", siteTitle),
+		Text:    emailText(intro, action+"\n"+link, validity),
+		Body: emailDocument(l, siteTitle,
+			P(Text(intro)),
+			P(Text(action)),
+			emailButton(link, l.T("Register")),
+			P(Text(validity)),
+		),
+	}
+}
+
+// PasswordResetEmail renders a password reset link.
+func PasswordResetEmail(l *i18n.Locale, siteTitle, username, link string) Email {
+	intro := l.T("A password reset was requested for the user %s.", username)
+	action := l.T("Choose a new password with this one-time link:")
+	validity := l.T("The link is valid for an hour. If you did not request the reset, ignore this email.")
+
+	return Email{
+		Subject: l.T("Password reset for %s", siteTitle),
+		Text:    emailText(intro, action+"\n"+link, validity),
+		Body: emailDocument(l, siteTitle,
+			P(Text(intro)),
+			P(Text(action)),
+			emailButton(link, l.T("Reset password")),
+			P(Text(validity)),
+		),
+	}
+}
+
+// ModerationEmail renders the moderation decision of an event for its author.
+func ModerationEmail(l *i18n.Locale, siteTitle string, ev *domain.Event, approved bool, link string) Email {
+	var subject, message string
+
+	if approved {
+		subject = l.T("Your event was approved")
+		message = l.T("Your event %s was approved.", ev.Title)
+		if ev.IsDraft {
+			message += " " + l.T("It will be published at %s.", l.FormatDateTime(ev.PublishAt.In(ev.StartAt.Location())))
+		}
+	} else {
+		subject = l.T("Your event was not approved")
+		message = l.T("Your event %s was not approved and was returned to your drafts.", ev.Title)
+	}
+
+	return Email{
+		Subject: subject,
+		Text:    emailText(message, link),
+		Body: emailDocument(l, siteTitle,
+			P(Text(message)),
+			emailButton(link, l.T("View event")),
+		),
+	}
+}
+
+// emailText joins paragraphs of a text email.
+func emailText(paragraphs ...string) string {
+	return strings.Join(paragraphs, "\n\n") + "\n"
+}
+
+// emailDocument renders an HTML email document. Email clients
+// ignore stylesheets so the styles are inline.
+func emailDocument(l *i18n.Locale, siteTitle string, children ...Node) Node {
+	return HTML5(HTML5Props{
+		Title:    siteTitle,
+		Language: l.Code(),
+		Body: []Node{
+			Div(Style("max-width:600px;margin:0 auto;padding:16px;font-family:sans-serif;line-height:1.5;color:#1f2937"),
+				H1(Style("font-size:20px"), Text(siteTitle)),
+				Group(children),
+			),
+		},
+	})
+}
+
+func emailButton(link, label string) Node {
+	return P(
+		A(Style("display:inline-block;padding:8px 16px;border-radius:8px;background:#d97706;color:#ffffff;font-weight:bold;text-decoration:none"),
+			Href(link),
+			Text(label),
+		),
+	)
+}
@@ -0,0 +1,185 @@
+package html
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// EmbedScript is the script partner sites include to show the embedded event list.
+//
+//go:embed embed.js
+var EmbedScript string
+
+//go:embed embedframe.js
+var embedFrameScript string
+
+// EmbedPage renders the embedded list of upcoming events.
+// Links open the events on this site in a new window.
+func EmbedPage(l *i18n.Locale, settings *domain.Settings, events []*domain.Event, baseURL string, themed bool) Node {
+	return HTML5(HTML5Props{
+		Title:    settings.Title,
+		Language: l.Code(),
+		Head: []Node{
+			Meta(Name("robots"), Content("noindex")),
+			Iff(themed, func() Node {
+				return Group{
+					Link(Rel("stylesheet"), Href(calendar.GetAssetPath("app.css"))),
+					If(settings.AccentColor != "",
+						StyleEl(Raw(":root { --accent: "+settings.AccentColor+"; }")),
+					),
+				}
+			}),
+		},
+		Body: []Node{
+			Main(If(themed, Class("px-3 py-2")),
+				If(len(events) == 0,
+					P(If(themed, Class("py-2 text-gray-500")), Text(l.T("No events"))),
+				),
+				Ul(
+					Map(events, func(ev *domain.Event) Node {
+						return Li(If(themed, Class("py-2 border-b border-gray-200")),
+							Time(If(themed, Class("block text-sm text-gray-500")),
+								DateTime(ev.StartAt.Format(time.RFC3339)),
+								Text(l.FormatDateTime(ev.StartAt)),
+							),
+							A(If(themed, Class("block font-semibold text-accent hover:underline")),
+								Href(fmt.Sprintf("%s/event/%d", baseURL, ev.ID)),
+								Target("_blank"),
+								Rel("noopener"),
+								Text(ev.Title),
+							),
+							If(ev.Location != "",
+								P(If(themed, Class("text-sm")), Text(ev.Location)),
+							),
+						)
+					}),
+				),
+				P(If(themed, Class("py-2 text-sm")),
+					A(If(themed, Class("text-accent hover:underline")),
+						Href(baseURL+"/"),
+						Target("_blank"),
+						Rel("noopener"),
+						Text(l.T("More events on %s", settings.Title)),
+					),
+				),
+			),
+			Script(Raw(embedFrameScript)),
+		},
+	})
+}
+
+// EmbedSnippetMain renders the embed snippet generator.
+func EmbedSnippetMain(
+	l *i18n.Locale,
+	settings *domain.Settings,
+	categories []*domain.Category,
+	venues []*domain.Venue,
+	req contract.EmbedRequest,
+	baseURL string,
+) Node {
+	query := req.Query().Encode()
+
+	embedURL := baseURL + "/embed"
+	eventsURL := baseURL + "/embed/events.json"
+
+	if query != "" {
+		embedURL += "?" + query
+		eventsURL += "?" + query
+	}
+
+	snippet := fmt.Sprintf(
+		"<div data-calendar-embed=\"%s\" data-calendar-title=\"%s\"></div>\n<script async src=\"%s\"></script>",
+		template.HTMLEscapeString(embedURL),
+		template.HTMLEscapeString(settings.Title),
+		template.HTMLEscapeString(baseURL+"/embed.js"),
+	)
+
+	var tag string
+	if len(req.Tags) > 0 {
+		tag = req.Tags[0]
+	}
+
+	var limit string
+	if req.Limit > 0 {
+		limit = strconv.Itoa(req.GetLimit())
+	}
+
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			Form(Class("w-full py-4"),
+				Method("GET"),
+				Action("/embed/snippet"),
+
+				P(Class("pb-2"), Text(l.T("Show upcoming events on partner sites. Choose the events and copy the snippet to the partner site."))),
+
+				Label(Class("block w-full pb-2"), For("category"), Text(l.T("Category"))),
+				Select(components.BaseFormElementClasses(), ID("category"), Name("category"),
+					Option(Value(""), Text(l.T("All categories"))),
+					Map(categories, func(c *domain.Category) Node {
+						return Option(Value(c.Name), If(c.Name == req.Category, Selected()), Text(c.Name))
+					}),
+				),
+
+				Label(Class("block w-full pb-2"), For("venue"), Text(l.T("Venue"))),
+				Select(components.BaseFormElementClasses(), ID("venue"), Name("venue"),
+					Option(Value(""), Text(l.T("All venues"))),
+					Map(venues, func(v *domain.Venue) Node {
+						return Option(Value(v.ID.String()), If(v.ID == req.Venue, Selected()), Text(v.Name))
+					}),
+				),
+
+				Label(Class("block w-full pb-2"), For("tag"), Text(l.T("Tag"))),
+				components.InputElement("tag", "text", l.T("Tag"), tag, "", false, false),
+
+				Label(Class("block w-full pb-2"), For("limit"), Text(l.T("Number of events"))),
+				components.InputElement("limit", "number", strconv.Itoa(contract.EmbedDefaultLimit), limit, "", false, false),
+
+				Label(Class("block w-full pb-2"), For("lang"), Text(l.T("Language"))),
+				Select(components.BaseFormElementClasses(), ID("lang"), Name("lang"),
+					Option(Value(""), Text(l.T("Language of the visitor"))),
+					Map(i18n.Locales, func(locale *i18n.Locale) Node {
+						return Option(Value(locale.Code()), If(locale.Code() == req.Language, Selected()), Text(locale.Name()))
+					}),
+				),
+
+				Label(Class("block w-full pb-2"), For("theme"), Text(l.T("Style"))),
+				Select(components.BaseFormElementClasses(), ID("theme"), Name("theme"),
+					Option(Value(""), Text(l.T("Site theme"))),
+					Option(Value(contract.EmbedThemeNone), If(req.Theme == contract.EmbedThemeNone, Selected()), Text(l.T("No styles"))),
+				),
+
+				components.SubmitButtonElement(l.T("Generate")),
+			),
+
+			If(len(settings.EmbedOrigins) == 0,
+				P(Class("py-2 text-red-500"),
+					Text(l.T("No sites are allowed to embed the event list. Add the partner sites in the ")),
+					A(Class("text-accent hover:underline"), Href("/settings"), Text(l.T("settings"))),
+					Text("."),
+				),
+			),
+
+			Label(Class("block w-full pb-2"), For("snippet"), Text(l.T("Snippet"))),
+			Textarea(components.BaseFormElementClasses(), ID("snippet"), Rows("3"), ReadOnly(), Text(snippet)),
+
+			P(Class("py-2 text-sm text-gray-500 break-all"),
+				Text(l.T("Custom renderers can fetch the events as JSON from %s", eventsURL)),
+			),
+
+			P(Class("font-semibold py-2"), Text(l.T("Preview"))),
+			IFrame(Class("w-full h-96 border border-gray-200"), Src(embedURL), Title(settings.Title)),
+		),
+	)
+}
@@ -0,0 +1,34 @@
+// Show the event lists of the calendar on partner sites.
+// Each element with a data-calendar-embed attribute gets a frame of the list URL
+// which is resized to fit the list.
+(function () {
+  const frames = [];
+
+  document.querySelectorAll("[data-calendar-embed]").forEach((el) => {
+    if (el.querySelector("iframe")) {
+      return;
+    }
+
+    const frame = document.createElement("iframe");
+    frame.src = el.dataset.calendarEmbed;
+    frame.title = el.dataset.calendarTitle || "Events";
+    frame.loading = "lazy";
+    frame.style.width = "100%";
+    frame.style.border = "0";
+
+    el.appendChild(frame);
+    frames.push(frame);
+  });
+
+  window.addEventListener("message", (e) => {
+    if (!e.data || e.data.type !== "calendar-embed-height") {
+      return;
+    }
+
+    for (const frame of frames) {
+      if (frame.contentWindow === e.source) {
+        frame.style.height = `${e.data.height}px`;
+      }
+    }
+  });
+})();
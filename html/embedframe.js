@@ -0,0 +1,19 @@
+// Report the height of the embedded event list to the embedding page.
+(function () {
+  if (window.parent === window) {
+    return;
+  }
+
+  const report = () => {
+    window.parent.postMessage(
+      {
+        type: "calendar-embed-height",
+        height: document.documentElement.scrollHeight,
+      },
+      "*",
+    );
+  };
+
+  window.addEventListener("load", report);
+  new ResizeObserver(report).observe(document.body);
+})();
@@ -1,15 +1,16 @@
 package html
 
 import (
+	"github.com/mgnsk/calendar/i18n"
 	. "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/html"
 )
 
 // ErrorMain renders the error page main content.
-func ErrorMain(text string) Node {
+func ErrorMain(l *i18n.Locale, code int, msg string) Node {
 	return Main(
 		Div(Class("max-w-3xl mx-auto"),
-			H1(Text(text)),
+			H1(Text(l.T("Error %d: %s", code, l.T(msg)))),
 		),
 	)
 }
@@ -10,8 +10,9 @@ import (
 
 	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/mgnsk/calendar/pkg/markdown"
-	"github.com/mgnsk/calendar/pkg/timestamp"
 	. "maragu.dev/gomponents"
 	hx "maragu.dev/gomponents-htmx"
 	. "maragu.dev/gomponents/components"
@@ -35,20 +36,21 @@ func EventsMain(csrf string) Node {
 }
 
 // EventListPartial renders the event list partial.
-func EventListPartial(user *domain.User, offset int64, events []*domain.Event, csrf string) Node {
+// The tz parameter is the viewer time zone or nil if not known.
+func EventListPartial(l *i18n.Locale, user *domain.User, tz *time.Location, offset int64, events []*domain.Event, csrf string) Node {
 	if len(events) == 0 {
 		return Div(Class("px-3 py-4 text-center"),
-			P(Text("reached the end...")),
+			P(Text(l.T("reached the end..."))),
 		)
 	}
 
 	return Group{
 		Map(events, func(ev *domain.Event) Node {
-			return EventCard(user, ev, csrf)
+			return EventCard(l, user, tz, ev, csrf)
 		}),
 		Div(ID("load-more"),
 			hx.Post(""),
-			hx.Include("[name='search']"), // CSS query to include data from inputs.
+			hx.Include("[name='search'], [name='sort']"), // CSS query to include data from inputs.
 			hx.Vals(string(must(json.Marshal(map[string]string{
 				"csrf":    csrf,
 				"last_id": events[len(events)-1].ID.String(),
@@ -62,8 +64,33 @@ func EventListPartial(user *domain.User, offset int64, events []*domain.Event, c
 	}
 }
 
+// EventListErrorPartial renders an error message in place of the event list.
+func EventListErrorPartial(l *i18n.Locale, msg string) Node {
+	return Div(Class("px-3 py-4 text-center text-red-500"),
+		P(Text(l.T(msg))),
+	)
+}
+
+// EventMain renders the event page main content with all images in full size.
+// The rsvp parameter is the current response of the user or nil.
+func EventMain(l *i18n.Locale, user *domain.User, tz *time.Location, ev *domain.Event, rsvp *domain.RSVP, anonymousRSVP bool, form contract.RSVPForm, errs url.Values, csrf string) Node {
+	return Main(
+		eventCard(l, user, tz, ev, csrf, true),
+		rsvpSection(l, user, ev, rsvp, anonymousRSVP, form, errs, csrf),
+	)
+}
+
 // EventCard renders the event card.
-func EventCard(user *domain.User, ev *domain.Event, csrf string) Node {
+// The tz parameter is the viewer time zone or nil if not known.
+// Translated title and description are shown in the locale language.
+func EventCard(l *i18n.Locale, user *domain.User, tz *time.Location, ev *domain.Event, csrf string) Node {
+	return eventCard(l, user, tz, ev, csrf, false)
+}
+
+// eventCard renders the event card with the poster thumbnail or with all images when full is set.
+func eventCard(l *i18n.Locale, user *domain.User, tz *time.Location, ev *domain.Event, csrf string, full bool) Node {
+	ev = ev.Translate(l.Code())
+
 	inPast := ev.StartAt.Before(time.Now())
 
 	return Div(
@@ -83,26 +110,40 @@ func EventCard(user *domain.User, ev *domain.Event, csrf string) Node {
 		},
 		Div(Class("py-4 md:py-8 px-3 md:px-6 items-center grid grid-cols-7"),
 			Div(Class("pr-4 col-span-1 hidden sm:inline-block"),
-				eventDay(ev),
+				eventDay(l, ev),
 			),
 			Div(Class("col-span-7 sm:col-span-6"),
-				eventTitle(ev),
-				eventDate(ev),
+				eventTitle(l, ev),
+				eventDate(l, ev, tz),
 				eventLocation(ev),
+				eventAttendance(l, ev),
+				eventSnippet(ev),
+				If(!full, eventPoster(ev)),
 				eventDesc(ev),
+				If(full, eventImages(ev)),
+				eventFiles(ev),
+				eventCategories(ev),
 				If(user != nil && (user.Role == domain.Admin || user.ID == ev.UserID), Div(Class("mt-5 flex justify-between"),
-					A(Class("hover:underline text-amber-600 font-semibold"),
+					A(Class("hover:underline text-accent font-semibold"),
 						Href(fmt.Sprintf("/edit/%d", ev.ID)),
-						Text("EDIT"),
+						Text(l.T("EDIT")),
 					),
-					A(Class("hover:underline text-amber-600 font-semibold"),
+					A(Class("hover:underline text-accent font-semibold"),
+						Href(fmt.Sprintf("/edit/0?duplicate=%d", ev.ID)),
+						Text(l.T("DUPLICATE")),
+					),
+					If(!ev.IsDraft, A(Class("hover:underline text-accent font-semibold"),
+						Href(fmt.Sprintf("/event/%d/attendees", ev.ID)),
+						Text(l.T("ATTENDEES")),
+					)),
+					A(Class("hover:underline text-accent font-semibold"),
 						hx.Post(fmt.Sprintf("/delete/%d", ev.ID)),
-						hx.Confirm("Are you sure?"),
+						hx.Confirm(l.T("Are you sure?")),
 						hx.Vals(string(must(json.Marshal(map[string]string{
 							"csrf": csrf,
 						})))),
 						Href("#"),
-						Text("DELETE"),
+						Text(l.T("DELETE")),
 					),
 				)),
 			),
@@ -110,10 +151,15 @@ func EventCard(user *domain.User, ev *domain.Event, csrf string) Node {
 	)
 }
 
-func eventTitle(ev *domain.Event) Node {
+func eventTitle(l *i18n.Locale, ev *domain.Event) Node {
 	title := ev.Title
-	if ev.IsDraft {
-		title = fmt.Sprintf("[Draft] %s", ev.Title)
+	switch {
+	case ev.IsPending:
+		title = l.T("[Pending review] %s", ev.Title)
+	case ev.IsScheduled():
+		title = l.T("[Scheduled for %s] %s", l.FormatDateTime(ev.PublishAt.In(ev.StartAt.Location())), ev.Title)
+	case ev.IsDraft:
+		title = l.T("[Draft] %s", ev.Title)
 	}
 
 	return H1(Class("tracking-wide text-xl md:text-2xl font-semibold"),
@@ -128,17 +174,16 @@ func eventTitle(ev *domain.Event) Node {
 
 func eventLocation(ev *domain.Event) Node {
 	return Iff(ev.Location != "", func() Node {
-		u, err := url.Parse("http://maps.google.com")
-		if err != nil {
-			panic(err)
+		if ev.VenueID > 0 {
+			return A(Class("hover:underline"), Href(fmt.Sprintf("/venue/%d", ev.VenueID)),
+				Div(Class("block mt-2 tracking-wide text-sm text-gray-400"),
+					I(Class("fa fa-location-arrow pr-1"), Aria("hidden", "true")),
+					Text(ev.Location),
+				),
+			)
 		}
 
-		q := url.Values{}
-		q.Set("q", ev.Location)
-
-		u.RawQuery = q.Encode()
-
-		return A(Class("hover:underline"), Rel("noopener noreferrer"), Target("_blank"), Href(u.String()),
+		return A(Class("hover:underline"), Rel("noopener noreferrer"), Target("_blank"), Href(mapsURL(ev.Location)),
 			Div(Class("block mt-2 tracking-wide text-sm text-gray-400"),
 				I(Class("fa fa-location-arrow pr-1"), Aria("hidden", "true")),
 				Text(ev.Location),
@@ -147,6 +192,33 @@ func eventLocation(ev *domain.Event) Node {
 	})
 }
 
+func mapsURL(location string) string {
+	u, err := url.Parse("http://maps.google.com")
+	if err != nil {
+		panic(err)
+	}
+
+	q := url.Values{}
+	q.Set("q", location)
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func eventSnippet(ev *domain.Event) Node {
+	return Iff(len(ev.Snippet) > 0, func() Node {
+		return P(Class("event-snippet mt-2 text-sm italic text-gray-500"),
+			Map(ev.Snippet, func(fragment domain.SnippetFragment) Node {
+				if fragment.Match {
+					return Mark(Text(fragment.Text))
+				}
+				return Text(fragment.Text)
+			}),
+		)
+	})
+}
+
 func eventDesc(ev *domain.Event) Node {
 	return Div(Class("text-justify"),
 		Div(Class("mt-2 text-gray-700 [&>p]:py-3 [&_a:hover]:underline"), NodeFunc(func(w io.Writer) error {
@@ -158,18 +230,111 @@ func eventDesc(ev *domain.Event) Node {
 	)
 }
 
-func eventDay(ev *domain.Event) Node {
-	day := ev.StartAt.Day()
+// eventPoster renders the thumbnail of the first image linking to the event page.
+func eventPoster(ev *domain.Event) Node {
+	poster := ev.GetPoster()
+	if poster == nil {
+		return nil
+	}
+
+	return A(Href(fmt.Sprintf("/event/%d", ev.ID)),
+		Img(Class("mt-3 max-h-64 rounded-lg"), Src(poster.GetThumbnailURL()), Alt(poster.Name), Loading("lazy")),
+	)
+}
+
+func eventImages(ev *domain.Event) Node {
+	return Map(ev.Attachments, func(a domain.Attachment) Node {
+		if !a.IsImage() {
+			return nil
+		}
+
+		return A(Href(a.GetURL()), Target("_blank"),
+			Img(Class("mt-3 w-full rounded-lg"), Src(a.GetURL()), Alt(a.Name),
+				Width(strconv.Itoa(a.Width)), Height(strconv.Itoa(a.Height)),
+				Loading("lazy"),
+			),
+		)
+	})
+}
+
+// eventFiles renders download links of attachments other than images.
+func eventFiles(ev *domain.Event) Node {
+	return Map(ev.Attachments, func(a domain.Attachment) Node {
+		if a.IsImage() {
+			return nil
+		}
+
+		return A(Class("block mt-2 text-sm hover:underline"), Href(a.GetURL()), Target("_blank"),
+			I(Class("fa fa-file-pdf-o pr-1"), Aria("hidden", "true")),
+			Text(a.Name),
+		)
+	})
+}
+
+func eventCategories(ev *domain.Event) Node {
+	return Iff(len(ev.Categories) > 0, func() Node {
+		return Div(Class("mt-3 flex flex-wrap gap-2"),
+			Map(ev.Categories, func(name string) Node {
+				q := url.Values{}
+				q.Set("category", name)
+
+				return components.Chip(name, "/?"+q.Encode())
+			}),
+		)
+	})
+}
 
+func eventDay(l *i18n.Locale, ev *domain.Event) Node {
 	return P(Class("text-2xl md:text-4xl font-bold text-center"),
-		Text(timestamp.FormatDay(day)),
+		Text(l.FormatDay(ev.StartAt.Day())),
 	)
 }
 
-func eventDate(ev *domain.Event) Node {
+func eventDate(l *i18n.Locale, ev *domain.Event, tz *time.Location) Node {
+	differs := viewerTimeDiffers(ev, tz)
+
 	return Group{
-		H2(Class("block mt-2 uppercase tracking-wide text-sm text-amber-600 font-semibold"),
-			Text(ev.GetDateString()),
+		H2(Class("block mt-2 uppercase tracking-wide text-sm text-accent font-semibold"),
+			Text(l.FormatDateTime(ev.StartAt)),
+			If(differs, Text(" "+ev.StartAt.Format("MST"))),
+			relativeDay(l, ev, tz),
 		),
+		viewerDate(l, ev, tz, "text-sm text-gray-500"),
 	}
 }
+
+// viewerDate renders the event start time in the viewer time zone if it differs from the event time zone.
+func viewerDate(l *i18n.Locale, ev *domain.Event, tz *time.Location, class string) Node {
+	return Iff(viewerTimeDiffers(ev, tz), func() Node {
+		viewerTime := ev.StartAt.In(tz)
+
+		return P(Class(class),
+			Text(l.T("Your time: %s", l.FormatDateTime(viewerTime)+" "+viewerTime.Format("MST"))),
+		)
+	})
+}
+
+func relativeDay(l *i18n.Locale, ev *domain.Event, tz *time.Location) Node {
+	if tz == nil {
+		return nil
+	}
+
+	return Iff(!ev.IsDraft, func() Node {
+		label := l.RelativeDay(ev.StartAt, time.Now().In(tz))
+
+		return If(label != "", Span(Class("ml-2 normal-case font-normal text-gray-500"), Text(label)))
+	})
+}
+
+// viewerTimeDiffers reports whether the event start time has a different
+// UTC offset in the viewer time zone.
+func viewerTimeDiffers(ev *domain.Event, tz *time.Location) bool {
+	if tz == nil {
+		return false
+	}
+
+	_, offset := ev.StartAt.Zone()
+	_, viewerOffset := ev.StartAt.In(tz).Zone()
+
+	return offset != viewerOffset
+}
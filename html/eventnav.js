@@ -3,8 +3,8 @@ function changeTab(link) {
   // Make all links inactive.
   document.querySelectorAll(".nav-link").forEach(function (el) {
     el.classList.add("text-gray-400");
-    el.classList.add("hover:text-amber-600");
-    el.classList.remove("text-amber-600");
+    el.classList.add("hover:text-accent");
+    el.classList.remove("text-accent");
 
     el.parentElement.classList.remove("-mb-px");
     el.parentElement.classList.remove("border-l");
@@ -17,8 +17,8 @@ function changeTab(link) {
 
   // Make this link active.
   link.classList.remove("text-gray-400");
-  link.classList.remove("hover:text-amber-600");
-  link.classList.add("text-amber-600");
+  link.classList.remove("hover:text-accent");
+  link.classList.add("text-accent");
 
   link.parentElement.classList.add("-mb-px");
   link.parentElement.classList.add("border-l");
@@ -28,3 +28,33 @@ function changeTab(link) {
 
   link.setAttribute("aria-current", "page");
 }
+
+/* exported nearMe */
+function nearMe() {
+  if (!navigator.geolocation) {
+    alert("Your browser does not support geolocation");
+    return;
+  }
+
+  navigator.geolocation.getCurrentPosition(
+    (pos) => {
+      const url = new URL(window.location.href);
+      url.searchParams.set(
+        "near",
+        `${pos.coords.latitude.toFixed(4)},${pos.coords.longitude.toFixed(4)}`,
+      );
+      window.location.assign(url);
+    },
+    () => alert("Could not determine your location"),
+  );
+}
+
+/* exported openMap */
+function openMap(link) {
+  const url = new URL(link.href);
+  const search = document.getElementById("search");
+  if (search && search.value.trim() !== "") {
+    url.searchParams.set("search", search.value.trim());
+  }
+  window.location.assign(url);
+}
@@ -5,20 +5,67 @@ import (
 
 	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
 	. "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/html"
 )
 
 // LoginMain renders the login page main content.
-func LoginMain(form contract.LoginForm, errs url.Values, csrf string) Node {
+// The password reset link is shown when mail is enabled.
+func LoginMain(l *i18n.Locale, form contract.LoginForm, errs url.Values, mailEnabled bool, csrf string) Node {
 	return Main(
 		Div(Class("max-w-3xl mx-auto"),
 			Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
 				Method("POST"),
-				components.InputElement("username", "text", "Username", form.Username, errs.Get("username"), true, true),
-				components.InputElement("password", "password", "Password", form.Password, errs.Get("password"), true, false),
+				components.InputElement("username", "text", l.T("Username"), form.Username, l.T(errs.Get("username")), true, true),
+				components.InputElement("password", "password", l.T("Password"), form.Password, l.T(errs.Get("password")), true, false),
 				Input(Type("hidden"), Name("csrf"), Value(csrf)),
-				components.SubmitButtonElement("Login"),
+				components.SubmitButtonElement(l.T("Login")),
+				If(mailEnabled,
+					A(Class("block pt-3 hover:underline text-accent text-sm"), Href("/forgot-password"), Text(l.T("Forgot password?"))),
+				),
+			),
+		),
+	)
+}
+
+// ForgotPasswordMain renders the password reset request page main content.
+// The sent parameter reports whether the request was submitted.
+func ForgotPasswordMain(l *i18n.Locale, form contract.ForgotPasswordForm, errs url.Values, sent bool, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			If(sent,
+				P(Class("text-center px-3 py-4"), Text(l.T("If an account with this email exists, a password reset link was sent to it."))),
+			),
+			If(!sent,
+				Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+					Method("POST"),
+					P(Text(l.T("Enter the email address of your account to receive a password reset link."))),
+					components.InputElement("email", "email", l.T("Email"), form.Email, l.T(errs.Get("email")), true, true),
+					Input(Type("hidden"), Name("csrf"), Value(csrf)),
+					components.SubmitButtonElement(l.T("Send")),
+				),
+			),
+		),
+	)
+}
+
+// ResetPasswordMain renders the password reset page main content.
+func ResetPasswordMain(l *i18n.Locale, form contract.ResetPasswordForm, errs url.Values, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+				Method("POST"),
+
+				Label(Class("block w-full pt-2"), For("password1"), Text(l.T("New password"))),
+				components.InputElement("password1", "password", l.T("Password"), form.Password1, l.T(errs.Get("password1")), true, false),
+
+				Label(Class("block w-full pt-2"), For("password2"), Text(l.T("Password again"))),
+				components.InputElement("password2", "password", l.T("Password again"), form.Password2, l.T(errs.Get("password2")), true, false),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				components.SubmitButtonElement(l.T("Reset password")),
 			),
 		),
 	)
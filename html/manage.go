@@ -0,0 +1,257 @@
+package html
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// ManageEventsMain renders the event management table with filters and bulk actions.
+// Users are only listed for admins.
+func ManageEventsMain(
+	l *i18n.Locale,
+	user *domain.User,
+	req contract.ManageEventsRequest,
+	events []*domain.Event,
+	categories []*domain.Category,
+	users []*domain.User,
+	errs url.Values,
+	summary *contract.BulkSummary,
+	csrf string,
+) Node {
+	isAdmin := user.Role == domain.Admin
+
+	usernames := map[snowflake.ID]string{}
+	for _, u := range users {
+		usernames[u.ID] = u.Username
+	}
+
+	query := req.Query()
+
+	return Main(
+		Div(Class("max-w-5xl mx-auto px-3"),
+			manageFilters(l, req, categories, users),
+
+			Iff(summary != nil, func() Node {
+				return bulkSummary(l, summary)
+			}),
+
+			Form(ID("bulk-form"), Method("POST"), Action("/manage?"+query.Encode()),
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				bulkActions(l, isAdmin, categories, users, errs),
+
+				If(len(events) == 0,
+					Div(Class("px-3 py-4 text-center"),
+						P(Text(l.T("No events"))),
+					),
+				),
+
+				Iff(len(events) > 0, func() Node {
+					return Table(Class("w-full text-sm"),
+						THead(
+							Tr(Class("text-left"),
+								Th(Class("w-8"),
+									Input(Type("checkbox"), ID("select-all"), Title(l.T("Select all"))),
+								),
+								Th(sortLink(l.T("Title"), contract.ManageSortTitle, req)),
+								Th(sortLink(l.T("Start time"), contract.ManageSortStartAt, req)),
+								Th(Text(l.T("Status"))),
+								If(isAdmin, Th(Text(l.T("Owner")))),
+								Th(Text(l.T("Categories"))),
+							),
+						),
+						TBody(
+							Map(events, func(ev *domain.Event) Node {
+								return Tr(Class("border-t border-gray-200"),
+									Td(Class("py-1"),
+										Input(Type("checkbox"), Name("event_id"), Value(ev.ID.String())),
+									),
+									Td(
+										A(Class("hover:underline font-semibold"), Href(fmt.Sprintf("/edit/%d", ev.ID)), Text(ev.Title)),
+									),
+									Td(Text(l.FormatDateTime(ev.StartAt))),
+									Td(Text(eventStatus(l, ev))),
+									If(isAdmin, Td(Text(ownerName(l, usernames, ev.UserID)))),
+									Td(Text(strings.Join(ev.Categories, ", "))),
+								)
+							}),
+						),
+					)
+				}),
+			),
+
+			Div(Class("flex justify-between py-4"),
+				Iff(req.Offset > 0, func() Node {
+					prev := req.Query()
+					if offset := req.Offset - contract.ManageEventsLimit; offset > 0 {
+						prev.Set("offset", strconv.FormatInt(offset, 10))
+					}
+
+					return A(Class("hover:underline text-accent font-semibold"), Href("/manage?"+prev.Encode()), Text(l.T("Previous page")))
+				}),
+				Iff(len(events) == contract.ManageEventsLimit, func() Node {
+					next := req.Query()
+					next.Set("offset", strconv.FormatInt(req.Offset+contract.ManageEventsLimit, 10))
+
+					return A(Class("hover:underline text-accent font-semibold ml-auto"), Href("/manage?"+next.Encode()), Text(l.T("Next page")))
+				}),
+			),
+
+			Script(Raw(`document.getElementById("select-all")?.addEventListener("change", (e) => {
+	document.querySelectorAll('#bulk-form [name="event_id"]').forEach((el) => el.checked = e.target.checked)
+})`)),
+		),
+	)
+}
+
+func manageFilters(l *i18n.Locale, req contract.ManageEventsRequest, categories []*domain.Category, users []*domain.User) Node {
+	return Form(Class("flex flex-wrap items-end gap-2 py-4"), Method("GET"), Action("/manage"),
+		Input(components.BaseFormElementClasses(), Type("search"), Name("title"), Value(req.Title), Placeholder(l.T("Title"))),
+		Select(components.BaseFormElementClasses(), Name("status"),
+			Option(Value(""), Text(l.T("All statuses"))),
+			Map([]string{contract.StatusPublished, contract.StatusDraft, contract.StatusPending}, func(status string) Node {
+				return Option(Value(status), If(req.Status == status, Selected()), Text(statusLabel(l, status)))
+			}),
+		),
+		Iff(len(categories) > 0, func() Node {
+			return Select(components.BaseFormElementClasses(), Name("category"),
+				Option(Value(""), Text(l.T("All categories"))),
+				Map(categories, func(c *domain.Category) Node {
+					return Option(Value(c.Name), If(req.Category == c.Name, Selected()), Text(c.Name))
+				}),
+			)
+		}),
+		Iff(len(users) > 0, func() Node {
+			return Select(components.BaseFormElementClasses(), Name("owner"),
+				Option(Value(""), Text(l.T("All owners"))),
+				Map(users, func(u *domain.User) Node {
+					return Option(Value(u.ID.String()), If(req.Owner == u.ID, Selected()), Text(u.Username))
+				}),
+			)
+		}),
+		Input(Type("hidden"), Name("sort"), Value(req.Sort)),
+		components.SubmitButtonElement(l.T("Filter")),
+	)
+}
+
+func bulkActions(l *i18n.Locale, isAdmin bool, categories []*domain.Category, users []*domain.User, errs url.Values) Node {
+	return FieldSet(components.BaseFormElementClasses(),
+		Legend(Class("font-semibold"), Text(l.T("Bulk actions"))),
+		Map([]string{"event_id", "action", "new_owner", "bulk_category", "days"}, func(name string) Node {
+			return If(errs.Get(name) != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get(name)))))
+		}),
+		Div(Class("flex flex-wrap items-center gap-2"),
+			Select(components.BaseFormElementClasses(), Name("action"),
+				Option(Value(contract.BulkPublish), Text(l.T("Publish"))),
+				Option(Value(contract.BulkUnpublish), Text(l.T("Unpublish"))),
+				Option(Value(contract.BulkDelete), Text(l.T("Delete"))),
+				If(isAdmin, Option(Value(contract.BulkChangeOwner), Text(l.T("Change owner")))),
+				If(len(categories) > 0, Group{
+					Option(Value(contract.BulkAddCategory), Text(l.T("Add category"))),
+					Option(Value(contract.BulkRemoveCategory), Text(l.T("Remove category"))),
+				}),
+				Option(Value(contract.BulkShiftDate), Text(l.T("Shift date"))),
+			),
+			If(isAdmin, Select(components.BaseFormElementClasses(), Name("new_owner"), Title(l.T("New owner")),
+				Map(users, func(u *domain.User) Node {
+					return Option(Value(u.ID.String()), Text(u.Username))
+				}),
+			)),
+			Iff(len(categories) > 0, func() Node {
+				return Select(components.BaseFormElementClasses(), Name("bulk_category"), Title(l.T("Category")),
+					Map(categories, func(c *domain.Category) Node {
+						return Option(Value(c.Name), Text(c.Name))
+					}),
+				)
+			}),
+			Input(components.BaseFormElementClasses(), Type("number"), Name("days"), Value("7"),
+				Min(strconv.Itoa(-contract.BulkMaxShiftDays)), Max(strconv.Itoa(contract.BulkMaxShiftDays)),
+				Title(l.T("Days to shift by"))),
+			components.SubmitButtonElement(l.T("Apply"),
+				Attr("onclick", fmt.Sprintf("return confirm(%q)", l.T("Apply the action to the selected events?"))),
+			),
+		),
+	)
+}
+
+func bulkSummary(l *i18n.Locale, summary *contract.BulkSummary) Node {
+	return Div(Class("py-2"), Role("status"),
+		P(Class("font-semibold"),
+			Text(l.T("%d events updated, %d failed", summary.Succeeded, len(summary.Failures))),
+		),
+		Iff(len(summary.Failures) > 0, func() Node {
+			return Ul(Class("text-sm text-red-700 list-disc pl-5"),
+				Map(summary.Failures, func(f contract.BulkFailure) Node {
+					title := f.Title
+					if title == "" {
+						title = f.EventID.String()
+					}
+
+					return Li(Text(title + ": " + l.T(f.Reason)))
+				}),
+			)
+		}),
+	)
+}
+
+// sortLink renders a column header which toggles between ascending and descending order.
+func sortLink(label, sort string, req contract.ManageEventsRequest) Node {
+	q := req.Query()
+
+	indicator := ""
+
+	switch req.Sort {
+	case sort:
+		q.Set("sort", "-"+sort)
+		indicator = " ▲"
+	case "-" + sort:
+		q.Set("sort", sort)
+		indicator = " ▼"
+	default:
+		q.Set("sort", sort)
+	}
+
+	return A(Class("hover:underline"), Href("/manage?"+q.Encode()), Text(label+indicator))
+}
+
+func eventStatus(l *i18n.Locale, ev *domain.Event) string {
+	switch {
+	case ev.IsPending:
+		return statusLabel(l, contract.StatusPending)
+	case ev.IsScheduled():
+		return l.T("scheduled")
+	case ev.IsDraft:
+		return statusLabel(l, contract.StatusDraft)
+	default:
+		return statusLabel(l, contract.StatusPublished)
+	}
+}
+
+func statusLabel(l *i18n.Locale, status string) string {
+	switch status {
+	case contract.StatusPending:
+		return l.T("pending review")
+	case contract.StatusDraft:
+		return l.T("draft")
+	default:
+		return l.T("published")
+	}
+}
+
+func ownerName(l *i18n.Locale, usernames map[snowflake.ID]string, id snowflake.ID) string {
+	if name, ok := usernames[id]; ok {
+		return name
+	}
+
+	return l.T("Anonymous")
+}
@@ -0,0 +1,133 @@
+package html
+
+import (
+	"maps"
+	"net/url"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// MapTiles configures the map tile layer.
+type MapTiles struct {
+	URL         string
+	Attribution string
+}
+
+// MapMain renders the event map page main content.
+func MapMain(l *i18n.Locale, tiles MapTiles, past bool, query url.Values) Node {
+	filter := maps.Clone(query)
+	filter.Del("past")
+
+	upcoming := "/map"
+	if len(filter) > 0 {
+		upcoming += "?" + filter.Encode()
+	}
+
+	pastFilter := maps.Clone(filter)
+	pastFilter.Set("past", "1")
+
+	list := "/"
+	if past {
+		list = "/past"
+	}
+	listFilter := maps.Clone(filter)
+	listFilter.Del("search")
+	listFilter.Del("bbox")
+	if len(listFilter) > 0 {
+		list += "?" + listFilter.Encode()
+	}
+
+	return Main(
+		Div(Class("max-w-5xl mx-auto px-3"),
+			Div(Class("flex flex-wrap items-center gap-2 py-3"),
+				mapTab(l.T("Upcoming"), upcoming, !past),
+				mapTab(l.T("Past"), "/map?"+pastFilter.Encode(), past),
+				Iff(filter.Get("search") != "", func() Node {
+					return P(Class("text-sm text-gray-500"), Text(l.T(`Matching "%s"`, filter.Get("search"))))
+				}),
+				A(Class("ml-auto text-sm hover:underline text-accent font-semibold"), Href(list),
+					I(Class("fa fa-list pr-1"), Aria("hidden", "true")),
+					Text(l.T("List view")),
+				),
+			),
+			Div(ID("event-map"), Class("w-full h-[70vh] rounded-xl shadow-md z-0"),
+				Data("tile-url", tiles.URL),
+				Data("tile-attribution", tiles.Attribution),
+				Data("geojson-url", "/events.geojson?"+query.Encode()),
+			),
+		),
+	)
+}
+
+func mapTab(text, href string, active bool) Node {
+	return A(Classes{
+		"py-1":              true,
+		"px-3":              true,
+		"rounded-full":      true,
+		"font-semibold":     true,
+		"text-white":        active,
+		"bg-accent":         active,
+		"text-gray-400":     !active,
+		"hover:text-accent": !active,
+	},
+		Href(href),
+		If(active, Aria("current", "page")),
+		Text(text),
+	)
+}
+
+// MapPopupPartial renders the compact event cards shown in a map marker popup.
+// The tz parameter is the viewer time zone or nil if not known.
+func MapPopupPartial(l *i18n.Locale, tz *time.Location, events []*domain.Event) Node {
+	if len(events) == 0 {
+		return P(Text(l.T("No events")))
+	}
+
+	return Div(Class("divide-y divide-gray-200"),
+		Map(events, func(ev *domain.Event) Node {
+			return CompactEventCard(l, tz, ev)
+		}),
+	)
+}
+
+// CompactEventCard renders a compact event card with title, date and location.
+func CompactEventCard(l *i18n.Locale, tz *time.Location, ev *domain.Event) Node {
+	ev = ev.Translate(l.Code())
+
+	return Div(Class("py-2"),
+		H3(Class("font-semibold text-base"),
+			If(ev.URL != "",
+				A(Class("hover:underline"), Href(ev.URL), Target("_blank"), Rel("noopener"), Text(ev.Title)),
+			),
+			If(ev.URL == "",
+				Text(ev.Title),
+			),
+		),
+		P(Class("uppercase tracking-wide text-xs text-accent font-semibold"),
+			Text(l.FormatDateTime(ev.StartAt)),
+			If(viewerTimeDiffers(ev, tz), Text(" "+ev.StartAt.Format("MST"))),
+			relativeDay(l, ev, tz),
+		),
+		viewerDate(l, ev, tz, "text-xs text-gray-500"),
+		If(ev.Location != "", P(Class("text-xs text-gray-400"),
+			I(Class("fa fa-location-arrow pr-1"), Aria("hidden", "true")),
+			Text(ev.Location),
+		)),
+		Iff(len(ev.Categories) > 0, func() Node {
+			return Div(Class("mt-1 flex flex-wrap gap-1"),
+				Map(ev.Categories, func(name string) Node {
+					q := url.Values{}
+					q.Set("category", name)
+
+					return components.Chip(name, "/map?"+q.Encode())
+				}),
+			)
+		}),
+	)
+}
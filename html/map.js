@@ -0,0 +1,130 @@
+/* global L */
+
+// Markers closer than this many pixels are clustered together.
+const clusterRadius = 48;
+
+function clusterFeatures(map, features) {
+  const clusters = [];
+
+  for (const feature of features) {
+    const [lng, lat] = feature.geometry.coordinates;
+    const point = map.project([lat, lng], map.getZoom());
+
+    const cluster = clusters.find(
+      (c) => c.point.distanceTo(point) < clusterRadius,
+    );
+    if (cluster) {
+      cluster.features.push(feature);
+    } else {
+      clusters.push({ point: point, latlng: L.latLng(lat, lng), features: [feature] });
+    }
+  }
+
+  return clusters;
+}
+
+function isSingleLocation(features) {
+  const [lng, lat] = features[0].geometry.coordinates;
+  return features.every(
+    (f) => f.geometry.coordinates[0] === lng && f.geometry.coordinates[1] === lat,
+  );
+}
+
+function markerIcon(count) {
+  return L.divIcon({
+    className: "",
+    html: `<div class="flex items-center justify-center w-8 h-8 rounded-full bg-accent text-white text-sm font-semibold shadow-md border-2 border-white">${count}</div>`,
+    iconSize: [32, 32],
+    iconAnchor: [16, 16],
+    popupAnchor: [0, -16],
+  });
+}
+
+async function loadPopup(marker, features) {
+  const params = new URLSearchParams();
+  features.forEach((f) => params.append("id", f.id));
+
+  try {
+    const response = await fetch(`/map/popup?${params}`);
+    if (!response.ok) {
+      throw new Error(`${response.status} - ${response.statusText}`);
+    }
+    marker.setPopupContent(await response.text());
+  } catch (error) {
+    console.error("Map popup error:", error);
+    marker.setPopupContent("Error loading events");
+  }
+}
+
+document.addEventListener("DOMContentLoaded", async () => {
+  const el = document.getElementById("event-map");
+  if (!el) {
+    return;
+  }
+
+  const map = L.map(el).setView([0, 0], 2);
+
+  L.tileLayer(el.dataset.tileUrl, {
+    maxZoom: 19,
+    attribution: el.dataset.tileAttribution,
+  }).addTo(map);
+
+  const layer = L.layerGroup().addTo(map);
+
+  let features = [];
+
+  const render = () => {
+    layer.clearLayers();
+
+    for (const cluster of clusterFeatures(map, features)) {
+      const marker = L.marker(cluster.latlng, {
+        icon: markerIcon(cluster.features.length),
+      });
+
+      if (isSingleLocation(cluster.features) || map.getZoom() >= map.getMaxZoom()) {
+        marker.bindPopup("Loading...", { maxWidth: 320, maxHeight: 320 });
+        marker.on("popupopen", () => loadPopup(marker, cluster.features));
+      } else {
+        // Zoom in to split the cluster.
+        marker.on("click", () => {
+          map.fitBounds(
+            L.latLngBounds(
+              cluster.features.map((f) => [
+                f.geometry.coordinates[1],
+                f.geometry.coordinates[0],
+              ]),
+            ),
+            { padding: [40, 40] },
+          );
+        });
+      }
+
+      layer.addLayer(marker);
+    }
+  };
+
+  map.on("zoomend", render);
+
+  try {
+    const response = await fetch(el.dataset.geojsonUrl);
+    if (!response.ok) {
+      throw new Error(`${response.status} - ${response.statusText}`);
+    }
+
+    features = (await response.json()).features;
+  } catch (error) {
+    console.error("Map error:", error);
+    return;
+  }
+
+  if (features.length > 0) {
+    map.fitBounds(
+      L.latLngBounds(
+        features.map((f) => [f.geometry.coordinates[1], f.geometry.coordinates[0]]),
+      ),
+      { maxZoom: 14, padding: [40, 40] },
+    );
+  }
+
+  render();
+});
@@ -0,0 +1,52 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	hx "maragu.dev/gomponents-htmx"
+	. "maragu.dev/gomponents/html"
+)
+
+// ModerationMain renders the list of events awaiting moderation.
+func ModerationMain(l *i18n.Locale, user *domain.User, tz *time.Location, events []*domain.Event, csrf string) Node {
+	if len(events) == 0 {
+		return Main(
+			Div(Class("px-3 py-4 text-center"),
+				P(Text(l.T("no events awaiting review"))),
+			),
+		)
+	}
+
+	vals := string(must(json.Marshal(map[string]string{
+		"csrf": csrf,
+	})))
+
+	return Main(
+		Map(events, func(ev *domain.Event) Node {
+			return Div(Class("max-w-3xl mx-auto"),
+				EventCard(l, user, tz, ev, csrf),
+				Div(Class("px-3 -mt-3 mb-5 flex gap-6"),
+					A(Class("hover:underline text-accent font-semibold"),
+						hx.Post(fmt.Sprintf("/moderation/approve/%d", ev.ID)),
+						hx.Confirm(l.T("Publish this event?")),
+						hx.Vals(vals),
+						Href("#"),
+						Text(l.T("APPROVE")),
+					),
+					A(Class("hover:underline text-accent font-semibold"),
+						hx.Post(fmt.Sprintf("/moderation/reject/%d", ev.ID)),
+						hx.Confirm(l.T("Reject this event?")),
+						hx.Vals(vals),
+						Href("#"),
+						Text(l.T("REJECT")),
+					),
+				),
+			)
+		}),
+	)
+}
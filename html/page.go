@@ -3,10 +3,14 @@ package html
 import (
 	_ "embed"
 	"fmt"
+	"io"
+	"net/url"
 
 	"github.com/mgnsk/calendar"
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/pkg/markdown"
 	. "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/components"
 	. "maragu.dev/gomponents/html"
@@ -21,43 +25,60 @@ var eventNavScript string
 //go:embed editevent.js
 var editEventScript string
 
+//go:embed map.js
+var mapScript string
+
+//go:embed timezone.js
+var timezoneScript string
+
 // PageProps is props for page.
 type PageProps struct {
 	Title        string
+	Settings     *domain.Settings
+	Locale       *i18n.Locale
 	User         *domain.User
 	Path         string
+	Query        url.Values
 	CSRF         string
+	Timezone     string
 	Children     Node
 	FlashSuccess string
+	FlashError   string
 }
 
 // Page renders a page.
 func Page(props PageProps) Node {
 	return HTML5(HTML5Props{
 		Title:    props.Title,
-		Language: "en",
+		Language: props.Locale.Code(),
 		Head: []Node{
-			Link(Rel("alternate"), Type("application/rss+xml"), Title(fmt.Sprintf("RSS feed for %s", props.Title)), Href("/feed")),
-			Link(Rel("icon"), Type("image/x-icon"), Href(calendar.GetAssetPath("favicon.ico"))),
+			Link(Rel("alternate"), Type("application/rss+xml"), Title(props.Locale.T("RSS feed for %s", props.Title)), Href("/feed")),
+			Iff(props.Settings != nil && props.Settings.FaviconHash != "", func() Node {
+				return Link(Rel("icon"), Href("/site/favicon?v="+props.Settings.FaviconHash))
+			}),
+			If(props.Settings == nil || props.Settings.FaviconHash == "",
+				Link(Rel("icon"), Type("image/x-icon"), Href(calendar.GetAssetPath("favicon.ico"))),
+			),
 
 			Map([]string{
 				"node_modules/easymde/dist/easymde.min.css",
 				"node_modules/@fortawesome/fontawesome-free/css/fontawesome.min.css",
 				"node_modules/@fortawesome/fontawesome-free/css/solid.min.css",
 				"node_modules/leaflet/dist/leaflet.css",
-				"node_modules/leaflet-geosearch/dist/geosearch.css",
 				"node_modules/jquery-ui/dist/themes/base/jquery-ui.min.css",
 				"app.css",
 			}, func(path string) Node {
 				return Link(Rel("stylesheet"), Href(calendar.GetAssetPath(path)))
 			}),
+			Iff(props.Settings != nil && props.Settings.AccentColor != "", func() Node {
+				return StyleEl(Raw(":root { --accent: " + props.Settings.AccentColor + "; }"))
+			}),
 
 			Map([]string{
 				"node_modules/htmx.org/dist/htmx.min.js",
 				"node_modules/mark.js/dist/mark.min.js",
 				"node_modules/easymde/dist/easymde.min.js",
 				"node_modules/leaflet/dist/leaflet.js",
-				"node_modules/leaflet-geosearch/dist/bundle.min.js",
 				"node_modules/jquery/dist/jquery.min.js",
 				"node_modules/jquery-ui/dist/jquery-ui.min.js",
 			}, func(path string) Node {
@@ -65,9 +86,11 @@ func Page(props PageProps) Node {
 			}),
 
 			Map([]string{
+				timezoneScript,
 				eventNavScript,
 				searchScript,
 				editEventScript,
+				mapScript,
 			}, func(s string) Node {
 				return Script(Defer(), Raw(s))
 			}),
@@ -76,23 +99,53 @@ func Page(props PageProps) Node {
 		},
 		Body: []Node{
 			components.UserNav(
+				props.Locale,
+				props.Settings,
 				props.User,
 				If(
 					props.Path == "/" ||
 						props.Path == "/past" ||
 						props.Path == "/tags" ||
 						props.Path == "/my-events",
-					components.EventNav(props.User, props.Path, props.CSRF),
+					components.EventNav(props.Locale, props.Settings, props.User, props.Path, props.Query, props.CSRF),
 				),
 			),
 			props.Children,
+			Iff(props.Settings != nil, func() Node {
+				return siteFooter(props.Locale, props.Settings)
+			}),
+			If(props.CSRF != "", timezoneSelector(props.Locale, props.Timezone, props.CSRF)),
 			components.LoadingSpinner(),
-			If(props.FlashSuccess != "", flashMessage(true, props.FlashSuccess)),
+			If(props.FlashError != "", flashMessage(props.Locale, false, props.FlashError)),
+			If(props.FlashError == "" && props.FlashSuccess != "", flashMessage(props.Locale, true, props.FlashSuccess)),
 		},
 	})
 }
 
-func flashMessage(success bool, message string) Node {
+func siteFooter(l *i18n.Locale, settings *domain.Settings) Node {
+	if settings.Footer == "" && settings.ContactEmail == "" {
+		return nil
+	}
+
+	return Footer(Class("max-w-3xl mx-auto px-3 py-6 text-sm text-gray-500 text-center"),
+		If(settings.Footer != "",
+			Div(Class("[&>p]:py-1 [&_a]:text-accent [&_a:hover]:underline"), NodeFunc(func(w io.Writer) error {
+				if err := markdown.Convert(w, settings.Footer); err != nil {
+					return fmt.Errorf("error rendering footer markdown: %w", err)
+				}
+				return nil
+			})),
+		),
+		If(settings.ContactEmail != "",
+			P(Class("py-1"),
+				Text(l.T("Contact: ")),
+				A(Class("text-accent hover:underline"), Href("mailto:"+settings.ContactEmail), Text(settings.ContactEmail)),
+			),
+		),
+	)
+}
+
+func flashMessage(l *i18n.Locale, success bool, message string) Node {
 	return Div(
 		Div(ID("alert"), Classes{
 			"fixed":           true,
@@ -113,7 +166,7 @@ func flashMessage(success bool, message string) Node {
 			Role("alert"),
 			Div(Class("flex items-center"),
 				I(Class("fa fa-info-circle pr-1"), Aria("hidden", "true")),
-				P(Class("font-bold"), Text(message)),
+				P(Class("font-bold"), Text(l.T(message))),
 			),
 		),
 		Script(Raw(`setTimeout(() => document.getElementById("alert").remove(), 5000)`)),
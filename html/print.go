@@ -0,0 +1,127 @@
+package html
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+const printStyle = `
+@page { margin: 15mm; }
+@media print {
+  .no-print { display: none !important; }
+  .print-day { break-inside: avoid-page; }
+  .print-event { break-inside: avoid; }
+}
+`
+
+// PrintPage renders the printable program of events grouped by day.
+// QR codes are data URIs of the QR code images by event ID.
+func PrintPage(
+	l *i18n.Locale,
+	settings *domain.Settings,
+	days []domain.EventDay,
+	req contract.PrintRequest,
+	from, until time.Time,
+	qrCodes map[snowflake.ID]string,
+) Node {
+	query := url.Values{}
+	query.Set("from", from.Format(contract.PrintDateLayout))
+	query.Set("until", until.AddDate(0, 0, -1).Format(contract.PrintDateLayout))
+
+	if req.Category != "" {
+		query.Set("category", req.Category)
+	}
+
+	if req.Venue > 0 {
+		query.Set("venue", req.Venue.String())
+	}
+
+	if req.QR {
+		query.Set("qr", "true")
+	}
+
+	return HTML5(HTML5Props{
+		Title:    settings.Title,
+		Language: l.Code(),
+		Head: []Node{
+			Link(Rel("stylesheet"), Href(calendar.GetAssetPath("app.css"))),
+			If(settings.AccentColor != "",
+				StyleEl(Raw(":root { --accent: "+settings.AccentColor+"; }")),
+			),
+			StyleEl(Raw(printStyle)),
+		},
+		Body: []Node{
+			Main(Class("max-w-3xl mx-auto px-3 py-4"),
+				Form(Class("no-print flex flex-wrap items-end gap-2 pb-4"),
+					Method("GET"),
+					Action("/print"),
+
+					Label(Class("text-sm"), Text(l.T("From")),
+						Input(Class("block border border-gray-300 rounded px-2 py-1"), Type("date"), Name("from"), Value(query.Get("from"))),
+					),
+					Label(Class("text-sm"), Text(l.T("Until")),
+						Input(Class("block border border-gray-300 rounded px-2 py-1"), Type("date"), Name("until"), Value(query.Get("until"))),
+					),
+					If(req.Category != "", Input(Type("hidden"), Name("category"), Value(req.Category))),
+					If(req.Venue > 0, Input(Type("hidden"), Name("venue"), Value(req.Venue.String()))),
+					Label(Class("text-sm py-1"),
+						Input(Type("checkbox"), Name("qr"), Value("true"), If(req.QR, Checked())),
+						Text(" "+l.T("QR codes")),
+					),
+
+					Button(Class("text-accent font-semibold px-2 py-1 hover:underline"), Type("submit"), Text(l.T("Show"))),
+					Button(Class("text-accent font-semibold px-2 py-1 hover:underline"), Type("button"), Attr("onclick", "window.print()"), Text(l.T("Print"))),
+					A(Class("text-accent font-semibold px-2 py-1 hover:underline"), Href("/print.pdf?"+query.Encode()), Text(l.T("Download PDF"))),
+				),
+
+				Header(Class("pb-4 border-b border-gray-300"),
+					If(settings.LogoHash != "",
+						Img(Class("max-h-20 pb-2"), Src("/site/logo?v="+settings.LogoHash), Alt(settings.Title)),
+					),
+					H1(Class("text-3xl font-bold"), Text(settings.Title)),
+					If(settings.Description != "",
+						P(Class("py-1"), Text(settings.Description)),
+					),
+					P(Class("text-gray-500"),
+						Text(fmt.Sprintf("%s – %s", l.FormatDate(from), l.FormatDate(until.AddDate(0, 0, -1)))),
+					),
+				),
+
+				If(len(days) == 0,
+					P(Class("py-4 text-center"), Text(l.T("No events"))),
+				),
+
+				Map(days, func(day domain.EventDay) Node {
+					return Section(Class("print-day py-3 border-b border-gray-200"),
+						H2(Class("text-xl font-semibold pb-1"), Text(l.FormatDate(day.Date))),
+						Map(day.Events, func(ev *domain.Event) Node {
+							return Article(Class("print-event flex gap-3 py-1"),
+								Time(Class("w-20 shrink-0 font-semibold"),
+									DateTime(ev.StartAt.Format(time.RFC3339)),
+									Text(l.FormatTime(ev.StartAt)),
+								),
+								Div(Class("grow"),
+									P(Class("font-semibold"), Text(ev.Title)),
+									If(ev.Location != "", P(Class("text-sm text-gray-500"), Text(ev.Location))),
+								),
+								Iff(qrCodes[ev.ID] != "", func() Node {
+									return Img(Class("w-20 h-20 shrink-0"), Src(qrCodes[ev.ID]), Alt(l.T("QR code of %s", ev.Title)))
+								}),
+							)
+						}),
+					)
+				}),
+			),
+		},
+	})
+}
@@ -0,0 +1,250 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "maragu.dev/gomponents"
+	hx "maragu.dev/gomponents-htmx"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// eventAttendance renders the RSVP counts of a published event.
+func eventAttendance(l *i18n.Locale, ev *domain.Event) Node {
+	if ev.IsDraft || (ev.Capacity == 0 && ev.Attendance.IsEmpty()) {
+		return nil
+	}
+
+	var parts []string
+
+	parts = append(parts, l.T("%d going", ev.Attendance.Going))
+
+	if ev.Attendance.Maybe > 0 {
+		parts = append(parts, l.T("%d maybe", ev.Attendance.Maybe))
+	}
+
+	if ev.Attendance.Waitlisted > 0 {
+		parts = append(parts, l.T("%d on the waitlist", ev.Attendance.Waitlisted))
+	}
+
+	switch {
+	case ev.IsFull():
+		parts = append(parts, l.T("full"))
+	case ev.Capacity > 0:
+		parts = append(parts, l.T("%d places left", ev.GetSpotsLeft()))
+	}
+
+	return A(Class("block mt-2 text-sm text-gray-500 hover:underline"), Href(fmt.Sprintf("/event/%d#rsvp", ev.ID)),
+		I(Class("fa fa-users pr-1"), Aria("hidden", "true")),
+		Map(parts, func(part string) Node {
+			return Span(Class("pr-2"), Text(part))
+		}),
+	)
+}
+
+// rsvpSection renders the RSVP form of an upcoming published event.
+// The rsvp parameter is the current response of the user or nil.
+func rsvpSection(l *i18n.Locale, user *domain.User, ev *domain.Event, rsvp *domain.RSVP, anonymousRSVP bool, form contract.RSVPForm, errs url.Values, csrf string) Node {
+	if ev.IsDraft || ev.StartAt.Before(time.Now()) || (user == nil && !anonymousRSVP) {
+		return nil
+	}
+
+	statusButton := func(status domain.RSVPStatus, label string) Node {
+		current := rsvp != nil && rsvp.Status == status
+
+		return Button(
+			Classes{
+				"px-4 py-2 rounded-lg border font-semibold": true,
+				"bg-accent text-white border-accent":        current,
+				"text-accent border-gray-300":               !current,
+			},
+			Type("submit"),
+			Name("status"),
+			Value(string(status)),
+			Text(label),
+		)
+	}
+
+	return Div(ID("rsvp"), Class("max-w-3xl mx-auto bg-white rounded-xl shadow-md px-3 md:px-6 py-4 my-5"),
+		H2(Class("font-semibold text-lg"), Text(l.T("Are you going?"))),
+
+		Iff(rsvp != nil, func() Node {
+			return P(Class("text-sm text-gray-500"), Text(rsvpStatusMessage(l, rsvp)))
+		}),
+
+		If(ev.IsFull() && (rsvp == nil || !rsvp.IsAttending()),
+			P(Class("text-sm text-gray-500"), Text(l.T("The event is full. Going adds you to the waitlist."))),
+		),
+
+		Form(Class("mt-3"),
+			Method("POST"),
+			Action(fmt.Sprintf("/event/%d/rsvp", ev.ID)),
+
+			Iff(user == nil, func() Node {
+				return Group{
+					components.InputElement("name", "text", l.T("Name"), form.Name, l.T(errs.Get("name")), true, true),
+					components.InputElement("email", "email", l.T("Email"), form.Email, l.T(errs.Get("email")), true, true),
+				}
+			}),
+
+			If(errs.Get("status") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("status"))))),
+
+			Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+			Div(Class("flex flex-wrap gap-2"),
+				statusButton(domain.RSVPGoing, l.T("Going")),
+				statusButton(domain.RSVPMaybe, l.T("Maybe")),
+				statusButton(domain.RSVPNotGoing, l.T("Not going")),
+			),
+		),
+	)
+}
+
+func rsvpStatusMessage(l *i18n.Locale, rsvp *domain.RSVP) string {
+	switch {
+	case rsvp.Waitlisted:
+		return l.T("You are on the waitlist")
+	case rsvp.Status == domain.RSVPGoing:
+		return l.T("You are going")
+	case rsvp.Status == domain.RSVPMaybe:
+		return l.T("You might be going")
+	default:
+		return l.T("You are not going")
+	}
+}
+
+// rsvpStatusLabel returns the translated status of an RSVP.
+func rsvpStatusLabel(l *i18n.Locale, rsvp *domain.RSVP) string {
+	switch {
+	case rsvp.Waitlisted:
+		return l.T("waitlisted")
+	case rsvp.Status == domain.RSVPGoing:
+		return l.T("going")
+	case rsvp.Status == domain.RSVPMaybe:
+		return l.T("maybe")
+	default:
+		return l.T("not going")
+	}
+}
+
+// AttendeesMain renders the RSVPs of an event for its owner.
+func AttendeesMain(l *i18n.Locale, ev *domain.Event, rsvps []*domain.RSVP, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			H1(Class("tracking-wide text-xl md:text-2xl font-semibold"),
+				A(Class("hover:underline"), Href(fmt.Sprintf("/event/%d", ev.ID)), Text(ev.Title)),
+			),
+			P(Class("mt-2 text-sm text-gray-500"), Text(l.FormatDateTime(ev.StartAt))),
+			eventAttendance(l, ev),
+
+			If(len(rsvps) == 0,
+				Div(Class("px-3 py-4 text-center"),
+					P(Text(l.T("no responses yet"))),
+				),
+			),
+
+			Iff(len(rsvps) > 0, func() Node {
+				return Group{
+					Div(Class("my-3 text-right"),
+						A(Class("hover:underline text-accent font-semibold"),
+							Href(fmt.Sprintf("/event/%d/attendees.csv", ev.ID)),
+							Text(l.T("EXPORT CSV")),
+						),
+					),
+					Table(Class("w-full text-sm"),
+						THead(
+							Tr(
+								Th(Class("text-left"), Text(l.T("Name"))),
+								Th(Class("text-left"), Text(l.T("Email"))),
+								Th(Class("text-left"), Text(l.T("Status"))),
+								Th(Class("text-left"), Text(l.T("Responded at"))),
+								Th(Class("text-left"), Text(l.T("Actions"))),
+							),
+						),
+						TBody(
+							Map(rsvps, func(r *domain.RSVP) Node {
+								return Tr(
+									Td(Class("py-1"),
+										Text(r.Name),
+										If(r.IsAnonymous(), Span(Class("pl-1 text-gray-400"), Text(l.T("(guest)")))),
+									),
+									Td(Text(r.Email)),
+									Td(Text(rsvpStatusLabel(l, r))),
+									Td(Text(l.FormatDateTime(r.UpdatedAt.In(ev.StartAt.Location())))),
+									Td(
+										A(Class("hover:underline text-accent font-semibold"),
+											hx.Post(fmt.Sprintf("/event/%d/attendees/remove", ev.ID)),
+											hx.Confirm(l.T("Are you sure?")),
+											hx.Vals(string(must(json.Marshal(map[string]string{
+												"csrf":    csrf,
+												"rsvp_id": r.ID.String(),
+											})))),
+											Href("#"),
+											Text(l.T("REMOVE")),
+										),
+									),
+								)
+							}),
+						),
+					),
+				}
+			}),
+		),
+	)
+}
+
+// MyRSVPsMain renders the events the user has responded to
+// and the address of the personal calendar feed.
+func MyRSVPsMain(l *i18n.Locale, tz *time.Location, events []*domain.Event, rsvps map[snowflake.ID]*domain.RSVP, calendarURL string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			Div(Class("my-3"),
+				P(Text(l.T("Add your personal calendar to a calendar app. It contains the events you are going to and your own events with their attendees."))),
+				components.InputElement("calendar_url", "text", "", calendarURL, "", false, false),
+				P(Class("text-sm text-gray-500"), Text(l.T("Keep the address private, anyone with it can see your calendar."))),
+			),
+
+			If(len(events) == 0,
+				Div(Class("px-3 py-4 text-center"),
+					P(Text(l.T("no responses yet"))),
+				),
+			),
+
+			Iff(len(events) > 0, func() Node {
+				return Table(Class("w-full text-sm"),
+					THead(
+						Tr(
+							Th(Class("text-left"), Text(l.T("Title"))),
+							Th(Class("text-left"), Text(l.T("Start time"))),
+							Th(Class("text-left"), Text(l.T("Status"))),
+						),
+					),
+					TBody(
+						Map(events, func(ev *domain.Event) Node {
+							startAt := ev.StartAt
+							if tz != nil {
+								startAt = startAt.In(tz)
+							}
+
+							return Tr(
+								Td(Class("py-1"),
+									A(Class("hover:underline"), Href(fmt.Sprintf("/event/%d", ev.ID)), Text(ev.Translate(l.Code()).Title)),
+								),
+								Td(Text(l.FormatDateTime(startAt))),
+								Td(Text(rsvpStatusLabel(l, rsvps[ev.ID]))),
+							)
+						}),
+					),
+				)
+			}),
+		),
+	)
+}
@@ -5,11 +5,15 @@ const punct = ":;.,-–—‒_(){}[]!'\"+=".split("");
 async function highlightResults(targetNode, searchValue) {
   return new Promise((resolve) => {
     const mark = new Mark(targetNode);
-    const s = searchValue.replace(/"+/g, ""); // Remove double quotes.
+    const s = searchValue
+      .replace(/\b(after|before):\S*/gi, "") // Remove date operators.
+      .replace(/\b(title|description|location):/gi, "") // Remove field prefixes.
+      .replace(/"+/g, ""); // Remove double quotes.
     const results = [];
 
     mark.mark(s, {
       ignorePunctuation: punct,
+      exclude: [".event-snippet", ".event-snippet *"], // Highlighted on the server.
       each: function (el) {
         results.push(el);
       },
@@ -0,0 +1,97 @@
+package html
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// SettingsMain renders the site settings form.
+func SettingsMain(l *i18n.Locale, form contract.SettingsForm, settings *domain.Settings, errs url.Values, csrf string) Node {
+	accentColor := form.AccentColor
+	if accentColor == "" {
+		accentColor = domain.DefaultAccentColor
+	}
+
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			Form(Class("w-full px-3 py-4 mx-auto"),
+				Method("POST"),
+				EncType("multipart/form-data"),
+
+				Label(Class("block w-full pb-2"), For("pagetitle"), Text(l.T("Title"))),
+				components.InputElement("pagetitle", "text", l.T("Title"), form.Title, l.T(errs.Get("pagetitle")), true, false),
+
+				Label(Class("block w-full pb-2"), For("pagedesc"), Text(l.T("Description"))),
+				components.TextareaElement("pagedesc", form.Description, l.T(errs.Get("pagedesc")), 3, false, false),
+
+				Label(Class("block w-full pb-2"), For("locale"), Text(l.T("Default language"))),
+				localeSelect("locale", form.Locale),
+				If(errs.Get("locale") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("locale"))))),
+
+				Label(Class("block w-full pb-2"), For("timezone"), Text(l.T("Default timezone of events"))),
+				components.DataListInputElement("timezone", "UTC", form.Timezone, l.T(errs.Get("timezone")), "timezones"),
+				DataList(ID("timezones"), Data("timezones", "")),
+
+				Label(Class("block w-full pb-2"), For("accent_color"), Text(l.T("Accent color"))),
+				components.InputElement("accent_color", "color", "", accentColor, l.T(errs.Get("accent_color")), false, false),
+
+				siteAssetInput(l, domain.AssetLogo, l.T("Logo"), settings.LogoHash, "remove_logo", errs),
+				siteAssetInput(l, domain.AssetFavicon, l.T("Favicon"), settings.FaviconHash, "remove_favicon", errs),
+
+				Label(Class("block w-full pb-2"), For("footer"), Text(l.T("Footer text in markdown"))),
+				components.TextareaElement("footer", form.Footer, l.T(errs.Get("footer")), 5, false, false),
+
+				Label(Class("block w-full pb-2"), For("contact_email"), Text(l.T("Contact email"))),
+				components.InputElement("contact_email", "email", l.T("Contact email"), form.ContactEmail, l.T(errs.Get("contact_email")), false, false),
+
+				FieldSet(components.BaseFormElementClasses(),
+					Legend(Class("font-semibold"), Text(l.T("Features"))),
+					Div(Class("flex flex-col gap-y-1"),
+						components.CheckboxElement("public_submission", "true", l.T("Visitors can submit events without logging in"), form.PublicSubmission),
+						components.CheckboxElement("moderation", "true", l.T("Events of non-admins are published after review"), form.Moderation),
+						components.CheckboxElement("tags_page_enabled", "true", l.T("Show the tags page"), form.TagsPageEnabled),
+						components.CheckboxElement("anonymous_rsvp", "true", l.T("Visitors can RSVP with their name and email"), form.AnonymousRSVP),
+					),
+				),
+
+				FieldSet(components.BaseFormElementClasses(),
+					Legend(Class("font-semibold"), Text(l.T("Old events"))),
+					If(errs.Get("retention_action") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("retention_action"))))),
+					Select(components.BaseFormElementClasses(), Name("retention_action"),
+						Option(Value(string(domain.RetentionKeep)), If(form.RetentionAction == string(domain.RetentionKeep), Selected()), Text(l.T("Keep forever"))),
+						Option(Value(string(domain.RetentionArchive)), If(form.RetentionAction == string(domain.RetentionArchive), Selected()), Text(l.T("Return to drafts"))),
+						Option(Value(string(domain.RetentionDelete)), If(form.RetentionAction == string(domain.RetentionDelete), Selected()), Text(l.T("Delete"))),
+					),
+					Label(Class("block w-full pb-2"), For("retention_days"), Text(l.T("Days after the event start"))),
+					components.InputElement("retention_days", "number", l.T("Days after the event start"), strconv.Itoa(form.RetentionDays), l.T(errs.Get("retention_days")), false, false),
+				),
+
+				Label(Class("block w-full pb-2"), For("embed_origins"), Text(l.T("Sites allowed to embed the event list, one origin per line"))),
+				components.TextareaElement("embed_origins", form.EmbedOrigins, l.T(errs.Get("embed_origins")), 3, false, false),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				components.SubmitButtonElement(l.T("Save")),
+			),
+		),
+	)
+}
+
+func siteAssetInput(l *i18n.Locale, name, label, hash, removeName string, errs url.Values) Node {
+	return Div(Class("mb-3"),
+		Label(Class("block w-full pb-2"), For(name), Text(label)),
+		If(errs.Get(name) != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get(name))))),
+		Div(Class("flex items-center gap-4"),
+			If(hash != "", Img(Class("h-8"), Src("/site/"+name+"?v="+hash), Alt(label))),
+			Input(Class("py-2"), ID(name), Name(name), Type("file"), Accept("image/*")),
+			If(hash != "", components.CheckboxElement(removeName, "true", l.T("Remove"), false)),
+		),
+	)
+}
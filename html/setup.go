@@ -5,35 +5,50 @@ import (
 
 	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
 	. "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/html"
 )
 
 // SetupMain renders the setup page main content.
-func SetupMain(form contract.SetupForm, errs url.Values, csrf string) Node {
+func SetupMain(l *i18n.Locale, form contract.SetupForm, errs url.Values, csrf string) Node {
 	return Main(
 		Div(Class("max-w-3xl mx-auto"),
 			Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
 				Method("POST"),
-				Label(Class("block w-full pt-2"), For("title"), Text("Title")),
-				components.InputElement("pagetitle", "text", "Title", form.Title, errs.Get("pagetitle"), true, false),
+				Label(Class("block w-full pt-2"), For("title"), Text(l.T("Title"))),
+				components.InputElement("pagetitle", "text", l.T("Title"), form.Title, l.T(errs.Get("pagetitle")), true, false),
 
-				Label(Class("block w-full pt-2"), For("desc"), Text("Description")),
-				components.TextareaElement("pagedesc", form.Description, errs.Get("pagedesc"), 3, false, false),
+				Label(Class("block w-full pt-2"), For("desc"), Text(l.T("Description"))),
+				components.TextareaElement("pagedesc", form.Description, l.T(errs.Get("pagedesc")), 3, false, false),
 
-				Label(Class("block w-full pt-2"), For("username"), Text("Username")),
-				components.InputElement("username", "text", "Username", form.Username, errs.Get("username"), true, false),
+				Label(Class("block w-full pt-2"), For("locale"), Text(l.T("Default language"))),
+				localeSelect("locale", form.Locale),
+				If(errs.Get("locale") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("locale"))))),
 
-				Label(Class("block w-full pt-2"), For("password1"), Text("Password")),
-				components.InputElement("password1", "password", "Password", form.Password1, errs.Get("password1"), true, false),
+				Label(Class("block w-full pt-2"), For("username"), Text(l.T("Username"))),
+				components.InputElement("username", "text", l.T("Username"), form.Username, l.T(errs.Get("username")), true, false),
 
-				Label(Class("block w-full pt-2"), For("password2"), Text("Password again")),
-				components.InputElement("password2", "password", "Password again", form.Password2, errs.Get("password2"), true, false),
+				Label(Class("block w-full pt-2"), For("password1"), Text(l.T("Password"))),
+				components.InputElement("password1", "password", l.T("Password"), form.Password1, l.T(errs.Get("password1")), true, false),
+
+				Label(Class("block w-full pt-2"), For("password2"), Text(l.T("Password again"))),
+				components.InputElement("password2", "password", l.T("Password again"), form.Password2, l.T(errs.Get("password2")), true, false),
 
 				Input(Type("hidden"), Name("csrf"), Value(csrf)),
 
-				components.SubmitButtonElement("Save"),
+				components.SubmitButtonElement(l.T("Save")),
 			),
 		),
 	)
 }
+
+func localeSelect(name, code string) Node {
+	return Select(components.BaseFormElementClasses(),
+		ID(name),
+		Name(name),
+		Map(i18n.Locales, func(locale *i18n.Locale) Node {
+			return Option(Value(locale.Code()), If(locale.Code() == code, Selected()), Text(locale.Name()))
+		}),
+	)
+}
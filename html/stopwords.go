@@ -1,21 +1,31 @@
 package html
 
 import (
+	"net/url"
 	"strings"
 
 	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/pkg/textfilter"
 	. "maragu.dev/gomponents"
 	. "maragu.dev/gomponents/html"
 )
 
 // StopWordsMain renders the stop words form.
-func StopWordsMain(words []string, csrf string) Node {
+func StopWordsMain(l *i18n.Locale, words []string, tagLanguage string, errs url.Values, csrf string) Node {
+	stemmed := []textfilter.Language{}
+	for _, lang := range textfilter.Languages {
+		if _, ok := textfilter.NewStemmer(lang.Code); ok {
+			stemmed = append(stemmed, lang)
+		}
+	}
+
 	return Main(
 		Div(Class("max-w-3xl mx-auto"),
 			Form(Class("text-center w-full  px-3 py-4 mx-auto"),
 				Method("POST"),
 
-				Label(Class("block w-full pb-2"), For("words"), Text("Stop words are excluded from tags page. One word per line.")),
+				Label(Class("block w-full pb-2"), For("words"), Text(l.T("Stop words are excluded from tags page. One word per line."))),
 
 				components.TextareaElement("words",
 					strings.Join(words, "\n"),
@@ -25,9 +35,39 @@ func StopWordsMain(words []string, csrf string) Node {
 					false,
 				),
 
+				Label(Class("block w-full pb-2"), For("tag_language"), Text(l.T("Tag language. Words are reduced to their stem so that word forms share a tag."))),
+
+				Select(components.BaseFormElementClasses(),
+					Name("tag_language"),
+					Option(Value(""), If(tagLanguage == "", Selected()), Text(l.T("No stemming"))),
+					Map(stemmed, func(lang textfilter.Language) Node {
+						return Option(Value(lang.Code), If(lang.Code == tagLanguage, Selected()), Text(l.T(lang.Name)))
+					}),
+				),
+				If(errs.Get("tag_language") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("tag_language"))))),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				components.SubmitButtonElement(l.T("Save")),
+			),
+
+			Form(Class("text-center w-full  px-3 py-4 mx-auto"),
+				Method("POST"),
+				Action("/stopwords/preset"),
+
+				Label(Class("block w-full pb-2"), For("preset"), Text(l.T("Add stop words from a bundled list."))),
+
+				Select(components.BaseFormElementClasses(),
+					Name("preset"),
+					Map(textfilter.StopWordPresets(), func(lang textfilter.Language) Node {
+						return Option(Value(lang.Code), Text(l.T(lang.Name)))
+					}),
+				),
+				If(errs.Get("preset") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("preset"))))),
+
 				Input(Type("hidden"), Name("csrf"), Value(csrf)),
 
-				components.SubmitButtonElement("Save"),
+				components.SubmitButtonElement(l.T("Add preset")),
 			),
 		),
 	)
@@ -0,0 +1,205 @@
+package html
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// SubscribeMain renders the subscribe page main content. The feed URL
+// is the RSS feed of the filter. The email digest form is shown when mail is enabled.
+func SubscribeMain(l *i18n.Locale, categories []*domain.Category, form contract.SubscribeForm, errs url.Values, feedURL string, mailEnabled bool, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			Div(Class("my-3"),
+				P(Text(l.T("Follow %s in a feed reader:", subscriptionFilter(l, form.Category, form.Tag)))),
+				components.InputElement("feed_url", "text", "", feedURL, "", false, false),
+			),
+
+			Iff(mailEnabled, func() Node {
+				return Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+					Method("POST"),
+					Action("/subscribe"),
+
+					P(Text(l.T("Or receive the upcoming events by email:"))),
+
+					components.InputElement("email", "email", l.T("Email"), form.Email, l.T(errs.Get("email")), true, true),
+
+					Label(Class("block w-full pt-2"), For("frequency"), Text(l.T("Frequency"))),
+					Select(components.BaseFormElementClasses(),
+						ID("frequency"),
+						Name("frequency"),
+						Option(Value(string(domain.DigestWeekly)), If(form.Frequency != string(domain.DigestDaily), Selected()), Text(l.T("Weekly"))),
+						Option(Value(string(domain.DigestDaily)), If(form.Frequency == string(domain.DigestDaily), Selected()), Text(l.T("Daily"))),
+					),
+					If(errs.Get("frequency") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("frequency"))))),
+
+					Iff(len(categories) > 0, func() Node {
+						return Group{
+							Label(Class("block w-full pt-2"), For("category"), Text(l.T("Category"))),
+							Select(components.BaseFormElementClasses(),
+								ID("category"),
+								Name("category"),
+								Option(Value(""), Text(l.T("All categories"))),
+								Map(categories, func(c *domain.Category) Node {
+									return Option(Value(c.Name), If(c.Name == form.Category, Selected()), Text(c.Name))
+								}),
+							),
+						}
+					}),
+
+					Label(Class("block w-full pt-2"), For("tag"), Text(l.T("Tag (optional)"))),
+					components.InputElement("tag", "text", l.T("Tag"), form.Tag, l.T(errs.Get("tag")), false, false),
+
+					Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+					components.SubmitButtonElement(l.T("Subscribe")),
+				)
+			}),
+		),
+	)
+}
+
+// SubscriptionMessageMain renders a subscription status message.
+func SubscriptionMessageMain(l *i18n.Locale, message string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			P(Class("text-center px-3 py-4"), Text(l.T(message))),
+		),
+	)
+}
+
+// UnsubscribeMain renders the unsubscribe confirmation.
+func UnsubscribeMain(l *i18n.Locale, s *domain.Subscription, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+				Method("POST"),
+				P(Text(l.T("Stop sending the %s digest of %s to %s?", digestFrequencyLabel(l, s.Frequency), subscriptionFilter(l, s.Category, s.Tag), s.Email))),
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+				components.SubmitButtonElement(l.T("Unsubscribe")),
+			),
+		),
+	)
+}
+
+// SubscribersMain renders the subscriber counts for admins.
+func SubscribersMain(l *i18n.Locale, stats *domain.SubscriptionStats) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			Table(Class("w-full text-sm"),
+				TBody(
+					Tr(Td(Class("py-1"), Text(l.T("Daily subscribers"))), Td(Text(fmt.Sprint(stats.Daily)))),
+					Tr(Td(Class("py-1"), Text(l.T("Weekly subscribers"))), Td(Text(fmt.Sprint(stats.Weekly)))),
+					Tr(Td(Class("py-1"), Text(l.T("Awaiting confirmation"))), Td(Text(fmt.Sprint(stats.Pending)))),
+				),
+			),
+
+			Iff(len(stats.Filters) > 0, func() Node {
+				return Table(Class("w-full text-sm mt-5"),
+					THead(
+						Tr(
+							Th(Class("text-left"), Text(l.T("Filter"))),
+							Th(Class("text-left"), Text(l.T("Subscribers"))),
+						),
+					),
+					TBody(
+						Map(stats.Filters, func(f domain.SubscriptionFilterCount) Node {
+							return Tr(
+								Td(Class("py-1"), Text(subscriptionFilter(l, f.Category, f.Tag))),
+								Td(Text(fmt.Sprint(f.Count))),
+							)
+						}),
+					),
+				)
+			}),
+		),
+	)
+}
+
+// SubscriptionConfirmEmail renders the double opt-in confirmation email.
+func SubscriptionConfirmEmail(l *i18n.Locale, siteTitle string, s *domain.Subscription, link string) Email {
+	intro := l.T("You subscribed to the %s digest of %s.", digestFrequencyLabel(l, s.Frequency), subscriptionFilter(l, s.Category, s.Tag))
+	action := l.T("Confirm your subscription with this link:")
+	ignore := l.T("If you did not subscribe, ignore this email.")
+
+	return Email{
+		Subject: l.T("Confirm your subscription to %s", siteTitle),
+		Text:    emailText(intro, action+"\n"+link, ignore),
+		Body: emailDocument(l, siteTitle,
+			P(Text(intro)),
+			P(Text(action)),
+			emailButton(link, l.T("Confirm")),
+			P(Text(ignore)),
+		),
+	}
+}
+
+// DigestEmail renders a digest of upcoming events. Event links are relative to baseURL.
+func DigestEmail(l *i18n.Locale, siteTitle string, s *domain.Subscription, events []*domain.Event, baseURL, unsubscribeLink string) Email {
+	var text []string
+
+	for _, ev := range events {
+		ev = ev.Translate(l.Code())
+
+		lines := []string{ev.Title, l.FormatDateTime(ev.StartAt)}
+		if ev.Location != "" {
+			lines = append(lines, ev.Location)
+		}
+		lines = append(lines, fmt.Sprintf("%s/event/%d", baseURL, ev.ID))
+
+		text = append(text, strings.Join(lines, "\n"))
+	}
+
+	intro := l.T("Upcoming events of %s:", subscriptionFilter(l, s.Category, s.Tag))
+	footer := l.T("Unsubscribe: %s", unsubscribeLink)
+
+	return Email{
+		Subject: l.T("%s: %d upcoming events", siteTitle, len(events)),
+		Text:    emailText(append(append([]string{intro}, text...), footer)...),
+		Body: emailDocument(l, siteTitle,
+			P(Text(intro)),
+			Map(events, func(ev *domain.Event) Node {
+				ev = ev.Translate(l.Code())
+
+				return Div(Style("margin:16px 0"),
+					A(Style("font-weight:bold;color:#d97706"), Href(fmt.Sprintf("%s/event/%d", baseURL, ev.ID)), Text(ev.Title)),
+					Div(Text(l.FormatDateTime(ev.StartAt))),
+					If(ev.Location != "", Div(Text(ev.Location))),
+				)
+			}),
+			P(Style("font-size:12px;color:#6b7280"),
+				A(Style("color:#6b7280"), Href(unsubscribeLink), Text(l.T("Unsubscribe"))),
+			),
+		),
+	}
+}
+
+// subscriptionFilter describes the filter of a subscription.
+func subscriptionFilter(l *i18n.Locale, category, tag string) string {
+	switch {
+	case category != "" && tag != "":
+		return l.T("%s events tagged #%s", category, tag)
+	case category != "":
+		return l.T("%s events", category)
+	case tag != "":
+		return l.T("events tagged #%s", tag)
+	default:
+		return l.T("all events")
+	}
+}
+
+func digestFrequencyLabel(l *i18n.Locale, f domain.DigestFrequency) string {
+	if f == domain.DigestDaily {
+		return l.T("daily")
+	}
+
+	return l.T("weekly")
+}
@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"net/url"
+	"slices"
 
 	"github.com/aybabtme/uniplot/histogram"
 	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/samber/lo"
 	. "maragu.dev/gomponents"
 	hx "maragu.dev/gomponents-htmx"
@@ -31,13 +35,40 @@ func TagsMain(csrf string) Node {
 }
 
 // TagListPartial renders the tag list partial.
-func TagListPartial(tags []*domain.Tag, csrf string) Node {
-	if len(tags) == 0 {
+// Selected tags are highlighted and clicking a tag toggles it in the selection.
+func TagListPartial(l *i18n.Locale, tags []*domain.Tag, categories []*domain.Category, selected []string, match string) Node {
+	if len(tags) == 0 && len(categories) == 0 {
 		return Div(Class("px-3 py-4 text-center"),
-			P(Text("no tags found")),
+			P(Text(l.T("no tags found"))),
 		)
 	}
 
+	return Group{
+		Iff(len(categories) > 0, func() Node {
+			return categoryList(categories)
+		}),
+		Iff(len(tags) > 0, func() Node {
+			return tagCloud(l, tags, selected, match)
+		}),
+	}
+}
+
+func categoryList(categories []*domain.Category) Node {
+	return Div(Class("max-w-3xl mx-auto my-5"),
+		Ul(Class("flex justify-center flex-wrap align-center gap-2 leading-8"),
+			Map(categories, func(c *domain.Category) Node {
+				q := url.Values{}
+				q.Set("category", c.Name)
+
+				return Li(
+					components.Chip(fmt.Sprintf("%s (%d)", c.Name, c.EventCount), "/?"+q.Encode()),
+				)
+			}),
+		),
+	)
+}
+
+func tagCloud(l *i18n.Locale, tags []*domain.Tag, selected []string, match string) Node {
 	hist, classes := calcHistogram(tags)
 
 	getHistogramClasses := func(tag *domain.Tag) Classes {
@@ -49,34 +80,59 @@ func TagListPartial(tags []*domain.Tag, csrf string) Node {
 		panic("no bucket found")
 	}
 
+	// getTagURL returns the upcoming events URL with the tag toggled in the selection.
+	getTagURL := func(tag *domain.Tag) string {
+		q := url.Values{}
+
+		if slices.Contains(selected, tag.Name) {
+			for _, name := range selected {
+				if name != tag.Name {
+					q.Add("tag", name)
+				}
+			}
+		} else {
+			for _, name := range selected {
+				q.Add("tag", name)
+			}
+			q.Add("tag", tag.Name)
+		}
+
+		if match != "" && len(q["tag"]) > 1 {
+			q.Set("tag_match", match)
+		}
+
+		if len(q) == 0 {
+			return "/"
+		}
+
+		return "/?" + q.Encode()
+	}
+
 	return Div(Class("max-w-3xl mx-auto my-5"),
 		Ul(Class("flex justify-center flex-wrap align-center gap-2 leading-8"),
 			Map(tags, func(tag *domain.Tag) Node {
+				isSelected := slices.Contains(selected, tag.Name)
+
 				classes := Classes{
 					"hover:underline":      true,
 					"hover:cursor-pointer": true,
 				}
 				maps.Copy(classes, getHistogramClasses(tag))
+				maps.Copy(classes, Classes{
+					"underline":         isSelected,
+					"decoration-accent": isSelected,
+					"decoration-2":      isSelected,
+				})
 
 				return Li(
 					A(classes,
+						Href(getTagURL(tag)),
+						If(isSelected, Title(l.T("Remove from filter"))),
+						If(!isSelected, Title(l.T("Add to filter"))),
 						Text(tag.Name),
 						Sup(Class("text-gray-400"),
 							Textf("(%d)", tag.EventCount),
 						),
-						// Show upcoming tagged events on click.
-						hx.Post("/"),
-						hx.Trigger("click"),
-						Attr("onclick", fmt.Sprintf(`changeTab(document.querySelectorAll(".nav-link")[0]); setSearch("%s")`, tag.Name)),
-
-						hx.Target("#event-list"),
-						hx.Swap("innerHTML"),
-						hx.PushURL("true"),
-						hx.Indicator("#loading-spinner"),
-						hx.Vals(string(must(json.Marshal(map[string]string{
-							"csrf":   csrf,
-							"search": tag.Name,
-						})))),
 					),
 				)
 			}),
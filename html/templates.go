@@ -0,0 +1,81 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	hx "maragu.dev/gomponents-htmx"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+)
+
+// TemplatesMain renders the event templates of the user.
+func TemplatesMain(l *i18n.Locale, user *domain.User, templates []*domain.EventTemplate, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			If(len(templates) == 0,
+				Div(Class("px-3 py-4 text-center"),
+					P(Text(l.T("no templates found"))),
+					P(Class("text-sm text-gray-500"), Text(l.T("Templates are saved from the event form."))),
+				),
+			),
+
+			Iff(len(templates) > 0, func() Node {
+				return Table(Class("table-fixed w-full"),
+					THead(
+						Tr(
+							Th(Class("text-left"), Text(l.T("Template"))),
+							Th(Class("text-left"), Text(l.T("Title"))),
+							Th(Class("text-left"), Text(l.T("Actions"))),
+						),
+					),
+					TBody(
+						Map(templates, func(t *domain.EventTemplate) Node {
+							return Tr(
+								Td(Class("py-1"),
+									Span(Class("font-semibold"), Text(t.Name)),
+									If(t.IsSiteWide(), P(Class("text-sm text-gray-400"), Text(l.T("Available to all users")))),
+								),
+								Td(Text(t.Title)),
+								Td(
+									A(Class("hover:underline text-accent font-semibold px-1"),
+										Href(fmt.Sprintf("/edit/0?template=%d", t.ID)),
+										Text(l.T("USE")),
+									),
+									If(t.CanDelete(user),
+										A(Class("hover:underline text-accent font-semibold px-1"),
+											hx.Post("/templates/delete"),
+											hx.Confirm(l.T("Are you sure?")),
+											hx.Vals(string(must(json.Marshal(map[string]string{
+												"csrf":        csrf,
+												"template_id": t.ID.String(),
+											})))),
+											Href("#"),
+											Text(l.T("DELETE")),
+										),
+									),
+								),
+							)
+						}),
+					),
+				)
+			}),
+		),
+	)
+}
+
+// SaveTemplatePartial renders the result of saving a template.
+func SaveTemplatePartial(l *i18n.Locale, message string, success bool) Node {
+	return P(Classes{
+		"text-sm":       true,
+		"italic":        true,
+		"pt-2":          true,
+		"text-teal-700": success,
+		"text-red-500":  !success,
+	},
+		Text(l.T(message)),
+	)
+}
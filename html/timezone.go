@@ -0,0 +1,27 @@
+package html
+
+import (
+	"github.com/mgnsk/calendar/i18n"
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// timezoneSelector renders the viewer time zone selector.
+func timezoneSelector(l *i18n.Locale, timezone, csrf string) Node {
+	return Form(Class("max-w-3xl mx-auto px-3 py-4 flex items-center justify-end gap-2 text-sm text-gray-500"),
+		Method("POST"),
+		Action("/timezone"),
+		Label(For("viewer-timezone"), Text(l.T("Times shown in"))),
+		Input(ID("viewer-timezone"), Class("border-b border-gray-300 px-1 focus:outline-none"),
+			Name("timezone"),
+			Type("text"),
+			Value(timezone),
+			Placeholder(l.T("Automatic")),
+			Attr("list", "viewer-timezones"),
+			AutoComplete("off"),
+		),
+		DataList(ID("viewer-timezones"), Data("timezones", "")),
+		Input(Type("hidden"), Name("csrf"), Value(csrf)),
+		Button(Type("submit"), Class("hover:underline text-accent font-semibold"), Text(l.T("Set"))),
+	)
+}
@@ -0,0 +1,29 @@
+// Remember the browser time zone unless the viewer has chosen one.
+(function () {
+  const hasTimezone = document.cookie
+    .split("; ")
+    .some((c) => c.startsWith("timezone="));
+
+  if (!hasTimezone) {
+    const tz = Intl.DateTimeFormat().resolvedOptions().timeZone;
+    if (tz) {
+      document.cookie = `timezone=${tz}; path=/; max-age=31536000; samesite=lax`;
+    }
+  }
+})();
+
+document.addEventListener("DOMContentLoaded", () => {
+  if (!Intl.supportedValuesOf) {
+    return;
+  }
+
+  const timezones = Intl.supportedValuesOf("timeZone");
+
+  document.querySelectorAll("datalist[data-timezones]").forEach((list) => {
+    for (const tz of timezones) {
+      const option = document.createElement("option");
+      option.value = tz;
+      list.appendChild(option);
+    }
+  });
+});
@@ -10,25 +10,27 @@ import (
 	"github.com/mgnsk/calendar/contract"
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
 	. "maragu.dev/gomponents"
 	hx "maragu.dev/gomponents-htmx"
 	. "maragu.dev/gomponents/html"
 )
 
 // UsersMain renders the users page main content.
-func UsersMain(currentUser *domain.User, users []*domain.User, csrf string) Node {
+// The invite form has an email field when mail is enabled.
+func UsersMain(l *i18n.Locale, currentUser *domain.User, users []*domain.User, mailEnabled bool, csrf string) Node {
 	return Main(
 		Div(Class("max-w-3xl mx-auto"),
-			UsersListPartial(currentUser, users, csrf),
+			UsersListPartial(l, currentUser, users, mailEnabled, csrf),
 		),
 	)
 }
 
 // UsersListPartial renders users list partial.
-func UsersListPartial(currentUser *domain.User, users []*domain.User, csrf string) Node {
+func UsersListPartial(l *i18n.Locale, currentUser *domain.User, users []*domain.User, mailEnabled bool, csrf string) Node {
 	if len(users) == 0 {
 		return Div(Class("px-3 py-4 text-center"),
-			P(Text("no users found")),
+			P(Text(l.T("no users found"))),
 		)
 	}
 
@@ -36,41 +38,43 @@ func UsersListPartial(currentUser *domain.User, users []*domain.User, csrf strin
 		Table(Class("table-fixed w-full"),
 			THead(
 				Tr(
-					Th(Class("text-left"), Text("Username")),
-					Th(Class("text-left"), Text("Role")),
-					Th(Class("text-left"), Text("Created at")),
-					Th(Class("text-left"), Text("Actions")),
+					Th(Class("text-left"), Text(l.T("Username"))),
+					Th(Class("text-left"), Text(l.T("Email"))),
+					Th(Class("text-left"), Text(l.T("Role"))),
+					Th(Class("text-left"), Text(l.T("Created at"))),
+					Th(Class("text-left"), Text(l.T("Actions"))),
 				),
 			),
 			TBody(
 				Map(users, func(user *domain.User) Node {
 					return Tr(
 						Td(Text(user.Username)),
-						Td(Text(string(user.Role))),
+						Td(Class("truncate"), Text(user.Email)),
+						Td(Text(l.T(string(user.Role)))),
 						Td(Text(user.GetCreatedAt().Format(time.DateTime))),
 						Td(
 							If(currentUser.Role == domain.Admin && currentUser.ID != user.ID,
-								A(Class("hover:underline text-amber-600 font-semibold px-1"),
+								A(Class("hover:underline text-accent font-semibold px-1"),
 									hx.Post("/delete-user"),
-									hx.Confirm("Delete user. Are you sure?"),
+									hx.Confirm(l.T("Delete user. Are you sure?")),
 									hx.Vals(string(must(json.Marshal(map[string]string{
 										"csrf":    csrf,
 										"user_id": user.ID.String(),
 									})))),
 									Href("#"),
-									Text("DELETE"),
+									Text(l.T("DELETE")),
 								),
 							),
 							If(currentUser.Role == domain.Admin && user.Role != domain.Admin,
-								A(Class("hover:underline text-amber-600 font-semibold px-1"),
+								A(Class("hover:underline text-accent font-semibold px-1"),
 									hx.Post("/upgrade-user"),
-									hx.Confirm("Upgrade user to admin. Are you sure?"),
+									hx.Confirm(l.T("Upgrade user to admin. Are you sure?")),
 									hx.Vals(string(must(json.Marshal(map[string]string{
 										"csrf":    csrf,
 										"user_id": user.ID.String(),
 									})))),
 									Href("#"),
-									Text("ADMIN"),
+									Text(l.T("ADMIN")),
 								),
 							),
 						),
@@ -78,26 +82,45 @@ func UsersListPartial(currentUser *domain.User, users []*domain.User, csrf strin
 				}),
 			),
 		),
-		Div(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
-			components.ButtonElement("Invite",
-				hx.Post("/invite"),
-				hx.Swap("outerHTML"),
-				hx.Vals(string(must(json.Marshal(map[string]string{
-					"csrf": csrf,
-				})))),
+		Iff(mailEnabled, func() Node {
+			return InviteFormPartial(l, contract.InviteForm{}, nil, csrf)
+		}),
+		If(!mailEnabled,
+			Div(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+				components.ButtonElement(l.T("Invite"),
+					hx.Post("/invite"),
+					hx.Swap("outerHTML"),
+					hx.Vals(string(must(json.Marshal(map[string]string{
+						"csrf": csrf,
+					})))),
+				),
 			),
 		),
 	)
 }
 
+// InviteFormPartial renders the invite form with an optional email address.
+func InviteFormPartial(l *i18n.Locale, form contract.InviteForm, errs url.Values, csrf string) Node {
+	return Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+		hx.Post("/invite"),
+		hx.Swap("outerHTML"),
+		P(Class("text-sm text-gray-500"), Text(l.T("Enter an email address to send the invite link or leave it empty to copy the link."))),
+		components.InputElement("email", "email", l.T("Email"), form.Email, l.T(errs.Get("email")), false, false),
+		Input(Type("hidden"), Name("csrf"), Value(csrf)),
+		components.SubmitButtonElement(l.T("Invite")),
+	)
+}
+
 // InviteLinkPartial renders an invite link.
-func InviteLinkPartial(token uuid.UUID) Node {
+// The email is the address the link was sent to or empty.
+func InviteLinkPartial(l *i18n.Locale, token uuid.UUID, email string) Node {
 	u := fmt.Sprintf("/register/%s", token.String())
 
 	return Div(
-		P(Text("Copy and share this one-time link:")),
+		If(email != "", P(Text(l.T("The invite link was emailed to %s.", email)))),
+		P(Text(l.T("Copy and share this one-time link:"))),
 		A(ID("invite-link"),
-			Class("hover:underline text-amber-600 font-semibold"),
+			Class("hover:underline text-accent font-semibold"),
 			Href(u),
 			Target("_blank"),
 		),
@@ -106,24 +129,60 @@ func InviteLinkPartial(token uuid.UUID) Node {
 }
 
 // RegisterMain renders the registration page main content.
-func RegisterMain(form contract.RegisterForm, errs url.Values, csrf string) Node {
+// The optional email field is shown when mail is enabled.
+func RegisterMain(l *i18n.Locale, form contract.RegisterForm, errs url.Values, mailEnabled bool, csrf string) Node {
 	return Main(
 		Div(Class("max-w-3xl mx-auto"),
 			Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
 				Method("POST"),
 
-				Label(Class("block w-full pt-2"), For("username"), Text("Username")),
-				components.InputElement("username", "text", "Username", form.Username, errs.Get("username"), true, false),
+				Label(Class("block w-full pt-2"), For("username"), Text(l.T("Username"))),
+				components.InputElement("username", "text", l.T("Username"), form.Username, l.T(errs.Get("username")), true, false),
+
+				Iff(mailEnabled, func() Node {
+					return Group{
+						Label(Class("block w-full pt-2"), For("email"), Text(l.T("Email (optional)"))),
+						components.InputElement("email", "email", l.T("Email"), form.Email, l.T(errs.Get("email")), false, false),
+					}
+				}),
 
-				Label(Class("block w-full pt-2"), For("password1"), Text("Password")),
-				components.InputElement("password1", "password", "Password", form.Password1, errs.Get("password1"), true, false),
+				Label(Class("block w-full pt-2"), For("password1"), Text(l.T("Password"))),
+				components.InputElement("password1", "password", l.T("Password"), form.Password1, l.T(errs.Get("password1")), true, false),
 
-				Label(Class("block w-full pt-2"), For("password2"), Text("Password again")),
-				components.InputElement("password2", "password", "Password again", form.Password2, errs.Get("password2"), true, false),
+				Label(Class("block w-full pt-2"), For("password2"), Text(l.T("Password again"))),
+				components.InputElement("password2", "password", l.T("Password again"), form.Password2, l.T(errs.Get("password2")), true, false),
 
 				Input(Type("hidden"), Name("csrf"), Value(csrf)),
 
-				components.SubmitButtonElement("Register"),
+				components.SubmitButtonElement(l.T("Register")),
+			),
+		),
+	)
+}
+
+// AccountMain renders the account settings page main content.
+func AccountMain(l *i18n.Locale, user *domain.User, form contract.AccountForm, errs url.Values, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+				Method("POST"),
+
+				P(Text(user.Username)),
+
+				Label(Class("block w-full pt-2"), For("email"), Text(l.T("Email"))),
+				components.InputElement("email", "email", l.T("Email"), form.Email, l.T(errs.Get("email")), false, true),
+				P(Class("text-sm text-gray-500"), Text(l.T("Used for notifications and resetting your password."))),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				components.SubmitButtonElement(l.T("Save")),
+			),
+
+			P(Class("text-center"),
+				A(Class("hover:underline text-accent font-semibold"),
+					Href("/app-passwords"),
+					Text(l.T("App passwords for calendar apps")),
+				),
 			),
 		),
 	)
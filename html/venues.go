@@ -0,0 +1,227 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "maragu.dev/gomponents"
+	hx "maragu.dev/gomponents-htmx"
+	. "maragu.dev/gomponents/html"
+)
+
+// VenuesMain renders the venue directory.
+func VenuesMain(l *i18n.Locale, user *domain.User, venues []*domain.Venue, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			Iff(user != nil, func() Node {
+				return Div(Class("flex justify-end gap-4 py-3"),
+					A(Class("hover:underline text-accent font-semibold"), Href("/venues/edit/0"), Text(l.T("ADD VENUE"))),
+					If(user.Role == domain.Admin,
+						A(Class("hover:underline text-accent font-semibold"), Href("/venues/merge"), Text(l.T("MERGE VENUES"))),
+					),
+				)
+			}),
+
+			If(len(venues) == 0,
+				Div(Class("px-3 py-4 text-center"),
+					P(Text(l.T("no venues found"))),
+				),
+			),
+
+			Iff(len(venues) > 0, func() Node {
+				return Table(Class("table-fixed w-full"),
+					THead(
+						Tr(
+							Th(Class("text-left"), Text(l.T("Venue"))),
+							Th(Class("text-left"), Text(l.T("Upcoming events"))),
+							If(user != nil && user.Role == domain.Admin, Th(Class("text-left"), Text(l.T("Actions")))),
+						),
+					),
+					TBody(
+						Map(venues, func(v *domain.Venue) Node {
+							return Tr(
+								Td(Class("py-1"),
+									A(Class("hover:underline font-semibold"), Href(fmt.Sprintf("/venue/%d", v.ID)), Text(v.Name)),
+									If(v.Address != "", P(Class("text-sm text-gray-400"), Text(v.Address))),
+								),
+								Td(Text(strconv.FormatUint(v.EventCount, 10))),
+								If(user != nil && user.Role == domain.Admin, Td(
+									A(Class("hover:underline text-accent font-semibold px-1"),
+										Href(fmt.Sprintf("/venues/edit/%d", v.ID)),
+										Text(l.T("EDIT")),
+									),
+									A(Class("hover:underline text-accent font-semibold px-1"),
+										hx.Post("/venues/delete"),
+										hx.Confirm(l.T("Delete venue. Events keep their location. Are you sure?")),
+										hx.Vals(string(must(json.Marshal(map[string]string{
+											"csrf":     csrf,
+											"venue_id": v.ID.String(),
+										})))),
+										Href("#"),
+										Text(l.T("DELETE")),
+									),
+								)),
+							)
+						}),
+					),
+				)
+			}),
+		),
+	)
+}
+
+// VenueMain renders the venue page with a list of upcoming events.
+func VenueMain(l *i18n.Locale, user *domain.User, venue *domain.Venue, csrf string) Node {
+	feed := url.Values{}
+	feed.Set("venue", venue.ID.String())
+
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3 md:px-6 py-4"),
+			H1(Class("tracking-wide text-xl md:text-2xl font-semibold"),
+				If(venue.URL != "",
+					A(Class("hover:underline"), Href(venue.URL), Target("_blank"), Rel("noopener"), Text(venue.Name)),
+				),
+				If(venue.URL == "",
+					Text(venue.Name),
+				),
+			),
+			If(venue.Address != "",
+				A(Class("hover:underline"), Rel("noopener noreferrer"), Target("_blank"), Href(mapsURL(venue.GetLocation())),
+					Div(Class("block mt-2 tracking-wide text-sm text-gray-400"),
+						I(Class("fa fa-location-arrow pr-1"), Aria("hidden", "true")),
+						Text(venue.Address),
+					),
+				),
+			),
+			If(venue.Accessibility != "",
+				P(Class("mt-2 text-gray-700 whitespace-pre-line"),
+					I(Class("fa fa-wheelchair pr-1"), Aria("hidden", "true")),
+					Text(venue.Accessibility),
+				),
+			),
+			Div(Class("mt-3 flex flex-wrap gap-4 text-sm"),
+				A(Class("hover:underline text-accent font-semibold"), Href("/feed?"+feed.Encode()), Text("RSS")),
+				A(Class("hover:underline text-accent font-semibold"), Href("/calendar.ics?"+feed.Encode()), Text("iCal")),
+				If(venue.Latitude != 0 || venue.Longitude != 0,
+					A(Class("hover:underline text-accent font-semibold"),
+						Href(fmt.Sprintf("/map?near=%s,%s&radius=1km",
+							strconv.FormatFloat(venue.Latitude, 'f', -1, 64),
+							strconv.FormatFloat(venue.Longitude, 'f', -1, 64),
+						)),
+						Text(l.T("Map")),
+					),
+				),
+				If(user != nil && user.Role == domain.Admin,
+					A(Class("hover:underline text-accent font-semibold"), Href(fmt.Sprintf("/venues/edit/%d", venue.ID)), Text(l.T("EDIT"))),
+				),
+			),
+		),
+		EventsMain(csrf),
+	)
+}
+
+// EditVenueMain renders the edit venue page main content.
+func EditVenueMain(l *i18n.Locale, form contract.EditVenueForm, errs url.Values, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			Form(Class("w-full px-3 py-4 mx-auto"),
+				Method("POST"),
+
+				components.InputElement("name", "text", l.T("Name"), form.Name, l.T(errs.Get("name")), true, false),
+
+				Div(Class("relative"),
+					components.InputElement("address", "text", l.T("Address"), form.Address, l.T(errs.Get("address")), false, false),
+					Div(ID("location-spinner"), Class("opacity-0 absolute top-0 right-0 h-full flex items-center mr-2"),
+						components.Spinner(2),
+					),
+				),
+
+				components.InputElement("url", "url", l.T("URL"), form.URL, l.T(errs.Get("url")), false, false),
+
+				Label(Class("block w-full pb-2"), For("accessibility"), Text(l.T("Accessibility notes"))),
+				components.TextareaElement("accessibility", form.Accessibility, l.T(errs.Get("accessibility")), 3, false, false),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+				Input(Type("hidden"), Name("osm_type"), Value(form.OSMType)),
+				Input(Type("hidden"), Name("osm_id"), Value(strconv.FormatUint(form.OSMID, 10))),
+				Input(Type("hidden"), Name("latitude"), Value(strconv.FormatFloat(form.Latitude, 'f', -1, 64))),
+				Input(Type("hidden"), Name("longitude"), Value(strconv.FormatFloat(form.Longitude, 'f', -1, 64))),
+
+				If(form.VenueID > 0,
+					P(Class("text-sm text-gray-500"), Text(l.T("Saving updates the location of all events at this venue."))),
+				),
+
+				components.SubmitButtonElement(l.T("Save")),
+			),
+		),
+	)
+}
+
+// MergeVenuesMain renders the merge venues form.
+func MergeVenuesMain(l *i18n.Locale, form contract.MergeVenuesForm, venues []*domain.Venue, errs url.Values, csrf string) Node {
+	label := func(v *domain.Venue) string {
+		return l.T("%s (%d events)", v.GetLocation(), v.EventCount)
+	}
+
+	return Main(
+		Div(Class("max-w-3xl mx-auto"),
+			Form(Class("w-full px-3 py-4 mx-auto"),
+				Method("POST"),
+
+				Label(Class("block w-full pb-2"), For("target_id"), Text(l.T("Venue to keep"))),
+				If(errs.Get("target_id") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("target_id"))))),
+				Select(components.BaseFormElementClasses(),
+					Name("target_id"),
+					Option(Value(""), Text(l.T("Select venue"))),
+					Map(venues, func(v *domain.Venue) Node {
+						return Option(Value(v.ID.String()), If(v.ID == form.TargetID, Selected()), Text(label(v)))
+					}),
+				),
+
+				FieldSet(components.BaseFormElementClasses(),
+					Legend(Class("font-semibold"), Text(l.T("Duplicates to merge into it"))),
+					If(errs.Get("source_id") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("source_id"))))),
+					Div(Class("flex flex-col gap-y-1"),
+						Map(venues, func(v *domain.Venue) Node {
+							return components.CheckboxElement("source_id", v.ID.String(), label(v), slices.Contains(form.SourceIDs, v.ID))
+						}),
+					),
+				),
+
+				P(Class("text-sm text-gray-500"), Text(l.T("Events of the merged venues are moved to the kept venue and the merged venues are deleted."))),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				components.SubmitButtonElement(l.T("Merge"),
+					Attr("onclick", fmt.Sprintf("return confirm(%q)", l.T("Confirm merging venues"))),
+				),
+			),
+		),
+	)
+}
+
+func venuePicker(l *i18n.Locale, venueID snowflake.ID, venues []*domain.Venue) Node {
+	return Select(components.BaseFormElementClasses(),
+		Name("venue_id"),
+		Option(Value("0"), Text(l.T("No venue, enter the location below"))),
+		Map(venues, func(v *domain.Venue) Node {
+			return Option(Value(v.ID.String()),
+				If(v.ID == venueID, Selected()),
+				Data("location", v.GetLocation()),
+				Data("osm-type", v.OSMType),
+				Data("osm-id", strconv.FormatUint(v.OSMID, 10)),
+				Data("latitude", strconv.FormatFloat(v.Latitude, 'f', -1, 64)),
+				Data("longitude", strconv.FormatFloat(v.Longitude, 'f', -1, 64)),
+				Text(v.Name),
+			)
+		}),
+	)
+}
@@ -0,0 +1,155 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mgnsk/calendar/contract"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/html/components"
+	"github.com/mgnsk/calendar/i18n"
+	"github.com/samber/lo"
+	. "maragu.dev/gomponents"
+	hx "maragu.dev/gomponents-htmx"
+	. "maragu.dev/gomponents/html"
+)
+
+// WebhooksMain renders the webhook list and the add webhook form.
+func WebhooksMain(l *i18n.Locale, webhooks []*domain.Webhook, form contract.WebhookForm, errs url.Values, csrf string) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			If(len(webhooks) == 0,
+				Div(Class("px-3 py-4 text-center"),
+					P(Text(l.T("no webhooks found"))),
+				),
+			),
+
+			Iff(len(webhooks) > 0, func() Node {
+				return Table(Class("table-fixed w-full text-sm"),
+					THead(
+						Tr(
+							Th(Class("text-left"), Text(l.T("URL"))),
+							Th(Class("text-left"), Text(l.T("Event types"))),
+							Th(Class("text-left"), Text(l.T("Actions"))),
+						),
+					),
+					TBody(
+						Map(webhooks, func(w *domain.Webhook) Node {
+							vals := string(must(json.Marshal(map[string]string{
+								"csrf":       csrf,
+								"webhook_id": w.ID.String(),
+							})))
+
+							return Tr(
+								Td(Class("py-1 break-all"),
+									P(Class("font-semibold"), Text(w.URL)),
+									P(Class("text-gray-400"), Text(l.T("Secret: %s", w.Secret))),
+								),
+								Td(Text(strings.Join(lo.Map(w.EventTypes, func(t domain.WebhookEventType, _ int) string {
+									return string(t)
+								}), ", "))),
+								Td(
+									A(Class("hover:underline text-accent font-semibold px-1"),
+										Href(fmt.Sprintf("/webhooks/%d/deliveries", w.ID)),
+										Text(l.T("DELIVERIES")),
+									),
+									A(Class("hover:underline text-accent font-semibold px-1"),
+										hx.Post("/webhooks/test"),
+										hx.Vals(vals),
+										Href("#"),
+										Text(l.T("SEND TEST")),
+									),
+									A(Class("hover:underline text-accent font-semibold px-1"),
+										hx.Post("/webhooks/delete"),
+										hx.Confirm(l.T("Delete webhook and its delivery log. Are you sure?")),
+										hx.Vals(vals),
+										Href("#"),
+										Text(l.T("DELETE")),
+									),
+								),
+							)
+						}),
+					),
+				)
+			}),
+
+			Form(Class("text-center w-full sm:w-1/2 px-3 py-4 mx-auto"),
+				Method("POST"),
+				Action("/webhooks"),
+
+				P(Text(l.T("Add a webhook. Requests are signed with the secret in the X-Calendar-Signature header."))),
+
+				components.InputElement("url", "url", "https://", form.URL, l.T(errs.Get("url")), true, false),
+
+				Div(Class("flex flex-col items-start gap-1 pt-2"),
+					Map(domain.WebhookEventTypes, func(t domain.WebhookEventType) Node {
+						return components.CheckboxElement("event_types", string(t), string(t), slices.Contains(form.EventTypes, string(t)))
+					}),
+				),
+				If(errs.Get("event_types") != "", P(Class("text-red-500 text-sm italic"), Text(l.T(errs.Get("event_types"))))),
+
+				Input(Type("hidden"), Name("csrf"), Value(csrf)),
+
+				components.SubmitButtonElement(l.T("Add webhook")),
+			),
+		),
+	)
+}
+
+// WebhookDeliveriesMain renders the delivery log of a webhook.
+func WebhookDeliveriesMain(l *i18n.Locale, w *domain.Webhook, deliveries []*domain.WebhookDelivery) Node {
+	return Main(
+		Div(Class("max-w-3xl mx-auto px-3"),
+			P(Class("font-semibold break-all py-3"), Text(w.URL)),
+
+			If(len(deliveries) == 0,
+				Div(Class("px-3 py-4 text-center"),
+					P(Text(l.T("no deliveries found"))),
+				),
+			),
+
+			Iff(len(deliveries) > 0, func() Node {
+				return Table(Class("w-full text-sm"),
+					THead(
+						Tr(
+							Th(Class("text-left"), Text(l.T("Time"))),
+							Th(Class("text-left"), Text(l.T("Event type"))),
+							Th(Class("text-left"), Text(l.T("Status"))),
+							Th(Class("text-left"), Text(l.T("Attempts"))),
+							Th(Class("text-left"), Text(l.T("Response"))),
+						),
+					),
+					TBody(
+						Map(deliveries, func(d *domain.WebhookDelivery) Node {
+							return Tr(
+								Td(Class("py-1"), Text(l.FormatDateTime(d.UpdatedAt))),
+								Td(Text(string(d.EventType))),
+								Td(Text(webhookDeliveryStatusLabel(l, d.Status))),
+								Td(Text(strconv.Itoa(d.Attempts))),
+								Td(Class("break-all"),
+									If(d.ResponseCode > 0, Text(strconv.Itoa(d.ResponseCode))),
+									If(d.LastError != "", P(Class("text-red-500"), Text(d.LastError))),
+								),
+							)
+						}),
+					),
+				)
+			}),
+		),
+	)
+}
+
+func webhookDeliveryStatusLabel(l *i18n.Locale, s domain.WebhookDeliveryStatus) string {
+	switch s {
+	case domain.WebhookDeliveryDelivered:
+		return l.T("Delivered")
+	case domain.WebhookDeliveryFailed:
+		return l.T("Failed")
+	default:
+		return l.T("Pending")
+	}
+}
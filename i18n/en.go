@@ -0,0 +1,60 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// English is the English locale. Messages are keyed by their English text
+// so that no translations are needed.
+var English = &Locale{
+	tag:      language.English,
+	name:     "English",
+	messages: map[string]string{},
+
+	formatDateTime: func(t time.Time) string {
+		var buf strings.Builder
+		buf.WriteString(t.Format("January _2, 2006 "))
+		buf.WriteString(englishTime(t))
+
+		return buf.String()
+	},
+
+	formatDate: func(t time.Time) string {
+		return t.Format("Monday, January 2, 2006")
+	},
+
+	formatTime: englishTime,
+
+	formatDay: func(day int) string {
+		return fmt.Sprintf("%d%s", day, englishOrdinalSuffix(day))
+	},
+}
+
+func englishTime(t time.Time) string {
+	if t.Minute() == 0 {
+		return t.Format("3PM")
+	}
+
+	return t.Format("3:04PM")
+}
+
+func englishOrdinalSuffix(n int) string {
+	if n >= 11 && n <= 13 {
+		return "th"
+	}
+
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	default:
+		return "th"
+	}
+}
@@ -0,0 +1,510 @@
+package i18n
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+var estonianMonths = [...]string{
+	"jaanuar",
+	"veebruar",
+	"märts",
+	"aprill",
+	"mai",
+	"juuni",
+	"juuli",
+	"august",
+	"september",
+	"oktoober",
+	"november",
+	"detsember",
+}
+
+var estonianWeekdays = [...]string{
+	"pühapäev",
+	"esmaspäev",
+	"teisipäev",
+	"kolmapäev",
+	"neljapäev",
+	"reede",
+	"laupäev",
+}
+
+// Estonian is the Estonian locale.
+var Estonian = &Locale{
+	tag:  language.Estonian,
+	name: "Eesti",
+	messages: map[string]string{
+		// Navigation.
+		"Account":                        "Konto",
+		"Account settings":               "Konto seaded",
+		"RSVPs":                          "Vastused",
+		"Events you have responded to":   "Sündmused, millele oled vastanud",
+		"Home":                           "Avaleht",
+		"Venues":                         "Toimumiskohad",
+		"RSS feed":                       "RSS-voog",
+		"RSS feed for %s":                "%s RSS-voog",
+		"iCal URL":                       "iCali aadress",
+		"Add to Google Calendar":         "Lisa Google'i kalendrisse",
+		"Add event":                      "Lisa sündmus",
+		"Templates":                      "Mallid",
+		"Event templates":                "Sündmuste mallid",
+		"Stop words":                     "Stoppsõnad",
+		"Configure tag cloud stop words": "Seadista sildipilve stoppsõnu",
+		"Categories":                     "Kategooriad",
+		"Configure event categories":     "Seadista sündmuste kategooriaid",
+		"Users":                          "Kasutajad",
+		"Manage users":                   "Halda kasutajaid",
+		"Logout":                         "Logi välja",
+		"Login":                          "Logi sisse",
+		"Upcoming":                       "Tulevased",
+		"Past":                           "Möödunud",
+		"Tags":                           "Sildid",
+		"My events":                      "Minu sündmused",
+		"Map":                            "Kaart",
+		"Show events on a map":           "Näita sündmusi kaardil",
+		"List view":                      "Nimekiri",
+		"Sort search results":            "Järjesta otsingutulemused",
+		"By date":                        "Kuupäeva järgi",
+		"By relevance":                   "Asjakohasuse järgi",
+		"By distance":                    "Kauguse järgi",
+		"Filter...":                      "Filtreeri...",
+		`Search words or "exact phrases". Narrow down with title:, location:, description:, after:YYYY-MM-DD and before:YYYY-MM-DD.`: `Otsi sõnu või "täpseid fraase". Kitsenda title:, location:, description:, after:AAAA-KK-PP ja before:AAAA-KK-PP abil.`,
+		"Near me":                        "Minu lähedal",
+		"Show events near your location": "Näita sündmusi sinu asukoha lähedal",
+		"Within %s of you":               "Sinust %s raadiuses",
+		"Category: %s":                   "Kategooria: %s",
+		"Matching any tag":               "Vastab mõnele sildile",
+		"Matching all tags":              "Vastab kõigile siltidele",
+		"Match any tag":                  "Vasta mõnele sildile",
+		"Match all tags":                 "Vasta kõigile siltidele",
+		"Remove filter":                  "Eemalda filter",
+		"Remove from filter":             "Eemalda filtrist",
+		"Add to filter":                  "Lisa filtrisse",
+		`Matching "%s"`:                  `Vastab otsingule "%s"`,
+		"Times shown in":                 "Ajad on näidatud ajavööndis",
+		"Automatic":                      "Automaatne",
+		"Set":                            "Määra",
+		"Moderation":                     "Modereerimine",
+		"Review submitted events":        "Vaata üle esitatud sündmused",
+		"Settings":                       "Seaded",
+		"Configure the site":             "Seadista lehte",
+		"Submit event":                   "Esita sündmus",
+		"Contact: ":                      "Kontakt: ",
+
+		// Events.
+		"reached the end...":                 "rohkem sündmusi pole...",
+		"no tags found":                      "silte ei leitud",
+		"No events":                          "Sündmusi pole",
+		"EDIT":                               "MUUDA",
+		"DELETE":                             "KUSTUTA",
+		"Are you sure?":                      "Oled kindel?",
+		"[Draft] %s":                         "[Mustand] %s",
+		"Your time: %s":                      "Sinu aeg: %s",
+		"today":                              "täna",
+		"tomorrow":                           "homme",
+		"yesterday":                          "eile",
+		"in %d days":                         "%d päeva pärast",
+		"%d days ago":                        "%d päeva tagasi",
+		"Status: ":                           "Olek: ",
+		"draft":                              "mustand",
+		"published":                          "avaldatud",
+		"Title":                              "Pealkiri",
+		"URL":                                "Veebiaadress",
+		"Location":                           "Asukoht",
+		"Timezone (automatic from location)": "Ajavöönd (automaatselt asukoha järgi)",
+		"Save Draft":                         "Salvesta mustand",
+		"Publish":                            "Avalda",
+		"Save":                               "Salvesta",
+		"Unpublish":                          "Peida",
+		"Confirm publishing this event":      "Kinnita sündmuse avaldamine",
+		"Confirm unpublishing this event":    "Kinnita sündmuse peitmine",
+		"No venue, enter the location below": "Toimumiskohta pole, sisesta asukoht allpool",
+		"Draft saved":                        "Mustand salvestatud",
+		"Event published":                    "Sündmus avaldatud",
+		"Event deleted":                      "Sündmus kustutatud",
+		"Invalid start_at value":             "Vigane algusaeg",
+		"Invalid location timezone":          "Asukoha ajavöönd on vigane",
+		"Non-admin users can only edit own events": "Tavakasutajad saavad muuta ainult oma sündmusi",
+		"Language of title and description":        "Pealkirja ja kirjelduse keel",
+		"Translations":                             "Tõlked",
+		"[Pending review] %s":                      "[Ootab ülevaatust] %s",
+		"pending review":                           "ootab ülevaatust",
+		"Submit":                                   "Esita",
+		"Event submitted for review":               "Sündmus saadeti ülevaatamiseks",
+		"no events awaiting review":                "ülevaatust ootavaid sündmusi pole",
+		"APPROVE":                                  "KINNITA",
+		"REJECT":                                   "LÜKKA TAGASI",
+		"Publish this event?":                      "Kas avaldada see sündmus?",
+		"Reject this event?":                       "Kas lükata see sündmus tagasi?",
+		"Event approved":                           "Sündmus kinnitatud",
+		"Event rejected":                           "Sündmus tagasi lükatud",
+		"Event is not awaiting review":             "Sündmus ei oota ülevaatust",
+		"Images and documents":                     "Pildid ja dokumendid",
+		"Posters and PDF programs, up to %d files of %d MB each.": "Plakatid ja PDF-kavad, kuni %d faili, igaüks kuni %d MB.",
+		"Too many attachments":                     "Liiga palju faile",
+		"Unsupported file type":                    "Failitüüp pole toetatud",
+		"Invalid image":                            "Vigane pilt",
+		"Only admins can moderate events":          "Ainult administraatorid saavad sündmusi modereerida",
+		"scheduled":                                "ajastatud",
+		"Publish the draft at (optional)":          "Avalda mustand ajal (valikuline)",
+		"[Scheduled for %s] %s":                    "[Ajastatud %s] %s",
+		"Draft saved and scheduled for publishing": "Mustand salvestatud ja avaldamine ajastatud",
+		"DUPLICATE":                                "KOPEERI",
+		"Start from a template:":                   "Alusta mallist:",
+		"Template":                                 "Mall",
+		"Save the title, description, location and categories for future events. %s in the title is replaced with the event date.": "Salvesta pealkiri, kirjeldus, asukoht ja kategooriad tulevaste sündmuste jaoks. %s pealkirjas asendatakse sündmuse kuupäevaga.",
+		"Template name":                            "Malli nimi",
+		"Available to all users":                   "Kõigile kasutajatele",
+		"Save as template":                         "Salvesta mallina",
+		"Template saved":                           "Mall salvestatud",
+		"Template deleted":                         "Mall kustutatud",
+		"Template name is required":                "Malli nimi on kohustuslik",
+		"no templates found":                       "malle ei leitud",
+		"Templates are saved from the event form.": "Malle saab salvestada sündmuse vormilt.",
+		"USE": "KASUTA",
+		"Only admins can save site-wide templates":   "Ainult administraatorid saavad salvestada kõigile kasutajatele mõeldud malle",
+		"Only admins can delete site-wide templates": "Ainult administraatorid saavad kustutada kõigile kasutajatele mõeldud malle",
+
+		// RSVPs.
+		"ATTENDEES":          "OSALEJAD",
+		"%d going":           "%d tuleb",
+		"%d maybe":           "%d võib-olla",
+		"%d on the waitlist": "%d ootenimekirjas",
+		"full":               "täis",
+		"%d places left":     "%d vaba kohta",
+		"Are you going?":     "Kas tuled?",
+		"The event is full. Going adds you to the waitlist.": "Sündmus on täis. Tulemise korral lisatakse sind ootenimekirja.",
+		"Email":                   "E-post",
+		"Going":                   "Tulen",
+		"Maybe":                   "Võib-olla",
+		"Not going":               "Ei tule",
+		"You are on the waitlist": "Oled ootenimekirjas",
+		"You are going":           "Sa tuled",
+		"You might be going":      "Sa võib-olla tuled",
+		"You are not going":       "Sa ei tule",
+		"waitlisted":              "ootenimekirjas",
+		"going":                   "tuleb",
+		"maybe":                   "võib-olla",
+		"not going":               "ei tule",
+		"no responses yet":        "vastuseid veel pole",
+		"EXPORT CSV":              "EKSPORDI CSV",
+		"Responded at":            "Vastamise aeg",
+		"(guest)":                 "(külaline)",
+		"REMOVE":                  "EEMALDA",
+		"Maximum number of attendees (0 means unlimited)": "Osalejate maksimaalne arv (0 tähendab piiramatut)",
+		"Capacity": "Kohtade arv",
+		"Add your personal calendar to a calendar app. It contains the events you are going to and your own events with their attendees.": "Lisa oma isiklik kalender kalendrirakendusse. See sisaldab sündmusi, kuhu sa tuled, ja sinu enda sündmusi koos osalejatega.",
+		"Keep the address private, anyone with it can see your calendar.":                                                                 "Hoia aadress privaatsena, igaüks, kellel see on, näeb sinu kalendrit.",
+		"The event is full, you were added to the waitlist":                                                                               "Sündmus on täis, sind lisati ootenimekirja",
+		"Your response was saved":                    "Sinu vastus salvestati",
+		"Attendee removed":                           "Osaleja eemaldatud",
+		"The event has already started":              "Sündmus on juba alanud",
+		"This email has already responded":           "Selle e-posti aadressiga on juba vastatud",
+		"Only the event owner can see the attendees": "Osalejaid näeb ainult sündmuse omanik",
+
+		// Subscriptions.
+		"Subscribe": "Telli",
+		"Get these events by email or in a feed reader": "Saa need sündmused e-postiga või uudistelugejasse",
+		"Follow %s in a feed reader:":                   "Jälgi: %s uudistelugejas:",
+		"Or receive the upcoming events by email:":      "Või saa tulevased sündmused e-postiga:",
+		"Frequency":      "Sagedus",
+		"Weekly":         "Iganädalane",
+		"Daily":          "Igapäevane",
+		"Tag (optional)": "Silt (valikuline)",
+		"Tag":            "Silt",
+		"Check your inbox to confirm the subscription.": "Tellimuse kinnitamiseks vaata oma postkasti.",
+		"Your subscription is confirmed.":               "Sinu tellimus on kinnitatud.",
+		"You have been unsubscribed.":                   "Tellimus on lõpetatud.",
+		"Stop sending the %s digest of %s to %s?":       "Lõpetada %s kokkuvõtte (%s) saatmine aadressile %s?",
+		"Unsubscribe":                                  "Loobu tellimusest",
+		"Subscribers":                                  "Tellijad",
+		"Digest subscriber counts":                     "Kokkuvõtete tellijate arv",
+		"Daily subscribers":                            "Igapäevased tellijad",
+		"Weekly subscribers":                           "Iganädalased tellijad",
+		"Awaiting confirmation":                        "Ootavad kinnitust",
+		"Only admins can view subscribers":             "Ainult administraatorid saavad tellijaid vaadata",
+		"You subscribed to the %s digest of %s.":       "Tellisid %s kokkuvõtte: %s.",
+		"Confirm your subscription with this link:":    "Kinnita oma tellimus selle lingiga:",
+		"If you did not subscribe, ignore this email.": "Kui sa ei tellinud, eira seda kirja.",
+		"Confirm your subscription to %s":              "Kinnita oma tellimus lehel %s",
+		"Confirm":                                      "Kinnita",
+		"Upcoming events of %s:":                       "Tulevased sündmused: %s:",
+		"Unsubscribe: %s":                              "Loobu tellimusest: %s",
+		"%s: %d upcoming events":                       "%s: %d tulevast sündmust",
+		"%s events tagged #%s":                         "%s sündmused sildiga #%s",
+		"%s events":                                    "%s sündmused",
+		"events tagged #%s":                            "sündmused sildiga #%s",
+		"all events":                                   "kõik sündmused",
+		"daily":                                        "igapäevase",
+		"weekly":                                       "iganädalase",
+
+		// Venues.
+		"ADD VENUE":                   "LISA TOIMUMISKOHT",
+		"MERGE VENUES":                "ÜHENDA TOIMUMISKOHAD",
+		"no venues found":             "toimumiskohti ei leitud",
+		"Venue":                       "Toimumiskoht",
+		"Upcoming events":             "Tulevased sündmused",
+		"Actions":                     "Tegevused",
+		"Name":                        "Nimi",
+		"Address":                     "Aadress",
+		"Accessibility notes":         "Ligipääsetavus",
+		"Venue to keep":               "Alles jäetav toimumiskoht",
+		"Select venue":                "Vali toimumiskoht",
+		"Duplicates to merge into it": "Sellega ühendatavad duplikaadid",
+		"Merge":                       "Ühenda",
+		"Confirm merging venues":      "Kinnita toimumiskohtade ühendamine",
+		"%s (%d events)":              "%s (%d sündmust)",
+		"Delete venue. Events keep their location. Are you sure?":                                    "Kustuta toimumiskoht. Sündmused säilitavad oma asukoha. Oled kindel?",
+		"Saving updates the location of all events at this venue.":                                   "Salvestamine uuendab kõigi selle toimumiskoha sündmuste asukohta.",
+		"Events of the merged venues are moved to the kept venue and the merged venues are deleted.": "Ühendatud toimumiskohtade sündmused viiakse alles jäetavasse toimumiskohta ja ühendatud toimumiskohad kustutatakse.",
+		"Venue saved":                               "Toimumiskoht salvestatud",
+		"Venue deleted":                             "Toimumiskoht kustutatud",
+		"Venues merged":                             "Toimumiskohad ühendatud",
+		"Select the venue to keep":                  "Vali alles jäetav toimumiskoht",
+		"Select venues to merge":                    "Vali ühendatavad toimumiskohad",
+		"Venue to keep can't be merged into itself": "Alles jäetavat toimumiskohta ei saa iseendaga ühendada",
+
+		// Administration.
+		"Visitors can RSVP with their name and email": "Külastajad saavad vastata oma nime ja e-postiga",
+		"Old events":                 "Vanad sündmused",
+		"Keep forever":               "Säilita alatiseks",
+		"Return to drafts":           "Muuda mustanditeks",
+		"Days after the event start": "Päevi pärast sündmuse algust",
+		"Invalid retention action":   "Vigane säilitamise toiming",
+		"Start time":                 "Algusaeg",
+		"Status":                     "Olek",
+		"Owner":                      "Omanik",
+		"Anonymous":                  "Anonüümne",
+		"Select all":                 "Vali kõik",
+		"All statuses":               "Kõik olekud",
+		"All categories":             "Kõik kategooriad",
+		"All owners":                 "Kõik omanikud",
+		"Filter":                     "Filtreeri",
+		"Bulk actions":               "Hulgitoimingud",
+		"Delete":                     "Kustuta",
+		"Change owner":               "Muuda omanikku",
+		"Add category":               "Lisa kategooria",
+		"Remove category":            "Eemalda kategooria",
+		"Shift date":                 "Nihuta kuupäeva",
+		"New owner":                  "Uus omanik",
+		"Category":                   "Kategooria",
+		"Days to shift by":           "Mitme päeva võrra nihutada",
+		"Apply":                      "Rakenda",
+		"Apply the action to the selected events?": "Rakendada toiming valitud sündmustele?",
+		"%d events updated, %d failed":             "%d sündmust uuendatud, %d ebaõnnestus",
+		"Previous page":                            "Eelmine leht",
+		"Next page":                                "Järgmine leht",
+		"No events selected":                       "Sündmusi pole valitud",
+		"Unknown user":                             "Tundmatu kasutaja",
+		"Unknown category":                         "Tundmatu kategooria",
+		"Event is already published":               "Sündmus on juba avaldatud",
+		"Event is already a draft":                 "Sündmus on juba mustand",
+		"Event already has the category":           "Sündmusel on see kategooria juba olemas",
+		"Event does not have the category":         "Sündmusel pole seda kategooriat",
+		"Invalid bulk action":                      "Vigane hulgitoiming",
+		"Only admins can change event owners":      "Ainult administraatorid saavad sündmuste omanikku muuta",
+		"Word tags":                                "Sõnasildid",
+		"Categories and word tags":                 "Kategooriad ja sõnasildid",
+		"Tags page shows":                          "Siltide lehel näidatakse",
+		"Categories authors can pick from. One category per line.":                      "Kategooriad, mille seast autorid saavad valida. Üks kategooria rea kohta.",
+		"Stop words are excluded from tags page. One word per line.":                    "Stoppsõnu siltide lehel ei näidata. Üks sõna rea kohta.",
+		"Tag language. Words are reduced to their stem so that word forms share a tag.": "Siltide keel. Sõnad taandatakse tüveks, et sõnavormidel oleks ühine silt.",
+		"No stemming":                         "Tüvestamiseta",
+		"Add stop words from a bundled list.": "Lisa stoppsõnad kaasasolevast loendist.",
+		"Add preset":                          "Lisa loend",
+		"Categories saved":                    "Kategooriad salvestatud",
+		"Stop words saved":                    "Stoppsõnad salvestatud",
+		"Stop words added":                    "Stoppsõnad lisatud",
+		"English":                             "Inglise",
+		"German":                              "Saksa",
+		"Estonian":                            "Eesti",
+		"French":                              "Prantsuse",
+		"Spanish":                             "Hispaania",
+		"Swedish":                             "Rootsi",
+		"Norwegian":                           "Norra",
+		"Hungarian":                           "Ungari",
+		"Russian":                             "Vene",
+		"Default timezone of events":          "Sündmuste vaikimisi ajavöönd",
+		"Accent color":                        "Rõhuvärv",
+		"Logo":                                "Logo",
+		"Favicon":                             "Lehe ikoon",
+		"Remove":                              "Eemalda",
+		"Footer text in markdown":             "Jaluse tekst markdownis",
+		"Contact email":                       "Kontakti e-post",
+		"Features":                            "Funktsioonid",
+		"Visitors can submit events without logging in":   "Külastajad saavad sündmusi esitada sisse logimata",
+		"Events of non-admins are published after review": "Tavakasutajate sündmused avaldatakse pärast ülevaatust",
+		"Show the tags page":                              "Näita siltide lehte",
+		"Settings saved":                                  "Seaded salvestatud",
+		"Only admins can edit settings":                   "Ainult administraatorid saavad seadeid muuta",
+		"Webhooks":                                        "Veebikonksud",
+		"Notify other services of event changes":          "Teavita teisi teenuseid sündmuste muudatustest",
+		"no webhooks found":                               "veebikonkse ei leitud",
+		"Event types":                                     "Sündmuste tüübid",
+		"Secret: %s":                                      "Saladus: %s",
+		"DELIVERIES":                                      "SAATMISED",
+		"SEND TEST":                                       "SAADA TEST",
+		"Delete webhook and its delivery log. Are you sure?":                                     "Kustutada veebikonks ja selle saatmiste logi. Oled sa kindel?",
+		"Add a webhook. Requests are signed with the secret in the X-Calendar-Signature header.": "Lisa veebikonks. Päringud allkirjastatakse saladusega X-Calendar-Signature päises.",
+		"Add webhook":                                "Lisa veebikonks",
+		"Select at least one event type":             "Vali vähemalt üks sündmuse tüüp",
+		"Invalid event type":                         "Vigane sündmuse tüüp",
+		"Webhook added":                              "Veebikonks lisatud",
+		"Webhook deleted":                            "Veebikonks kustutatud",
+		"Test delivered":                             "Test saadetud",
+		"Test delivery failed, see the delivery log": "Testi saatmine ebaõnnestus, vaata saatmiste logi",
+		"Only admins can manage webhooks":            "Ainult administraatorid saavad veebikonkse hallata",
+		"no deliveries found":                        "saatmisi ei leitud",
+		"Time":                                       "Aeg",
+		"Event type":                                 "Sündmuse tüüp",
+		"Attempts":                                   "Katseid",
+		"Response":                                   "Vastus",
+		"Delivered":                                  "Saadetud",
+		"Failed":                                     "Ebaõnnestus",
+		"Pending":                                    "Ootel",
+		"Sites allowed to embed the event list, one origin per line": "Saidid, mis võivad sündmuste nimekirja lisada, üks päritolu real",
+		"Embed":                        "Manustamine",
+		"Show events on partner sites": "Näita sündmusi partnerite saitidel",
+		"Only admins can embed events": "Ainult administraatorid saavad sündmusi manustada",
+		"More events on %s":            "Rohkem sündmusi lehel %s",
+		"Show upcoming events on partner sites. Choose the events and copy the snippet to the partner site.": "Näita tulevasi sündmusi partnerite saitidel. Vali sündmused ja kopeeri koodijupp partneri saidile.",
+		"All venues":              "Kõik toimumiskohad",
+		"Number of events":        "Sündmuste arv",
+		"Language":                "Keel",
+		"Language of the visitor": "Külastaja keel",
+		"Style":                   "Stiil",
+		"Site theme":              "Saidi teema",
+		"No styles":               "Stiilideta",
+		"Generate":                "Loo",
+		"No sites are allowed to embed the event list. Add the partner sites in the ": "Ükski sait ei tohi sündmuste nimekirja lisada. Lisa partnerite saidid ",
+		"settings": "seadetes",
+		"Snippet":  "Koodijupp",
+		"Custom renderers can fetch the events as JSON from %s": "Kohandatud kuvajad saavad sündmused JSON-ina laadida aadressilt %s",
+		"Preview":            "Eelvaade",
+		"Print":              "Prindi",
+		"Printable program":  "Prinditav kava",
+		"From":               "Alates",
+		"Until":              "Kuni",
+		"QR codes":           "QR-koodid",
+		"QR code of %s":      "Sündmuse %s QR-kood",
+		"Show":               "Näita",
+		"Download PDF":       "Laadi alla PDF",
+		"Invalid date":       "Vigane kuupäev",
+		"Invalid date range": "Vigane kuupäevavahemik",
+		"Too many events, choose a shorter date range": "Liiga palju sündmusi, vali lühem kuupäevavahemik",
+
+		// Users.
+		"no users found":                       "kasutajaid ei leitud",
+		"Username":                             "Kasutajanimi",
+		"Password":                             "Parool",
+		"Password again":                       "Parool uuesti",
+		"Role":                                 "Roll",
+		"admin":                                "administraator",
+		"author":                               "autor",
+		"Created at":                           "Loodud",
+		"ADMIN":                                "ADMINISTRAATOR",
+		"Delete user. Are you sure?":           "Kustuta kasutaja. Oled kindel?",
+		"Upgrade user to admin. Are you sure?": "Tee kasutajast administraator. Oled kindel?",
+		"Invite":                               "Kutsu",
+		"Copy and share this one-time link:":   "Kopeeri ja jaga seda ühekordset linki:",
+		"Register":                             "Registreeru",
+		"User deleted":                         "Kasutaja kustutatud",
+		"User upgraded to admin":               "Kasutajast sai administraator",
+		"User already exists":                  "Kasutaja on juba olemas",
+		"Cannot delete yourself":               "Iseennast ei saa kustutada",
+		"Cannot upgrade yourself":              "Iseennast ei saa administraatoriks teha",
+		"Invalid username or password":         "Vale kasutajanimi või parool",
+		"Email (optional)":                     "E-post (valikuline)",
+		"Enter an email address to send the invite link or leave it empty to copy the link.": "Sisesta e-posti aadress kutselingi saatmiseks või jäta tühjaks, et link kopeerida.",
+		"The invite link was emailed to %s.":                                                 "Kutselink saadeti aadressile %s.",
+		"Used for notifications and resetting your password.":                                "Kasutatakse teavitusteks ja parooli lähtestamiseks.",
+		"Account saved":                    "Konto salvestatud",
+		"Email already in use":             "E-posti aadress on juba kasutusel",
+		"Username or email already in use": "Kasutajanimi või e-posti aadress on juba kasutusel",
+		"Forgot password?":                 "Unustasid parooli?",
+		"Enter the email address of your account to receive a password reset link.":   "Sisesta oma konto e-posti aadress, et saada parooli lähtestamise link.",
+		"If an account with this email exists, a password reset link was sent to it.": "Kui selle e-posti aadressiga konto on olemas, saadeti sellele parooli lähtestamise link.",
+		"Send":                                 "Saada",
+		"New password":                         "Uus parool",
+		"Reset password":                       "Lähtesta parool",
+		"Password changed, you can now log in": "Parool muudetud, võid nüüd sisse logida",
+		"App passwords for calendar apps":      "Rakenduste paroolid kalendrirakendustele",
+		"Calendar apps such as Thunderbird and DAVx5 can add and edit your events over CalDAV. Sign in with your username and an app password.": "Kalendrirakendused nagu Thunderbird ja DAVx5 saavad CalDAV-i kaudu sinu sündmusi lisada ja muuta. Logi sisse oma kasutajanime ja rakenduse parooliga.",
+		"Username: %s": "Kasutajanimi: %s",
+		"Copy the app password now. It will not be shown again.": "Kopeeri rakenduse parool kohe. Seda ei näidata uuesti.",
+		"no app passwords found":                                 "rakenduste paroole ei leitud",
+		"Created":                                                "Loodud",
+		"Last used":                                              "Viimati kasutatud",
+		"Never":                                                  "Mitte kunagi",
+		"Calendar apps using the password will be signed out. Are you sure?": "Parooli kasutavad kalendrirakendused logitakse välja. Oled kindel?",
+		"App name":             "Rakenduse nimi",
+		"Phone":                "Telefon",
+		"Add app password":     "Lisa rakenduse parool",
+		"App password deleted": "Rakenduse parool kustutatud",
+
+		// Emails.
+		"You have been invited to add events to %s.":                                          "Sind on kutsutud lisama sündmusi lehele %s.",
+		"Register with this one-time link:":                                                   "Registreeru selle ühekordse lingiga:",
+		"The link is valid for 72 hours.":                                                     "Link kehtib 72 tundi.",
+		"Invitation to %s":                                                                    "Kutse lehele %s",
+		"A password reset was requested for the user %s.":                                     "Kasutajale %s taotleti parooli lähtestamist.",
+		"Choose a new password with this one-time link:":                                      "Vali uus parool selle ühekordse lingiga:",
+		"The link is valid for an hour. If you did not request the reset, ignore this email.": "Link kehtib ühe tunni. Kui sa lähtestamist ei taotlenud, eira seda kirja.",
+		"Password reset for %s":                                                               "Parooli lähtestamine lehel %s",
+		"Your event was approved":                                                             "Sinu sündmus kinnitati",
+		"Your event %s was approved.":                                                         "Sinu sündmus %s kinnitati.",
+		"It will be published at %s.":                                                         "See avaldatakse %s.",
+		"Your event was not approved":                                                         "Sinu sündmust ei kinnitatud",
+		"Your event %s was not approved and was returned to your drafts.":                     "Sinu sündmust %s ei kinnitatud ja see tagastati mustanditesse.",
+		"View event": "Vaata sündmust",
+
+		// Setup.
+		"Description":      "Kirjeldus",
+		"Default language": "Vaikimisi keel",
+
+		// Validation.
+		"Required":                               "Kohustuslik",
+		"Invalid value":                          "Vigane väärtus",
+		"Invalid URL":                            "Vigane veebiaadress",
+		"Invalid format":                         "Vigane vorming",
+		"Invalid origin":                         "Vigane päritolu",
+		"Unknown timezone":                       "Tundmatu ajavöönd",
+		"Unsupported language":                   "Keel pole toetatud",
+		"Title must be set":                      "Pealkiri on kohustuslik",
+		"Username must be set":                   "Kasutajanimi on kohustuslik",
+		"Username must be at least 3 characters": "Kasutajanimi peab olema vähemalt 3 tähemärki",
+		"Username must be at most 30 characters": "Kasutajanimi võib olla kuni 30 tähemärki",
+		"Password must be set":                   "Parool on kohustuslik",
+		"Passwords must match":                   "Paroolid peavad kattuma",
+		"Invalid email address":                  "Vigane e-posti aadress",
+		"File is too large":                      "Fail on liiga suur",
+		"Unsupported image format":               "Pildivorming pole toetatud",
+
+		// Errors.
+		"Error":                "Viga",
+		"Error %d: %s":         "Viga %d: %s",
+		"Something went wrong": "Midagi läks valesti",
+		"Timeout":              "Ajalõpp",
+		"Not found":            "Ei leitud",
+		"Must be logged in":    "Pead olema sisse logitud",
+	},
+
+	formatDateTime: func(t time.Time) string {
+		return fmt.Sprintf("%d. %s %d, %s", t.Day(), estonianMonths[t.Month()-1], t.Year(), t.Format("15:04"))
+	},
+
+	formatDate: func(t time.Time) string {
+		return fmt.Sprintf("%s, %d. %s %d", estonianWeekdays[t.Weekday()], t.Day(), estonianMonths[t.Month()-1], t.Year())
+	},
+
+	formatTime: func(t time.Time) string {
+		return t.Format("15:04")
+	},
+
+	formatDay: func(day int) string {
+		return fmt.Sprintf("%d.", day)
+	},
+}
@@ -0,0 +1,147 @@
+// Package i18n implements UI translations and locale-aware date formatting.
+package i18n
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mgnsk/calendar/pkg/timestamp"
+	"golang.org/x/text/language"
+)
+
+// Locale is a supported UI language.
+type Locale struct {
+	tag      language.Tag
+	name     string
+	messages map[string]string
+
+	formatDateTime func(t time.Time) string
+	formatDate     func(t time.Time) string
+	formatTime     func(t time.Time) string
+	formatDay      func(day int) string
+}
+
+// Code returns the BCP 47 language code.
+func (l *Locale) Code() string {
+	return l.get().tag.String()
+}
+
+// Name returns the language name in the language itself.
+func (l *Locale) Name() string {
+	return l.get().name
+}
+
+// T translates a message. Messages are keyed by their English text.
+// Args are formatted into the translated message with fmt.Sprintf.
+// Messages without a translation are returned in English.
+func (l *Locale) T(key string, args ...any) string {
+	msg := key
+	if translated, ok := l.get().messages[key]; ok {
+		msg = translated
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+
+	return msg
+}
+
+// FormatDateTime returns a formatted date and time of t in its own location.
+func (l *Locale) FormatDateTime(t time.Time) string {
+	return l.get().formatDateTime(t)
+}
+
+// FormatDate returns a formatted weekday and date of t in its own location.
+func (l *Locale) FormatDate(t time.Time) string {
+	return l.get().formatDate(t)
+}
+
+// FormatTime returns a formatted time of day of t in its own location.
+func (l *Locale) FormatTime(t time.Time) string {
+	return l.get().formatTime(t)
+}
+
+// FormatDay returns a formatted day of month.
+func (l *Locale) FormatDay(day int) string {
+	return l.get().formatDay(day)
+}
+
+// RelativeDay returns a label for the number of calendar days between now and t
+// in the location of now, such as "today", "tomorrow" or "in 3 days".
+// It returns an empty string if t is more than a week away.
+func (l *Locale) RelativeDay(t, now time.Time) string {
+	switch days := timestamp.CalendarDays(now, t); {
+	case days == 0:
+		return l.T("today")
+	case days == 1:
+		return l.T("tomorrow")
+	case days == -1:
+		return l.T("yesterday")
+	case days > 1 && days <= 7:
+		return l.T("in %d days", days)
+	case days < -1 && days >= -7:
+		return l.T("%d days ago", -days)
+	default:
+		return ""
+	}
+}
+
+// get returns the locale or the default locale if l is nil.
+func (l *Locale) get() *Locale {
+	if l == nil {
+		return English
+	}
+
+	return l
+}
+
+// Locales lists the supported locales. The first one is the default.
+var Locales = []*Locale{English, Estonian}
+
+var matcher = language.NewMatcher([]language.Tag{
+	English.tag,
+	Estonian.tag,
+})
+
+// IsSupported reports whether code is the code of a supported locale.
+func IsSupported(code string) bool {
+	for _, l := range Locales {
+		if l.Code() == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get returns the supported locale best matching code or the default locale.
+func Get(code string) *Locale {
+	tag, err := language.Parse(code)
+	if err != nil {
+		return Locales[0]
+	}
+
+	_, index, confidence := matcher.Match(tag)
+	if confidence == language.No {
+		return Locales[0]
+	}
+
+	return Locales[index]
+}
+
+// Negotiate returns the supported locale best matching an Accept-Language header value.
+// The fallback language code is used when nothing matches.
+func Negotiate(acceptLanguage, fallback string) *Locale {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Get(fallback)
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Get(fallback)
+	}
+
+	return Locales[index]
+}
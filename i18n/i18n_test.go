@@ -0,0 +1,79 @@
+package i18n_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/i18n"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("negotiating the locale",
+	func(acceptLanguage, fallback string, expected *i18n.Locale) {
+		Expect(i18n.Negotiate(acceptLanguage, fallback)).To(BeIdenticalTo(expected))
+	},
+	Entry("exact match", "et", "", i18n.Estonian),
+	Entry("regional variant", "et-EE,et;q=0.9", "", i18n.Estonian),
+	Entry("weighted preference", "de;q=0.9,en;q=0.8,et;q=0.7", "et", i18n.English),
+	Entry("no match uses fallback", "de", "et", i18n.Estonian),
+	Entry("empty header uses fallback", "", "et", i18n.Estonian),
+	Entry("invalid header uses fallback", ";;;", "et", i18n.Estonian),
+	Entry("no fallback uses default", "de", "", i18n.English),
+)
+
+var _ = Describe("translating", func() {
+	Specify("messages are translated", func() {
+		Expect(i18n.Estonian.T("Login")).To(Equal("Logi sisse"))
+		Expect(i18n.Estonian.T("in %d days", 3)).To(Equal("3 päeva pärast"))
+	})
+
+	Specify("missing messages fall back to English", func() {
+		Expect(i18n.Estonian.T("Unknown message")).To(Equal("Unknown message"))
+		Expect(i18n.English.T("in %d days", 3)).To(Equal("in 3 days"))
+	})
+
+	Specify("nil locale is English", func() {
+		var l *i18n.Locale
+		Expect(l.Code()).To(Equal("en"))
+		Expect(l.T("today")).To(Equal("today"))
+	})
+})
+
+var _ = DescribeTable("formatting dates",
+	func(l *i18n.Locale, t time.Time, expected string) {
+		Expect(l.FormatDateTime(t)).To(Equal(expected))
+	},
+	Entry("English on the hour", i18n.English, time.Date(2025, 3, 2, 18, 0, 0, 0, time.UTC), "March  2, 2025 6PM"),
+	Entry("English with minutes", i18n.English, time.Date(2025, 3, 12, 9, 30, 0, 0, time.UTC), "March 12, 2025 9:30AM"),
+	Entry("Estonian", i18n.Estonian, time.Date(2025, 3, 2, 18, 0, 0, 0, time.UTC), "2. märts 2025, 18:00"),
+)
+
+var _ = DescribeTable("formatting dates and times separately",
+	func(l *i18n.Locale, t time.Time, date, clock string) {
+		Expect(l.FormatDate(t)).To(Equal(date))
+		Expect(l.FormatTime(t)).To(Equal(clock))
+	},
+	Entry("English", i18n.English, time.Date(2025, 3, 2, 18, 30, 0, 0, time.UTC), "Sunday, March 2, 2025", "6:30PM"),
+	Entry("Estonian", i18n.Estonian, time.Date(2025, 3, 2, 18, 0, 0, 0, time.UTC), "pühapäev, 2. märts 2025", "18:00"),
+)
+
+var _ = DescribeTable("formatting days",
+	func(l *i18n.Locale, day int, expected string) {
+		Expect(l.FormatDay(day)).To(Equal(expected))
+	},
+	Entry(nil, i18n.English, 1, "1st"),
+	Entry(nil, i18n.English, 12, "12th"),
+	Entry(nil, i18n.English, 22, "22nd"),
+	Entry(nil, i18n.Estonian, 22, "22."),
+)
+
+var _ = DescribeTable("relative days",
+	func(l *i18n.Locale, t time.Time, expected string) {
+		now := time.Date(2025, 3, 29, 12, 0, 0, 0, time.UTC)
+		Expect(l.RelativeDay(t, now)).To(Equal(expected))
+	},
+	Entry("today", i18n.English, time.Date(2025, 3, 29, 20, 0, 0, 0, time.UTC), "today"),
+	Entry("tomorrow", i18n.Estonian, time.Date(2025, 3, 30, 20, 0, 0, 0, time.UTC), "homme"),
+	Entry("days ago", i18n.English, time.Date(2025, 3, 26, 20, 0, 0, 0, time.UTC), "3 days ago"),
+	Entry("more than a week away", i18n.English, time.Date(2025, 4, 10, 20, 0, 0, 0, time.UTC), ""),
+)
@@ -0,0 +1,6 @@
+CREATE TABLE `categories` (
+  `id` bigint PRIMARY KEY,
+  `name` text NOT NULL,
+  `sort_order` bigint NOT NULL,
+  UNIQUE (`name`)
+);
@@ -0,0 +1,2 @@
+DROP INDEX events_categories_event_id_idx;
+DROP TABLE `events_categories`;
@@ -0,0 +1,6 @@
+CREATE TABLE `events_categories` (
+  `category_id` bigint NOT NULL,
+  `event_id` bigint NOT NULL,
+  PRIMARY KEY (`category_id`, `event_id`)
+);
+CREATE INDEX events_categories_event_id_idx ON events_categories (event_id);
@@ -0,0 +1 @@
+ALTER TABLE settings DROP COLUMN tags_page;
@@ -0,0 +1 @@
+ALTER TABLE settings ADD COLUMN tags_page text NOT NULL DEFAULT 'words';
@@ -0,0 +1 @@
+ALTER TABLE settings ADD COLUMN tag_language text NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP TRIGGER events_rtree_au;
+DROP TRIGGER events_rtree_ad;
+DROP TRIGGER events_rtree_ai;
+DROP TABLE events_rtree;
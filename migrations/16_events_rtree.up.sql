@@ -0,0 +1,17 @@
+-- R*Tree index of event coordinates. Events without coordinates are not indexed.
+CREATE VIRTUAL TABLE events_rtree USING rtree(id, min_lat, max_lat, min_lng, max_lng);
+
+INSERT INTO events_rtree(id, min_lat, max_lat, min_lng, max_lng)
+  SELECT id, latitude, latitude, longitude, longitude FROM events WHERE latitude != 0 OR longitude != 0;
+
+-- Triggers to keep the R*Tree index up to date.
+CREATE TRIGGER events_rtree_ai AFTER INSERT ON events WHEN new.latitude != 0 OR new.longitude != 0 BEGIN
+  INSERT INTO events_rtree(id, min_lat, max_lat, min_lng, max_lng) VALUES (new.id, new.latitude, new.latitude, new.longitude, new.longitude);
+END;
+CREATE TRIGGER events_rtree_ad AFTER DELETE ON events BEGIN
+  DELETE FROM events_rtree WHERE id = old.id;
+END;
+CREATE TRIGGER events_rtree_au AFTER UPDATE ON events BEGIN
+  DELETE FROM events_rtree WHERE id = old.id;
+  INSERT INTO events_rtree(id, min_lat, max_lat, min_lng, max_lng) SELECT new.id, new.latitude, new.latitude, new.longitude, new.longitude WHERE new.latitude != 0 OR new.longitude != 0;
+END;
@@ -0,0 +1,2 @@
+DROP INDEX venues_name_idx;
+DROP TABLE `venues`;
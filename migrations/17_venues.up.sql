@@ -0,0 +1,12 @@
+CREATE TABLE `venues` (
+  `id` bigint PRIMARY KEY,
+  `name` text NOT NULL,
+  `address` text NOT NULL,
+  `osm_type` text NOT NULL,
+  `osm_id` bigint NOT NULL,
+  `latitude` real NOT NULL,
+  `longitude` real NOT NULL,
+  `url` text NOT NULL,
+  `accessibility` text NOT NULL
+);
+CREATE INDEX venues_name_idx ON venues (name);
@@ -0,0 +1,2 @@
+DROP INDEX events_venue_id_idx;
+ALTER TABLE events DROP COLUMN venue_id;
@@ -0,0 +1,2 @@
+ALTER TABLE events ADD COLUMN venue_id bigint NOT NULL DEFAULT '0';
+CREATE INDEX events_venue_id_idx ON events (venue_id);
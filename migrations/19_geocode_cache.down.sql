@@ -0,0 +1 @@
+DROP TABLE `geocode_cache`;
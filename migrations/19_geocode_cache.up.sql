@@ -0,0 +1,7 @@
+CREATE TABLE `geocode_cache` (
+  `query` text NOT NULL,
+  `language` text NOT NULL,
+  `results` text NOT NULL,
+  `created_at_unix` bigint NOT NULL,
+  PRIMARY KEY (`query`, `language`)
+);
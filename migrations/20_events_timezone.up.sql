@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN timezone text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE settings ADD COLUMN locale text NOT NULL DEFAULT '';
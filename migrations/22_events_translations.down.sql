@@ -0,0 +1,25 @@
+DROP TRIGGER events_translations_ai;
+DROP TRIGGER events_translations_ad;
+DROP TRIGGER events_translations_au;
+DROP TRIGGER events_ai;
+DROP TRIGGER events_ad;
+DROP TRIGGER events_au;
+DROP TABLE events_fts;
+DROP VIEW events_search;
+DROP TABLE `events_translations`;
+ALTER TABLE events DROP COLUMN language;
+
+CREATE VIRTUAL TABLE events_fts USING fts5(title, description, location, content='events', content_rowid='id', tokenize='trigram case_sensitive 0 remove_diacritics 1');
+
+INSERT INTO events_fts(events_fts) VALUES('rebuild');
+
+CREATE TRIGGER events_ai AFTER INSERT ON events BEGIN
+  INSERT INTO events_fts(rowid, title, description, location) VALUES (new.id, new.title, new.description, new.location);
+END;
+CREATE TRIGGER events_ad AFTER DELETE ON events BEGIN
+  INSERT INTO events_fts(events_fts, rowid, title, description, location) VALUES('delete', old.id, old.title, old.description, old.location);
+END;
+CREATE TRIGGER events_au AFTER UPDATE ON events BEGIN
+  INSERT INTO events_fts(events_fts, rowid, title, description, location) VALUES('delete', old.id, old.title, old.description, old.location);
+  INSERT INTO events_fts(rowid, title, description, location) VALUES (new.id, new.title, new.description, new.location);
+END;
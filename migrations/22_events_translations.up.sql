@@ -0,0 +1,53 @@
+ALTER TABLE events ADD COLUMN language text NOT NULL DEFAULT '';
+
+CREATE TABLE `events_translations` (
+  `event_id` bigint NOT NULL,
+  `language` text NOT NULL,
+  `title` text NOT NULL,
+  `description` text NOT NULL,
+  PRIMARY KEY (`event_id`, `language`)
+);
+
+-- Indexed event text with all translations appended to the title and description.
+CREATE VIEW events_search AS
+SELECT
+  e.id AS id,
+  e.title || coalesce(char(10) || (SELECT group_concat(t.title, char(10)) FROM events_translations AS t WHERE t.event_id = e.id), '') AS title,
+  e.description || coalesce(char(10) || (SELECT group_concat(t.description, char(10)) FROM events_translations AS t WHERE t.event_id = e.id), '') AS description,
+  e.location AS location
+FROM events AS e;
+
+-- Replace the external content fts5 table with one that stores its own content
+-- since the indexed text no longer maps to columns of a single table.
+DROP TRIGGER events_ai;
+DROP TRIGGER events_ad;
+DROP TRIGGER events_au;
+DROP TABLE events_fts;
+
+CREATE VIRTUAL TABLE events_fts USING fts5(title, description, location, tokenize='trigram case_sensitive 0 remove_diacritics 1');
+
+INSERT INTO events_fts(rowid, title, description, location) SELECT id, title, description, location FROM events_search;
+
+-- Triggers to keep the FTS index up to date.
+CREATE TRIGGER events_ai AFTER INSERT ON events BEGIN
+  INSERT INTO events_fts(rowid, title, description, location) SELECT id, title, description, location FROM events_search WHERE id = new.id;
+END;
+CREATE TRIGGER events_ad AFTER DELETE ON events BEGIN
+  DELETE FROM events_fts WHERE rowid = old.id;
+END;
+CREATE TRIGGER events_au AFTER UPDATE ON events BEGIN
+  DELETE FROM events_fts WHERE rowid = old.id;
+  INSERT INTO events_fts(rowid, title, description, location) SELECT id, title, description, location FROM events_search WHERE id = new.id;
+END;
+CREATE TRIGGER events_translations_ai AFTER INSERT ON events_translations BEGIN
+  DELETE FROM events_fts WHERE rowid = new.event_id;
+  INSERT INTO events_fts(rowid, title, description, location) SELECT id, title, description, location FROM events_search WHERE id = new.event_id;
+END;
+CREATE TRIGGER events_translations_ad AFTER DELETE ON events_translations BEGIN
+  DELETE FROM events_fts WHERE rowid = old.event_id;
+  INSERT INTO events_fts(rowid, title, description, location) SELECT id, title, description, location FROM events_search WHERE id = old.event_id;
+END;
+CREATE TRIGGER events_translations_au AFTER UPDATE ON events_translations BEGIN
+  DELETE FROM events_fts WHERE rowid = old.event_id;
+  INSERT INTO events_fts(rowid, title, description, location) SELECT id, title, description, location FROM events_search WHERE id = new.event_id;
+END;
@@ -0,0 +1,11 @@
+DROP TABLE `site_assets`;
+
+ALTER TABLE settings DROP COLUMN tags_page_enabled;
+ALTER TABLE settings DROP COLUMN moderation;
+ALTER TABLE settings DROP COLUMN public_submission;
+ALTER TABLE settings DROP COLUMN favicon_hash;
+ALTER TABLE settings DROP COLUMN logo_hash;
+ALTER TABLE settings DROP COLUMN contact_email;
+ALTER TABLE settings DROP COLUMN footer;
+ALTER TABLE settings DROP COLUMN accent_color;
+ALTER TABLE settings DROP COLUMN timezone;
@@ -0,0 +1,15 @@
+ALTER TABLE settings ADD COLUMN timezone text NOT NULL DEFAULT '';
+ALTER TABLE settings ADD COLUMN accent_color text NOT NULL DEFAULT '';
+ALTER TABLE settings ADD COLUMN footer text NOT NULL DEFAULT '';
+ALTER TABLE settings ADD COLUMN contact_email text NOT NULL DEFAULT '';
+ALTER TABLE settings ADD COLUMN logo_hash text NOT NULL DEFAULT '';
+ALTER TABLE settings ADD COLUMN favicon_hash text NOT NULL DEFAULT '';
+ALTER TABLE settings ADD COLUMN public_submission tinyint NOT NULL DEFAULT 0;
+ALTER TABLE settings ADD COLUMN moderation tinyint NOT NULL DEFAULT 0;
+ALTER TABLE settings ADD COLUMN tags_page_enabled tinyint NOT NULL DEFAULT 1;
+
+CREATE TABLE `site_assets` (
+  `name` text PRIMARY KEY,
+  `content_type` text NOT NULL,
+  `data` blob NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN is_pending tinyint NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX events_attachments_thumbnail_hash_idx;
+DROP INDEX events_attachments_hash_idx;
+DROP TABLE `events_attachments`;
@@ -0,0 +1,14 @@
+CREATE TABLE `events_attachments` (
+  `event_id` bigint NOT NULL,
+  `position` integer NOT NULL,
+  `hash` text NOT NULL,
+  `thumbnail_hash` text NOT NULL,
+  `name` text NOT NULL,
+  `content_type` text NOT NULL,
+  `size` bigint NOT NULL,
+  `width` integer NOT NULL,
+  `height` integer NOT NULL,
+  PRIMARY KEY (`event_id`, `position`)
+);
+CREATE INDEX events_attachments_hash_idx ON events_attachments (hash);
+CREATE INDEX events_attachments_thumbnail_hash_idx ON events_attachments (thumbnail_hash);
@@ -0,0 +1,17 @@
+CREATE TABLE `event_templates` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `user_id` bigint NOT NULL,
+  `name` text NOT NULL,
+  `title` text NOT NULL,
+  `description` text NOT NULL,
+  `url` text NOT NULL,
+  `venue_id` bigint NOT NULL,
+  `location` text NOT NULL,
+  `osm_type` text NOT NULL,
+  `osm_id` bigint NOT NULL,
+  `latitude` real NOT NULL,
+  `longitude` real NOT NULL,
+  `categories` text NOT NULL,
+  `language` text NOT NULL
+);
+CREATE INDEX event_templates_user_id_idx ON event_templates (user_id);
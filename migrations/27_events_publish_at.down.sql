@@ -0,0 +1,4 @@
+ALTER TABLE settings DROP COLUMN retention_action;
+ALTER TABLE settings DROP COLUMN retention_days;
+DROP INDEX events_publish_at_unix_idx;
+ALTER TABLE events DROP COLUMN publish_at_unix;
@@ -0,0 +1,4 @@
+ALTER TABLE events ADD COLUMN publish_at_unix bigint NOT NULL DEFAULT 0;
+CREATE INDEX events_publish_at_unix_idx ON events (publish_at_unix) WHERE publish_at_unix > 0;
+ALTER TABLE settings ADD COLUMN retention_days integer NOT NULL DEFAULT 0;
+ALTER TABLE settings ADD COLUMN retention_action text NOT NULL DEFAULT '';
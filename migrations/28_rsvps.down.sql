@@ -0,0 +1,4 @@
+DROP TABLE calendar_tokens;
+DROP TABLE rsvps;
+ALTER TABLE settings DROP COLUMN anonymous_rsvp;
+ALTER TABLE events DROP COLUMN capacity;
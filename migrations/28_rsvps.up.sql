@@ -0,0 +1,19 @@
+ALTER TABLE events ADD COLUMN capacity integer NOT NULL DEFAULT 0;
+ALTER TABLE settings ADD COLUMN anonymous_rsvp boolean NOT NULL DEFAULT 0;
+CREATE TABLE `rsvps` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `event_id` bigint NOT NULL,
+  `user_id` bigint NOT NULL,
+  `name` text NOT NULL,
+  `email` text NOT NULL,
+  `status` text NOT NULL,
+  `waitlisted` boolean NOT NULL,
+  `updated_at_unix` bigint NOT NULL
+);
+CREATE INDEX rsvps_event_id_idx ON rsvps (event_id);
+CREATE UNIQUE INDEX rsvps_event_id_user_id_idx ON rsvps (event_id, user_id) WHERE user_id > 0;
+CREATE UNIQUE INDEX rsvps_event_id_email_idx ON rsvps (event_id, email COLLATE NOCASE) WHERE user_id = 0;
+CREATE TABLE `calendar_tokens` (
+  `token` text PRIMARY KEY,
+  `user_id` bigint NOT NULL UNIQUE
+);
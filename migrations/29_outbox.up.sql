@@ -0,0 +1,18 @@
+CREATE TABLE `outbox` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `recipient` text NOT NULL,
+  `subject` text NOT NULL,
+  `text` text NOT NULL,
+  `html` text NOT NULL,
+  `attempts` integer NOT NULL,
+  `next_attempt_at_unix` bigint NOT NULL,
+  `last_error` text NOT NULL
+);
+CREATE INDEX outbox_next_attempt_at_unix_idx ON outbox (next_attempt_at_unix);
+ALTER TABLE users ADD COLUMN email text NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX users_email_idx ON users (email COLLATE NOCASE) WHERE email != '';
+CREATE TABLE `password_resets` (
+  `token` text PRIMARY KEY,
+  `user_id` bigint NOT NULL,
+  `valid_until_unix` bigint NOT NULL
+);
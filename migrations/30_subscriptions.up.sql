@@ -0,0 +1,12 @@
+CREATE TABLE `subscriptions` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `email` text NOT NULL,
+  `frequency` text NOT NULL,
+  `category` text NOT NULL,
+  `tag` text NOT NULL,
+  `language` text NOT NULL,
+  `token` text NOT NULL UNIQUE,
+  `confirmed_at_unix` bigint NOT NULL,
+  `last_sent_at_unix` bigint NOT NULL
+);
+CREATE UNIQUE INDEX subscriptions_filter_idx ON subscriptions (email COLLATE NOCASE, frequency, category, tag);
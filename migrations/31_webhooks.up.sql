@@ -0,0 +1,20 @@
+CREATE TABLE `webhooks` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `url` text NOT NULL,
+  `secret` text NOT NULL,
+  `event_types` text NOT NULL
+);
+CREATE TABLE `webhook_deliveries` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `webhook_id` bigint NOT NULL,
+  `event_type` text NOT NULL,
+  `payload` blob NOT NULL,
+  `status` text NOT NULL,
+  `attempts` integer NOT NULL,
+  `next_attempt_at_unix` bigint NOT NULL,
+  `response_code` integer NOT NULL,
+  `last_error` text NOT NULL,
+  `updated_at_unix` bigint NOT NULL
+);
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id);
+CREATE INDEX webhook_deliveries_status_next_attempt_at_unix_idx ON webhook_deliveries (status, next_attempt_at_unix);
@@ -0,0 +1,3 @@
+DROP TABLE activitypub_deliveries;
+DROP TABLE activitypub_followers;
+DROP TABLE activitypub_keys;
@@ -0,0 +1,22 @@
+CREATE TABLE `activitypub_keys` (
+  `id` integer NOT NULL PRIMARY KEY,
+  `private_key` text NOT NULL
+);
+CREATE TABLE `activitypub_followers` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `actor` text NOT NULL,
+  `inbox` text NOT NULL,
+  `follow_id` text NOT NULL
+);
+CREATE UNIQUE INDEX activitypub_followers_actor_idx ON activitypub_followers (actor);
+CREATE TABLE `activitypub_deliveries` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `inbox` text NOT NULL,
+  `activity_type` text NOT NULL,
+  `event_id` bigint NOT NULL,
+  `follower_id` bigint NOT NULL,
+  `attempts` integer NOT NULL,
+  `next_attempt_at_unix` bigint NOT NULL,
+  `last_error` text NOT NULL
+);
+CREATE INDEX activitypub_deliveries_next_attempt_at_unix_idx ON activitypub_deliveries (next_attempt_at_unix);
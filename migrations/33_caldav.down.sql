@@ -0,0 +1,7 @@
+DROP TRIGGER event_changes_au;
+DROP TRIGGER event_changes_ad;
+DROP TRIGGER event_changes_ai;
+DROP TABLE event_changes;
+DROP TABLE app_passwords;
+DROP INDEX events_uid_idx;
+ALTER TABLE events DROP COLUMN uid;
@@ -0,0 +1,33 @@
+ALTER TABLE events ADD COLUMN uid text NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX events_uid_idx ON events (uid) WHERE uid != '';
+CREATE TABLE `app_passwords` (
+  `id` bigint NOT NULL PRIMARY KEY,
+  `user_id` bigint NOT NULL,
+  `name` text NOT NULL,
+  `hash` text NOT NULL UNIQUE,
+  `last_used_at_unix` bigint NOT NULL
+);
+CREATE INDEX app_passwords_user_id_idx ON app_passwords (user_id);
+
+-- Change log of events for incremental calendar client sync.
+CREATE TABLE `event_changes` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `event_id` bigint NOT NULL,
+  `user_id` bigint NOT NULL,
+  `uid` text NOT NULL,
+  `deleted` boolean NOT NULL
+);
+CREATE INDEX event_changes_user_id_idx ON event_changes (user_id, id);
+
+-- Triggers to record every change of events.
+-- An event moved to another owner is deleted from the previous owner's calendar.
+CREATE TRIGGER event_changes_ai AFTER INSERT ON events BEGIN
+  INSERT INTO event_changes(event_id, user_id, uid, deleted) VALUES (new.id, new.user_id, new.uid, 0);
+END;
+CREATE TRIGGER event_changes_ad AFTER DELETE ON events BEGIN
+  INSERT INTO event_changes(event_id, user_id, uid, deleted) VALUES (old.id, old.user_id, old.uid, 1);
+END;
+CREATE TRIGGER event_changes_au AFTER UPDATE ON events BEGIN
+  INSERT INTO event_changes(event_id, user_id, uid, deleted) SELECT old.id, old.user_id, old.uid, 1 WHERE old.user_id != new.user_id;
+  INSERT INTO event_changes(event_id, user_id, uid, deleted) VALUES (new.id, new.user_id, new.uid, 0);
+END;
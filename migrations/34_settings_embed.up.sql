@@ -0,0 +1 @@
+ALTER TABLE settings ADD COLUMN embed_origins text NOT NULL DEFAULT '';
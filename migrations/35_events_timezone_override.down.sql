@@ -0,0 +1,2 @@
+ALTER TABLE events DROP COLUMN timezone_resolved;
+ALTER TABLE events DROP COLUMN timezone_override;
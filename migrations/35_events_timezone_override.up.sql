@@ -0,0 +1,5 @@
+ALTER TABLE events ADD COLUMN timezone_override integer NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN timezone_resolved integer NOT NULL DEFAULT 0;
+
+-- Events with a time zone name need no resolving.
+UPDATE events SET timezone_resolved = 1 WHERE timezone != '';
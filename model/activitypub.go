@@ -0,0 +1,268 @@
+package model
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/httpsig"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// ActorKey is the ActivityPub actor key database model.
+type ActorKey struct {
+	ID         int64  `bun:"id,pk"`
+	PrivateKey string `bun:"private_key"`
+
+	bun.BaseModel `bun:"activitypub_keys"`
+}
+
+// Follower is the ActivityPub follower database model.
+type Follower struct {
+	ID       snowflake.ID `bun:"id,pk"`
+	Actor    string       `bun:"actor"`
+	Inbox    string       `bun:"inbox"`
+	FollowID string       `bun:"follow_id"`
+
+	bun.BaseModel `bun:"activitypub_followers"`
+}
+
+// ActivityDelivery is the ActivityPub delivery queue database model.
+type ActivityDelivery struct {
+	ID                snowflake.ID `bun:"id,pk"`
+	Inbox             string       `bun:"inbox"`
+	ActivityType      string       `bun:"activity_type"`
+	EventID           snowflake.ID `bun:"event_id"`
+	FollowerID        snowflake.ID `bun:"follower_id"`
+	Attempts          int          `bun:"attempts"`
+	NextAttemptAtUnix int64        `bun:"next_attempt_at_unix"`
+	LastError         string       `bun:"last_error"`
+
+	bun.BaseModel `bun:"activitypub_deliveries"`
+}
+
+// GetActorKey returns the private key of the site actor.
+// The key is generated on first use.
+func GetActorKey(ctx context.Context, db bun.IDB) (*rsa.PrivateKey, error) {
+	model := &ActorKey{}
+
+	err := sqlite.NormalizeError(db.NewSelect().Model(model).
+		Where("id = 1").
+		Scan(ctx))
+	if err == nil {
+		return httpsig.DecodePrivateKey(model.PrivateKey)
+	}
+
+	if !errors.Is(err, calendar.NotFound) {
+		return nil, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the key of a concurrent request.
+	if _, err := db.NewInsert().Model(&ActorKey{
+		ID:         1,
+		PrivateKey: httpsig.EncodePrivateKey(key),
+	}).
+		On("CONFLICT (id) DO NOTHING").
+		Exec(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return GetActorKey(ctx, db)
+}
+
+// PutFollower inserts a follower or updates the inbox and the Follow activity of an existing one.
+func PutFollower(ctx context.Context, db bun.IDB, f *domain.Follower) (*domain.Follower, error) {
+	if err := sqlite.WithErrorChecking(db.NewInsert().Model(&Follower{
+		ID:       snowflake.Generate(),
+		Actor:    f.ActorID,
+		Inbox:    f.Inbox,
+		FollowID: f.FollowID,
+	}).
+		On("CONFLICT (actor) DO UPDATE").
+		Set("inbox = EXCLUDED.inbox").
+		Set("follow_id = EXCLUDED.follow_id").
+		Exec(ctx)); err != nil {
+		return nil, err
+	}
+
+	model := &Follower{}
+
+	if err := db.NewSelect().Model(model).
+		Where("actor = ?", f.ActorID).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return followerToDomain(model), nil
+}
+
+// GetFollower returns a follower.
+func GetFollower(ctx context.Context, db bun.IDB, id snowflake.ID) (*domain.Follower, error) {
+	model := &Follower{}
+
+	if err := db.NewSelect().Model(model).
+		Where("id = ?", id).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return followerToDomain(model), nil
+}
+
+// ListFollowers lists followers.
+func ListFollowers(ctx context.Context, db bun.IDB) ([]*domain.Follower, error) {
+	model := []*Follower{}
+
+	if err := db.NewSelect().Model(&model).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *Follower, _ int) *domain.Follower {
+		return followerToDomain(m)
+	}), nil
+}
+
+// CountFollowers returns the number of followers.
+func CountFollowers(ctx context.Context, db bun.IDB) (int, error) {
+	n, err := db.NewSelect().Model((*Follower)(nil)).Count(ctx)
+	if err != nil {
+		return 0, sqlite.NormalizeError(err)
+	}
+
+	return n, nil
+}
+
+// DeleteFollower deletes a follower by the remote actor ID.
+// Deleting an unknown follower is not an error.
+func DeleteFollower(ctx context.Context, db bun.IDB, actorID string) error {
+	_, err := db.NewDelete().Model((*Follower)(nil)).
+		Where("actor = ?", actorID).
+		Exec(ctx)
+
+	return sqlite.NormalizeError(err)
+}
+
+// InsertActivityDelivery queues an activity to be delivered immediately.
+func InsertActivityDelivery(ctx context.Context, db bun.IDB, d *domain.ActivityDelivery) error {
+	if d.ID == 0 {
+		d.ID = snowflake.Generate()
+	}
+
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = time.Now()
+	}
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(activityDeliveryToModel(d)).Exec(ctx))
+}
+
+// ListDueActivityDeliveries lists the deliveries which are due at now and have attempts left.
+func ListDueActivityDeliveries(ctx context.Context, db bun.IDB, now time.Time, limit int) ([]*domain.ActivityDelivery, error) {
+	model := []*ActivityDelivery{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("attempts < ?", domain.MaxActivityAttempts).
+		Where("next_attempt_at_unix <= ?", now.Unix()).
+		Order("next_attempt_at_unix ASC", "id ASC").
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *ActivityDelivery, _ int) *domain.ActivityDelivery {
+		return activityDeliveryToDomain(m)
+	}), nil
+}
+
+// UpdateActivityDelivery updates the delivery attempts of a queued activity.
+func UpdateActivityDelivery(ctx context.Context, db bun.IDB, d *domain.ActivityDelivery) error {
+	return sqlite.WithErrorChecking(db.NewUpdate().Model(activityDeliveryToModel(d)).
+		Column(
+			"attempts",
+			"next_attempt_at_unix",
+			"last_error",
+		).
+		Where("id = ?", d.ID).
+		Exec(ctx))
+}
+
+// DeleteActivityDelivery deletes a delivered activity from the queue.
+func DeleteActivityDelivery(ctx context.Context, db bun.IDB, id snowflake.ID) error {
+	return sqlite.WithErrorChecking(db.NewDelete().Model((*ActivityDelivery)(nil)).
+		Where("id = ?", id).
+		Exec(ctx))
+}
+
+// queueActivityDeliveries queues an event activity to the inboxes of followers.
+// Followers on the same server share an inbox.
+func queueActivityDeliveries(ctx context.Context, db bun.IDB, t domain.ActivityType, eventID snowflake.ID) error {
+	var inboxes []string
+
+	if err := db.NewSelect().Model((*Follower)(nil)).
+		Distinct().
+		Column("inbox").
+		Order("inbox ASC").
+		Scan(ctx, &inboxes); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	for _, inbox := range inboxes {
+		if err := InsertActivityDelivery(ctx, db, &domain.ActivityDelivery{
+			Inbox:   inbox,
+			Type:    t,
+			EventID: eventID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func followerToDomain(model *Follower) *domain.Follower {
+	return &domain.Follower{
+		ID:       model.ID,
+		ActorID:  model.Actor,
+		Inbox:    model.Inbox,
+		FollowID: model.FollowID,
+	}
+}
+
+func activityDeliveryToModel(d *domain.ActivityDelivery) *ActivityDelivery {
+	return &ActivityDelivery{
+		ID:                d.ID,
+		Inbox:             d.Inbox,
+		ActivityType:      string(d.Type),
+		EventID:           d.EventID,
+		FollowerID:        d.FollowerID,
+		Attempts:          d.Attempts,
+		NextAttemptAtUnix: d.NextAttemptAt.Unix(),
+		LastError:         d.LastError,
+	}
+}
+
+func activityDeliveryToDomain(model *ActivityDelivery) *domain.ActivityDelivery {
+	return &domain.ActivityDelivery{
+		ID:            model.ID,
+		Inbox:         model.Inbox,
+		Type:          domain.ActivityType(model.ActivityType),
+		EventID:       model.EventID,
+		FollowerID:    model.FollowerID,
+		Attempts:      model.Attempts,
+		NextAttemptAt: time.Unix(model.NextAttemptAtUnix, 0),
+		LastError:     model.LastError,
+	}
+}
@@ -0,0 +1,136 @@
+package model_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("ActivityPub", func() {
+	Specify("actor key is generated once", func(ctx SpecContext) {
+		key := Must(model.GetActorKey(ctx, db))
+
+		Expect(Must(model.GetActorKey(ctx, db))).To(Equal(key))
+	})
+
+	When("there are followers", func() {
+		var alice *domain.Follower
+
+		BeforeEach(func(ctx SpecContext) {
+			alice = Must(model.PutFollower(ctx, db, &domain.Follower{
+				ActorID:  "https://social.testing/users/alice",
+				Inbox:    "https://social.testing/inbox",
+				FollowID: "https://social.testing/follows/1",
+			}))
+
+			Must(model.PutFollower(ctx, db, &domain.Follower{
+				ActorID:  "https://social.testing/users/bob",
+				Inbox:    "https://social.testing/inbox",
+				FollowID: "https://social.testing/follows/2",
+			}))
+
+			Must(model.PutFollower(ctx, db, &domain.Follower{
+				ActorID:  "https://events.testing/@carol",
+				Inbox:    "https://events.testing/@carol/inbox",
+				FollowID: "https://events.testing/follows/3",
+			}))
+		})
+
+		Specify("following again updates the follower", func(ctx SpecContext) {
+			f := Must(model.PutFollower(ctx, db, &domain.Follower{
+				ActorID:  "https://social.testing/users/alice",
+				Inbox:    "https://social.testing/inbox",
+				FollowID: "https://social.testing/follows/4",
+			}))
+
+			Expect(f).To(PointTo(MatchAllFields(Fields{
+				"ID":       Equal(alice.ID),
+				"ActorID":  Equal("https://social.testing/users/alice"),
+				"Inbox":    Equal("https://social.testing/inbox"),
+				"FollowID": Equal("https://social.testing/follows/4"),
+			})))
+			Expect(Must(model.CountFollowers(ctx, db))).To(Equal(3))
+		})
+
+		Specify("unfollowing deletes the follower", func(ctx SpecContext) {
+			Expect(model.DeleteFollower(ctx, db, "https://social.testing/users/alice")).To(Succeed())
+			Expect(model.DeleteFollower(ctx, db, "https://social.testing/users/unknown")).To(Succeed())
+
+			Expect(Must(model.ListFollowers(ctx, db))).To(HaveExactElements(
+				HaveField("ActorID", "https://social.testing/users/bob"),
+				HaveField("ActorID", "https://events.testing/@carol"),
+			))
+		})
+
+		Specify("event changes are queued once per inbox", func(ctx SpecContext) {
+			ev := &domain.Event{
+				ID:          snowflake.Generate(),
+				StartAt:     time.Now().Add(24 * time.Hour),
+				Title:       "Event",
+				Description: "Desc",
+			}
+
+			Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+			Expect(model.DeleteEvent(ctx, db, ev)).To(Succeed())
+
+			deliveries := Must(model.ListDueActivityDeliveries(ctx, db, time.Now(), 10))
+
+			Expect(deliveries).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Inbox":   Equal("https://events.testing/@carol/inbox"),
+					"Type":    Equal(domain.ActivityCreate),
+					"EventID": Equal(ev.ID),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Inbox":   Equal("https://social.testing/inbox"),
+					"Type":    Equal(domain.ActivityCreate),
+					"EventID": Equal(ev.ID),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Inbox":   Equal("https://events.testing/@carol/inbox"),
+					"Type":    Equal(domain.ActivityDelete),
+					"EventID": Equal(ev.ID),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Inbox":   Equal("https://social.testing/inbox"),
+					"Type":    Equal(domain.ActivityDelete),
+					"EventID": Equal(ev.ID),
+				})),
+			))
+		})
+	})
+
+	Specify("failed delivery is retried until exhausted", func(ctx SpecContext) {
+		d := &domain.ActivityDelivery{
+			Inbox:      "https://social.testing/inbox",
+			Type:       domain.ActivityAccept,
+			FollowerID: snowflake.Generate(),
+		}
+		Expect(model.InsertActivityDelivery(ctx, db, d)).To(Succeed())
+
+		now := time.Now()
+		d.SetFailed(errors.New("connection refused"), now)
+		Expect(model.UpdateActivityDelivery(ctx, db, d)).To(Succeed())
+
+		Expect(Must(model.ListDueActivityDeliveries(ctx, db, now, 10))).To(BeEmpty())
+		Expect(Must(model.ListDueActivityDeliveries(ctx, db, now.Add(time.Minute), 10))).To(HaveExactElements(
+			HaveField("LastError", "connection refused"),
+		))
+
+		for !d.IsExhausted() {
+			d.SetFailed(errors.New("connection refused"), now)
+		}
+		Expect(model.UpdateActivityDelivery(ctx, db, d)).To(Succeed())
+
+		Expect(Must(model.ListDueActivityDeliveries(ctx, db, now.Add(24*time.Hour), 10))).To(BeEmpty())
+
+		Expect(model.DeleteActivityDelivery(ctx, db, d.ID)).To(Succeed())
+	})
+})
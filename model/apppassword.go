@@ -0,0 +1,95 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// AppPassword is the app password database model.
+type AppPassword struct {
+	ID             snowflake.ID `bun:"id,pk"`
+	UserID         snowflake.ID `bun:"user_id"`
+	Name           string       `bun:"name"`
+	Hash           string       `bun:"hash"`
+	LastUsedAtUnix int64        `bun:"last_used_at_unix"`
+
+	bun.BaseModel `bun:"app_passwords"`
+}
+
+// InsertAppPassword inserts an app password.
+func InsertAppPassword(ctx context.Context, db bun.IDB, p *domain.AppPassword) error {
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&AppPassword{
+		ID:             p.ID,
+		UserID:         p.UserID,
+		Name:           p.Name,
+		Hash:           p.Hash,
+		LastUsedAtUnix: unixOrZero(p.LastUsedAt),
+	}).Exec(ctx))
+}
+
+// ListAppPasswords lists the app passwords of a user.
+func ListAppPasswords(ctx context.Context, db bun.IDB, userID snowflake.ID) ([]*domain.AppPassword, error) {
+	model := []*AppPassword{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *AppPassword, _ int) *domain.AppPassword {
+		return appPasswordToDomain(m)
+	}), nil
+}
+
+// DeleteAppPassword deletes an app password of a user.
+func DeleteAppPassword(ctx context.Context, db bun.IDB, userID, id snowflake.ID) error {
+	return sqlite.WithErrorChecking(db.NewDelete().Model((*AppPassword)(nil)).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Exec(ctx))
+}
+
+// GetAppPasswordUser returns the user signing in with an app password
+// and records the use of the password.
+func GetAppPasswordUser(ctx context.Context, db bun.IDB, username, password string, now time.Time) (*domain.User, error) {
+	user, err := GetUserByUsername(ctx, db, username)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := db.NewUpdate().Model((*AppPassword)(nil)).
+		Set("last_used_at_unix = ?", now.Unix()).
+		Where("user_id = ?", user.ID).
+		Where("hash = ?", domain.HashAppPassword(password)).
+		Exec(ctx)
+	if err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, calendar.NotFound.New("App password not found")
+	}
+
+	return user, nil
+}
+
+func appPasswordToDomain(model *AppPassword) *domain.AppPassword {
+	return &domain.AppPassword{
+		ID:         model.ID,
+		UserID:     model.UserID,
+		Name:       model.Name,
+		Hash:       model.Hash,
+		LastUsedAt: timeOrZero(model.LastUsedAtUnix),
+	}
+}
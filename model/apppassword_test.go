@@ -0,0 +1,81 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("app passwords", func() {
+	var (
+		user     *domain.User
+		p        *domain.AppPassword
+		password string
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		user = &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "username",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		}
+
+		Expect(model.InsertUser(ctx, db, user)).To(Succeed())
+
+		p, password = domain.NewAppPassword(user.ID, "Phone")
+		Expect(model.InsertAppPassword(ctx, db, p)).To(Succeed())
+	})
+
+	Specify("password is not stored in plain text", func(ctx SpecContext) {
+		Expect(Must(model.ListAppPasswords(ctx, db, user.ID))).To(HaveExactElements(SatisfyAll(
+			HaveField("ID", p.ID),
+			HaveField("Name", "Phone"),
+			HaveField("Hash", Not(Equal(password))),
+			HaveField("LastUsedAt", BeZero()),
+		)))
+	})
+
+	Specify("signing in records the use of the password", func(ctx SpecContext) {
+		now := time.Now().Truncate(time.Second)
+
+		Expect(Must(model.GetAppPasswordUser(ctx, db, "username", password, now))).To(HaveField("ID", user.ID))
+
+		Expect(Must(model.ListAppPasswords(ctx, db, user.ID))).To(HaveExactElements(
+			HaveField("LastUsedAt", BeTemporally("==", now)),
+		))
+	})
+
+	Specify("wrong password is not found", func(ctx SpecContext) {
+		_, err := model.GetAppPasswordUser(ctx, db, "username", "password", time.Now())
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+
+	Specify("password of another user is not found", func(ctx SpecContext) {
+		Expect(model.InsertUser(ctx, db, &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "other",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		})).To(Succeed())
+
+		_, err := model.GetAppPasswordUser(ctx, db, "other", password, time.Now())
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+
+	Specify("password is deleted only by its user", func(ctx SpecContext) {
+		Expect(model.DeleteAppPassword(ctx, db, snowflake.Generate(), p.ID)).To(MatchError(calendar.PreconditionFailed))
+		Expect(model.DeleteAppPassword(ctx, db, user.ID, p.ID)).To(Succeed())
+
+		Expect(Must(model.ListAppPasswords(ctx, db, user.ID))).To(BeEmpty())
+
+		_, err := model.GetAppPasswordUser(ctx, db, "username", password, time.Now())
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+})
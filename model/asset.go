@@ -0,0 +1,55 @@
+package model
+
+import (
+	"context"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/uptrace/bun"
+)
+
+// SiteAsset is the site asset database model.
+type SiteAsset struct {
+	Name        string `bun:"name,pk"`
+	ContentType string `bun:"content_type"`
+	Data        []byte `bun:"data"`
+
+	bun.BaseModel `bun:"site_assets"`
+}
+
+// PutSiteAsset inserts or replaces a site asset.
+func PutSiteAsset(ctx context.Context, db bun.IDB, asset *domain.SiteAsset) error {
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&SiteAsset{
+		Name:        asset.Name,
+		ContentType: asset.ContentType,
+		Data:        asset.Data,
+	}).
+		On("CONFLICT (name) DO UPDATE").
+		Set("content_type = EXCLUDED.content_type").
+		Set("data = EXCLUDED.data").
+		Exec(ctx))
+}
+
+// GetSiteAsset returns a site asset.
+func GetSiteAsset(ctx context.Context, db bun.IDB, name string) (*domain.SiteAsset, error) {
+	model := &SiteAsset{}
+
+	if err := db.NewSelect().Model(model).
+		Where("name = ?", name).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return &domain.SiteAsset{
+		Name:        model.Name,
+		ContentType: model.ContentType,
+		Data:        model.Data,
+	}, nil
+}
+
+// DeleteSiteAsset deletes a site asset.
+func DeleteSiteAsset(ctx context.Context, db bun.IDB, name string) error {
+	return sqlite.WithErrorChecking(db.NewDelete().Model((*SiteAsset)(nil)).
+		Where("name = ?", name).
+		Exec(ctx))
+}
@@ -0,0 +1,37 @@
+package model_test
+
+import (
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("site assets", func() {
+	Specify("asset is replaced and deleted", func(ctx SpecContext) {
+		Expect(model.PutSiteAsset(ctx, db, &domain.SiteAsset{
+			Name:        domain.AssetLogo,
+			ContentType: "image/png",
+			Data:        []byte("png"),
+		})).To(Succeed())
+
+		Expect(model.PutSiteAsset(ctx, db, &domain.SiteAsset{
+			Name:        domain.AssetLogo,
+			ContentType: "image/gif",
+			Data:        []byte("gif"),
+		})).To(Succeed())
+
+		Expect(Must(model.GetSiteAsset(ctx, db, domain.AssetLogo))).To(Equal(&domain.SiteAsset{
+			Name:        domain.AssetLogo,
+			ContentType: "image/gif",
+			Data:        []byte("gif"),
+		}))
+
+		Expect(model.DeleteSiteAsset(ctx, db, domain.AssetLogo)).To(Succeed())
+
+		_, err := model.GetSiteAsset(ctx, db, domain.AssetLogo)
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+})
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// EventAttachment is the event attachment database model.
+type EventAttachment struct {
+	EventID       snowflake.ID `bun:"event_id,pk"`
+	Position      int          `bun:"position,pk"`
+	Hash          string       `bun:"hash"`
+	ThumbnailHash string       `bun:"thumbnail_hash"`
+	Name          string       `bun:"name"`
+	ContentType   string       `bun:"content_type"`
+	Size          int64        `bun:"size"`
+	Width         int          `bun:"width"`
+	Height        int          `bun:"height"`
+
+	bun.BaseModel `bun:"events_attachments"`
+}
+
+// setEventAttachments replaces the attachments of an event.
+func setEventAttachments(ctx context.Context, db bun.IDB, ev *domain.Event) error {
+	if err := deleteEventAttachments(ctx, db, ev.ID); err != nil {
+		return err
+	}
+
+	if len(ev.Attachments) == 0 {
+		return nil
+	}
+
+	model := lo.Map(ev.Attachments, func(a domain.Attachment, i int) *EventAttachment {
+		return &EventAttachment{
+			EventID:       ev.ID,
+			Position:      i,
+			Hash:          a.Hash,
+			ThumbnailHash: a.ThumbnailHash,
+			Name:          a.Name,
+			ContentType:   a.ContentType,
+			Size:          a.Size,
+			Width:         a.Width,
+			Height:        a.Height,
+		}
+	})
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&model).Exec(ctx))
+}
+
+// deleteEventAttachments deletes the attachments of an event.
+func deleteEventAttachments(ctx context.Context, db bun.IDB, eventID snowflake.ID) error {
+	if err := sqlite.WithErrorChecking(
+		db.NewDelete().Model((*EventAttachment)(nil)).
+			Where("event_id = ?", eventID).
+			Exec(ctx),
+	); err != nil && !errors.Is(err, calendar.PreconditionFailed) {
+		return err
+	}
+
+	return nil
+}
+
+// loadEventAttachments populates the attachments of events.
+func loadEventAttachments(ctx context.Context, db bun.IDB, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	model := []*EventAttachment{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("event_id IN (?)", bun.In(lo.Map(events, func(ev *domain.Event, _ int) snowflake.ID {
+			return ev.ID
+		}))).
+		Order("position ASC").
+		Scan(ctx); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	byEvent := lo.GroupByMap(model, func(a *EventAttachment) (snowflake.ID, domain.Attachment) {
+		return a.EventID, domain.Attachment{
+			Hash:          a.Hash,
+			ThumbnailHash: a.ThumbnailHash,
+			Name:          a.Name,
+			ContentType:   a.ContentType,
+			Size:          a.Size,
+			Width:         a.Width,
+			Height:        a.Height,
+		}
+	})
+
+	for _, ev := range events {
+		ev.Attachments = byEvent[ev.ID]
+	}
+
+	return nil
+}
+
+// ListReferencedHashes returns the subset of file hashes referenced by event attachments.
+func ListReferencedHashes(ctx context.Context, db bun.IDB, hashes ...string) ([]string, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	model := []*EventAttachment{}
+
+	if err := db.NewSelect().Model(&model).
+		Column("hash", "thumbnail_hash").
+		Where("hash IN (?)", bun.In(hashes)).
+		WhereOr("thumbnail_hash IN (?)", bun.In(hashes)).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	var result []string
+	for _, a := range model {
+		result = append(result, a.Hash, a.ThumbnailHash)
+	}
+
+	result = lo.Filter(result, func(hash string, _ int) bool {
+		return slices.Contains(hashes, hash)
+	})
+
+	return lo.Uniq(result), nil
+}
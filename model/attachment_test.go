@@ -0,0 +1,73 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("event attachments", func() {
+	var (
+		ev     *domain.Event
+		poster = domain.Attachment{
+			Hash:          "poster",
+			ThumbnailHash: "poster-thumb",
+			Name:          "poster.jpg",
+			ContentType:   "image/jpeg",
+			Size:          1000,
+			Width:         800,
+			Height:        600,
+		}
+		program = domain.Attachment{
+			Hash:        "program",
+			Name:        "program.pdf",
+			ContentType: "application/pdf",
+			Size:        2000,
+		}
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		ev = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().Add(2 * time.Hour),
+			Title:       "Concert",
+			Description: "Music",
+			Location:    "Hall",
+			Attachments: []domain.Attachment{program, poster},
+			UserID:      snowflake.Generate(),
+		}
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+	})
+
+	Specify("attachments are persisted in order", func(ctx SpecContext) {
+		event := Must(model.GetEvent(ctx, db, ev.ID))
+		Expect(event.Attachments).To(HaveExactElements(program, poster))
+		Expect(event.GetPoster()).To(PointTo(Equal(poster)))
+
+		By("updating attachments", func() {
+			event.Attachments = []domain.Attachment{poster}
+			Expect(model.UpdateEvent(ctx, db, event)).To(Succeed())
+
+			result := Must(model.NewEventsQuery().WithOrder(0, model.OrderStartAtAsc).List(ctx, db))
+			Expect(result).To(HaveExactElements(
+				HaveField("Attachments", HaveExactElements(poster)),
+			))
+		})
+	})
+
+	Specify("referenced hashes are listed", func(ctx SpecContext) {
+		Expect(model.ListReferencedHashes(ctx, db, "poster", "poster-thumb", "other")).To(ConsistOf("poster", "poster-thumb"))
+
+		By("deleting the event", func() {
+			Expect(model.DeleteEvent(ctx, db, ev)).To(Succeed())
+			Expect(model.ListReferencedHashes(ctx, db, "poster", "program")).To(BeEmpty())
+		})
+	})
+})
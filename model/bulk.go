@@ -0,0 +1,166 @@
+package model
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/uptrace/bun"
+)
+
+// BulkAction is an action applied to multiple events.
+type BulkAction string
+
+// Bulk action values.
+const (
+	BulkPublish        BulkAction = "publish"
+	BulkUnpublish      BulkAction = "unpublish"
+	BulkDelete         BulkAction = "delete"
+	BulkChangeOwner    BulkAction = "change_owner"
+	BulkAddCategory    BulkAction = "add_category"
+	BulkRemoveCategory BulkAction = "remove_category"
+	BulkShiftDate      BulkAction = "shift_date"
+)
+
+// BulkOperation is a bulk action with its arguments.
+type BulkOperation struct {
+	Action BulkAction
+
+	// RequireReview publishes drafts as pending review.
+	RequireReview bool
+
+	// UserID is the new owner.
+	UserID snowflake.ID
+
+	// Category is the category name to add or remove.
+	Category string
+
+	// Days is the number of days to shift the start date by.
+	Days int
+}
+
+// BulkResult is the result of a bulk operation on a single event.
+type BulkResult struct {
+	EventID snowflake.ID
+
+	// Event is the event before the operation. It is nil if the event was not found.
+	Event *domain.Event
+
+	// Err is the reason why the operation failed for the event.
+	Err error
+}
+
+// ApplyBulkOperation applies an operation to events in a single transaction.
+// Each event is changed in a savepoint so that failing events are rolled back
+// and reported in the results while the rest are applied.
+func ApplyBulkOperation(ctx context.Context, db *bun.DB, op BulkOperation, ids ...snowflake.ID) ([]BulkResult, error) {
+	var results []BulkResult
+
+	if err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		results = make([]BulkResult, 0, len(ids))
+
+		for _, id := range ids {
+			result := BulkResult{EventID: id}
+
+			result.Err = tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+				ev, err := GetEvent(ctx, tx, id)
+				if err != nil {
+					return err
+				}
+
+				result.Event = ev
+
+				return applyBulkOperation(ctx, tx, op, ev)
+			})
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			results = append(results, result)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func applyBulkOperation(ctx context.Context, db bun.IDB, op BulkOperation, ev *domain.Event) error {
+	// Operate on a copy to keep the original in the result.
+	updated := *ev
+	updated.Categories = slices.Clone(ev.Categories)
+
+	switch op.Action {
+	case BulkPublish:
+		if !ev.IsDraft {
+			return calendar.PreconditionFailed.New("Event is already published")
+		}
+
+		updated.IsDraft = op.RequireReview
+		updated.IsPending = op.RequireReview
+		if !op.RequireReview {
+			updated.PublishAt = time.Time{}
+		}
+
+	case BulkUnpublish:
+		if ev.IsDraft && !ev.IsPending {
+			return calendar.PreconditionFailed.New("Event is already a draft")
+		}
+
+		updated.IsDraft = true
+		updated.IsPending = false
+		updated.PublishAt = time.Time{}
+
+	case BulkDelete:
+		return deleteEvent(ctx, db, ev.ID)
+
+	case BulkChangeOwner:
+		return sqlite.WithErrorChecking(
+			db.NewUpdate().Model(&Event{UserID: op.UserID}).
+				Column("user_id").
+				Where("id = ?", ev.ID).
+				Exec(ctx),
+		)
+
+	case BulkAddCategory:
+		if slices.ContainsFunc(ev.Categories, func(name string) bool {
+			return strings.EqualFold(name, op.Category)
+		}) {
+			return calendar.PreconditionFailed.New("Event already has the category")
+		}
+
+		updated.Categories = append(updated.Categories, op.Category)
+
+		return setEventCategories(ctx, db, &updated)
+
+	case BulkRemoveCategory:
+		if !slices.ContainsFunc(ev.Categories, func(name string) bool {
+			return strings.EqualFold(name, op.Category)
+		}) {
+			return calendar.PreconditionFailed.New("Event does not have the category")
+		}
+
+		updated.Categories = slices.DeleteFunc(updated.Categories, func(name string) bool {
+			return strings.EqualFold(name, op.Category)
+		})
+
+		return setEventCategories(ctx, db, &updated)
+
+	case BulkShiftDate:
+		// Keep the wall clock time across daylight saving time changes.
+		updated.StartAt = ev.StartAt.AddDate(0, 0, op.Days)
+
+	default:
+		return calendar.InvalidValue.New("Invalid bulk action")
+	}
+
+	return updateEvent(ctx, db, &updated)
+}
@@ -0,0 +1,175 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("bulk operations", func() {
+	var (
+		draft     *domain.Event
+		published *domain.Event
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		Expect(model.SetCategories(ctx, db, domain.NewCategoryList("Music", "Theatre"))).To(Succeed())
+
+		loc := Must(time.LoadLocation("Europe/Tallinn"))
+
+		draft = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Date(2026, 10, 20, 19, 0, 0, 0, loc),
+			Title:       "Draft concert",
+			Description: "Desc",
+			IsDraft:     true,
+			UserID:      snowflake.Generate(),
+			Categories:  []string{"Music"},
+		}
+
+		published = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Date(2026, 10, 21, 19, 0, 0, 0, loc),
+			Title:       "Published play",
+			Description: "Desc",
+			UserID:      snowflake.Generate(),
+		}
+
+		Expect(model.InsertEvent(ctx, db, draft)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, published)).To(Succeed())
+	})
+
+	Specify("failing events are reported and the rest are applied", func(ctx SpecContext) {
+		missing := snowflake.Generate()
+
+		results := Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action: model.BulkPublish,
+		}, draft.ID, published.ID, missing))
+
+		Expect(results).To(HaveExactElements(
+			SatisfyAll(HaveField("EventID", draft.ID), HaveField("Err", BeNil())),
+			SatisfyAll(HaveField("EventID", published.ID), HaveField("Err", MatchError(calendar.PreconditionFailed))),
+			SatisfyAll(HaveField("EventID", missing), HaveField("Event", BeNil()), HaveField("Err", MatchError(calendar.NotFound))),
+		))
+
+		Expect(Must(model.GetEvent(ctx, db, draft.ID)).IsDraft).To(BeFalse())
+	})
+
+	Specify("drafts are published as pending when review is required", func(ctx SpecContext) {
+		Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action:        model.BulkPublish,
+			RequireReview: true,
+		}, draft.ID))
+
+		ev := Must(model.GetEvent(ctx, db, draft.ID))
+		Expect(ev.IsDraft).To(BeTrue())
+		Expect(ev.IsPending).To(BeTrue())
+	})
+
+	Specify("events are unpublished", func(ctx SpecContext) {
+		Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action: model.BulkUnpublish,
+		}, published.ID))
+
+		Expect(Must(model.GetEvent(ctx, db, published.ID)).IsDraft).To(BeTrue())
+	})
+
+	Specify("events are deleted", func(ctx SpecContext) {
+		results := Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action: model.BulkDelete,
+		}, draft.ID, published.ID))
+
+		Expect(results).To(HaveEach(HaveField("Err", BeNil())))
+
+		_, err := model.GetEvent(ctx, db, draft.ID)
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+
+	Specify("owner is changed", func(ctx SpecContext) {
+		owner := snowflake.Generate()
+
+		Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action: model.BulkChangeOwner,
+			UserID: owner,
+		}, draft.ID))
+
+		Expect(Must(model.GetEvent(ctx, db, draft.ID)).UserID).To(Equal(owner))
+	})
+
+	Specify("categories are added and removed", func(ctx SpecContext) {
+		results := Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action:   model.BulkAddCategory,
+			Category: "Music",
+		}, draft.ID, published.ID))
+
+		Expect(results).To(HaveExactElements(
+			HaveField("Err", MatchError(calendar.PreconditionFailed)),
+			HaveField("Err", BeNil()),
+		))
+		Expect(Must(model.GetEvent(ctx, db, published.ID)).Categories).To(HaveExactElements("Music"))
+
+		Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action:   model.BulkRemoveCategory,
+			Category: "Music",
+		}, draft.ID, published.ID))
+
+		Expect(Must(model.GetEvent(ctx, db, draft.ID)).Categories).To(BeEmpty())
+		Expect(Must(model.GetEvent(ctx, db, published.ID)).Categories).To(BeEmpty())
+	})
+
+	Specify("start date is shifted keeping the wall clock time", func(ctx SpecContext) {
+		// Daylight saving time ends on 2026-10-25 in Tallinn.
+		Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action: model.BulkShiftDate,
+			Days:   7,
+		}, draft.ID))
+
+		ev := Must(model.GetEvent(ctx, db, draft.ID))
+		Expect(ev.StartAt.Format(time.DateTime)).To(Equal("2026-10-27 19:00:00"))
+	})
+})
+
+var _ = Describe("managing events", func() {
+	BeforeEach(func(ctx SpecContext) {
+		for _, ev := range []*domain.Event{
+			{Title: "b_side", IsDraft: true},
+			{Title: "A 100% show"},
+			{Title: "c show", IsDraft: true, IsPending: true},
+		} {
+			ev.ID = snowflake.Generate()
+			ev.StartAt = time.Now()
+			ev.Description = "Desc"
+			Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+		}
+	})
+
+	Specify("events are listed by title", func(ctx SpecContext) {
+		events := Must(model.NewEventsQuery().WithIncludeDrafts().WithOrder(0, model.OrderTitleDesc).List(ctx, db))
+
+		Expect(events).To(HaveExactElements(
+			HaveField("Title", "c show"),
+			HaveField("Title", "b_side"),
+			HaveField("Title", "A 100% show"),
+		))
+	})
+
+	Specify("events are filtered by title with pattern characters escaped", func(ctx SpecContext) {
+		events := Must(model.NewEventsQuery().WithIncludeDrafts().WithTitleContains("0%").List(ctx, db))
+		Expect(events).To(HaveExactElements(HaveField("Title", "A 100% show")))
+
+		events = Must(model.NewEventsQuery().WithIncludeDrafts().WithTitleContains("b_").List(ctx, db))
+		Expect(events).To(HaveExactElements(HaveField("Title", "b_side")))
+	})
+
+	Specify("drafts which are not pending are listed", func(ctx SpecContext) {
+		events := Must(model.NewEventsQuery().WithDraftsOnly().List(ctx, db))
+
+		Expect(events).To(HaveExactElements(HaveField("Title", "b_side")))
+	})
+})
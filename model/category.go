@@ -0,0 +1,216 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// Category is the curated category database model.
+type Category struct {
+	ID         snowflake.ID `bun:"id,pk"`
+	Name       string       `bun:"name"`
+	Order      uint64       `bun:"sort_order"`
+	EventCount uint64       `bun:"event_count,scanonly"`
+
+	bun.BaseModel `bun:"categories"`
+}
+
+type eventToCategory struct {
+	CategoryID snowflake.ID `bun:"category_id"`
+	EventID    snowflake.ID `bun:"event_id"`
+
+	bun.BaseModel `bun:"events_categories"`
+}
+
+// SetCategories sets the curated categories in the database.
+// Existing categories are matched case-insensitively and keep their event relations.
+// Categories missing from the list are deleted along with their event relations.
+func SetCategories(ctx context.Context, db bun.IDB, names domain.CategoryList) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		existing := []*Category{}
+
+		if err := db.NewSelect().Model(&existing).Scan(ctx); err != nil {
+			return sqlite.NormalizeError(err)
+		}
+
+		byName := lo.KeyBy(existing, func(c *Category) string {
+			return strings.ToLower(c.Name)
+		})
+
+		removed := lo.FilterMap(existing, func(c *Category, _ int) (snowflake.ID, bool) {
+			return c.ID, !lo.ContainsBy(names, func(name string) bool {
+				return strings.EqualFold(name, c.Name)
+			})
+		})
+
+		if len(removed) > 0 {
+			if err := sqlite.WithErrorChecking(
+				db.NewDelete().Model((*eventToCategory)(nil)).
+					Where("category_id IN (?)", bun.In(removed)).
+					Exec(ctx),
+			); err != nil && !errors.Is(err, calendar.PreconditionFailed) {
+				return err
+			}
+
+			if err := sqlite.WithErrorChecking(
+				db.NewDelete().Model((*Category)(nil)).
+					Where("id IN (?)", bun.In(removed)).
+					Exec(ctx),
+			); err != nil {
+				return err
+			}
+		}
+
+		for idx, name := range names {
+			if c, ok := byName[strings.ToLower(name)]; ok {
+				if err := sqlite.WithErrorChecking(
+					db.NewUpdate().Model(&Category{
+						Name:  name,
+						Order: uint64(idx),
+					}).
+						Column("name", "sort_order").
+						Where("id = ?", c.ID).
+						Exec(ctx),
+				); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := sqlite.WithErrorChecking(db.NewInsert().Model(&Category{
+				ID:    snowflake.Generate(),
+				Name:  name,
+				Order: uint64(idx),
+			}).Exec(ctx)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListCategories lists all categories in the configured order along with
+// the number of related published events starting from eventStartAtFrom.
+func ListCategories(ctx context.Context, db bun.IDB, eventStartAtFrom time.Time) ([]*domain.Category, error) {
+	model := []*Category{}
+
+	query := db.NewSelect().Model(&model).
+		ColumnExpr("category.id, category.name, category.sort_order, COUNT(ev.id) AS event_count").
+		Join("LEFT JOIN events_categories AS ec ON ec.category_id = category.id").
+		Group("category.id").
+		Order("category.sort_order ASC")
+
+	if eventStartAtFrom.IsZero() {
+		query.Join("LEFT JOIN events AS ev ON ev.id = ec.event_id AND ev.is_draft = 0")
+	} else {
+		query.Join("LEFT JOIN events AS ev ON ev.id = ec.event_id AND ev.is_draft = 0 AND ev.start_at_unix >= ?", eventStartAtFrom.Unix())
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(c *Category, _ int) *domain.Category {
+		return &domain.Category{
+			Name:       c.Name,
+			EventCount: c.EventCount,
+		}
+	}), nil
+}
+
+// setEventCategories replaces the event's category relations.
+// Category names are matched case-insensitively. Unknown category names are ignored.
+func setEventCategories(ctx context.Context, db bun.IDB, ev *domain.Event) error {
+	if err := deleteEventCategories(ctx, db, ev.ID); err != nil {
+		return err
+	}
+
+	if len(ev.Categories) == 0 {
+		return nil
+	}
+
+	model := []*Category{}
+
+	if err := db.NewSelect().Model(&model).Scan(ctx); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	model = lo.Filter(model, func(c *Category, _ int) bool {
+		return lo.ContainsBy(ev.Categories, func(name string) bool {
+			return strings.EqualFold(name, c.Name)
+		})
+	})
+
+	if len(model) == 0 {
+		return nil
+	}
+
+	relations := lo.Map(model, func(c *Category, _ int) eventToCategory {
+		return eventToCategory{
+			CategoryID: c.ID,
+			EventID:    ev.ID,
+		}
+	})
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&relations).Exec(ctx))
+}
+
+// deleteEventCategories deletes event's category relations.
+func deleteEventCategories(ctx context.Context, db bun.IDB, eventID snowflake.ID) error {
+	if err := sqlite.WithErrorChecking(
+		db.NewDelete().Model((*eventToCategory)(nil)).
+			Where("event_id = ?", eventID).
+			Exec(ctx),
+	); err != nil && !errors.Is(err, calendar.PreconditionFailed) {
+		return err
+	}
+
+	return nil
+}
+
+// loadEventCategories populates the categories of events.
+func loadEventCategories(ctx context.Context, db bun.IDB, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	type row struct {
+		EventID snowflake.ID `bun:"event_id"`
+		Name    string       `bun:"name"`
+	}
+
+	rows := []row{}
+
+	if err := db.NewSelect().
+		TableExpr("events_categories AS ec").
+		ColumnExpr("ec.event_id, c.name").
+		Join("JOIN categories AS c ON c.id = ec.category_id").
+		Where("ec.event_id IN (?)", bun.In(lo.Map(events, func(ev *domain.Event, _ int) snowflake.ID {
+			return ev.ID
+		}))).
+		Order("c.sort_order ASC").
+		Scan(ctx, &rows); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	byEvent := lo.GroupByMap(rows, func(r row) (snowflake.ID, string) {
+		return r.EventID, r.Name
+	})
+
+	for _, ev := range events {
+		ev.Categories = byEvent[ev.ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("setting categories", func() {
+	JustBeforeEach(func(ctx SpecContext) {
+		Expect(model.SetCategories(ctx, db, domain.NewCategoryList("Music", "Theatre"))).To(Succeed())
+	})
+
+	Specify("categories are listed in order", func(ctx SpecContext) {
+		categories := Must(model.ListCategories(ctx, db, time.Time{}))
+
+		Expect(categories).To(HaveExactElements(
+			HaveField("Name", "Music"),
+			HaveField("Name", "Theatre"),
+		))
+	})
+
+	Specify("categories can be reordered and renamed", func(ctx SpecContext) {
+		Expect(model.SetCategories(ctx, db, domain.NewCategoryList("theatre", "Music", "Film"))).To(Succeed())
+
+		categories := Must(model.ListCategories(ctx, db, time.Time{}))
+
+		Expect(categories).To(HaveExactElements(
+			HaveField("Name", "theatre"),
+			HaveField("Name", "Music"),
+			HaveField("Name", "Film"),
+		))
+	})
+
+	When("a category with events is removed", func() {
+		var ev *domain.Event
+
+		JustBeforeEach(func(ctx SpecContext) {
+			ev = &domain.Event{
+				ID:          snowflake.Generate(),
+				StartAt:     time.Now().Add(time.Hour),
+				Title:       "Event 1",
+				Description: "Desc 1",
+				UserID:      snowflake.Generate(),
+				Categories:  []string{"Music", "Theatre"},
+			}
+
+			Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+			Expect(model.SetCategories(ctx, db, domain.NewCategoryList("Theatre"))).To(Succeed())
+		})
+
+		Specify("event relations are removed", func(ctx SpecContext) {
+			event := Must(model.GetEvent(ctx, db, ev.ID))
+
+			Expect(event.Categories).To(HaveExactElements("Theatre"))
+		})
+	})
+})
+
+var _ = Describe("listing categories", func() {
+	JustBeforeEach(func(ctx SpecContext) {
+		Expect(model.SetCategories(ctx, db, domain.NewCategoryList("Music", "Theatre", "Film"))).To(Succeed())
+
+		By("inserting events", func() {
+			events := []*domain.Event{
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Now().Add(2 * time.Hour),
+					Title:       "Event 1",
+					Description: "Desc 1",
+					Categories:  []string{"Music", "Theatre"},
+				},
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Now().Add(1 * time.Hour),
+					Title:       "Event 2",
+					Description: "Desc 2",
+					Categories:  []string{"Music"},
+				},
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Now().Add(1 * time.Hour),
+					Title:       "Event 3",
+					Description: "Desc 3",
+					IsDraft:     true,
+					Categories:  []string{"Theatre"},
+				},
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Now().Add(-24 * time.Hour),
+					Title:       "Event 4",
+					Description: "Desc 4",
+					Categories:  []string{"Film"},
+				},
+			}
+
+			for _, ev := range events {
+				Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+			}
+		})
+	})
+
+	Specify("categories contain the number of related published future events", func(ctx SpecContext) {
+		categories := Must(model.ListCategories(ctx, db, time.Now()))
+
+		Expect(categories).To(HaveExactElements(
+			PointTo(MatchAllFields(Fields{
+				"Name":       Equal("Music"),
+				"EventCount": Equal(uint64(2)),
+			})),
+			PointTo(MatchAllFields(Fields{
+				"Name":       Equal("Theatre"),
+				"EventCount": Equal(uint64(1)),
+			})),
+			PointTo(MatchAllFields(Fields{
+				"Name":       Equal("Film"),
+				"EventCount": Equal(uint64(0)),
+			})),
+		))
+	})
+
+	Specify("events can be filtered by category", func(ctx SpecContext) {
+		result := Must(
+			model.NewEventsQuery().
+				WithOrder(0, model.OrderStartAtAsc).
+				WithCategory("Music").
+				List(ctx, db),
+		)
+
+		Expect(result).To(HaveExactElements(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Title":      Equal("Event 2"),
+				"Categories": HaveExactElements("Music"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Title":      Equal("Event 1"),
+				"Categories": HaveExactElements("Music", "Theatre"),
+			})),
+		))
+	})
+
+	Specify("category names are matched case-insensitively", func(ctx SpecContext) {
+		ev := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().Add(3 * time.Hour),
+			Title:       "Event 5",
+			Description: "Desc 5",
+			Categories:  []string{"film"},
+		}
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+
+		result := Must(
+			model.NewEventsQuery().
+				WithStartAtFrom(time.Now()).
+				WithCategory("FILM").
+				List(ctx, db),
+		)
+
+		Expect(result).To(HaveExactElements(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Title":      Equal("Event 5"),
+				"Categories": HaveExactElements("Film"),
+			})),
+		))
+	})
+
+	Specify("draft categories are kept", func(ctx SpecContext) {
+		result := Must(
+			model.NewEventsQuery().
+				WithOrder(0, model.OrderStartAtAsc).
+				WithCategory("Theatre").
+				WithIncludeDrafts().
+				List(ctx, db),
+		)
+
+		Expect(result).To(HaveLen(2))
+	})
+})
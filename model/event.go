@@ -2,14 +2,15 @@ package model
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/pkg/snowflake"
 	"github.com/mgnsk/calendar/pkg/sqlite"
 	"github.com/mgnsk/calendar/pkg/textfilter"
+	"github.com/mgnsk/calendar/pkg/timestamp"
 	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 )
@@ -19,6 +20,7 @@ type Event struct {
 	ID             snowflake.ID `bun:"id,pk"`
 	StartAtUnix    int64        `bun:"start_at_unix"`
 	TimezoneOffset int          `bun:"tz_offset"`
+	Timezone       string       `bun:"timezone"`
 	Title          string       `bun:"title"`
 	Description    string       `bun:"description"`
 	URL            string       `bun:"url"`
@@ -27,9 +29,21 @@ type Event struct {
 	OSMID          uint64       `bun:"osm_id"`
 	Latitude       float64      `bun:"latitude"`
 	Longitude      float64      `bun:"longitude"`
+	VenueID        snowflake.ID `bun:"venue_id"`
+	Language       string       `bun:"language"`
+	UID            string       `bun:"uid"`
 
-	IsDraft bool         `bun:"is_draft"`
-	UserID  snowflake.ID `bun:"user_id"`
+	IsDraft   bool         `bun:"is_draft"`
+	IsPending bool         `bun:"is_pending"`
+	UserID    snowflake.ID `bun:"user_id"`
+
+	PublishAtUnix int64 `bun:"publish_at_unix"`
+	Capacity      int   `bun:"capacity"`
+
+	TimezoneOverride bool `bun:"timezone_override"`
+	TimezoneResolved bool `bun:"timezone_resolved"`
+
+	Snippet string `bun:"snippet,scanonly"`
 
 	bun.BaseModel `bun:"events"`
 }
@@ -42,7 +56,7 @@ type eventToTag struct {
 }
 
 // GetEvent retrieves a single event.
-func GetEvent(ctx context.Context, db *bun.DB, id snowflake.ID) (*domain.Event, error) {
+func GetEvent(ctx context.Context, db bun.IDB, id snowflake.ID) (*domain.Event, error) {
 	model := &Event{}
 
 	if err := db.NewSelect().Model(model).
@@ -51,7 +65,40 @@ func GetEvent(ctx context.Context, db *bun.DB, id snowflake.ID) (*domain.Event,
 		return nil, sqlite.NormalizeError(err)
 	}
 
-	return eventToDomain(model), nil
+	ev := eventToDomain(model)
+
+	if err := loadEventCategories(ctx, db, []*domain.Event{ev}); err != nil {
+		return nil, err
+	}
+
+	if err := loadEventTranslations(ctx, db, []*domain.Event{ev}); err != nil {
+		return nil, err
+	}
+
+	if err := loadEventAttachments(ctx, db, []*domain.Event{ev}); err != nil {
+		return nil, err
+	}
+
+	if err := loadEventAttendance(ctx, db, []*domain.Event{ev}); err != nil {
+		return nil, err
+	}
+
+	return ev, nil
+}
+
+// GetEventByUID retrieves a single event by its iCalendar UID.
+func GetEventByUID(ctx context.Context, db bun.IDB, uid string) (*domain.Event, error) {
+	var id snowflake.ID
+
+	if err := db.NewSelect().Model((*Event)(nil)).
+		Column("id").
+		Where("uid = ?", uid).
+		WhereOr("uid = '' AND CAST(id AS text) = ?", uid).
+		Scan(ctx, &id); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return GetEvent(ctx, db, id)
 }
 
 // InsertEvent inserts an event to the database.
@@ -63,6 +110,7 @@ func InsertEvent(ctx context.Context, db *bun.DB, ev *domain.Event) error {
 			ID:             ev.ID,
 			StartAtUnix:    ev.StartAt.Unix(),
 			TimezoneOffset: offset,
+			Timezone:       ev.GetTimezoneName(),
 			Title:          ev.Title,
 			Description:    ev.Description,
 			URL:            ev.URL,
@@ -71,100 +119,236 @@ func InsertEvent(ctx context.Context, db *bun.DB, ev *domain.Event) error {
 			OSMID:          ev.OSMID,
 			Latitude:       ev.Latitude,
 			Longitude:      ev.Longitude,
+			VenueID:        ev.VenueID,
+			Language:       ev.Language,
+			UID:            ev.UID,
 			IsDraft:        ev.IsDraft,
+			IsPending:      ev.IsPending,
 			UserID:         ev.UserID,
+			PublishAtUnix:  unixOrZero(ev.PublishAt),
+			Capacity:       ev.Capacity,
+
+			TimezoneOverride: ev.TimezoneOverride,
+			TimezoneResolved: true,
 		}).Exec(ctx)); err != nil {
 			return err
 		}
 
+		if err := setEventCategories(ctx, db, ev); err != nil {
+			return err
+		}
+
+		if err := setEventTranslations(ctx, db, ev); err != nil {
+			return err
+		}
+
+		if err := setEventAttachments(ctx, db, ev); err != nil {
+			return err
+		}
+
 		if ev.IsDraft {
 			return nil
 		}
 
-		return createEventTagRelations(ctx, db, ev)
+		x, err := newTagExtractor(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		if err := createEventTagRelations(ctx, db, x, ev); err != nil {
+			return err
+		}
+
+		return notifyEventChange(ctx, db, domain.WebhookPublished, ev.ID)
 	})
 }
 
 // UpdateEvent updates an event.
 func UpdateEvent(ctx context.Context, db *bun.DB, ev *domain.Event) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		return updateEvent(ctx, db, ev)
+	})
+}
+
+func updateEvent(ctx context.Context, db bun.IDB, ev *domain.Event) error {
 	_, offset := ev.StartAt.Zone()
 
-	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
-		if err := sqlite.WithErrorChecking(
-			db.NewUpdate().Model(&Event{
-				StartAtUnix:    ev.StartAt.Unix(),
-				TimezoneOffset: offset,
-				Title:          ev.Title,
-				Description:    ev.Description,
-				URL:            ev.URL,
-				Location:       ev.Location,
-				OSMType:        ev.OSMType,
-				OSMID:          ev.OSMID,
-				Latitude:       ev.Latitude,
-				Longitude:      ev.Longitude,
-				IsDraft:        ev.IsDraft,
-			}).
-				Column(
-					"start_at_unix",
-					"tz_offset",
-					"title",
-					"description",
-					"url",
-					"location",
-					"osm_type",
-					"osm_id",
-					"latitude",
-					"longitude",
-					"is_draft",
-				).
-				Where("id = ?", ev.ID).
-				Exec(ctx),
-		); err != nil {
-			return err
-		}
+	var wasDraft bool
+	if err := db.NewSelect().Model((*Event)(nil)).
+		Column("is_draft").
+		Where("id = ?", ev.ID).
+		Scan(ctx, &wasDraft); err != nil {
+		return sqlite.NormalizeError(err)
+	}
 
-		// Delete old tag relations.
-		if err := DeleteTags(ctx, db, ev.ID); err != nil {
-			return err
-		}
+	if err := sqlite.WithErrorChecking(
+		db.NewUpdate().Model(&Event{
+			StartAtUnix:    ev.StartAt.Unix(),
+			TimezoneOffset: offset,
+			Timezone:       ev.GetTimezoneName(),
+			Title:          ev.Title,
+			Description:    ev.Description,
+			URL:            ev.URL,
+			Location:       ev.Location,
+			OSMType:        ev.OSMType,
+			OSMID:          ev.OSMID,
+			Latitude:       ev.Latitude,
+			Longitude:      ev.Longitude,
+			VenueID:        ev.VenueID,
+			Language:       ev.Language,
+			IsDraft:        ev.IsDraft,
+			IsPending:      ev.IsPending,
+			PublishAtUnix:  unixOrZero(ev.PublishAt),
+			Capacity:       ev.Capacity,
+
+			TimezoneOverride: ev.TimezoneOverride,
+			TimezoneResolved: true,
+		}).
+			Column(
+				"start_at_unix",
+				"tz_offset",
+				"timezone",
+				"title",
+				"description",
+				"url",
+				"location",
+				"osm_type",
+				"osm_id",
+				"latitude",
+				"longitude",
+				"venue_id",
+				"language",
+				"is_draft",
+				"is_pending",
+				"publish_at_unix",
+				"capacity",
+				"timezone_override",
+				"timezone_resolved",
+			).
+			Where("id = ?", ev.ID).
+			Exec(ctx),
+	); err != nil {
+		return err
+	}
 
-		// Clean up orphaned tags.
-		if err := CleanTags(ctx, db); err != nil {
-			return err
-		}
+	if err := setEventCategories(ctx, db, ev); err != nil {
+		return err
+	}
 
-		if ev.IsDraft {
+	if err := setEventTranslations(ctx, db, ev); err != nil {
+		return err
+	}
+
+	if err := setEventAttachments(ctx, db, ev); err != nil {
+		return err
+	}
+
+	// The capacity may have been raised.
+	if err := promoteWaitlist(ctx, db, ev.ID, ev.Capacity); err != nil {
+		return err
+	}
+
+	// Delete old tag relations.
+	if err := DeleteTags(ctx, db, ev.ID); err != nil {
+		return err
+	}
+
+	// Clean up orphaned tags.
+	if err := CleanTags(ctx, db); err != nil {
+		return err
+	}
+
+	if ev.IsDraft {
+		if wasDraft {
 			return nil
 		}
 
-		// Recreate tag relations.
-		return createEventTagRelations(ctx, db, ev)
-	})
+		return notifyEventChange(ctx, db, domain.WebhookUnpublished, ev.ID)
+	}
+
+	x, err := newTagExtractor(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	// Recreate tag relations.
+	if err := createEventTagRelations(ctx, db, x, ev); err != nil {
+		return err
+	}
+
+	if wasDraft {
+		return notifyEventChange(ctx, db, domain.WebhookPublished, ev.ID)
+	}
+
+	return notifyEventChange(ctx, db, domain.WebhookUpdated, ev.ID)
 }
 
 // DeleteEvent deletes an event..
 func DeleteEvent(ctx context.Context, db *bun.DB, ev *domain.Event) error {
 	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
-		if err := sqlite.WithErrorChecking(
-			db.NewDelete().Model((*Event)(nil)).
-				Where("id = ?", ev.ID).
-				Exec(ctx),
-		); err != nil {
-			return err
-		}
+		return deleteEvent(ctx, db, ev.ID)
+	})
+}
 
-		// Delete tag relations.
-		if err := DeleteTags(ctx, db, ev.ID); err != nil {
+func deleteEvent(ctx context.Context, db bun.IDB, id snowflake.ID) error {
+	var isDraft bool
+	if err := db.NewSelect().Model((*Event)(nil)).
+		Column("is_draft").
+		Where("id = ?", id).
+		Scan(ctx, &isDraft); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	if !isDraft {
+		if err := notifyEventChange(ctx, db, domain.WebhookDeleted, id); err != nil {
 			return err
 		}
+	}
 
-		// Clean up orphaned tags.
-		return CleanTags(ctx, db)
-	})
+	if err := sqlite.WithErrorChecking(
+		db.NewDelete().Model((*Event)(nil)).
+			Where("id = ?", id).
+			Exec(ctx),
+	); err != nil {
+		return err
+	}
+
+	if err := deleteEventCategories(ctx, db, id); err != nil {
+		return err
+	}
+
+	if err := deleteEventTranslations(ctx, db, id); err != nil {
+		return err
+	}
+
+	if err := deleteEventAttachments(ctx, db, id); err != nil {
+		return err
+	}
+
+	if err := deleteEventRSVPs(ctx, db, id); err != nil {
+		return err
+	}
+
+	// Delete tag relations.
+	if err := DeleteTags(ctx, db, id); err != nil {
+		return err
+	}
+
+	// Clean up orphaned tags.
+	return CleanTags(ctx, db)
 }
 
-func createEventTagRelations(ctx context.Context, db bun.IDB, ev *domain.Event) error {
-	tags := ev.GetTags()
+// notifyEventChange queues the webhook and ActivityPub deliveries of an event lifecycle change.
+func notifyEventChange(ctx context.Context, db bun.IDB, t domain.WebhookEventType, id snowflake.ID) error {
+	if err := queueWebhookDeliveries(ctx, db, t, id); err != nil {
+		return err
+	}
+
+	return queueActivityDeliveries(ctx, db, domain.NewActivityType(t), id)
+}
+
+func createEventTagRelations(ctx context.Context, db bun.IDB, x *textfilter.TagExtractor, ev *domain.Event) error {
+	tags := ev.GetTags(x)
 	if len(tags) == 0 {
 		return nil
 	}
@@ -200,13 +384,27 @@ var (
 	OrderStartAtDesc   = &[]string{"event.start_at_unix DESC", "event.id ASC"}
 	OrderCreatedAtAsc  = &[]string{"event.id ASC"}
 	OrderCreatedAtDesc = &[]string{"event.id DESC"}
+	OrderTitleAsc      = &[]string{"event.title COLLATE NOCASE ASC", "event.id ASC"}
+	OrderTitleDesc     = &[]string{"event.title COLLATE NOCASE DESC", "event.id ASC"}
+
+	// OrderRelevance orders search results by relevance, falling back
+	// to start time when there is no search text.
+	OrderRelevance = &[]string{"search_result.rank ASC", "event.start_at_unix ASC", "event.id ASC"}
+
+	// OrderDistance orders events by distance from the WithinRadius point,
+	// falling back to start time when there is no point.
+	OrderDistance = &[]string{"event.start_at_unix ASC", "event.id ASC"}
 )
 
 // SelectQuery is an events select query.
 type SelectQuery struct {
 	*bun.SelectQuery
-	includeDrafts bool
-	searchText    string
+	includeDrafts  bool
+	searchText     string
+	searchLocation *time.Location
+	orderRelevance bool
+	orderDistance  bool
+	near           *geoPoint
 }
 
 // EventsQueryBuilder builds an event list query.
@@ -258,6 +456,28 @@ func (build EventsQueryBuilder) WithOrder(cursor int64, orders EventOrder) Event
 				q.Where("event.id < ?", cursor)
 			}
 
+		case OrderTitleAsc, OrderTitleDesc:
+			for _, order := range *orders {
+				q.OrderExpr(order)
+			}
+			if cursor > 0 {
+				q.Offset(int(cursor))
+			}
+
+		case OrderRelevance:
+			// Applied in List when the search text is known.
+			q.orderRelevance = true
+			if cursor > 0 {
+				q.Offset(int(cursor))
+			}
+
+		case OrderDistance:
+			// Applied in List when the point is known.
+			q.orderDistance = true
+			if cursor > 0 {
+				q.Offset(int(cursor))
+			}
+
 		default:
 			panic("invalid order")
 		}
@@ -291,6 +511,81 @@ func (build EventsQueryBuilder) WithUserID(userID snowflake.ID) EventsQueryBuild
 	}
 }
 
+// WithIDs filters the event list by IDs.
+func (build EventsQueryBuilder) WithIDs(ids ...snowflake.ID) EventsQueryBuilder {
+	return func(q *SelectQuery) {
+		build(q)
+
+		q.Where("event.id IN (?)", bun.In(ids))
+	}
+}
+
+// WithVenue filters the event list by venue ID.
+func (build EventsQueryBuilder) WithVenue(venueID snowflake.ID) EventsQueryBuilder {
+	return func(q *SelectQuery) {
+		build(q)
+
+		q.Where("event.venue_id = ?", venueID)
+	}
+}
+
+// WithCategory filters the event list by curated category name case-insensitively.
+func (build EventsQueryBuilder) WithCategory(name string) EventsQueryBuilder {
+	return func(q *SelectQuery) {
+		build(q)
+
+		q.Where("event.id IN (SELECT ec.event_id FROM events_categories AS ec JOIN categories AS c ON c.id = ec.category_id WHERE c.name = ? COLLATE NOCASE)", name)
+	}
+}
+
+// TagMatch specifies how multiple tags are matched.
+type TagMatch string
+
+// Tag match values.
+const (
+	// TagMatchAll matches events which have all of the tags.
+	TagMatchAll TagMatch = "all"
+
+	// TagMatchAny matches events which have any of the tags.
+	TagMatchAny TagMatch = "any"
+)
+
+// ParseTagMatch parses a tag match value, defaulting to TagMatchAll.
+func ParseTagMatch(s string) TagMatch {
+	if TagMatch(s) == TagMatchAny {
+		return TagMatchAny
+	}
+
+	return TagMatchAll
+}
+
+// WithTags filters the event list by tags.
+func (build EventsQueryBuilder) WithTags(match TagMatch, names ...string) EventsQueryBuilder {
+	names = lo.Uniq(lo.FilterMap(names, func(name string, _ int) (string, bool) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		return name, name != ""
+	}))
+
+	return func(q *SelectQuery) {
+		build(q)
+
+		if len(names) == 0 {
+			return
+		}
+
+		switch match {
+		case TagMatchAll:
+			q.Where("event.id IN (SELECT et.event_id FROM events_tags AS et JOIN tags AS t ON t.id = et.tag_id WHERE t.name IN (?) GROUP BY et.event_id HAVING COUNT(DISTINCT t.id) = ?)", bun.In(names), len(names))
+
+		case TagMatchAny:
+			q.Where("event.id IN (SELECT et.event_id FROM events_tags AS et JOIN tags AS t ON t.id = et.tag_id WHERE t.name IN (?))", bun.In(names))
+
+		default:
+			panic("invalid tag match")
+		}
+	}
+}
+
 // WithIncludeDrafts includes drafts.
 func (build EventsQueryBuilder) WithIncludeDrafts() EventsQueryBuilder {
 	return func(q *SelectQuery) {
@@ -300,12 +595,52 @@ func (build EventsQueryBuilder) WithIncludeDrafts() EventsQueryBuilder {
 	}
 }
 
+// WithPending filters the event list by drafts awaiting moderation.
+func (build EventsQueryBuilder) WithPending() EventsQueryBuilder {
+	return func(q *SelectQuery) {
+		build(q)
+
+		q.includeDrafts = true
+		q.Where("event.is_pending = 1")
+	}
+}
+
+// WithDraftsOnly filters the event list by drafts which are not awaiting moderation.
+func (build EventsQueryBuilder) WithDraftsOnly() EventsQueryBuilder {
+	return func(q *SelectQuery) {
+		build(q)
+
+		q.includeDrafts = true
+		q.Where("event.is_draft = 1 AND event.is_pending = 0")
+	}
+}
+
+// likeEscaper escapes LIKE pattern characters.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// WithTitleContains filters the event list by a case-insensitive substring of the title.
+func (build EventsQueryBuilder) WithTitleContains(s string) EventsQueryBuilder {
+	s = strings.TrimSpace(s)
+
+	return func(q *SelectQuery) {
+		build(q)
+
+		if s == "" {
+			return
+		}
+
+		q.Where("event.title LIKE ? ESCAPE '\\'", "%"+likeEscaper.Replace(s)+"%")
+	}
+}
+
 // WithSearchText filters the result by search text.
-func (build EventsQueryBuilder) WithSearchText(s string) EventsQueryBuilder {
+// Dates in the search text are parsed in loc.
+func (build EventsQueryBuilder) WithSearchText(s string, loc *time.Location) EventsQueryBuilder {
 	return func(q *SelectQuery) {
 		build(q)
 
 		q.searchText = s
+		q.searchLocation = loc
 	}
 }
 
@@ -331,36 +666,100 @@ func (build EventsQueryBuilder) List(ctx context.Context, db *bun.DB) ([]*domain
 	// 	return q.Order("tag.name ASC")
 	// })
 
-	// Note: for search, we do not order by `rank` column from fts table.
-	// The original order of the events query is used.
+	var search *searchQuery
+
 	if q.searchText != "" {
 		q.searchText = strings.TrimSpace(q.searchText)
-		if len(q.searchText) < 3 {
+		if utf8.RuneCountInString(q.searchText) < 3 {
 			return []*domain.Event{}, nil
 		}
 
-		searchWord := buildSearchQuery(q.searchText)
+		var err error
 
+		loc := q.searchLocation
+		if loc == nil {
+			loc = time.UTC
+		}
+
+		search, err = parseSearchQuery(q.searchText, loc)
+		if err != nil {
+			return nil, err
+		}
+
+		if !search.after.IsZero() {
+			q.Where("event.start_at_unix >= ?", search.after.Unix())
+		}
+
+		if !search.before.IsZero() {
+			q.Where("event.start_at_unix < ?", search.before.Unix())
+		}
+	}
+
+	switch {
+	case search != nil && search.match != "":
+		// Column weights rank title matches above location
+		// and location matches above description.
 		ftsQuery := db.NewSelect().
 			ColumnExpr("rowid").
+			ColumnExpr("bm25(events_fts, 10.0, 1.0, 5.0) AS rank").
+			ColumnExpr("snippet(events_fts, -1, ?, ?, '…', 32) AS snippet", snippetMatchStart, snippetMatchEnd).
 			Table("events_fts").
-			Where("events_fts MATCH ?", searchWord)
+			Where("events_fts MATCH ?", search.match)
+
+		q.ColumnExpr("event.*").ColumnExpr("search_result.snippet")
+		q.Join("JOIN (?) AS search_result ON search_result.rowid = event.id", ftsQuery)
 
-		q.Join("JOIN (?) AS exact_result ON exact_result.rowid = event.id", ftsQuery)
+		if q.orderRelevance {
+			q.Order(*OrderRelevance...)
+		}
+
+	case q.orderRelevance:
+		q.Order(*OrderStartAtAsc...)
+	}
+
+	if q.orderDistance {
+		if q.near != nil {
+			q.OrderExpr(distanceExpr+" ASC", distanceArgs(q.near.lat, q.near.lng)...)
+		}
+		q.Order(*OrderDistance...)
 	}
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, sqlite.NormalizeError(err)
 	}
 
-	return lo.Map(model, func(ev *Event, _ int) *domain.Event {
+	events := lo.Map(model, func(ev *Event, _ int) *domain.Event {
 		return eventToDomain(ev)
-	}), nil
+	})
+
+	if err := loadEventCategories(ctx, db, events); err != nil {
+		return nil, err
+	}
+
+	if err := loadEventTranslations(ctx, db, events); err != nil {
+		return nil, err
+	}
+
+	if err := loadEventAttachments(ctx, db, events); err != nil {
+		return nil, err
+	}
+
+	if err := loadEventAttendance(ctx, db, events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
 }
 
 func eventToDomain(ev *Event) *domain.Event {
 	zone := time.FixedZone("", ev.TimezoneOffset)
 
+	if ev.Timezone != "" {
+		if loc, err := timestamp.LoadLocation(ev.Timezone); err == nil {
+			zone = loc
+		}
+	}
+
 	return &domain.Event{
 		ID:          ev.ID,
 		StartAt:     time.Unix(ev.StartAtUnix, 0).In(zone),
@@ -372,34 +771,34 @@ func eventToDomain(ev *Event) *domain.Event {
 		OSMID:       ev.OSMID,
 		Latitude:    ev.Latitude,
 		Longitude:   ev.Longitude,
+		VenueID:     ev.VenueID,
 		IsDraft:     ev.IsDraft,
+		IsPending:   ev.IsPending,
 		UserID:      ev.UserID,
+		Language:    ev.Language,
+		UID:         ev.UID,
+		PublishAt:   timeOrZero(ev.PublishAtUnix),
+		Capacity:    ev.Capacity,
+		Snippet:     parseSnippet(ev.Snippet),
+
+		TimezoneOverride: ev.TimezoneOverride,
 	}
 }
 
-func buildSearchQuery(text string) string {
-	var (
-		exact   string
-		general []string
-	)
-
-	if !strings.Contains(text, `"`) {
-		exact = textfilter.EnsureQuoted(text)
+// unixOrZero returns the unix time of t or zero if t is zero.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
 	}
 
-	general = textfilter.PrepareFTSSearchStrings(text)
+	return t.Unix()
+}
 
-	var searchWord string
-	if exact == "" {
-		// Only general search.
-		searchWord = strings.Join(general, " ")
-	} else if len(general) == 0 || len(general) == 1 && exact == general[0] {
-		// Only exact search.
-		searchWord = exact
-	} else {
-		// Both exact and general.
-		searchWord = fmt.Sprintf("(%s) OR (%s)", exact, strings.Join(general, " "))
+// timeOrZero returns the time of a unix timestamp or zero time if the timestamp is zero.
+func timeOrZero(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
 	}
 
-	return searchWord
+	return time.Unix(unix, 0)
 }
@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	"github.com/samber/lo"
 )
 
 var _ = Describe("inserting events", func() {
@@ -46,18 +47,30 @@ var _ = Describe("inserting events", func() {
 				Expect(event).To(SatisfyAll(
 					HaveField("GetCreatedAt()", BeTemporally("~", time.Now(), time.Second)),
 					PointTo(MatchAllFields(Fields{
-						"ID":          Equal(ev.ID),
-						"StartAt":     BeTemporally("~", ev.StartAt, time.Second),
-						"Title":       Equal(ev.Title),
-						"Description": Equal(ev.Description),
-						"URL":         Equal(ev.URL),
-						"Location":    Equal("hash"),
-						"OSMType":     Equal("node"),
-						"OSMID":       Equal(uint64(123)),
-						"Latitude":    Equal(float64(1)),
-						"Longitude":   Equal(float64(1)),
-						"IsDraft":     BeFalse(),
-						"UserID":      Equal(ev.UserID),
+						"ID":               Equal(ev.ID),
+						"StartAt":          BeTemporally("~", ev.StartAt, time.Second),
+						"Title":            Equal(ev.Title),
+						"Description":      Equal(ev.Description),
+						"URL":              Equal(ev.URL),
+						"Location":         Equal("hash"),
+						"OSMType":          Equal("node"),
+						"OSMID":            Equal(uint64(123)),
+						"Latitude":         Equal(float64(1)),
+						"Longitude":        Equal(float64(1)),
+						"VenueID":          BeZero(),
+						"IsDraft":          BeFalse(),
+						"IsPending":        BeFalse(),
+						"PublishAt":        BeZero(),
+						"Capacity":         BeZero(),
+						"TimezoneOverride": BeFalse(),
+						"Attendance":       BeZero(),
+						"UserID":           Equal(ev.UserID),
+						"Categories":       BeEmpty(),
+						"Language":         BeEmpty(),
+						"Translations":     BeEmpty(),
+						"UID":              BeEmpty(),
+						"Attachments":      BeEmpty(),
+						"Snippet":          BeEmpty(),
 					})),
 				))
 			})
@@ -69,18 +82,30 @@ var _ = Describe("inserting events", func() {
 					SatisfyAll(
 						HaveField("GetCreatedAt()", BeTemporally("~", time.Now(), time.Second)),
 						PointTo(MatchAllFields(Fields{
-							"ID":          Equal(ev.ID),
-							"StartAt":     BeTemporally("~", ev.StartAt, time.Second),
-							"Title":       Equal(ev.Title),
-							"Description": Equal(ev.Description),
-							"URL":         Equal(ev.URL),
-							"Location":    Equal("hash"),
-							"OSMType":     Equal("node"),
-							"OSMID":       Equal(uint64(123)),
-							"Latitude":    Equal(float64(1)),
-							"Longitude":   Equal(float64(1)),
-							"IsDraft":     BeFalse(),
-							"UserID":      Equal(ev.UserID),
+							"ID":               Equal(ev.ID),
+							"StartAt":          BeTemporally("~", ev.StartAt, time.Second),
+							"Title":            Equal(ev.Title),
+							"Description":      Equal(ev.Description),
+							"URL":              Equal(ev.URL),
+							"Location":         Equal("hash"),
+							"OSMType":          Equal("node"),
+							"OSMID":            Equal(uint64(123)),
+							"Latitude":         Equal(float64(1)),
+							"Longitude":        Equal(float64(1)),
+							"VenueID":          BeZero(),
+							"IsDraft":          BeFalse(),
+							"IsPending":        BeFalse(),
+							"PublishAt":        BeZero(),
+							"Capacity":         BeZero(),
+							"TimezoneOverride": BeFalse(),
+							"Attendance":       BeZero(),
+							"UserID":           Equal(ev.UserID),
+							"Categories":       BeEmpty(),
+							"Language":         BeEmpty(),
+							"Translations":     BeEmpty(),
+							"UID":              BeEmpty(),
+							"Attachments":      BeEmpty(),
+							"Snippet":          BeEmpty(),
 						})),
 					),
 				))
@@ -148,6 +173,7 @@ var _ = Describe("updating events", func() {
 				"OSMID":       Equal(uint64(123)),
 				"Latitude":    Equal(float64(2)),
 				"Longitude":   Equal(float64(2)),
+				"VenueID":     BeZero(),
 				"IsDraft":     BeFalse(),
 			})))
 		})
@@ -430,7 +456,7 @@ var _ = Describe("full text search", func() {
 				WithStartAtFrom(time.Now().Add(1*time.Hour).Add(30*time.Minute)).
 				WithStartAtUntil(time.Now().Add(2*time.Hour).Add(30*time.Minute)).
 				WithOrder(0, model.OrderStartAtAsc).
-				WithSearchText(query).
+				WithSearchText(query, time.UTC).
 				List(ctx, db)
 
 			Expect(result).To(BeEmpty())
@@ -450,7 +476,7 @@ var _ = Describe("full text search", func() {
 					WithStartAtFrom(startTime).
 					WithStartAtUntil(endTime).
 					WithOrder(0, model.OrderStartAtAsc).
-					WithSearchText(query).
+					WithSearchText(query, time.UTC).
 					List(ctx, db),
 			)
 
@@ -479,6 +505,133 @@ var _ = Describe("full text search", func() {
 	)
 })
 
+var _ = Describe("advanced search", func() {
+	JustBeforeEach(func(ctx SpecContext) {
+		By("inserting events", func() {
+			events := []*domain.Event{
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Date(2026, 11, 5, 18, 0, 0, 0, time.UTC),
+					Title:       "Poetry night",
+					Description: "Bring your own poems to the open mic.",
+					Location:    "Tallinn",
+				},
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Date(2026, 10, 5, 18, 0, 0, 0, time.UTC),
+					Title:       "Open mic",
+					Description: "Music and poetry.",
+					Location:    "Tartu",
+				},
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Date(2026, 12, 5, 18, 0, 0, 0, time.UTC),
+					Title:       "Jazz evening",
+					Description: "Live music.",
+					Location:    "Open Mic Bar, Tallinn",
+				},
+			}
+
+			for _, ev := range events {
+				Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+			}
+		})
+	})
+
+	search := func(ctx SpecContext, query string, order model.EventOrder) []string {
+		result := Must(model.NewEventsQuery().
+			WithOrder(0, order).
+			WithSearchText(query, time.UTC).
+			List(ctx, db))
+
+		return lo.Map(result, func(ev *domain.Event, _ int) string {
+			return ev.Title
+		})
+	}
+
+	Specify("results are ranked by title, location and description matches", func(ctx SpecContext) {
+		Expect(search(ctx, "open mic", model.OrderRelevance)).To(HaveExactElements(
+			"Open mic",
+			"Jazz evening",
+			"Poetry night",
+		))
+	})
+
+	Specify("relevance order falls back to start time without search text", func(ctx SpecContext) {
+		Expect(search(ctx, "", model.OrderRelevance)).To(HaveExactElements(
+			"Open mic",
+			"Poetry night",
+			"Jazz evening",
+		))
+	})
+
+	Specify("results contain highlighted snippets", func(ctx SpecContext) {
+		result := Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderStartAtAsc).
+			WithSearchText("poems", time.UTC).
+			List(ctx, db))
+
+		Expect(result).To(HaveExactElements(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Title": Equal("Poetry night"),
+				"Snippet": ContainElement(domain.SnippetFragment{
+					Text:  "poems",
+					Match: true,
+				}),
+			})),
+		))
+	})
+
+	DescribeTable("field scoped and date queries",
+		func(ctx SpecContext, query string, expected []string) {
+			Expect(search(ctx, query, model.OrderStartAtAsc)).To(HaveExactElements(expected))
+		},
+		Entry("location", "location:tallinn", []string{"Poetry night", "Jazz evening"}),
+		Entry("quoted title", `title:"open mic"`, []string{"Open mic"}),
+		Entry("field and word", "location:tallinn music", []string{"Jazz evening"}),
+		Entry("after", "after:2026-11-01", []string{"Poetry night", "Jazz evening"}),
+		Entry("before", "before:2026-11-01", []string{"Open mic"}),
+		Entry("date range and word", "music after:2026-10-01 before:2026-11-01", []string{"Open mic"}),
+	)
+
+	Specify("dates are parsed in the given location", func(ctx SpecContext) {
+		loc := Must(time.LoadLocation("Pacific/Kiritimati"))
+
+		result := Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderStartAtAsc).
+			WithSearchText("before:2026-11-06", loc).
+			List(ctx, db))
+
+		Expect(result).To(HaveExactElements(
+			HaveField("Title", "Open mic"),
+		))
+
+		Expect(search(ctx, "before:2026-11-06", model.OrderStartAtAsc)).To(HaveExactElements(
+			"Open mic",
+			"Poetry night",
+		))
+	})
+
+	DescribeTable("invalid queries have descriptive errors",
+		func(ctx SpecContext, query, message string) {
+			_, err := model.NewEventsQuery().
+				WithOrder(0, model.OrderStartAtAsc).
+				WithSearchText(query, time.UTC).
+				List(ctx, db)
+
+			Expect(err).To(SatisfyAll(
+				MatchError(calendar.InvalidValue),
+				MatchError(ContainSubstring(message)),
+			))
+		},
+		Entry("unclosed quote", `"open mic`, "Missing closing quote"),
+		Entry("invalid date", "after:tomorrow", `Invalid date "tomorrow"`),
+		Entry("empty field", "location:", "Missing search term after location:"),
+		Entry("leading operator", "AND music", "AND must be placed between two search terms"),
+		Entry("repeated operator", "music OR OR jazz", "OR must be placed between two search terms"),
+	)
+})
+
 var _ = Describe("concurrent insert", func() {
 	Specify("test", func(ctx SpecContext) {
 		concurrency := 100
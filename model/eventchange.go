@@ -0,0 +1,69 @@
+package model
+
+import (
+	"context"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// EventChange is the event change log database model.
+// Rows are inserted by triggers on the events table.
+type EventChange struct {
+	ID      int64        `bun:"id,pk,autoincrement"`
+	EventID snowflake.ID `bun:"event_id"`
+	UserID  snowflake.ID `bun:"user_id"`
+	UID     string       `bun:"uid"`
+	Deleted bool         `bun:"deleted"`
+
+	bun.BaseModel `bun:"event_changes"`
+}
+
+// GetLastEventChange returns the position of the latest event change
+// or zero if there are no changes.
+func GetLastEventChange(ctx context.Context, db bun.IDB) (int64, error) {
+	var seq int64
+
+	if err := db.NewSelect().Model((*EventChange)(nil)).
+		ColumnExpr("COALESCE(MAX(id), 0)").
+		Scan(ctx, &seq); err != nil {
+		return 0, sqlite.NormalizeError(err)
+	}
+
+	return seq, nil
+}
+
+// ListEventChanges lists the latest change of each event changed after the since position.
+// Changes are limited to the events of a user unless userID is zero.
+func ListEventChanges(ctx context.Context, db bun.IDB, userID snowflake.ID, since int64) ([]*domain.EventChange, error) {
+	latest := db.NewSelect().Model((*EventChange)(nil)).
+		ColumnExpr("MAX(id)").
+		Where("id > ?", since).
+		Group("event_id")
+
+	if userID > 0 {
+		latest = latest.Where("user_id = ?", userID)
+	}
+
+	model := []*EventChange{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("id IN (?)", latest).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *EventChange, _ int) *domain.EventChange {
+		return &domain.EventChange{
+			Seq:     m.ID,
+			EventID: m.EventID,
+			UserID:  m.UserID,
+			UID:     m.UID,
+			Deleted: m.Deleted,
+		}
+	}), nil
+}
@@ -0,0 +1,82 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("event changes", func() {
+	var (
+		ev     *domain.Event
+		userID snowflake.ID
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		userID = snowflake.Generate()
+
+		ev = &domain.Event{
+			ID:          snowflake.Generate(),
+			UID:         "uid@calendar.testing",
+			StartAt:     time.Now().Add(24 * time.Hour),
+			Title:       "Event",
+			Description: "Desc",
+			UserID:      userID,
+		}
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+	})
+
+	Specify("no changes are listed after the latest change", func(ctx SpecContext) {
+		seq := Must(model.GetLastEventChange(ctx, db))
+		Expect(seq).To(BeNumerically(">", 0))
+
+		Expect(Must(model.ListEventChanges(ctx, db, userID, seq))).To(BeEmpty())
+	})
+
+	Specify("only the latest change of an event is listed", func(ctx SpecContext) {
+		ev.Title = "Updated"
+		Expect(model.UpdateEvent(ctx, db, ev)).To(Succeed())
+		Expect(model.DeleteEvent(ctx, db, ev)).To(Succeed())
+
+		Expect(Must(model.ListEventChanges(ctx, db, userID, 0))).To(HaveExactElements(SatisfyAll(
+			HaveField("EventID", ev.ID),
+			HaveField("UID", "uid@calendar.testing"),
+			HaveField("Deleted", true),
+		)))
+	})
+
+	Specify("changes are limited to the events of a user", func(ctx SpecContext) {
+		Expect(Must(model.ListEventChanges(ctx, db, snowflake.Generate(), 0))).To(BeEmpty())
+		Expect(Must(model.ListEventChanges(ctx, db, 0, 0))).To(HaveLen(1))
+	})
+
+	Specify("event moved to another owner is deleted for the previous owner", func(ctx SpecContext) {
+		seq := Must(model.GetLastEventChange(ctx, db))
+		otherID := snowflake.Generate()
+
+		_ = Must(model.ApplyBulkOperation(ctx, db, model.BulkOperation{
+			Action: model.BulkChangeOwner,
+			UserID: otherID,
+		}, ev.ID))
+
+		Expect(Must(model.ListEventChanges(ctx, db, userID, seq))).To(HaveExactElements(SatisfyAll(
+			HaveField("EventID", ev.ID),
+			HaveField("Deleted", true),
+		)))
+
+		Expect(Must(model.ListEventChanges(ctx, db, otherID, seq))).To(HaveExactElements(SatisfyAll(
+			HaveField("EventID", ev.ID),
+			HaveField("Deleted", false),
+		)))
+	})
+
+	Specify("event is found by UID", func(ctx SpecContext) {
+		Expect(Must(model.GetEventByUID(ctx, db, "uid@calendar.testing"))).To(HaveField("ID", ev.ID))
+	})
+})
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"math"
+
+	"github.com/uptrace/bun"
+)
+
+// earthRadiusKM is the mean radius of the Earth.
+const earthRadiusKM = 6371.0
+
+// geoPoint is a point in degrees.
+type geoPoint struct {
+	lat, lng float64
+}
+
+// WithinBBox filters the event list by a bounding box in degrees.
+// Events without coordinates are excluded.
+func (build EventsQueryBuilder) WithinBBox(minLat, minLng, maxLat, maxLng float64) EventsQueryBuilder {
+	return func(q *SelectQuery) {
+		build(q)
+
+		q.Where("event.id IN (?)", bboxQuery(q.DB(), minLat, minLng, maxLat, maxLng))
+	}
+}
+
+// WithinRadius filters the event list by great-circle distance from a point.
+// Events without coordinates are excluded.
+func (build EventsQueryBuilder) WithinRadius(lat, lng, km float64) EventsQueryBuilder {
+	return func(q *SelectQuery) {
+		build(q)
+
+		// Narrow down candidates with the R*Tree index before computing distances.
+		dLat := km / (math.Pi * earthRadiusKM / 180)
+		dLng := 180.0
+		if c := math.Cos(lat * math.Pi / 180); c > 0.01 {
+			dLng = min(dLat/c, 180)
+		}
+
+		minLat, maxLat := max(lat-dLat, -90), min(lat+dLat, 90)
+
+		// Wrap the box around the antimeridian.
+		q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q.Where("event.id IN (?)", bboxQuery(q.DB(), minLat, max(lng-dLng, -180), maxLat, min(lng+dLng, 180)))
+
+			if dLng < 180 && lng-dLng < -180 {
+				q.WhereOr("event.id IN (?)", bboxQuery(q.DB(), minLat, lng-dLng+360, maxLat, 180))
+			}
+
+			if dLng < 180 && lng+dLng > 180 {
+				q.WhereOr("event.id IN (?)", bboxQuery(q.DB(), minLat, -180, maxLat, lng+dLng-360))
+			}
+
+			return q
+		})
+		q.Where(distanceExpr+" <= ?", distanceArgs(lat, lng, km)...)
+
+		q.near = &geoPoint{lat: lat, lng: lng}
+	}
+}
+
+func bboxQuery(db bun.IDB, minLat, minLng, maxLat, maxLng float64) *bun.SelectQuery {
+	return db.NewSelect().
+		ColumnExpr("id").
+		Table("events_rtree").
+		Where("max_lat >= ?", minLat).
+		Where("min_lat <= ?", maxLat).
+		Where("max_lng >= ?", minLng).
+		Where("min_lng <= ?", maxLng)
+}
+
+// distanceExpr is the haversine distance in kilometers from event to a point.
+// See distanceArgs for the arguments.
+const distanceExpr = "(2 * ? * asin(min(1, sqrt(pow(sin(radians(event.latitude - ?) / 2), 2) + cos(radians(?)) * cos(radians(event.latitude)) * pow(sin(radians(event.longitude - ?) / 2), 2)))))"
+
+// distanceArgs returns the distanceExpr arguments followed by extra arguments.
+func distanceArgs(lat, lng float64, extra ...any) []any {
+	return append([]any{earthRadiusKM, lat, lat, lng}, extra...)
+}
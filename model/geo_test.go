@@ -0,0 +1,159 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+)
+
+var _ = Describe("geographic search", func() {
+	var tallinn, tartu, helsinki, online *domain.Event
+
+	titles := func(events []*domain.Event) []string {
+		return lo.Map(events, func(ev *domain.Event, _ int) string {
+			return ev.Title
+		})
+	}
+
+	JustBeforeEach(func(ctx SpecContext) {
+		tallinn = &domain.Event{
+			ID:        snowflake.Generate(),
+			StartAt:   time.Now().Add(3 * time.Hour),
+			Title:     "Tallinn",
+			Latitude:  59.4370,
+			Longitude: 24.7536,
+		}
+
+		tartu = &domain.Event{
+			ID:        snowflake.Generate(),
+			StartAt:   time.Now().Add(2 * time.Hour),
+			Title:     "Tartu",
+			Latitude:  58.3780,
+			Longitude: 26.7290,
+		}
+
+		helsinki = &domain.Event{
+			ID:        snowflake.Generate(),
+			StartAt:   time.Now().Add(1 * time.Hour),
+			Title:     "Helsinki",
+			Latitude:  60.1699,
+			Longitude: 24.9384,
+		}
+
+		online = &domain.Event{
+			ID:      snowflake.Generate(),
+			StartAt: time.Now().Add(4 * time.Hour),
+			Title:   "Online",
+		}
+
+		By("inserting events", func() {
+			for _, ev := range []*domain.Event{tallinn, tartu, helsinki, online} {
+				Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+			}
+		})
+	})
+
+	Specify("events within radius are listed", func(ctx SpecContext) {
+		events := Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderStartAtAsc).
+			WithinRadius(tallinn.Latitude, tallinn.Longitude, 100).
+			List(ctx, db))
+
+		Expect(titles(events)).To(HaveExactElements("Helsinki", "Tallinn"))
+	})
+
+	Specify("radius search wraps around the antimeridian", func(ctx SpecContext) {
+		east := &domain.Event{
+			ID:        snowflake.Generate(),
+			StartAt:   time.Now().Add(5 * time.Hour),
+			Title:     "East",
+			Latitude:  -16.5,
+			Longitude: 179.9,
+		}
+
+		west := &domain.Event{
+			ID:        snowflake.Generate(),
+			StartAt:   time.Now().Add(6 * time.Hour),
+			Title:     "West",
+			Latitude:  -16.5,
+			Longitude: -179.9,
+		}
+
+		Expect(model.InsertEvent(ctx, db, east)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, west)).To(Succeed())
+
+		events := Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderStartAtAsc).
+			WithinRadius(east.Latitude, east.Longitude, 50).
+			List(ctx, db))
+
+		Expect(titles(events)).To(HaveExactElements("East", "West"))
+
+		events = Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderStartAtAsc).
+			WithinRadius(west.Latitude, west.Longitude, 50).
+			List(ctx, db))
+
+		Expect(titles(events)).To(HaveExactElements("East", "West"))
+	})
+
+	Specify("events within bounding box are listed", func(ctx SpecContext) {
+		events := Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderStartAtAsc).
+			WithinBBox(58, 24, 60, 27).
+			List(ctx, db))
+
+		Expect(titles(events)).To(HaveExactElements("Tartu", "Tallinn"))
+	})
+
+	Specify("events can be sorted by distance", func(ctx SpecContext) {
+		events := Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderDistance).
+			WithinRadius(tartu.Latitude, tartu.Longitude, 500).
+			List(ctx, db))
+
+		Expect(titles(events)).To(HaveExactElements("Tartu", "Tallinn", "Helsinki"))
+	})
+
+	Specify("distance order falls back to start time", func(ctx SpecContext) {
+		events := Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderDistance).
+			List(ctx, db))
+
+		Expect(titles(events)).To(HaveExactElements("Helsinki", "Tartu", "Tallinn", "Online"))
+	})
+
+	When("event is moved", func() {
+		JustBeforeEach(func(ctx SpecContext) {
+			tartu.Latitude = tallinn.Latitude
+			tartu.Longitude = tallinn.Longitude
+			Expect(model.UpdateEvent(ctx, db, tartu)).To(Succeed())
+		})
+
+		It("is found at the new location", func(ctx SpecContext) {
+			events := Must(model.NewEventsQuery().
+				WithOrder(0, model.OrderStartAtAsc).
+				WithinRadius(tallinn.Latitude, tallinn.Longitude, 10).
+				List(ctx, db))
+
+			Expect(titles(events)).To(HaveExactElements("Tartu", "Tallinn"))
+		})
+	})
+
+	When("event is deleted", func() {
+		JustBeforeEach(func(ctx SpecContext) {
+			Expect(model.DeleteEvent(ctx, db, tallinn)).To(Succeed())
+		})
+
+		It("is removed from the index", func(ctx SpecContext) {
+			count := Must(db.NewSelect().Table("events_rtree").Count(ctx))
+			Expect(count).To(Equal(2))
+		})
+	})
+})
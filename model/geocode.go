@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/uptrace/bun"
+)
+
+// GeocodeCacheTTL specifies how long geocoding results are cached.
+const GeocodeCacheTTL = 30 * 24 * time.Hour
+
+// GeocodeResult is the cached geocoding result database model.
+type GeocodeResult struct {
+	Query         string `bun:"query,pk"`
+	Language      string `bun:"language,pk"`
+	Results       string `bun:"results"`
+	CreatedAtUnix int64  `bun:"created_at_unix"`
+
+	bun.BaseModel `bun:"geocode_cache"`
+}
+
+// GetGeocodeResults returns cached geocoding results.
+// Queries are matched case-insensitively. Expired results are not returned.
+func GetGeocodeResults(ctx context.Context, db bun.IDB, query, language string) ([]*domain.Place, error) {
+	model := &GeocodeResult{}
+
+	if err := db.NewSelect().Model(model).
+		Where("query = ?", normalizeGeocodeQuery(query)).
+		Where("language = ?", language).
+		Where("created_at_unix >= ?", time.Now().Add(-GeocodeCacheTTL).Unix()).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	places := []*domain.Place{}
+
+	if err := json.Unmarshal([]byte(model.Results), &places); err != nil {
+		return nil, calendar.Internal.New("Invalid cached geocoding results", err)
+	}
+
+	return places, nil
+}
+
+// SetGeocodeResults caches geocoding results.
+func SetGeocodeResults(ctx context.Context, db bun.IDB, query, language string, places []*domain.Place) error {
+	if places == nil {
+		places = []*domain.Place{}
+	}
+
+	results, err := json.Marshal(places)
+	if err != nil {
+		return err
+	}
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&GeocodeResult{
+		Query:         normalizeGeocodeQuery(query),
+		Language:      language,
+		Results:       string(results),
+		CreatedAtUnix: time.Now().Unix(),
+	}).
+		On("CONFLICT (query, language) DO UPDATE").
+		Set("results = EXCLUDED.results").
+		Set("created_at_unix = EXCLUDED.created_at_unix").
+		Exec(ctx))
+}
+
+func normalizeGeocodeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
@@ -0,0 +1,43 @@
+package model_test
+
+import (
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("caching geocoding results", func() {
+	place := &domain.Place{
+		Label:     "Sveta, Telliskivi 60a, Tallinn",
+		OSMType:   "node",
+		OSMID:     123,
+		Latitude:  59.44,
+		Longitude: 24.73,
+	}
+
+	Specify("missing results are not found", func(ctx SpecContext) {
+		_, err := model.GetGeocodeResults(ctx, db, "sveta", "en")
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+
+	Specify("results are cached by normalized query and language", func(ctx SpecContext) {
+		Expect(model.SetGeocodeResults(ctx, db, "Sveta  Tallinn", "en", []*domain.Place{place})).To(Succeed())
+
+		Expect(Must(model.GetGeocodeResults(ctx, db, " sveta tallinn", "en"))).To(HaveExactElements(
+			Equal(place),
+		))
+
+		_, err := model.GetGeocodeResults(ctx, db, "sveta tallinn", "et")
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+
+	Specify("results are replaced", func(ctx SpecContext) {
+		Expect(model.SetGeocodeResults(ctx, db, "sveta", "en", []*domain.Place{place})).To(Succeed())
+		Expect(model.SetGeocodeResults(ctx, db, "sveta", "en", nil)).To(Succeed())
+
+		Expect(Must(model.GetGeocodeResults(ctx, db, "sveta", "en"))).To(BeEmpty())
+	})
+})
@@ -23,7 +23,7 @@ type Invite struct {
 }
 
 // InsertInvite inserts a new event to the database.
-func InsertInvite(ctx context.Context, db *bun.DB, invite *domain.Invite) error {
+func InsertInvite(ctx context.Context, db bun.IDB, invite *domain.Invite) error {
 	return sqlite.WithErrorChecking(db.NewInsert().Model(&Invite{
 		Token:          invite.Token,
 		ValidUntilUnix: invite.ValidUntil.Unix(),
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// Email is the outbox database model.
+type Email struct {
+	ID                snowflake.ID `bun:"id,pk"`
+	Recipient         string       `bun:"recipient"`
+	Subject           string       `bun:"subject"`
+	Text              string       `bun:"text"`
+	HTML              string       `bun:"html"`
+	Attempts          int          `bun:"attempts"`
+	NextAttemptAtUnix int64        `bun:"next_attempt_at_unix"`
+	LastError         string       `bun:"last_error"`
+
+	bun.BaseModel `bun:"outbox"`
+}
+
+// InsertEmail queues an email to be sent immediately.
+func InsertEmail(ctx context.Context, db bun.IDB, email *domain.Email) error {
+	if email.ID == 0 {
+		email.ID = snowflake.Generate()
+	}
+
+	if email.NextAttemptAt.IsZero() {
+		email.NextAttemptAt = time.Now()
+	}
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(emailToModel(email)).Exec(ctx))
+}
+
+// ListDueEmails lists queued emails with attempts left whose next attempt is due at now.
+func ListDueEmails(ctx context.Context, db bun.IDB, now time.Time, limit int) ([]*domain.Email, error) {
+	model := []*Email{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("attempts < ?", domain.MaxEmailAttempts).
+		Where("next_attempt_at_unix <= ?", now.Unix()).
+		Order("next_attempt_at_unix ASC", "id ASC").
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *Email, _ int) *domain.Email {
+		return emailToDomain(m)
+	}), nil
+}
+
+// UpdateEmail updates the sending attempts of a queued email.
+func UpdateEmail(ctx context.Context, db bun.IDB, email *domain.Email) error {
+	return sqlite.WithErrorChecking(db.NewUpdate().Model(emailToModel(email)).
+		Column(
+			"attempts",
+			"next_attempt_at_unix",
+			"last_error",
+		).
+		Where("id = ?", email.ID).
+		Exec(ctx))
+}
+
+// DeleteEmail deletes a sent email from the outbox.
+func DeleteEmail(ctx context.Context, db bun.IDB, id snowflake.ID) error {
+	return sqlite.WithErrorChecking(db.NewDelete().Model((*Email)(nil)).
+		Where("id = ?", id).
+		Exec(ctx))
+}
+
+func emailToModel(email *domain.Email) *Email {
+	return &Email{
+		ID:                email.ID,
+		Recipient:         email.To,
+		Subject:           email.Subject,
+		Text:              email.Text,
+		HTML:              email.HTML,
+		Attempts:          email.Attempts,
+		NextAttemptAtUnix: email.NextAttemptAt.Unix(),
+		LastError:         email.LastError,
+	}
+}
+
+func emailToDomain(model *Email) *domain.Email {
+	return &domain.Email{
+		ID:            model.ID,
+		To:            model.Recipient,
+		Subject:       model.Subject,
+		Text:          model.Text,
+		HTML:          model.HTML,
+		Attempts:      model.Attempts,
+		NextAttemptAt: time.Unix(model.NextAttemptAtUnix, 0),
+		LastError:     model.LastError,
+	}
+}
@@ -0,0 +1,126 @@
+package model_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("email outbox", func() {
+	var email *domain.Email
+
+	BeforeEach(func(ctx SpecContext) {
+		email = &domain.Email{
+			To:      "user@calendar.testing",
+			Subject: "Subject",
+			Text:    "Text",
+			HTML:    "<p>Text</p>",
+		}
+
+		Expect(model.InsertEmail(ctx, db, email)).To(Succeed())
+	})
+
+	Specify("queued email is due immediately", func(ctx SpecContext) {
+		emails := Must(model.ListDueEmails(ctx, db, time.Now(), 10))
+
+		Expect(emails).To(HaveExactElements(PointTo(MatchAllFields(Fields{
+			"ID":            Equal(email.ID),
+			"To":            Equal("user@calendar.testing"),
+			"Subject":       Equal("Subject"),
+			"Text":          Equal("Text"),
+			"HTML":          Equal("<p>Text</p>"),
+			"Attempts":      BeZero(),
+			"NextAttemptAt": BeTemporally("~", time.Now(), time.Second),
+			"LastError":     BeEmpty(),
+		}))))
+	})
+
+	Specify("failed email is retried later", func(ctx SpecContext) {
+		now := time.Now()
+
+		email.SetFailed(errors.New("connection refused"), now)
+		Expect(model.UpdateEmail(ctx, db, email)).To(Succeed())
+
+		Expect(Must(model.ListDueEmails(ctx, db, now, 10))).To(BeEmpty())
+		Expect(Must(model.ListDueEmails(ctx, db, now.Add(time.Minute), 10))).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("Attempts", Equal(1)),
+				HaveField("LastError", Equal("connection refused")),
+			),
+		))
+	})
+
+	Specify("exhausted email is not retried", func(ctx SpecContext) {
+		for !email.IsExhausted() {
+			email.SetFailed(errors.New("connection refused"), time.Now())
+		}
+		Expect(model.UpdateEmail(ctx, db, email)).To(Succeed())
+
+		Expect(Must(model.ListDueEmails(ctx, db, time.Now().Add(24*time.Hour), 10))).To(BeEmpty())
+	})
+
+	Specify("sent email is deleted", func(ctx SpecContext) {
+		Expect(model.DeleteEmail(ctx, db, email.ID)).To(Succeed())
+
+		Expect(Must(model.ListDueEmails(ctx, db, time.Now(), 10))).To(BeEmpty())
+	})
+})
+
+var _ = Describe("password resets", func() {
+	var userID snowflake.ID
+
+	BeforeEach(func() {
+		userID = snowflake.Generate()
+	})
+
+	Specify("password reset is inserted and deleted with the user's resets", func(ctx SpecContext) {
+		token := uuid.New()
+
+		Expect(model.InsertPasswordReset(ctx, db, &domain.PasswordReset{
+			Token:      token,
+			UserID:     userID,
+			ValidUntil: time.Now().Add(time.Hour),
+		})).To(Succeed())
+
+		reset := Must(model.GetPasswordReset(ctx, db, token))
+		Expect(reset.UserID).To(Equal(userID))
+		Expect(reset.IsValid()).To(BeTrue())
+
+		Expect(model.DeletePasswordResets(ctx, db, userID)).To(Succeed())
+
+		_, err := model.GetPasswordReset(ctx, db, token)
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+
+	Specify("expired password resets are deleted", func(ctx SpecContext) {
+		expired, active := uuid.New(), uuid.New()
+
+		Expect(model.InsertPasswordReset(ctx, db, &domain.PasswordReset{
+			Token:      expired,
+			UserID:     userID,
+			ValidUntil: time.Now().Add(-time.Hour),
+		})).To(Succeed())
+
+		Expect(model.InsertPasswordReset(ctx, db, &domain.PasswordReset{
+			Token:      active,
+			UserID:     userID,
+			ValidUntil: time.Now().Add(time.Hour),
+		})).To(Succeed())
+
+		Expect(model.DeleteExpiredPasswordResets(ctx, db)).To(Succeed())
+
+		_, err := model.GetPasswordReset(ctx, db, expired)
+		Expect(err).To(MatchError(calendar.NotFound))
+
+		Expect(Must(model.GetPasswordReset(ctx, db, active)).Token).To(Equal(active))
+	})
+})
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/uptrace/bun"
+)
+
+// PasswordReset is the password reset database model.
+type PasswordReset struct {
+	Token          uuid.UUID    `bun:"token"`
+	UserID         snowflake.ID `bun:"user_id"`
+	ValidUntilUnix int64        `bun:"valid_until_unix"`
+
+	bun.BaseModel `bun:"password_resets"`
+}
+
+// InsertPasswordReset inserts a new password reset.
+func InsertPasswordReset(ctx context.Context, db bun.IDB, reset *domain.PasswordReset) error {
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&PasswordReset{
+		Token:          reset.Token,
+		UserID:         reset.UserID,
+		ValidUntilUnix: reset.ValidUntil.Unix(),
+	}).Exec(ctx))
+}
+
+// GetPasswordReset returns a password reset.
+func GetPasswordReset(ctx context.Context, db bun.IDB, token uuid.UUID) (*domain.PasswordReset, error) {
+	model := &PasswordReset{}
+
+	if err := db.NewSelect().Model(model).
+		Where("token = ?", token).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return &domain.PasswordReset{
+		Token:      model.Token,
+		UserID:     model.UserID,
+		ValidUntil: time.Unix(model.ValidUntilUnix, 0),
+	}, nil
+}
+
+// DeletePasswordResets deletes all password resets of a user.
+func DeletePasswordResets(ctx context.Context, db bun.IDB, userID snowflake.ID) error {
+	_, err := db.NewDelete().Model((*PasswordReset)(nil)).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+
+	return sqlite.NormalizeError(err)
+}
+
+// DeleteExpiredPasswordResets deletes expired password resets.
+func DeleteExpiredPasswordResets(ctx context.Context, db *bun.DB) error {
+	err := sqlite.WithErrorChecking(db.NewDelete().Model((*PasswordReset)(nil)).
+		Where("valid_until_unix < ?", time.Now().Unix()).
+		Exec(ctx))
+
+	if errors.Is(err, calendar.PreconditionFailed) {
+		return nil
+	}
+
+	return err
+}
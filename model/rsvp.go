@@ -0,0 +1,361 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// RSVP is the event RSVP database model.
+type RSVP struct {
+	ID            snowflake.ID `bun:"id,pk"`
+	EventID       snowflake.ID `bun:"event_id"`
+	UserID        snowflake.ID `bun:"user_id"`
+	Name          string       `bun:"name"`
+	Email         string       `bun:"email"`
+	Status        string       `bun:"status"`
+	Waitlisted    bool         `bun:"waitlisted"`
+	UpdatedAtUnix int64        `bun:"updated_at_unix"`
+
+	bun.BaseModel `bun:"rsvps"`
+}
+
+// CalendarToken is the personal calendar feed token database model.
+type CalendarToken struct {
+	Token  uuid.UUID    `bun:"token"`
+	UserID snowflake.ID `bun:"user_id"`
+
+	bun.BaseModel `bun:"calendar_tokens"`
+}
+
+// GetRSVP retrieves a single RSVP.
+func GetRSVP(ctx context.Context, db bun.IDB, id snowflake.ID) (*domain.RSVP, error) {
+	model := &RSVP{}
+
+	if err := db.NewSelect().Model(model).
+		Where("id = ?", id).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return rsvpToDomain(model), nil
+}
+
+// GetUserRSVP retrieves the RSVP of a user to an event.
+func GetUserRSVP(ctx context.Context, db bun.IDB, eventID, userID snowflake.ID) (*domain.RSVP, error) {
+	model := &RSVP{}
+
+	if err := db.NewSelect().Model(model).
+		Where("event_id = ?", eventID).
+		Where("user_id = ?", userID).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return rsvpToDomain(model), nil
+}
+
+// ListRSVPs lists the RSVPs of events in the order they were made.
+// Waitlisted RSVPs are listed in the order they are promoted.
+func ListRSVPs(ctx context.Context, db bun.IDB, eventIDs ...snowflake.ID) ([]*domain.RSVP, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
+
+	model := []*RSVP{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("event_id IN (?)", bun.In(eventIDs)).
+		Order("waitlisted ASC", "updated_at_unix ASC", "id ASC").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(r *RSVP, _ int) *domain.RSVP {
+		return rsvpToDomain(r)
+	}), nil
+}
+
+// ListUserRSVPs lists the RSVPs of a user.
+func ListUserRSVPs(ctx context.Context, db bun.IDB, userID snowflake.ID) ([]*domain.RSVP, error) {
+	model := []*RSVP{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(r *RSVP, _ int) *domain.RSVP {
+		return rsvpToDomain(r)
+	}), nil
+}
+
+// SetRSVP inserts or updates an RSVP.
+// Users have a single RSVP per event which is updated. Anonymous RSVPs
+// can't be changed and an already existing email results in an error.
+// Going RSVPs over the event capacity are waitlisted.
+// The ID, Waitlisted and UpdatedAt fields of r are updated.
+func SetRSVP(ctx context.Context, db *bun.DB, r *domain.RSVP) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		var capacity int
+
+		if err := db.NewSelect().Model((*Event)(nil)).
+			Column("capacity").
+			Where("id = ?", r.EventID).
+			Scan(ctx, &capacity); err != nil {
+			return sqlite.NormalizeError(err)
+		}
+
+		var existing *domain.RSVP
+
+		if !r.IsAnonymous() {
+			current, err := GetUserRSVP(ctx, db, r.EventID, r.UserID)
+			if err != nil && !errors.Is(err, calendar.NotFound) {
+				return err
+			}
+			existing = current
+		}
+
+		switch {
+		case existing != nil && existing.Status == r.Status:
+			// Keep the place or the position in the waitlist.
+			r.Waitlisted = existing.Waitlisted
+			r.UpdatedAt = existing.UpdatedAt
+
+		case r.Status == domain.RSVPGoing && capacity > 0:
+			going, err := countGoing(ctx, db, r.EventID)
+			if err != nil {
+				return err
+			}
+			r.Waitlisted = going >= capacity
+			r.UpdatedAt = time.Now()
+
+		default:
+			r.Waitlisted = false
+			r.UpdatedAt = time.Now()
+		}
+
+		if existing == nil {
+			r.ID = snowflake.Generate()
+
+			if err := sqlite.WithErrorChecking(db.NewInsert().Model(rsvpToModel(r)).Exec(ctx)); err != nil {
+				return err
+			}
+		} else {
+			r.ID = existing.ID
+
+			if err := sqlite.WithErrorChecking(db.NewUpdate().Model(rsvpToModel(r)).
+				Column("name", "email", "status", "waitlisted", "updated_at_unix").
+				Where("id = ?", r.ID).
+				Exec(ctx)); err != nil {
+				return err
+			}
+		}
+
+		return promoteWaitlist(ctx, db, r.EventID, capacity)
+	})
+}
+
+// DeleteRSVP deletes an RSVP and promotes the waitlist.
+func DeleteRSVP(ctx context.Context, db *bun.DB, r *domain.RSVP) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		if err := sqlite.WithErrorChecking(db.NewDelete().Model((*RSVP)(nil)).
+			Where("id = ?", r.ID).
+			Exec(ctx)); err != nil {
+			return err
+		}
+
+		var capacity int
+
+		if err := db.NewSelect().Model((*Event)(nil)).
+			Column("capacity").
+			Where("id = ?", r.EventID).
+			Scan(ctx, &capacity); err != nil {
+			return sqlite.NormalizeError(err)
+		}
+
+		return promoteWaitlist(ctx, db, r.EventID, capacity)
+	})
+}
+
+// GetCalendarToken returns the personal calendar feed token of a user.
+// The token is created on first use.
+func GetCalendarToken(ctx context.Context, db *bun.DB, userID snowflake.ID) (uuid.UUID, error) {
+	model := &CalendarToken{}
+
+	err := db.NewSelect().Model(model).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if err == nil {
+		return model.Token, nil
+	}
+
+	if err := sqlite.NormalizeError(err); !errors.Is(err, calendar.NotFound) {
+		return uuid.Nil, err
+	}
+
+	model.Token = uuid.New()
+	model.UserID = userID
+
+	if err := sqlite.WithErrorChecking(db.NewInsert().Model(model).Exec(ctx)); err != nil {
+		return uuid.Nil, err
+	}
+
+	return model.Token, nil
+}
+
+// GetCalendarTokenUser returns the user of a personal calendar feed token.
+func GetCalendarTokenUser(ctx context.Context, db *bun.DB, token uuid.UUID) (*domain.User, error) {
+	model := &CalendarToken{}
+
+	if err := db.NewSelect().Model(model).
+		Where("token = ?", token).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return GetUser(ctx, db, model.UserID)
+}
+
+// promoteWaitlist gives free places to waitlisted RSVPs in the order they were made.
+func promoteWaitlist(ctx context.Context, db bun.IDB, eventID snowflake.ID, capacity int) error {
+	q := db.NewSelect().Model((*RSVP)(nil)).
+		Column("id").
+		Where("event_id = ?", eventID).
+		Where("waitlisted = 1").
+		Order("updated_at_unix ASC", "id ASC")
+
+	if capacity > 0 {
+		going, err := countGoing(ctx, db, eventID)
+		if err != nil {
+			return err
+		}
+
+		if going >= capacity {
+			return nil
+		}
+
+		q = q.Limit(capacity - going)
+	}
+
+	var ids []snowflake.ID
+
+	if err := q.Scan(ctx, &ids); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return sqlite.WithErrorChecking(db.NewUpdate().Model((*RSVP)(nil)).
+		Set("waitlisted = 0").
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx))
+}
+
+// countGoing returns the number of attendees who have a place at the event.
+func countGoing(ctx context.Context, db bun.IDB, eventID snowflake.ID) (int, error) {
+	n, err := db.NewSelect().Model((*RSVP)(nil)).
+		Where("event_id = ?", eventID).
+		Where("status = ?", domain.RSVPGoing).
+		Where("waitlisted = 0").
+		Count(ctx)
+	if err != nil {
+		return 0, sqlite.NormalizeError(err)
+	}
+
+	return n, nil
+}
+
+func loadEventAttendance(ctx context.Context, db bun.IDB, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		EventID    snowflake.ID `bun:"event_id"`
+		Status     string       `bun:"status"`
+		Waitlisted bool         `bun:"waitlisted"`
+		Count      int          `bun:"count"`
+	}
+
+	if err := db.NewSelect().Model((*RSVP)(nil)).
+		Column("event_id", "status", "waitlisted").
+		ColumnExpr("COUNT(*) AS count").
+		Where("event_id IN (?)", bun.In(lo.Map(events, func(ev *domain.Event, _ int) snowflake.ID {
+			return ev.ID
+		}))).
+		Group("event_id", "status", "waitlisted").
+		Scan(ctx, &rows); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	byEvent := map[snowflake.ID]*domain.Attendance{}
+
+	for _, ev := range events {
+		ev.Attendance = domain.Attendance{}
+		byEvent[ev.ID] = &ev.Attendance
+	}
+
+	for _, row := range rows {
+		a := byEvent[row.EventID]
+
+		switch {
+		case row.Waitlisted:
+			a.Waitlisted += row.Count
+		case row.Status == string(domain.RSVPGoing):
+			a.Going += row.Count
+		case row.Status == string(domain.RSVPMaybe):
+			a.Maybe += row.Count
+		case row.Status == string(domain.RSVPNotGoing):
+			a.NotGoing += row.Count
+		}
+	}
+
+	return nil
+}
+
+func deleteEventRSVPs(ctx context.Context, db bun.IDB, eventID snowflake.ID) error {
+	_, err := db.NewDelete().Model((*RSVP)(nil)).
+		Where("event_id = ?", eventID).
+		Exec(ctx)
+
+	return sqlite.NormalizeError(err)
+}
+
+func rsvpToModel(r *domain.RSVP) *RSVP {
+	return &RSVP{
+		ID:            r.ID,
+		EventID:       r.EventID,
+		UserID:        r.UserID,
+		Name:          r.Name,
+		Email:         r.Email,
+		Status:        string(r.Status),
+		Waitlisted:    r.Waitlisted,
+		UpdatedAtUnix: r.UpdatedAt.Unix(),
+	}
+}
+
+func rsvpToDomain(r *RSVP) *domain.RSVP {
+	return &domain.RSVP{
+		ID:         r.ID,
+		EventID:    r.EventID,
+		UserID:     r.UserID,
+		Name:       r.Name,
+		Email:      r.Email,
+		Status:     domain.RSVPStatus(r.Status),
+		Waitlisted: r.Waitlisted,
+		UpdatedAt:  time.Unix(r.UpdatedAtUnix, 0),
+	}
+}
@@ -0,0 +1,140 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("event RSVPs", func() {
+	var ev *domain.Event
+
+	BeforeEach(func(ctx SpecContext) {
+		ev = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().Add(24 * time.Hour),
+			Title:       "Concert",
+			Description: "Desc",
+			Capacity:    1,
+		}
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+	})
+
+	rsvp := func(userID snowflake.ID, status domain.RSVPStatus) *domain.RSVP {
+		return &domain.RSVP{
+			EventID: ev.ID,
+			UserID:  userID,
+			Name:    "user",
+			Status:  status,
+		}
+	}
+
+	Specify("users have a single RSVP which is updated", func(ctx SpecContext) {
+		r := rsvp(1, domain.RSVPMaybe)
+		Expect(model.SetRSVP(ctx, db, r)).To(Succeed())
+		Expect(model.SetRSVP(ctx, db, rsvp(1, domain.RSVPNotGoing))).To(Succeed())
+
+		result := Must(model.ListRSVPs(ctx, db, ev.ID))
+		Expect(result).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("ID", Equal(r.ID)),
+				HaveField("Status", Equal(domain.RSVPNotGoing)),
+			),
+		))
+
+		Expect(Must(model.GetEvent(ctx, db, ev.ID)).Attendance).To(Equal(domain.Attendance{NotGoing: 1}))
+	})
+
+	Specify("anonymous RSVPs with the same email are rejected", func(ctx SpecContext) {
+		Expect(model.SetRSVP(ctx, db, &domain.RSVP{
+			EventID: ev.ID,
+			Name:    "Visitor",
+			Email:   "visitor@calendar.testing",
+			Status:  domain.RSVPMaybe,
+		})).To(Succeed())
+
+		Expect(model.SetRSVP(ctx, db, &domain.RSVP{
+			EventID: ev.ID,
+			Name:    "Visitor",
+			Email:   "Visitor@calendar.testing",
+			Status:  domain.RSVPGoing,
+		})).To(MatchError(calendar.AlreadyExists))
+	})
+
+	Specify("RSVPs over capacity are waitlisted and promoted in order", func(ctx SpecContext) {
+		first := rsvp(1, domain.RSVPGoing)
+		second := rsvp(2, domain.RSVPGoing)
+		third := rsvp(3, domain.RSVPGoing)
+
+		Expect(model.SetRSVP(ctx, db, first)).To(Succeed())
+		Expect(model.SetRSVP(ctx, db, second)).To(Succeed())
+		Expect(model.SetRSVP(ctx, db, third)).To(Succeed())
+
+		Expect(first.Waitlisted).To(BeFalse())
+		Expect(second.Waitlisted).To(BeTrue())
+		Expect(third.Waitlisted).To(BeTrue())
+
+		Expect(Must(model.GetEvent(ctx, db, ev.ID)).Attendance).To(Equal(domain.Attendance{Going: 1, Waitlisted: 2}))
+
+		By("cancelling promotes the first waitlisted RSVP", func() {
+			Expect(model.SetRSVP(ctx, db, rsvp(1, domain.RSVPNotGoing))).To(Succeed())
+
+			Expect(Must(model.GetUserRSVP(ctx, db, ev.ID, 2)).Waitlisted).To(BeFalse())
+			Expect(Must(model.GetUserRSVP(ctx, db, ev.ID, 3)).Waitlisted).To(BeTrue())
+		})
+
+		By("deleting promotes the next waitlisted RSVP", func() {
+			Expect(model.DeleteRSVP(ctx, db, Must(model.GetUserRSVP(ctx, db, ev.ID, 2)))).To(Succeed())
+
+			Expect(Must(model.GetUserRSVP(ctx, db, ev.ID, 3)).Waitlisted).To(BeFalse())
+		})
+	})
+
+	Specify("raising the capacity promotes the waitlist", func(ctx SpecContext) {
+		Expect(model.SetRSVP(ctx, db, rsvp(1, domain.RSVPGoing))).To(Succeed())
+		Expect(model.SetRSVP(ctx, db, rsvp(2, domain.RSVPGoing))).To(Succeed())
+
+		ev.Capacity = 0
+		Expect(model.UpdateEvent(ctx, db, ev)).To(Succeed())
+
+		Expect(Must(model.GetEvent(ctx, db, ev.ID)).Attendance).To(Equal(domain.Attendance{Going: 2}))
+	})
+
+	Specify("RSVPs are deleted with the event", func(ctx SpecContext) {
+		Expect(model.SetRSVP(ctx, db, rsvp(1, domain.RSVPGoing))).To(Succeed())
+		Expect(model.DeleteEvent(ctx, db, ev)).To(Succeed())
+
+		Expect(Must(model.ListRSVPs(ctx, db, ev.ID))).To(BeEmpty())
+	})
+
+	Specify("RSVP to a nonexistent event fails", func(ctx SpecContext) {
+		r := rsvp(1, domain.RSVPGoing)
+		r.EventID = snowflake.Generate()
+
+		Expect(model.SetRSVP(ctx, db, r)).To(MatchError(calendar.NotFound))
+	})
+})
+
+var _ = Describe("calendar tokens", func() {
+	Specify("token is created once and resolves to the user", func(ctx SpecContext) {
+		user := &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "attendee",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		}
+		Expect(model.InsertUser(ctx, db, user)).To(Succeed())
+
+		token := Must(model.GetCalendarToken(ctx, db, user.ID))
+		Expect(Must(model.GetCalendarToken(ctx, db, user.ID))).To(Equal(token))
+
+		Expect(Must(model.GetCalendarTokenUser(ctx, db, token)).ID).To(Equal(user.ID))
+	})
+})
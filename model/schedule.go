@@ -0,0 +1,136 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/uptrace/bun"
+)
+
+// PublishScheduledEvents publishes drafts which are due at now and creates their tag relations.
+// Drafts awaiting moderation are not published. It returns the IDs of published events.
+func PublishScheduledEvents(ctx context.Context, db *bun.DB, now time.Time) ([]snowflake.ID, error) {
+	var ids []snowflake.ID
+
+	if err := db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		model := []*Event{}
+
+		if err := db.NewSelect().Model(&model).
+			Where("is_draft = 1").
+			Where("is_pending = 0").
+			Where("publish_at_unix > 0").
+			Where("publish_at_unix <= ?", now.Unix()).
+			Order("publish_at_unix ASC", "id ASC").
+			Scan(ctx); err != nil {
+			return sqlite.NormalizeError(err)
+		}
+
+		if len(model) == 0 {
+			return nil
+		}
+
+		x, err := newTagExtractor(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range model {
+			if err := sqlite.WithErrorChecking(
+				db.NewUpdate().Model(&Event{}).
+					Column("is_draft", "publish_at_unix").
+					Where("id = ?", m.ID).
+					Exec(ctx),
+			); err != nil {
+				return err
+			}
+
+			ev := eventToDomain(m)
+			ev.IsDraft = false
+			ev.PublishAt = time.Time{}
+
+			if err := DeleteTags(ctx, db, ev.ID); err != nil {
+				return err
+			}
+
+			if err := createEventTagRelations(ctx, db, x, ev); err != nil {
+				return err
+			}
+
+			if err := notifyEventChange(ctx, db, domain.WebhookPublished, ev.ID); err != nil {
+				return err
+			}
+
+			ids = append(ids, ev.ID)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ApplyRetention archives or deletes events which started before cutoff.
+// Archived events are returned to drafts. It returns the IDs of affected events.
+func ApplyRetention(ctx context.Context, db *bun.DB, action domain.RetentionAction, cutoff time.Time) ([]snowflake.ID, error) {
+	var ids []snowflake.ID
+
+	if err := db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		query := db.NewSelect().Model((*Event)(nil)).
+			Column("id").
+			Where("start_at_unix < ?", cutoff.Unix())
+
+		switch action {
+		case domain.RetentionArchive:
+			query.Where("is_draft = 0")
+
+		case domain.RetentionDelete:
+
+		default:
+			return calendar.InvalidValue.New("Invalid retention action")
+		}
+
+		if err := query.Scan(ctx, &ids); err != nil {
+			return sqlite.NormalizeError(err)
+		}
+
+		for _, id := range ids {
+			if action == domain.RetentionDelete {
+				if err := deleteEvent(ctx, db, id); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := sqlite.WithErrorChecking(
+				db.NewUpdate().Model(&Event{IsDraft: true}).
+					Column("is_draft").
+					Where("id = ?", id).
+					Exec(ctx),
+			); err != nil {
+				return err
+			}
+
+			if err := DeleteTags(ctx, db, id); err != nil {
+				return err
+			}
+
+			if err := notifyEventChange(ctx, db, domain.WebhookUnpublished, id); err != nil {
+				return err
+			}
+		}
+
+		// Clean up orphaned tags.
+		return CleanTags(ctx, db)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
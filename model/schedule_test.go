@@ -0,0 +1,127 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scheduled publishing", func() {
+	var (
+		due     *domain.Event
+		later   *domain.Event
+		pending *domain.Event
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		now := time.Now()
+
+		due = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     now.Add(24 * time.Hour),
+			Title:       "Announcement",
+			Description: "Desc",
+			IsDraft:     true,
+			PublishAt:   now.Add(-time.Minute),
+		}
+
+		later = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     now.Add(24 * time.Hour),
+			Title:       "Later",
+			Description: "Desc",
+			IsDraft:     true,
+			PublishAt:   now.Add(time.Hour),
+		}
+
+		pending = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     now.Add(24 * time.Hour),
+			Title:       "Pending",
+			Description: "Desc",
+			IsDraft:     true,
+			IsPending:   true,
+			PublishAt:   now.Add(-time.Minute),
+		}
+
+		Expect(model.InsertEvent(ctx, db, due)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, later)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, pending)).To(Succeed())
+	})
+
+	Specify("due drafts are published with tags", func(ctx SpecContext) {
+		ids := Must(model.PublishScheduledEvents(ctx, db, time.Now()))
+		Expect(ids).To(HaveExactElements(due.ID))
+
+		ev := Must(model.GetEvent(ctx, db, due.ID))
+		Expect(ev.IsDraft).To(BeFalse())
+		Expect(ev.PublishAt).To(BeZero())
+
+		tags := Must(model.ListTags(ctx, db, time.Now(), 10))
+		Expect(tags).To(ContainElement(HaveField("Name", "announcement")))
+
+		Expect(Must(model.GetEvent(ctx, db, later.ID)).IsScheduled()).To(BeTrue())
+		Expect(Must(model.GetEvent(ctx, db, pending.ID)).IsDraft).To(BeTrue())
+	})
+
+	Specify("nothing is published twice", func(ctx SpecContext) {
+		Must(model.PublishScheduledEvents(ctx, db, time.Now()))
+
+		Expect(model.PublishScheduledEvents(ctx, db, time.Now())).To(BeEmpty())
+	})
+})
+
+var _ = Describe("retention", func() {
+	var (
+		old    *domain.Event
+		recent *domain.Event
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		old = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().AddDate(0, 0, -40),
+			Title:       "Old event",
+			Description: "Desc",
+		}
+
+		recent = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().AddDate(0, 0, -10),
+			Title:       "Recent event",
+			Description: "Desc",
+		}
+
+		Expect(model.InsertEvent(ctx, db, old)).To(Succeed())
+		Expect(model.InsertEvent(ctx, db, recent)).To(Succeed())
+	})
+
+	Specify("old events are archived", func(ctx SpecContext) {
+		cutoff := time.Now().AddDate(0, 0, -30)
+
+		ids := Must(model.ApplyRetention(ctx, db, domain.RetentionArchive, cutoff))
+		Expect(ids).To(HaveExactElements(old.ID))
+
+		Expect(Must(model.GetEvent(ctx, db, old.ID)).IsDraft).To(BeTrue())
+		Expect(Must(model.GetEvent(ctx, db, recent.ID)).IsDraft).To(BeFalse())
+
+		By("not archiving drafts again")
+		Expect(model.ApplyRetention(ctx, db, domain.RetentionArchive, cutoff)).To(BeEmpty())
+	})
+
+	Specify("old events are deleted", func(ctx SpecContext) {
+		ids := Must(model.ApplyRetention(ctx, db, domain.RetentionDelete, time.Now().AddDate(0, 0, -30)))
+		Expect(ids).To(HaveExactElements(old.ID))
+
+		_, err := model.GetEvent(ctx, db, old.ID)
+		Expect(err).To(MatchError(calendar.NotFound))
+
+		Expect(model.GetEvent(ctx, db, recent.ID)).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/textfilter"
+)
+
+// searchColumns are the events_fts columns which can scope a search term.
+var searchColumns = []string{"title", "description", "location"}
+
+// Markers wrapping matches in FTS5 snippets.
+const (
+	snippetMatchStart = "\x02"
+	snippetMatchEnd   = "\x03"
+)
+
+// searchQuery is a parsed search query.
+type searchQuery struct {
+	match  string
+	after  time.Time
+	before time.Time
+}
+
+// parseSearchQuery parses a search query into an FTS5 match expression.
+// Besides words, quoted phrases and AND/OR/NOT operators, the query supports
+// column scoped terms such as location:tallinn or title:"open mic"
+// and date operators after:2026-11-01 and before:2026-12-01 in loc.
+func parseSearchQuery(text string, loc *time.Location) (*searchQuery, error) {
+	if strings.Count(text, `"`)%2 != 0 {
+		return nil, calendar.InvalidValue.New(`Missing closing quote (")`)
+	}
+
+	var (
+		q      = &searchQuery{}
+		terms  []string
+		scoped bool
+	)
+
+	for _, field := range textfilter.SplitQuoted(text) {
+		if isSearchOperator(field) {
+			terms = append(terms, field)
+			continue
+		}
+
+		key, value, ok := strings.Cut(field, ":")
+		key = strings.ToLower(key)
+
+		switch {
+		case ok && (key == "after" || key == "before"):
+			t, err := time.ParseInLocation(time.DateOnly, strings.Trim(value, `"`), loc)
+			if err != nil {
+				return nil, calendar.InvalidValue.New(fmt.Sprintf("Invalid date %q after %s:, use the format YYYY-MM-DD", value, key), err)
+			}
+
+			if key == "after" {
+				q.after = t
+			} else {
+				q.before = t
+			}
+
+			scoped = true
+
+		case ok && slices.Contains(searchColumns, key):
+			value = strings.Trim(value, `"`)
+			if value == "" {
+				return nil, calendar.InvalidValue.New(fmt.Sprintf("Missing search term after %s:", key))
+			}
+
+			if strings.Contains(value, `"`) {
+				return nil, calendar.InvalidValue.New(fmt.Sprintf("Unexpected quote in %s", field))
+			}
+
+			terms = append(terms, fmt.Sprintf("%s : %s", key, textfilter.EnsureQuoted(value)))
+			scoped = true
+
+		default:
+			if strings.Contains(strings.Trim(field, `"`), `"`) {
+				return nil, calendar.InvalidValue.New(fmt.Sprintf("Unexpected quote in %s", field))
+			}
+
+			terms = append(terms, textfilter.EnsureQuoted(field))
+		}
+	}
+
+	for i, term := range terms {
+		if isSearchOperator(term) && (i == 0 || i == len(terms)-1 || isSearchOperator(terms[i-1])) {
+			return nil, calendar.InvalidValue.New(fmt.Sprintf("%s must be placed between two search terms", term))
+		}
+	}
+
+	if len(terms) == 0 {
+		return q, nil
+	}
+
+	general := strings.Join(terms, " ")
+
+	switch {
+	case scoped || strings.Contains(text, `"`):
+		// Only general search.
+		q.match = general
+
+	case len(terms) == 1:
+		// Only exact search.
+		q.match = terms[0]
+
+	default:
+		// Both exact and general.
+		q.match = fmt.Sprintf("(%s) OR (%s)", textfilter.EnsureQuoted(strings.TrimSpace(text)), general)
+	}
+
+	return q, nil
+}
+
+func isSearchOperator(s string) bool {
+	switch s {
+	case "AND", "OR", "NOT":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseSnippet parses an FTS5 snippet with matches wrapped in markers.
+func parseSnippet(s string) domain.Snippet {
+	var snippet domain.Snippet
+
+	for s != "" {
+		before, rest, found := strings.Cut(s, snippetMatchStart)
+		if before != "" {
+			snippet = append(snippet, domain.SnippetFragment{Text: before})
+		}
+
+		if !found {
+			break
+		}
+
+		match, after, _ := strings.Cut(rest, snippetMatchEnd)
+		if match != "" {
+			snippet = append(snippet, domain.SnippetFragment{Text: match, Match: true})
+		}
+
+		s = after
+	}
+
+	return snippet
+}
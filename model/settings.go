@@ -1,7 +1,11 @@
 package model
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
 
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/pkg/sqlite"
@@ -10,29 +14,43 @@ import (
 
 // Settings is the settings database model.
 type Settings struct {
-	ID          int64  `bun:"id"`
-	Title       string `bun:"title"`
-	Description string `bun:"description"`
+	ID               int64  `bun:"id"`
+	Title            string `bun:"title"`
+	Description      string `bun:"description"`
+	TagsPage         string `bun:"tags_page"`
+	TagLanguage      string `bun:"tag_language"`
+	Locale           string `bun:"locale"`
+	Timezone         string `bun:"timezone"`
+	AccentColor      string `bun:"accent_color"`
+	Footer           string `bun:"footer"`
+	ContactEmail     string `bun:"contact_email"`
+	LogoHash         string `bun:"logo_hash"`
+	FaviconHash      string `bun:"favicon_hash"`
+	PublicSubmission bool   `bun:"public_submission"`
+	Moderation       bool   `bun:"moderation"`
+	TagsPageEnabled  bool   `bun:"tags_page_enabled"`
+	AnonymousRSVP    bool   `bun:"anonymous_rsvp"`
+	RetentionDays    int    `bun:"retention_days"`
+	RetentionAction  string `bun:"retention_action"`
+	EmbedOrigins     string `bun:"embed_origins"`
 
 	bun.BaseModel `bun:"settings"`
 }
 
 // InsertSettings inserts settings.
+// When inserting in a transaction, call InvalidateSettings after commit.
 func InsertSettings(ctx context.Context, db bun.IDB, s *domain.Settings) error {
-	return sqlite.WithErrorChecking(db.NewInsert().Model(&Settings{
-		ID:          1,
-		Title:       s.Title,
-		Description: s.Description,
-	}).Exec(ctx))
+	defer InvalidateSettings()
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(settingsToModel(s)).Exec(ctx))
 }
 
 // UpdateSettings updates settings.
+// When updating in a transaction, call InvalidateSettings after commit.
 func UpdateSettings(ctx context.Context, db bun.IDB, s *domain.Settings) error {
-	return sqlite.WithErrorChecking(db.NewUpdate().Model(&Settings{
-		ID:          1,
-		Title:       s.Title,
-		Description: s.Description,
-	}).Where("id = 1").Exec(ctx))
+	defer InvalidateSettings()
+
+	return sqlite.WithErrorChecking(db.NewUpdate().Model(settingsToModel(s)).Where("id = 1").Exec(ctx))
 }
 
 // GetSettings returns settings.
@@ -46,7 +64,95 @@ func GetSettings(ctx context.Context, db bun.IDB) (*domain.Settings, error) {
 	}
 
 	return &domain.Settings{
-		Title:       model.Title,
-		Description: model.Description,
+		Title:            model.Title,
+		Description:      model.Description,
+		TagsPage:         cmp.Or(domain.TagsPageMode(model.TagsPage), domain.TagsPageWords),
+		TagLanguage:      model.TagLanguage,
+		Locale:           model.Locale,
+		Timezone:         model.Timezone,
+		AccentColor:      model.AccentColor,
+		Footer:           model.Footer,
+		ContactEmail:     model.ContactEmail,
+		LogoHash:         model.LogoHash,
+		FaviconHash:      model.FaviconHash,
+		PublicSubmission: model.PublicSubmission,
+		Moderation:       model.Moderation,
+		TagsPageEnabled:  model.TagsPageEnabled,
+		AnonymousRSVP:    model.AnonymousRSVP,
+		RetentionDays:    model.RetentionDays,
+		RetentionAction:  domain.RetentionAction(model.RetentionAction),
+		EmbedOrigins:     strings.Fields(model.EmbedOrigins),
 	}, nil
 }
+
+type cachedSettings struct {
+	db       *bun.DB
+	version  uint64
+	settings domain.Settings
+}
+
+var (
+	settingsCache   atomic.Pointer[cachedSettings]
+	settingsVersion atomic.Uint64
+)
+
+// GetCachedSettings returns settings, querying the database only
+// when settings have been inserted or updated since the last query.
+// The result is a copy which may be modified by the caller.
+func GetCachedSettings(ctx context.Context, db *bun.DB) (*domain.Settings, error) {
+	version := settingsVersion.Load()
+
+	if cached := settingsCache.Load(); cached != nil && cached.db == db && cached.version == version {
+		s := cached.settings
+		s.EmbedOrigins = slices.Clone(s.EmbedOrigins)
+
+		return &s, nil
+	}
+
+	s, err := GetSettings(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := &cachedSettings{
+		db:       db,
+		version:  version,
+		settings: *s,
+	}
+	cached.settings.EmbedOrigins = slices.Clone(s.EmbedOrigins)
+
+	settingsCache.Store(cached)
+
+	return s, nil
+}
+
+// InvalidateSettings makes GetCachedSettings query the database again.
+// A concurrent query may cache settings read before a transaction commits,
+// so the cache must be invalidated again once the transaction has committed.
+func InvalidateSettings() {
+	settingsVersion.Add(1)
+}
+
+func settingsToModel(s *domain.Settings) *Settings {
+	return &Settings{
+		ID:               1,
+		Title:            s.Title,
+		Description:      s.Description,
+		TagsPage:         string(s.TagsPage),
+		TagLanguage:      s.TagLanguage,
+		Locale:           s.Locale,
+		Timezone:         s.Timezone,
+		AccentColor:      s.AccentColor,
+		Footer:           s.Footer,
+		ContactEmail:     s.ContactEmail,
+		LogoHash:         s.LogoHash,
+		FaviconHash:      s.FaviconHash,
+		PublicSubmission: s.PublicSubmission,
+		Moderation:       s.Moderation,
+		TagsPageEnabled:  s.TagsPageEnabled,
+		AnonymousRSVP:    s.AnonymousRSVP,
+		RetentionDays:    s.RetentionDays,
+		RetentionAction:  string(s.RetentionAction),
+		EmbedOrigins:     strings.Join(s.EmbedOrigins, " "),
+	}
+}
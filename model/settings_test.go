@@ -20,8 +20,24 @@ var _ = Describe("inserting settings", func() {
 
 			settings := Must(model.GetSettings(ctx, db))
 			Expect(settings).To(PointTo(MatchAllFields(Fields{
-				"Title":       Equal("Page Title"),
-				"Description": Equal("Description"),
+				"Title":            Equal("Page Title"),
+				"Description":      Equal("Description"),
+				"TagsPage":         Equal(domain.TagsPageWords),
+				"TagLanguage":      BeEmpty(),
+				"Locale":           BeEmpty(),
+				"Timezone":         BeEmpty(),
+				"AccentColor":      BeEmpty(),
+				"Footer":           BeEmpty(),
+				"ContactEmail":     BeEmpty(),
+				"LogoHash":         BeEmpty(),
+				"FaviconHash":      BeEmpty(),
+				"PublicSubmission": BeFalse(),
+				"Moderation":       BeFalse(),
+				"TagsPageEnabled":  BeFalse(),
+				"AnonymousRSVP":    BeFalse(),
+				"RetentionDays":    BeZero(),
+				"RetentionAction":  Equal(domain.RetentionKeep),
+				"EmbedOrigins":     BeEmpty(),
 			})))
 		})
 	})
@@ -40,22 +56,74 @@ var _ = Describe("updating settings", func() {
 	When("settings exist", func() {
 		JustBeforeEach(func(ctx SpecContext) {
 			Expect(model.InsertSettings(ctx, db, &domain.Settings{
-				Title:       "Page Title",
-				Description: "Description",
+				Title:        "Page Title",
+				Description:  "Description",
+				EmbedOrigins: []string{"https://partner.testing", "https://*.partner.testing"},
 			})).To(Succeed())
 		})
 
 		Specify("settings are updated", func(ctx SpecContext) {
 			Expect(model.UpdateSettings(ctx, db, &domain.Settings{
-				Title:       "Page Title 2",
-				Description: "Description 2",
+				Title:            "Page Title 2",
+				Description:      "Description 2",
+				TagsPage:         domain.TagsPageBoth,
+				TagLanguage:      "de",
+				Locale:           "et",
+				Timezone:         "Europe/Tallinn",
+				AccentColor:      "#0d9488",
+				Footer:           "**Footer**",
+				ContactEmail:     "info@calendar.testing",
+				LogoHash:         "abc",
+				FaviconHash:      "def",
+				PublicSubmission: true,
+				Moderation:       true,
+				TagsPageEnabled:  true,
+				AnonymousRSVP:    true,
+				RetentionDays:    365,
+				RetentionAction:  domain.RetentionArchive,
+				EmbedOrigins:     []string{"https://partner.testing", "https://*.partner.testing"},
 			})).To(Succeed())
 
 			settings := Must(model.GetSettings(ctx, db))
 			Expect(settings).To(PointTo(MatchAllFields(Fields{
-				"Title":       Equal("Page Title 2"),
-				"Description": Equal("Description 2"),
+				"Title":            Equal("Page Title 2"),
+				"Description":      Equal("Description 2"),
+				"TagsPage":         Equal(domain.TagsPageBoth),
+				"TagLanguage":      Equal("de"),
+				"Locale":           Equal("et"),
+				"Timezone":         Equal("Europe/Tallinn"),
+				"AccentColor":      Equal("#0d9488"),
+				"Footer":           Equal("**Footer**"),
+				"ContactEmail":     Equal("info@calendar.testing"),
+				"LogoHash":         Equal("abc"),
+				"FaviconHash":      Equal("def"),
+				"PublicSubmission": BeTrue(),
+				"Moderation":       BeTrue(),
+				"TagsPageEnabled":  BeTrue(),
+				"AnonymousRSVP":    BeTrue(),
+				"RetentionDays":    Equal(365),
+				"RetentionAction":  Equal(domain.RetentionArchive),
+				"EmbedOrigins":     HaveExactElements("https://partner.testing", "https://*.partner.testing"),
 			})))
 		})
+
+		Specify("cached settings are refreshed on update", func(ctx SpecContext) {
+			settings := Must(model.GetCachedSettings(ctx, db))
+			Expect(settings.Title).To(Equal("Page Title"))
+
+			By("asserting modifying the result does not modify the cache", func() {
+				settings.Title = "Modified"
+				settings.EmbedOrigins[0] = "https://modified.testing"
+
+				cached := Must(model.GetCachedSettings(ctx, db))
+				Expect(cached.Title).To(Equal("Page Title"))
+				Expect(cached.EmbedOrigins).To(HaveExactElements("https://partner.testing", "https://*.partner.testing"))
+			})
+
+			settings.Title = "Page Title 2"
+			Expect(model.UpdateSettings(ctx, db, settings)).To(Succeed())
+
+			Expect(Must(model.GetCachedSettings(ctx, db)).Title).To(Equal("Page Title 2"))
+		})
 	})
 })
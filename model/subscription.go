@@ -0,0 +1,214 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// Subscription is the digest subscription database model.
+type Subscription struct {
+	ID              snowflake.ID `bun:"id,pk"`
+	Email           string       `bun:"email"`
+	Frequency       string       `bun:"frequency"`
+	Category        string       `bun:"category"`
+	Tag             string       `bun:"tag"`
+	Language        string       `bun:"language"`
+	Token           uuid.UUID    `bun:"token"`
+	ConfirmedAtUnix int64        `bun:"confirmed_at_unix"`
+	LastSentAtUnix  int64        `bun:"last_sent_at_unix"`
+
+	bun.BaseModel `bun:"subscriptions"`
+}
+
+// InsertSubscription inserts an unconfirmed subscription. When the email already
+// has a subscription with the same frequency and filter, the existing subscription is returned.
+func InsertSubscription(ctx context.Context, db bun.IDB, s *domain.Subscription) (*domain.Subscription, error) {
+	err := sqlite.WithErrorChecking(db.NewInsert().Model(subscriptionToModel(s)).Exec(ctx))
+	if err == nil {
+		return s, nil
+	}
+
+	if !errors.Is(err, calendar.AlreadyExists) {
+		return nil, err
+	}
+
+	model := &Subscription{}
+
+	if err := db.NewSelect().Model(model).
+		Where("email = ? COLLATE NOCASE", s.Email).
+		Where("frequency = ?", s.Frequency).
+		Where("category = ?", s.Category).
+		Where("tag = ?", s.Tag).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return subscriptionToDomain(model), nil
+}
+
+// GetSubscription returns a subscription by its token.
+func GetSubscription(ctx context.Context, db bun.IDB, token uuid.UUID) (*domain.Subscription, error) {
+	model := &Subscription{}
+
+	if err := db.NewSelect().Model(model).
+		Where("token = ?", token).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return subscriptionToDomain(model), nil
+}
+
+// ConfirmSubscription confirms a subscription.
+func ConfirmSubscription(ctx context.Context, db bun.IDB, s *domain.Subscription, now time.Time) error {
+	s.ConfirmedAt = now
+
+	return sqlite.WithErrorChecking(db.NewUpdate().Model(subscriptionToModel(s)).
+		Column("confirmed_at_unix").
+		Where("id = ?", s.ID).
+		Exec(ctx))
+}
+
+// SetSubscriptionSent records when the last digest of a subscription was sent.
+func SetSubscriptionSent(ctx context.Context, db bun.IDB, s *domain.Subscription, sentAt time.Time) error {
+	s.LastSentAt = sentAt
+
+	return sqlite.WithErrorChecking(db.NewUpdate().Model(subscriptionToModel(s)).
+		Column("last_sent_at_unix").
+		Where("id = ?", s.ID).
+		Exec(ctx))
+}
+
+// DeleteSubscription deletes a subscription.
+func DeleteSubscription(ctx context.Context, db bun.IDB, id snowflake.ID) error {
+	return sqlite.WithErrorChecking(db.NewDelete().Model((*Subscription)(nil)).
+		Where("id = ?", id).
+		Exec(ctx))
+}
+
+// ListDueSubscriptions lists confirmed subscriptions whose digest is due at now.
+func ListDueSubscriptions(ctx context.Context, db bun.IDB, now time.Time) ([]*domain.Subscription, error) {
+	model := []*Subscription{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("confirmed_at_unix > 0").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			for _, f := range []domain.DigestFrequency{domain.DigestDaily, domain.DigestWeekly} {
+				q = q.WhereOr("frequency = ? AND last_sent_at_unix <= ?", f, now.Add(-f.Period()).Unix())
+			}
+			return q
+		}).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *Subscription, _ int) *domain.Subscription {
+		return subscriptionToDomain(m)
+	}), nil
+}
+
+// GetSubscriptionStats returns the subscriber counts.
+func GetSubscriptionStats(ctx context.Context, db bun.IDB) (*domain.SubscriptionStats, error) {
+	stats := &domain.SubscriptionStats{}
+
+	counts := []struct {
+		Frequency string `bun:"frequency"`
+		Confirmed bool   `bun:"confirmed"`
+		Count     int    `bun:"count"`
+	}{}
+
+	if err := db.NewSelect().Model((*Subscription)(nil)).
+		Column("frequency").
+		ColumnExpr("confirmed_at_unix > 0 AS confirmed").
+		ColumnExpr("COUNT(*) AS count").
+		Group("frequency", "confirmed").
+		Scan(ctx, &counts); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	for _, c := range counts {
+		switch {
+		case !c.Confirmed:
+			stats.Pending += c.Count
+		case domain.DigestFrequency(c.Frequency) == domain.DigestWeekly:
+			stats.Weekly += c.Count
+		default:
+			stats.Daily += c.Count
+		}
+	}
+
+	if err := db.NewSelect().Model((*Subscription)(nil)).
+		Column("category", "tag").
+		ColumnExpr("COUNT(*) AS count").
+		Where("confirmed_at_unix > 0").
+		Group("category", "tag").
+		Order("count DESC", "category ASC", "tag ASC").
+		Scan(ctx, &stats.Filters); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return stats, nil
+}
+
+// DeleteUnconfirmedSubscriptions deletes subscriptions which were not confirmed
+// and were created before the cutoff.
+func DeleteUnconfirmedSubscriptions(ctx context.Context, db *bun.DB, cutoff time.Time) error {
+	model := []*Subscription{}
+
+	if err := db.NewSelect().Model(&model).
+		Column("id").
+		Where("confirmed_at_unix = 0").
+		Scan(ctx); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	ids := lo.FilterMap(model, func(m *Subscription, _ int) (snowflake.ID, bool) {
+		return m.ID, snowflake.ParseTime(m.ID.Int64()).Before(cutoff)
+	})
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return sqlite.WithErrorChecking(db.NewDelete().Model((*Subscription)(nil)).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx))
+}
+
+func subscriptionToModel(s *domain.Subscription) *Subscription {
+	return &Subscription{
+		ID:              s.ID,
+		Email:           s.Email,
+		Frequency:       string(s.Frequency),
+		Category:        s.Category,
+		Tag:             s.Tag,
+		Language:        s.Language,
+		Token:           s.Token,
+		ConfirmedAtUnix: unixOrZero(s.ConfirmedAt),
+		LastSentAtUnix:  unixOrZero(s.LastSentAt),
+	}
+}
+
+func subscriptionToDomain(model *Subscription) *domain.Subscription {
+	return &domain.Subscription{
+		ID:          model.ID,
+		Email:       model.Email,
+		Frequency:   domain.DigestFrequency(model.Frequency),
+		Category:    model.Category,
+		Tag:         model.Tag,
+		Language:    model.Language,
+		Token:       model.Token,
+		ConfirmedAt: timeOrZero(model.ConfirmedAtUnix),
+		LastSentAt:  timeOrZero(model.LastSentAtUnix),
+	}
+}
@@ -0,0 +1,112 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("digest subscriptions", func() {
+	subscription := func(email string, frequency domain.DigestFrequency, category string) *domain.Subscription {
+		return &domain.Subscription{
+			ID:        snowflake.Generate(),
+			Email:     email,
+			Frequency: frequency,
+			Category:  category,
+			Language:  "en",
+			Token:     uuid.New(),
+		}
+	}
+
+	Specify("subscribing again returns the existing subscription", func(ctx SpecContext) {
+		s := subscription("visitor@calendar.testing", domain.DigestWeekly, "Music")
+		Expect(Must(model.InsertSubscription(ctx, db, s))).To(Equal(s))
+
+		existing := Must(model.InsertSubscription(ctx, db, subscription("Visitor@calendar.testing", domain.DigestWeekly, "Music")))
+		Expect(existing.ID).To(Equal(s.ID))
+		Expect(existing.Token).To(Equal(s.Token))
+
+		other := subscription("visitor@calendar.testing", domain.DigestWeekly, "Theatre")
+		Expect(Must(model.InsertSubscription(ctx, db, other)).ID).To(Equal(other.ID))
+	})
+
+	Specify("only confirmed subscriptions are due", func(ctx SpecContext) {
+		now := time.Now()
+
+		daily := Must(model.InsertSubscription(ctx, db, subscription("daily@calendar.testing", domain.DigestDaily, "")))
+		weekly := Must(model.InsertSubscription(ctx, db, subscription("weekly@calendar.testing", domain.DigestWeekly, "")))
+		Must(model.InsertSubscription(ctx, db, subscription("pending@calendar.testing", domain.DigestDaily, "")))
+
+		Expect(model.ConfirmSubscription(ctx, db, daily, now)).To(Succeed())
+		Expect(model.ConfirmSubscription(ctx, db, weekly, now)).To(Succeed())
+
+		Expect(Must(model.ListDueSubscriptions(ctx, db, now))).To(HaveExactElements(
+			HaveField("ID", daily.ID),
+			HaveField("ID", weekly.ID),
+		))
+
+		Expect(model.SetSubscriptionSent(ctx, db, daily, now)).To(Succeed())
+		Expect(model.SetSubscriptionSent(ctx, db, weekly, now)).To(Succeed())
+
+		Expect(Must(model.ListDueSubscriptions(ctx, db, now.Add(23*time.Hour)))).To(BeEmpty())
+		Expect(Must(model.ListDueSubscriptions(ctx, db, now.Add(24*time.Hour)))).To(HaveExactElements(
+			HaveField("ID", daily.ID),
+		))
+		Expect(Must(model.ListDueSubscriptions(ctx, db, now.Add(7*24*time.Hour)))).To(HaveExactElements(
+			HaveField("ID", daily.ID),
+			HaveField("ID", weekly.ID),
+		))
+	})
+
+	Specify("subscriber counts are returned", func(ctx SpecContext) {
+		for _, s := range []*domain.Subscription{
+			subscription("a@calendar.testing", domain.DigestDaily, "Music"),
+			subscription("b@calendar.testing", domain.DigestWeekly, "Music"),
+			subscription("c@calendar.testing", domain.DigestWeekly, ""),
+		} {
+			Expect(model.ConfirmSubscription(ctx, db, Must(model.InsertSubscription(ctx, db, s)), time.Now())).To(Succeed())
+		}
+		Must(model.InsertSubscription(ctx, db, subscription("d@calendar.testing", domain.DigestDaily, "")))
+
+		Expect(Must(model.GetSubscriptionStats(ctx, db))).To(Equal(&domain.SubscriptionStats{
+			Daily:   1,
+			Weekly:  2,
+			Pending: 1,
+			Filters: []domain.SubscriptionFilterCount{
+				{Category: "Music", Count: 2},
+				{Count: 1},
+			},
+		}))
+	})
+
+	Specify("unsubscribing deletes the subscription", func(ctx SpecContext) {
+		s := Must(model.InsertSubscription(ctx, db, subscription("visitor@calendar.testing", domain.DigestDaily, "")))
+
+		Expect(model.DeleteSubscription(ctx, db, s.ID)).To(Succeed())
+
+		_, err := model.GetSubscription(ctx, db, s.Token)
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+
+	Specify("old unconfirmed subscriptions are deleted", func(ctx SpecContext) {
+		pending := Must(model.InsertSubscription(ctx, db, subscription("pending@calendar.testing", domain.DigestDaily, "")))
+		confirmed := Must(model.InsertSubscription(ctx, db, subscription("confirmed@calendar.testing", domain.DigestDaily, "")))
+		Expect(model.ConfirmSubscription(ctx, db, confirmed, time.Now())).To(Succeed())
+
+		Expect(model.DeleteUnconfirmedSubscriptions(ctx, db, time.Now().Add(-time.Hour))).To(Succeed())
+		Expect(Must(model.GetSubscription(ctx, db, pending.Token)).ID).To(Equal(pending.ID))
+
+		Expect(model.DeleteUnconfirmedSubscriptions(ctx, db, time.Now().Add(time.Hour))).To(Succeed())
+
+		_, err := model.GetSubscription(ctx, db, pending.Token)
+		Expect(err).To(MatchError(calendar.NotFound))
+		Expect(Must(model.GetSubscription(ctx, db, confirmed.Token)).ID).To(Equal(confirmed.ID))
+	})
+})
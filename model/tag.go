@@ -9,6 +9,7 @@ import (
 	"github.com/mgnsk/calendar/domain"
 	"github.com/mgnsk/calendar/pkg/snowflake"
 	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/mgnsk/calendar/pkg/textfilter"
 	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 )
@@ -112,6 +113,62 @@ func DeleteTags(ctx context.Context, db bun.IDB, eventID snowflake.ID) error {
 	return nil
 }
 
+// RetagEvents recreates tag relations for all published events
+// using the current tag language and stop words.
+func RetagEvents(ctx context.Context, db bun.IDB) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		// Delete all tag relations.
+		if err := sqlite.WithErrorChecking(
+			db.NewTruncateTable().Model((*eventToTag)(nil)).Exec(ctx),
+		); err != nil && !errors.Is(err, calendar.PreconditionFailed) {
+			return err
+		}
+
+		x, err := newTagExtractor(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		events := []*Event{}
+
+		if err := db.NewSelect().Model(&events).
+			Where("is_draft = 0").
+			Scan(ctx); err != nil {
+			return sqlite.NormalizeError(err)
+		}
+
+		for _, ev := range events {
+			if err := createEventTagRelations(ctx, db, x, eventToDomain(ev)); err != nil {
+				return err
+			}
+		}
+
+		// Clean up orphaned tags.
+		return CleanTags(ctx, db)
+	})
+}
+
+// newTagExtractor creates a tag extractor configured by settings and stop words.
+func newTagExtractor(ctx context.Context, db bun.IDB) (*textfilter.TagExtractor, error) {
+	settings, err := GetSettings(ctx, db)
+	if err != nil {
+		if !errors.Is(err, calendar.NotFound) {
+			return nil, err
+		}
+
+		settings = domain.NewDefaultSettings()
+	}
+
+	words, err := ListStopWords(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	stemmer, _ := textfilter.NewStemmer(settings.TagLanguage)
+
+	return textfilter.NewTagExtractor(stemmer, words...), nil
+}
+
 // getTagID returns a tag IDs from database.
 func getTagIDs(ctx context.Context, db bun.IDB, names ...string) ([]snowflake.ID, error) {
 	model := []*Tag{}
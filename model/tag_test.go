@@ -1,6 +1,8 @@
 package model_test
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/mgnsk/calendar/domain"
@@ -10,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	"github.com/uptrace/bun"
 )
 
 var _ = Describe("inserting tags", func() {
@@ -151,3 +154,205 @@ var _ = Describe("listing tags", func() {
 		))
 	})
 })
+
+var _ = Describe("filtering events by tags", func() {
+	JustBeforeEach(func(ctx SpecContext) {
+		By("inserting events", func() {
+			events := []*domain.Event{
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Now().Add(3 * time.Hour),
+					Title:       "Event 1",
+					Description: "jazz concert",
+				},
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Now().Add(2 * time.Hour),
+					Title:       "Event 2",
+					Description: "jazz workshop",
+				},
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Now().Add(1 * time.Hour),
+					Title:       "Event 3",
+					Description: "rock concert",
+				},
+				{
+					ID:          snowflake.Generate(),
+					StartAt:     time.Now().Add(-1 * time.Hour),
+					Title:       "Event 4",
+					Description: "jazz concert",
+				},
+			}
+
+			for _, ev := range events {
+				Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+			}
+		})
+	})
+
+	Specify("events having all tags are listed", func(ctx SpecContext) {
+		result := Must(
+			model.NewEventsQuery().
+				WithStartAtFrom(time.Now()).
+				WithOrder(0, model.OrderStartAtAsc).
+				WithTags(model.TagMatchAll, "jazz", "Concert").
+				List(ctx, db),
+		)
+
+		Expect(result).To(HaveExactElements(
+			HaveField("Title", "Event 1"),
+		))
+	})
+
+	Specify("events having any tag are listed", func(ctx SpecContext) {
+		result := Must(
+			model.NewEventsQuery().
+				WithStartAtFrom(time.Now()).
+				WithOrder(0, model.OrderStartAtAsc).
+				WithTags(model.TagMatchAny, "workshop", "rock").
+				List(ctx, db),
+		)
+
+		Expect(result).To(HaveExactElements(
+			HaveField("Title", "Event 3"),
+			HaveField("Title", "Event 2"),
+		))
+	})
+
+	Specify("tags can be combined with search text", func(ctx SpecContext) {
+		result := Must(
+			model.NewEventsQuery().
+				WithStartAtFrom(time.Now()).
+				WithOrder(0, model.OrderStartAtAsc).
+				WithTags(model.TagMatchAny, "concert").
+				WithSearchText("rock", time.UTC).
+				List(ctx, db),
+		)
+
+		Expect(result).To(HaveExactElements(
+			HaveField("Title", "Event 3"),
+		))
+	})
+
+	Specify("tags can be combined with past events window", func(ctx SpecContext) {
+		result := Must(
+			model.NewEventsQuery().
+				WithStartAtUntil(time.Now()).
+				WithOrder(0, model.OrderStartAtDesc).
+				WithTags(model.TagMatchAll, "jazz", "concert").
+				List(ctx, db),
+		)
+
+		Expect(result).To(HaveExactElements(
+			HaveField("Title", "Event 4"),
+		))
+	})
+})
+
+var _ = Describe("retagging events", func() {
+	JustBeforeEach(func(ctx SpecContext) {
+		By("inserting events", func() {
+			events := []*domain.Event{
+				{
+					ID:      snowflake.Generate(),
+					StartAt: time.Now().Add(2 * time.Hour),
+					Title:   "Concerts in the park",
+				},
+				{
+					ID:      snowflake.Generate(),
+					StartAt: time.Now().Add(1 * time.Hour),
+					Title:   "Concert",
+				},
+				{
+					ID:      snowflake.Generate(),
+					StartAt: time.Now().Add(1 * time.Hour),
+					Title:   "Draft concert",
+					IsDraft: true,
+				},
+			}
+
+			for _, ev := range events {
+				Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+			}
+		})
+	})
+
+	When("tag language is set", func() {
+		JustBeforeEach(func(ctx SpecContext) {
+			settings := domain.NewDefaultSettings()
+			settings.TagLanguage = "en"
+			Expect(model.InsertSettings(ctx, db, settings)).To(Succeed())
+		})
+
+		It("merges word forms into stemmed tags", func(ctx SpecContext) {
+			Expect(model.RetagEvents(ctx, db)).To(Succeed())
+
+			tags := Must(model.ListTags(ctx, db, time.Time{}, 0))
+			Expect(tags).To(HaveExactElements(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":       Equal("concert"),
+					"EventCount": Equal(uint64(2)),
+				})),
+
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":       Equal("park"),
+					"EventCount": Equal(uint64(1)),
+				})),
+
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":       Equal("the"),
+					"EventCount": Equal(uint64(1)),
+				})),
+			))
+		})
+	})
+
+	When("stop words are set", func() {
+		JustBeforeEach(func(ctx SpecContext) {
+			Expect(model.SetStopWords(ctx, db, domain.NewStopWordList("the", "park"))).To(Succeed())
+		})
+
+		It("deletes stop word tags", func(ctx SpecContext) {
+			Expect(model.RetagEvents(ctx, db)).To(Succeed())
+
+			count := Must(db.NewSelect().Model((*model.Tag)(nil)).
+				Where("name IN (?)", bun.In([]string{"the", "park"})).
+				Count(ctx))
+			Expect(count).To(BeZero())
+
+			tags := Must(model.ListTags(ctx, db, time.Time{}, 0))
+			Expect(tags).To(HaveExactElements(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":       Equal("concert"),
+					"EventCount": Equal(uint64(1)),
+				})),
+
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Name":       Equal("concerts"),
+					"EventCount": Equal(uint64(1)),
+				})),
+			))
+		})
+	})
+
+	When("retagging with new stop words is rolled back", func() {
+		It("keeps the stop words and tags unchanged", func(ctx SpecContext) {
+			Expect(model.RetagEvents(ctx, db)).To(Succeed())
+
+			errRollback := errors.New("rollback")
+
+			Expect(db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+				Expect(model.SetStopWords(ctx, tx, domain.NewStopWordList("concert"))).To(Succeed())
+				Expect(model.RetagEvents(ctx, tx)).To(Succeed())
+
+				return errRollback
+			})).To(MatchError(errRollback))
+
+			Expect(Must(model.ListStopWords(ctx, db))).To(BeEmpty())
+			Expect(Must(model.ListTags(ctx, db, time.Time{}, 0))).To(ContainElement(
+				PointTo(HaveField("Name", "concert")),
+			))
+		})
+	})
+})
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// EventTemplate is the event template database model.
+type EventTemplate struct {
+	ID          snowflake.ID `bun:"id,pk"`
+	UserID      snowflake.ID `bun:"user_id"`
+	Name        string       `bun:"name"`
+	Title       string       `bun:"title"`
+	Description string       `bun:"description"`
+	URL         string       `bun:"url"`
+	VenueID     snowflake.ID `bun:"venue_id"`
+	Location    string       `bun:"location"`
+	OSMType     string       `bun:"osm_type"`
+	OSMID       uint64       `bun:"osm_id"`
+	Latitude    float64      `bun:"latitude"`
+	Longitude   float64      `bun:"longitude"`
+	Categories  string       `bun:"categories"`
+	Language    string       `bun:"language"`
+
+	bun.BaseModel `bun:"event_templates"`
+}
+
+// GetEventTemplate retrieves a single event template.
+func GetEventTemplate(ctx context.Context, db bun.IDB, id snowflake.ID) (*domain.EventTemplate, error) {
+	model := &EventTemplate{}
+
+	if err := db.NewSelect().Model(model).
+		Where("id = ?", id).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return eventTemplateToDomain(model)
+}
+
+// ListEventTemplates lists the site-wide templates and templates of a user by name.
+func ListEventTemplates(ctx context.Context, db bun.IDB, userID snowflake.ID) ([]*domain.EventTemplate, error) {
+	model := []*EventTemplate{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("user_id IN (0, ?)", userID).
+		OrderExpr("name COLLATE NOCASE ASC").
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	templates := make([]*domain.EventTemplate, 0, len(model))
+
+	for _, m := range model {
+		t, err := eventTemplateToDomain(m)
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, t)
+	}
+
+	return templates, nil
+}
+
+// InsertEventTemplate inserts an event template to the database.
+func InsertEventTemplate(ctx context.Context, db bun.IDB, t *domain.EventTemplate) error {
+	categories, err := json.Marshal(lo.Uniq(append([]string{}, t.Categories...)))
+	if err != nil {
+		return err
+	}
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&EventTemplate{
+		ID:          t.ID,
+		UserID:      t.UserID,
+		Name:        t.Name,
+		Title:       t.Title,
+		Description: t.Description,
+		URL:         t.URL,
+		VenueID:     t.VenueID,
+		Location:    t.Location,
+		OSMType:     t.OSMType,
+		OSMID:       t.OSMID,
+		Latitude:    t.Latitude,
+		Longitude:   t.Longitude,
+		Categories:  string(categories),
+		Language:    t.Language,
+	}).Exec(ctx))
+}
+
+// DeleteEventTemplate deletes an event template.
+func DeleteEventTemplate(ctx context.Context, db bun.IDB, id snowflake.ID) error {
+	return sqlite.WithErrorChecking(db.NewDelete().Model((*EventTemplate)(nil)).
+		Where("id = ?", id).
+		Exec(ctx))
+}
+
+func eventTemplateToDomain(t *EventTemplate) (*domain.EventTemplate, error) {
+	categories := []string{}
+
+	if err := json.Unmarshal([]byte(t.Categories), &categories); err != nil {
+		return nil, calendar.Internal.New("Invalid template categories", err)
+	}
+
+	return &domain.EventTemplate{
+		ID:          t.ID,
+		UserID:      t.UserID,
+		Name:        t.Name,
+		Title:       t.Title,
+		Description: t.Description,
+		URL:         t.URL,
+		VenueID:     t.VenueID,
+		Location:    t.Location,
+		OSMType:     t.OSMType,
+		OSMID:       t.OSMID,
+		Latitude:    t.Latitude,
+		Longitude:   t.Longitude,
+		Categories:  categories,
+		Language:    t.Language,
+	}, nil
+}
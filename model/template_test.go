@@ -0,0 +1,99 @@
+package model_test
+
+import (
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("event templates", func() {
+	var (
+		userID       snowflake.ID
+		siteTemplate *domain.EventTemplate
+		userTemplate *domain.EventTemplate
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		userID = snowflake.Generate()
+
+		siteTemplate = &domain.EventTemplate{
+			ID:          snowflake.Generate(),
+			Name:        "Weekly jam",
+			Title:       "Jam session {date}",
+			Description: "Bring your instruments",
+			Location:    "Sveta",
+			Categories:  []string{"music"},
+			Language:    "en",
+		}
+
+		userTemplate = &domain.EventTemplate{
+			ID:          snowflake.Generate(),
+			UserID:      userID,
+			Name:        "book club",
+			Title:       "Book club",
+			Description: "Monthly meeting",
+			URL:         "https://example.com",
+			VenueID:     snowflake.Generate(),
+			Location:    "Library",
+			OSMType:     "node",
+			OSMID:       1,
+			Latitude:    59.44,
+			Longitude:   24.73,
+			Categories:  []string{"books", "books", "social"},
+			Language:    "et",
+		}
+
+		Expect(model.InsertEventTemplate(ctx, db, siteTemplate)).To(Succeed())
+		Expect(model.InsertEventTemplate(ctx, db, userTemplate)).To(Succeed())
+
+		Expect(model.InsertEventTemplate(ctx, db, &domain.EventTemplate{
+			ID:     snowflake.Generate(),
+			UserID: snowflake.Generate(),
+			Name:   "Other user",
+		})).To(Succeed())
+	})
+
+	Specify("template is retrieved with all fields", func(ctx SpecContext) {
+		t := Must(model.GetEventTemplate(ctx, db, userTemplate.ID))
+
+		Expect(t).To(PointTo(MatchAllFields(Fields{
+			"ID":          Equal(userTemplate.ID),
+			"UserID":      Equal(userID),
+			"Name":        Equal("book club"),
+			"Title":       Equal("Book club"),
+			"Description": Equal("Monthly meeting"),
+			"URL":         Equal("https://example.com"),
+			"VenueID":     Equal(userTemplate.VenueID),
+			"Location":    Equal("Library"),
+			"OSMType":     Equal("node"),
+			"OSMID":       Equal(uint64(1)),
+			"Latitude":    Equal(59.44),
+			"Longitude":   Equal(24.73),
+			"Categories":  HaveExactElements("books", "social"),
+			"Language":    Equal("et"),
+		})))
+	})
+
+	Specify("site-wide and own templates are listed by name", func(ctx SpecContext) {
+		templates := Must(model.ListEventTemplates(ctx, db, userID))
+
+		Expect(templates).To(HaveExactElements(
+			HaveField("ID", userTemplate.ID),
+			HaveField("ID", siteTemplate.ID),
+		))
+
+		Expect(templates[1].IsSiteWide()).To(BeTrue())
+	})
+
+	Specify("template can be deleted", func(ctx SpecContext) {
+		Expect(model.DeleteEventTemplate(ctx, db, userTemplate.ID)).To(Succeed())
+
+		_, err := model.GetEventTemplate(ctx, db, userTemplate.ID)
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+})
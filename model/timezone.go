@@ -0,0 +1,77 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/mgnsk/calendar/pkg/timestamp"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// ResolveEventTimezones sets the IANA time zone of events which only have
+// a fixed UTC offset, resolving it from the event coordinates.
+// The zone is only set when its offset at the event start time matches the stored
+// offset so that the local start time of the event does not change.
+// Each event is only attempted once. It returns the number of updated events.
+func ResolveEventTimezones(ctx context.Context, db *bun.DB, getTimezoneName func(lng, lat float64) string) (int, error) {
+	var count int
+
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		events := []*Event{}
+
+		if err := db.NewSelect().Model(&events).
+			Where("timezone_resolved = 0").
+			Where("latitude != 0 OR longitude != 0").
+			Scan(ctx); err != nil {
+			return sqlite.NormalizeError(err)
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		for _, ev := range events {
+			name := getTimezoneName(ev.Longitude, ev.Latitude)
+			if name == "" {
+				continue
+			}
+
+			loc, err := timestamp.LoadLocation(name)
+			if err != nil {
+				continue
+			}
+
+			if _, offset := time.Unix(ev.StartAtUnix, 0).In(loc).Zone(); offset != ev.TimezoneOffset {
+				continue
+			}
+
+			if err := sqlite.WithErrorChecking(
+				db.NewUpdate().Model(&Event{Timezone: name}).
+					Column("timezone").
+					Where("id = ?", ev.ID).
+					Exec(ctx),
+			); err != nil {
+				return err
+			}
+
+			count++
+		}
+
+		// Do not attempt events without a matching zone on every startup.
+		ids := lo.Map(events, func(ev *Event, _ int) snowflake.ID {
+			return ev.ID
+		})
+
+		return sqlite.WithErrorChecking(
+			db.NewUpdate().Model((*Event)(nil)).
+				Set("timezone_resolved = 1").
+				Where("id IN (?)", bun.In(ids)).
+				Exec(ctx),
+		)
+	})
+
+	return count, err
+}
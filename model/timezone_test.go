@@ -0,0 +1,118 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("event time zones", func() {
+	tallinn := Must(time.LoadLocation("Europe/Tallinn"))
+
+	Specify("time zone name is persisted", func(ctx SpecContext) {
+		ev := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Date(2025, 7, 1, 18, 0, 0, 0, tallinn),
+			Title:       "Event",
+			Description: "Desc",
+			UserID:      snowflake.Generate(),
+		}
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+
+		event := Must(model.GetEvent(ctx, db, ev.ID))
+		Expect(event.GetTimezoneName()).To(Equal("Europe/Tallinn"))
+		Expect(event.StartAt.Equal(ev.StartAt)).To(BeTrue())
+
+		By("updating the event to a time in winter", func() {
+			event.StartAt = time.Date(2025, 12, 1, 18, 0, 0, 0, tallinn)
+			Expect(model.UpdateEvent(ctx, db, event)).To(Succeed())
+
+			event := Must(model.GetEvent(ctx, db, ev.ID))
+			Expect(event.GetTimezoneName()).To(Equal("Europe/Tallinn"))
+			Expect(event.StartAt.Format(time.RFC3339)).To(Equal("2025-12-01T18:00:00+02:00"))
+		})
+	})
+
+	Specify("time zone override is persisted", func(ctx SpecContext) {
+		ev := &domain.Event{
+			ID:               snowflake.Generate(),
+			StartAt:          time.Date(2025, 7, 1, 18, 0, 0, 0, tallinn),
+			Title:            "Event",
+			Description:      "Desc",
+			UserID:           snowflake.Generate(),
+			TimezoneOverride: true,
+		}
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+		Expect(Must(model.GetEvent(ctx, db, ev.ID))).To(HaveField("TimezoneOverride", BeTrue()))
+
+		ev.TimezoneOverride = false
+		Expect(model.UpdateEvent(ctx, db, ev)).To(Succeed())
+		Expect(Must(model.GetEvent(ctx, db, ev.ID))).To(HaveField("TimezoneOverride", BeFalse()))
+	})
+
+	Specify("time zones are resolved for events with a fixed offset", func(ctx SpecContext) {
+		summer := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Date(2025, 7, 1, 18, 0, 0, 0, time.FixedZone("", 3*3600)),
+			Title:       "Summer",
+			Description: "Desc",
+			Latitude:    59.44,
+			Longitude:   24.73,
+			UserID:      snowflake.Generate(),
+		}
+
+		mismatch := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Date(2025, 7, 1, 18, 0, 0, 0, time.FixedZone("", 0)),
+			Title:       "Mismatch",
+			Description: "Desc",
+			Latitude:    59.44,
+			Longitude:   24.73,
+			UserID:      snowflake.Generate(),
+		}
+
+		noLocation := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Date(2025, 7, 1, 18, 0, 0, 0, time.FixedZone("", 3*3600)),
+			Title:       "No location",
+			Description: "Desc",
+			UserID:      snowflake.Generate(),
+		}
+
+		for _, ev := range []*domain.Event{summer, mismatch, noLocation} {
+			Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+		}
+
+		By("marking the events as created before time zones were stored", func() {
+			Must(db.NewUpdate().Model((*model.Event)(nil)).
+				Set("timezone_resolved = 0").
+				Where("1 = 1").
+				Exec(ctx))
+		})
+
+		n := Must(model.ResolveEventTimezones(ctx, db, func(_, _ float64) string {
+			return "Europe/Tallinn"
+		}))
+		Expect(n).To(Equal(1))
+
+		Expect(Must(model.GetEvent(ctx, db, summer.ID)).GetTimezoneName()).To(Equal("Europe/Tallinn"))
+		Expect(Must(model.GetEvent(ctx, db, mismatch.ID)).GetTimezoneName()).To(BeEmpty())
+		Expect(Must(model.GetEvent(ctx, db, noLocation.ID)).GetTimezoneName()).To(BeEmpty())
+
+		By("asserting events are not resolved again", func() {
+			calls := 0
+			Expect(Must(model.ResolveEventTimezones(ctx, db, func(_, _ float64) string {
+				calls++
+				return "Europe/Tallinn"
+			}))).To(BeZero())
+			Expect(calls).To(BeZero())
+		})
+	})
+})
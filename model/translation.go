@@ -0,0 +1,95 @@
+package model
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// EventTranslation is the event translation database model.
+type EventTranslation struct {
+	EventID     snowflake.ID `bun:"event_id,pk"`
+	Language    string       `bun:"language,pk"`
+	Title       string       `bun:"title"`
+	Description string       `bun:"description"`
+
+	bun.BaseModel `bun:"events_translations"`
+}
+
+// setEventTranslations replaces the translations of an event.
+// Empty translations and translations to the primary language are skipped.
+func setEventTranslations(ctx context.Context, db bun.IDB, ev *domain.Event) error {
+	if err := deleteEventTranslations(ctx, db, ev.ID); err != nil {
+		return err
+	}
+
+	model := lo.FilterMap(ev.Translations, func(t domain.EventTranslation, _ int) (*EventTranslation, bool) {
+		return &EventTranslation{
+			EventID:     ev.ID,
+			Language:    t.Language,
+			Title:       t.Title,
+			Description: t.Description,
+		}, t.Language != "" && t.Language != ev.Language && (t.Title != "" || t.Description != "")
+	})
+
+	model = lo.UniqBy(model, func(t *EventTranslation) string {
+		return t.Language
+	})
+
+	if len(model) == 0 {
+		return nil
+	}
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&model).Exec(ctx))
+}
+
+// deleteEventTranslations deletes the translations of an event.
+func deleteEventTranslations(ctx context.Context, db bun.IDB, eventID snowflake.ID) error {
+	if err := sqlite.WithErrorChecking(
+		db.NewDelete().Model((*EventTranslation)(nil)).
+			Where("event_id = ?", eventID).
+			Exec(ctx),
+	); err != nil && !errors.Is(err, calendar.PreconditionFailed) {
+		return err
+	}
+
+	return nil
+}
+
+// loadEventTranslations populates the translations of events.
+func loadEventTranslations(ctx context.Context, db bun.IDB, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	model := []*EventTranslation{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("event_id IN (?)", bun.In(lo.Map(events, func(ev *domain.Event, _ int) snowflake.ID {
+			return ev.ID
+		}))).
+		Order("language ASC").
+		Scan(ctx); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	byEvent := lo.GroupByMap(model, func(t *EventTranslation) (snowflake.ID, domain.EventTranslation) {
+		return t.EventID, domain.EventTranslation{
+			Language:    t.Language,
+			Title:       t.Title,
+			Description: t.Description,
+		}
+	})
+
+	for _, ev := range events {
+		ev.Translations = byEvent[ev.ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("event translations", func() {
+	var ev *domain.Event
+
+	BeforeEach(func(ctx SpecContext) {
+		ev = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().Add(2 * time.Hour),
+			Title:       "Poetry evening",
+			Description: "Poems read aloud",
+			Location:    "Library",
+			Language:    "en",
+			Translations: []domain.EventTranslation{
+				{Language: "et", Title: "Luuleõhtu", Description: "Luuletused ettelugemiseks"},
+				{Language: "en", Title: "Skipped", Description: "Primary language"},
+				{Language: "de", Title: "", Description: ""},
+			},
+			UserID: snowflake.Generate(),
+		}
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+	})
+
+	Specify("translations are persisted", func(ctx SpecContext) {
+		event := Must(model.GetEvent(ctx, db, ev.ID))
+
+		Expect(event.Language).To(Equal("en"))
+		Expect(event.Translations).To(HaveExactElements(
+			domain.EventTranslation{Language: "et", Title: "Luuleõhtu", Description: "Luuletused ettelugemiseks"},
+		))
+
+		By("updating translations", func() {
+			event.Translations = []domain.EventTranslation{
+				{Language: "et", Title: "Luulekohvik", Description: "Luuletused"},
+			}
+			Expect(model.UpdateEvent(ctx, db, event)).To(Succeed())
+
+			result := Must(model.NewEventsQuery().WithOrder(0, model.OrderStartAtAsc).List(ctx, db))
+			Expect(result).To(HaveExactElements(
+				HaveField("Translations", HaveExactElements(
+					HaveField("Title", "Luulekohvik"),
+				)),
+			))
+		})
+	})
+
+	DescribeTable("all translations are searchable",
+		func(ctx SpecContext, query string, found bool) {
+			result := Must(model.NewEventsQuery().
+				WithOrder(0, model.OrderRelevance).
+				WithSearchText(query, time.UTC).
+				List(ctx, db),
+			)
+
+			if found {
+				Expect(result).To(HaveExactElements(HaveField("ID", ev.ID)))
+			} else {
+				Expect(result).To(BeEmpty())
+			}
+		},
+		Entry("primary title", "poetry", true),
+		Entry("translated title", "luuleõhtu", true),
+		Entry("translated description", "description:ettelugemiseks", true),
+		Entry("translated title scoped to location", "location:luule", false),
+		Entry("skipped translation", "skipped", false),
+	)
+
+	Specify("deleted translations are removed from the search index", func(ctx SpecContext) {
+		ev.Translations = nil
+		Expect(model.UpdateEvent(ctx, db, ev)).To(Succeed())
+
+		Expect(Must(model.NewEventsQuery().
+			WithOrder(0, model.OrderRelevance).
+			WithSearchText("luuleõhtu", time.UTC).
+			List(ctx, db),
+		)).To(BeEmpty())
+	})
+
+	Specify("translations are deleted with the event", func(ctx SpecContext) {
+		Expect(model.DeleteEvent(ctx, db, ev)).To(Succeed())
+
+		count := Must(db.NewSelect().Model((*model.EventTranslation)(nil)).Where("event_id = ?", ev.ID).Count(ctx))
+		Expect(count).To(BeZero())
+	})
+})
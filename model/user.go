@@ -14,6 +14,7 @@ import (
 type User struct {
 	ID       snowflake.ID `bun:"id,pk"`
 	Username string       `bun:"username"`
+	Email    string       `bun:"email"`
 	Password []byte       `bun:"password"`
 	Role     string       `bun:"role"`
 
@@ -25,6 +26,7 @@ func InsertUser(ctx context.Context, db bun.IDB, user *domain.User) error {
 	return sqlite.WithErrorChecking(db.NewInsert().Model(&User{
 		ID:       user.ID,
 		Username: user.Username,
+		Email:    user.Email,
 		Password: user.Password,
 		Role:     string(user.Role),
 	}).Exec(ctx))
@@ -35,11 +37,13 @@ func UpdateUser(ctx context.Context, db bun.IDB, user *domain.User) error {
 	return sqlite.WithErrorChecking(db.NewUpdate().Model(&User{
 		ID:       user.ID,
 		Username: user.Username,
+		Email:    user.Email,
 		Password: user.Password,
 		Role:     string(user.Role),
 	}).
 		Column(
 			"username",
+			"email",
 			"password",
 			"role",
 		).
@@ -80,6 +84,20 @@ func GetUserByUsername(ctx context.Context, db bun.IDB, username string) (*domai
 	return userToDomain(model), nil
 }
 
+// GetUserByEmail returns a user. Emails are compared case-insensitively.
+func GetUserByEmail(ctx context.Context, db bun.IDB, email string) (*domain.User, error) {
+	model := &User{}
+
+	if err := db.NewSelect().Model(model).
+		Where("email = ? COLLATE NOCASE", email).
+		Where("email != ''").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return userToDomain(model), nil
+}
+
 // ListUsers lists users.
 func ListUsers(ctx context.Context, db bun.IDB) ([]*domain.User, error) {
 	model := []*User{}
@@ -103,6 +121,7 @@ func userToDomain(user *User) *domain.User {
 	return &domain.User{
 		ID:       user.ID,
 		Username: user.Username,
+		Email:    user.Email,
 		Password: user.Password,
 		Role:     domain.Role(user.Role),
 	}
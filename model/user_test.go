@@ -29,6 +29,7 @@ var _ = Describe("inserting users", func() {
 				Expect(user).To(PointTo(MatchAllFields(Fields{
 					"ID":       Equal(userID),
 					"Username": Equal("username"),
+					"Email":    BeEmpty(),
 					"Password": Equal([]byte("password")),
 					"Role":     Equal(domain.Admin),
 				})))
@@ -39,6 +40,7 @@ var _ = Describe("inserting users", func() {
 				Expect(user).To(PointTo(MatchAllFields(Fields{
 					"ID":       Equal(userID),
 					"Username": Equal("username"),
+					"Email":    BeEmpty(),
 					"Password": Equal([]byte("password")),
 					"Role":     Equal(domain.Admin),
 				})))
@@ -88,6 +90,7 @@ var _ = Describe("updating users", func() {
 			Expect(model.UpdateUser(ctx, db, &domain.User{
 				ID:       userID,
 				Username: "username2",
+				Email:    "user@calendar.testing",
 				Password: []byte("password2"),
 				Role:     domain.Author,
 			})).To(Succeed())
@@ -96,6 +99,7 @@ var _ = Describe("updating users", func() {
 			Expect(user).To(PointTo(MatchAllFields(Fields{
 				"ID":       Equal(userID),
 				"Username": Equal("username2"),
+				"Email":    Equal("user@calendar.testing"),
 				"Password": Equal([]byte("password2")),
 				"Role":     Equal(domain.Author),
 			})))
@@ -103,6 +107,55 @@ var _ = Describe("updating users", func() {
 	})
 })
 
+var _ = Describe("user emails", func() {
+	JustBeforeEach(func(ctx SpecContext) {
+		Expect(model.InsertUser(ctx, db, &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "user1",
+			Email:    "user@calendar.testing",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		})).To(Succeed())
+
+		Expect(model.InsertUser(ctx, db, &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "user2",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		})).To(Succeed())
+	})
+
+	Specify("user is found by email case-insensitively", func(ctx SpecContext) {
+		Expect(Must(model.GetUserByEmail(ctx, db, "User@calendar.testing")).Username).To(Equal("user1"))
+	})
+
+	Specify("empty email does not match users without an email", func(ctx SpecContext) {
+		_, err := model.GetUserByEmail(ctx, db, "")
+		Expect(err).To(MatchError(calendar.NotFound))
+	})
+
+	Specify("emails are unique", func(ctx SpecContext) {
+		err := model.InsertUser(ctx, db, &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "user3",
+			Email:    "USER@calendar.testing",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		})
+
+		Expect(err).To(MatchError(calendar.AlreadyExists))
+	})
+
+	Specify("multiple users can have no email", func(ctx SpecContext) {
+		Expect(model.InsertUser(ctx, db, &domain.User{
+			ID:       snowflake.Generate(),
+			Username: "user3",
+			Password: []byte("password"),
+			Role:     domain.Author,
+		})).To(Succeed())
+	})
+})
+
 var _ = Describe("deleting users", func() {
 	var userID snowflake.ID
 
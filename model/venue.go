@@ -0,0 +1,249 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// Venue is the venue database model.
+type Venue struct {
+	ID            snowflake.ID `bun:"id,pk"`
+	Name          string       `bun:"name"`
+	Address       string       `bun:"address"`
+	OSMType       string       `bun:"osm_type"`
+	OSMID         uint64       `bun:"osm_id"`
+	Latitude      float64      `bun:"latitude"`
+	Longitude     float64      `bun:"longitude"`
+	URL           string       `bun:"url"`
+	Accessibility string       `bun:"accessibility"`
+	EventCount    uint64       `bun:"event_count,scanonly"`
+
+	bun.BaseModel `bun:"venues"`
+}
+
+// GetVenue retrieves a single venue.
+func GetVenue(ctx context.Context, db bun.IDB, id snowflake.ID) (*domain.Venue, error) {
+	model := &Venue{}
+
+	if err := db.NewSelect().Model(model).
+		Where("id = ?", id).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return venueToDomain(model), nil
+}
+
+// ListVenues lists all venues by name along with
+// the number of related published events starting from eventStartAtFrom.
+func ListVenues(ctx context.Context, db bun.IDB, eventStartAtFrom time.Time) ([]*domain.Venue, error) {
+	model := []*Venue{}
+
+	query := db.NewSelect().Model(&model).
+		ColumnExpr("venue.*, COUNT(ev.id) AS event_count").
+		Group("venue.id").
+		OrderExpr("venue.name COLLATE NOCASE ASC").
+		Order("venue.id ASC")
+
+	if eventStartAtFrom.IsZero() {
+		query.Join("LEFT JOIN events AS ev ON ev.venue_id = venue.id AND ev.is_draft = 0")
+	} else {
+		query.Join("LEFT JOIN events AS ev ON ev.venue_id = venue.id AND ev.is_draft = 0 AND ev.start_at_unix >= ?", eventStartAtFrom.Unix())
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(v *Venue, _ int) *domain.Venue {
+		return venueToDomain(v)
+	}), nil
+}
+
+// InsertVenue inserts a venue to the database.
+func InsertVenue(ctx context.Context, db bun.IDB, v *domain.Venue) error {
+	return sqlite.WithErrorChecking(db.NewInsert().Model(venueToModel(v)).Exec(ctx))
+}
+
+// UpdateVenue updates a venue and the location of its events.
+func UpdateVenue(ctx context.Context, db *bun.DB, v *domain.Venue) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		if err := sqlite.WithErrorChecking(
+			db.NewUpdate().Model(venueToModel(v)).
+				Column(
+					"name",
+					"address",
+					"osm_type",
+					"osm_id",
+					"latitude",
+					"longitude",
+					"url",
+					"accessibility",
+				).
+				Where("id = ?", v.ID).
+				Exec(ctx),
+		); err != nil {
+			return err
+		}
+
+		return syncVenueEvents(ctx, db, v)
+	})
+}
+
+// DeleteVenue deletes a venue. Events keep their location but are detached from the venue.
+func DeleteVenue(ctx context.Context, db *bun.DB, id snowflake.ID) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		if err := sqlite.WithErrorChecking(
+			db.NewDelete().Model((*Venue)(nil)).
+				Where("id = ?", id).
+				Exec(ctx),
+		); err != nil {
+			return err
+		}
+
+		if err := sqlite.WithErrorChecking(
+			db.NewUpdate().Model((*Event)(nil)).
+				Set("venue_id = 0").
+				Where("venue_id = ?", id).
+				Exec(ctx),
+		); err != nil && !errors.Is(err, calendar.PreconditionFailed) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// MergeVenues merges source venues into the target venue.
+// Events of the source venues are moved to the target venue
+// and take its location. The source venues are deleted.
+func MergeVenues(ctx context.Context, db *bun.DB, targetID snowflake.ID, sourceIDs ...snowflake.ID) error {
+	sourceIDs = lo.Without(lo.Uniq(sourceIDs), targetID)
+	if len(sourceIDs) == 0 {
+		return calendar.InvalidValue.New("No venues to merge")
+	}
+
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		target, err := GetVenue(ctx, db, targetID)
+		if err != nil {
+			return err
+		}
+
+		if err := sqlite.WithErrorChecking(
+			db.NewDelete().Model((*Venue)(nil)).
+				Where("id IN (?)", bun.In(sourceIDs)).
+				Exec(ctx),
+		); err != nil {
+			return err
+		}
+
+		if err := sqlite.WithErrorChecking(
+			db.NewUpdate().Model((*Event)(nil)).
+				Set("venue_id = ?", target.ID).
+				Where("venue_id IN (?)", bun.In(sourceIDs)).
+				Exec(ctx),
+		); err != nil && !errors.Is(err, calendar.PreconditionFailed) {
+			return err
+		}
+
+		return syncVenueEvents(ctx, db, target)
+	})
+}
+
+// syncVenueEvents sets the venue location on its events and recreates their tag relations.
+func syncVenueEvents(ctx context.Context, db bun.IDB, v *domain.Venue) error {
+	events := []*Event{}
+
+	if err := db.NewSelect().Model(&events).
+		Where("venue_id = ?", v.ID).
+		Scan(ctx); err != nil {
+		return sqlite.NormalizeError(err)
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	x, err := newTagExtractor(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, model := range events {
+		ev := eventToDomain(model)
+		v.Apply(ev)
+
+		if err := sqlite.WithErrorChecking(
+			db.NewUpdate().Model(&Event{
+				Location:  ev.Location,
+				OSMType:   ev.OSMType,
+				OSMID:     ev.OSMID,
+				Latitude:  ev.Latitude,
+				Longitude: ev.Longitude,
+			}).
+				Column(
+					"location",
+					"osm_type",
+					"osm_id",
+					"latitude",
+					"longitude",
+				).
+				Where("id = ?", ev.ID).
+				Exec(ctx),
+		); err != nil {
+			return err
+		}
+
+		if err := DeleteTags(ctx, db, ev.ID); err != nil {
+			return err
+		}
+
+		if ev.IsDraft {
+			continue
+		}
+
+		if err := createEventTagRelations(ctx, db, x, ev); err != nil {
+			return err
+		}
+	}
+
+	// Clean up orphaned tags.
+	return CleanTags(ctx, db)
+}
+
+func venueToModel(v *domain.Venue) *Venue {
+	return &Venue{
+		ID:            v.ID,
+		Name:          v.Name,
+		Address:       v.Address,
+		OSMType:       v.OSMType,
+		OSMID:         v.OSMID,
+		Latitude:      v.Latitude,
+		Longitude:     v.Longitude,
+		URL:           v.URL,
+		Accessibility: v.Accessibility,
+	}
+}
+
+func venueToDomain(v *Venue) *domain.Venue {
+	return &domain.Venue{
+		ID:            v.ID,
+		Name:          v.Name,
+		Address:       v.Address,
+		OSMType:       v.OSMType,
+		OSMID:         v.OSMID,
+		Latitude:      v.Latitude,
+		Longitude:     v.Longitude,
+		URL:           v.URL,
+		Accessibility: v.Accessibility,
+		EventCount:    v.EventCount,
+	}
+}
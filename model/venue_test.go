@@ -0,0 +1,117 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("venues", func() {
+	var (
+		venue1 *domain.Venue
+		venue2 *domain.Venue
+		ev     *domain.Event
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		venue1 = &domain.Venue{
+			ID:        snowflake.Generate(),
+			Name:      "Sveta",
+			Address:   "Telliskivi 60a, Tallinn",
+			OSMType:   "node",
+			OSMID:     1,
+			Latitude:  59.4400,
+			Longitude: 24.7300,
+		}
+
+		venue2 = &domain.Venue{
+			ID:        snowflake.Generate(),
+			Name:      "sveta baar",
+			Latitude:  59.4410,
+			Longitude: 24.7310,
+		}
+
+		Expect(model.InsertVenue(ctx, db, venue1)).To(Succeed())
+		Expect(model.InsertVenue(ctx, db, venue2)).To(Succeed())
+
+		ev = &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().Add(time.Hour),
+			Title:       "Event 1",
+			Description: "Desc 1",
+			UserID:      snowflake.Generate(),
+		}
+		venue2.Apply(ev)
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+	})
+
+	Specify("venues are listed by name with event counts", func(ctx SpecContext) {
+		venues := Must(model.ListVenues(ctx, db, time.Now()))
+
+		Expect(venues).To(HaveExactElements(
+			SatisfyAll(HaveField("Name", "Sveta"), HaveField("EventCount", uint64(0))),
+			SatisfyAll(HaveField("Name", "sveta baar"), HaveField("EventCount", uint64(1))),
+		))
+	})
+
+	Specify("events are filtered by venue", func(ctx SpecContext) {
+		events := Must(model.NewEventsQuery().WithVenue(venue2.ID).List(ctx, db))
+
+		Expect(events).To(HaveExactElements(
+			HaveField("ID", ev.ID),
+		))
+
+		events = Must(model.NewEventsQuery().WithVenue(venue1.ID).List(ctx, db))
+
+		Expect(events).To(BeEmpty())
+	})
+
+	Specify("updating a venue updates the location of its events", func(ctx SpecContext) {
+		venue2.Name = "Sveta Baar"
+		venue2.Address = "Telliskivi 60a"
+		Expect(model.UpdateVenue(ctx, db, venue2)).To(Succeed())
+
+		event := Must(model.GetEvent(ctx, db, ev.ID))
+		Expect(event.Location).To(Equal("Sveta Baar, Telliskivi 60a"))
+
+		tags := Must(model.ListTags(ctx, db, time.Time{}, 100))
+		Expect(tags).To(ContainElement(HaveField("Name", "telliskivi")))
+	})
+
+	Specify("merging venues moves events to the target venue", func(ctx SpecContext) {
+		Expect(model.MergeVenues(ctx, db, venue1.ID, venue2.ID)).To(Succeed())
+
+		event := Must(model.GetEvent(ctx, db, ev.ID))
+		Expect(event.VenueID).To(Equal(venue1.ID))
+		Expect(event.Location).To(Equal("Sveta, Telliskivi 60a, Tallinn"))
+		Expect(event.Latitude).To(Equal(venue1.Latitude))
+		Expect(event.Longitude).To(Equal(venue1.Longitude))
+
+		_, err := model.GetVenue(ctx, db, venue2.ID)
+		Expect(err).To(MatchError(calendar.NotFound))
+
+		venues := Must(model.ListVenues(ctx, db, time.Now()))
+		Expect(venues).To(HaveExactElements(
+			SatisfyAll(HaveField("Name", "Sveta"), HaveField("EventCount", uint64(1))),
+		))
+	})
+
+	Specify("merging a venue into itself fails", func(ctx SpecContext) {
+		Expect(model.MergeVenues(ctx, db, venue1.ID, venue1.ID)).To(MatchError(calendar.InvalidValue))
+	})
+
+	Specify("deleting a venue detaches its events", func(ctx SpecContext) {
+		Expect(model.DeleteVenue(ctx, db, venue2.ID)).To(Succeed())
+
+		event := Must(model.GetEvent(ctx, db, ev.ID))
+		Expect(event.VenueID).To(BeZero())
+		Expect(event.Location).To(Equal("sveta baar"))
+	})
+})
@@ -0,0 +1,265 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	"github.com/mgnsk/calendar/pkg/sqlite"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+)
+
+// Webhook is the webhook database model.
+type Webhook struct {
+	ID         snowflake.ID `bun:"id,pk"`
+	URL        string       `bun:"url"`
+	Secret     string       `bun:"secret"`
+	EventTypes string       `bun:"event_types"`
+
+	bun.BaseModel `bun:"webhooks"`
+}
+
+// WebhookDelivery is the webhook delivery database model.
+type WebhookDelivery struct {
+	ID                snowflake.ID `bun:"id,pk"`
+	WebhookID         snowflake.ID `bun:"webhook_id"`
+	EventType         string       `bun:"event_type"`
+	Payload           []byte       `bun:"payload"`
+	Status            string       `bun:"status"`
+	Attempts          int          `bun:"attempts"`
+	NextAttemptAtUnix int64        `bun:"next_attempt_at_unix"`
+	ResponseCode      int          `bun:"response_code"`
+	LastError         string       `bun:"last_error"`
+	UpdatedAtUnix     int64        `bun:"updated_at_unix"`
+
+	bun.BaseModel `bun:"webhook_deliveries"`
+}
+
+// InsertWebhook inserts a webhook.
+func InsertWebhook(ctx context.Context, db bun.IDB, w *domain.Webhook) error {
+	return sqlite.WithErrorChecking(db.NewInsert().Model(&Webhook{
+		ID:     w.ID,
+		URL:    w.URL,
+		Secret: w.Secret,
+		EventTypes: strings.Join(lo.Map(w.EventTypes, func(t domain.WebhookEventType, _ int) string {
+			return string(t)
+		}), ","),
+	}).Exec(ctx))
+}
+
+// GetWebhook returns a webhook.
+func GetWebhook(ctx context.Context, db bun.IDB, id snowflake.ID) (*domain.Webhook, error) {
+	model := &Webhook{}
+
+	if err := db.NewSelect().Model(model).
+		Where("id = ?", id).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return webhookToDomain(model), nil
+}
+
+// ListWebhooks lists webhooks.
+func ListWebhooks(ctx context.Context, db bun.IDB) ([]*domain.Webhook, error) {
+	model := []*Webhook{}
+
+	if err := db.NewSelect().Model(&model).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *Webhook, _ int) *domain.Webhook {
+		return webhookToDomain(m)
+	}), nil
+}
+
+// DeleteWebhook deletes a webhook along with its deliveries.
+func DeleteWebhook(ctx context.Context, db *bun.DB, id snowflake.ID) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, db bun.Tx) error {
+		if err := sqlite.WithErrorChecking(db.NewDelete().Model((*Webhook)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)); err != nil {
+			return err
+		}
+
+		_, err := db.NewDelete().Model((*WebhookDelivery)(nil)).
+			Where("webhook_id = ?", id).
+			Exec(ctx)
+
+		return sqlite.NormalizeError(err)
+	})
+}
+
+// InsertWebhookDelivery queues a webhook delivery to be sent immediately.
+func InsertWebhookDelivery(ctx context.Context, db bun.IDB, d *domain.WebhookDelivery) error {
+	if d.ID == 0 {
+		d.ID = snowflake.Generate()
+	}
+
+	if d.Status == "" {
+		d.Status = domain.WebhookDeliveryPending
+	}
+
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = time.Now()
+	}
+
+	if d.UpdatedAt.IsZero() {
+		d.UpdatedAt = d.NextAttemptAt
+	}
+
+	return sqlite.WithErrorChecking(db.NewInsert().Model(webhookDeliveryToModel(d)).Exec(ctx))
+}
+
+// UpdateWebhookDelivery updates the status of a webhook delivery.
+func UpdateWebhookDelivery(ctx context.Context, db bun.IDB, d *domain.WebhookDelivery) error {
+	return sqlite.WithErrorChecking(db.NewUpdate().Model(webhookDeliveryToModel(d)).
+		Column(
+			"status",
+			"attempts",
+			"next_attempt_at_unix",
+			"response_code",
+			"last_error",
+			"updated_at_unix",
+		).
+		Where("id = ?", d.ID).
+		Exec(ctx))
+}
+
+// ListDueWebhookDeliveries lists pending deliveries whose next attempt is due at now.
+func ListDueWebhookDeliveries(ctx context.Context, db bun.IDB, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	model := []*WebhookDelivery{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("status = ?", domain.WebhookDeliveryPending).
+		Where("next_attempt_at_unix <= ?", now.Unix()).
+		Order("next_attempt_at_unix ASC", "id ASC").
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *WebhookDelivery, _ int) *domain.WebhookDelivery {
+		return webhookDeliveryToDomain(m)
+	}), nil
+}
+
+// ListWebhookDeliveries lists the latest deliveries of a webhook.
+func ListWebhookDeliveries(ctx context.Context, db bun.IDB, webhookID snowflake.ID, limit int) ([]*domain.WebhookDelivery, error) {
+	model := []*WebhookDelivery{}
+
+	if err := db.NewSelect().Model(&model).
+		Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, sqlite.NormalizeError(err)
+	}
+
+	return lo.Map(model, func(m *WebhookDelivery, _ int) *domain.WebhookDelivery {
+		return webhookDeliveryToDomain(m)
+	}), nil
+}
+
+// DeleteOldWebhookDeliveries deletes finished deliveries last updated before the cutoff.
+func DeleteOldWebhookDeliveries(ctx context.Context, db *bun.DB, cutoff time.Time) error {
+	err := sqlite.WithErrorChecking(db.NewDelete().Model((*WebhookDelivery)(nil)).
+		Where("status != ?", domain.WebhookDeliveryPending).
+		Where("updated_at_unix < ?", cutoff.Unix()).
+		Exec(ctx))
+
+	if errors.Is(err, calendar.PreconditionFailed) {
+		return nil
+	}
+
+	return err
+}
+
+// queueWebhookDeliveries queues deliveries of an event lifecycle change
+// to the webhooks which accept the event type. Deletions must be queued
+// before the event is deleted.
+func queueWebhookDeliveries(ctx context.Context, db bun.IDB, t domain.WebhookEventType, id snowflake.ID) error {
+	webhooks, err := ListWebhooks(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	webhooks = lo.Filter(webhooks, func(w *domain.Webhook, _ int) bool {
+		return w.Accepts(t)
+	})
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	ev, err := GetEvent(ctx, db, id)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(domain.NewWebhookPayload(t, ev, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	for _, w := range webhooks {
+		if err := InsertWebhookDelivery(ctx, db, &domain.WebhookDelivery{
+			WebhookID: w.ID,
+			EventType: t,
+			Payload:   payload,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func webhookToDomain(model *Webhook) *domain.Webhook {
+	return &domain.Webhook{
+		ID:     model.ID,
+		URL:    model.URL,
+		Secret: model.Secret,
+		EventTypes: lo.FilterMap(strings.Split(model.EventTypes, ","), func(s string, _ int) (domain.WebhookEventType, bool) {
+			return domain.WebhookEventType(s), s != ""
+		}),
+	}
+}
+
+func webhookDeliveryToModel(d *domain.WebhookDelivery) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:                d.ID,
+		WebhookID:         d.WebhookID,
+		EventType:         string(d.EventType),
+		Payload:           d.Payload,
+		Status:            string(d.Status),
+		Attempts:          d.Attempts,
+		NextAttemptAtUnix: d.NextAttemptAt.Unix(),
+		ResponseCode:      d.ResponseCode,
+		LastError:         d.LastError,
+		UpdatedAtUnix:     d.UpdatedAt.Unix(),
+	}
+}
+
+func webhookDeliveryToDomain(model *WebhookDelivery) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:            model.ID,
+		WebhookID:     model.WebhookID,
+		EventType:     domain.WebhookEventType(model.EventType),
+		Payload:       model.Payload,
+		Status:        domain.WebhookDeliveryStatus(model.Status),
+		Attempts:      model.Attempts,
+		NextAttemptAt: time.Unix(model.NextAttemptAtUnix, 0),
+		ResponseCode:  model.ResponseCode,
+		LastError:     model.LastError,
+		UpdatedAt:     time.Unix(model.UpdatedAtUnix, 0),
+	}
+}
@@ -0,0 +1,190 @@
+package model_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/snowflake"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("webhooks", func() {
+	var (
+		all       *domain.Webhook
+		published *domain.Webhook
+	)
+
+	eventTypes := func(ctx SpecContext, w *domain.Webhook) []domain.WebhookEventType {
+		deliveries := Must(model.ListWebhookDeliveries(ctx, db, w.ID, 100))
+
+		var types []domain.WebhookEventType
+		for i := len(deliveries) - 1; i >= 0; i-- {
+			types = append(types, deliveries[i].EventType)
+		}
+
+		return types
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		all = &domain.Webhook{
+			ID:         snowflake.Generate(),
+			URL:        "https://all.calendar.testing/hook",
+			Secret:     "secret",
+			EventTypes: domain.WebhookEventTypes,
+		}
+
+		published = &domain.Webhook{
+			ID:         snowflake.Generate(),
+			URL:        "https://published.calendar.testing/hook",
+			Secret:     "secret",
+			EventTypes: []domain.WebhookEventType{domain.WebhookPublished},
+		}
+
+		Expect(model.InsertWebhook(ctx, db, all)).To(Succeed())
+		Expect(model.InsertWebhook(ctx, db, published)).To(Succeed())
+	})
+
+	Specify("webhooks are listed with event types", func(ctx SpecContext) {
+		Expect(Must(model.ListWebhooks(ctx, db))).To(HaveExactElements(
+			Equal(all),
+			Equal(published),
+		))
+	})
+
+	Specify("event lifecycle changes are queued", func(ctx SpecContext) {
+		Expect(model.SetCategories(ctx, db, domain.NewCategoryList("Music"))).To(Succeed())
+
+		ev := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().Add(24 * time.Hour),
+			Title:       "Draft",
+			Description: "Desc",
+			IsDraft:     true,
+			Categories:  []string{"Music"},
+		}
+
+		By("inserting a draft")
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+		Expect(eventTypes(ctx, all)).To(BeEmpty())
+
+		By("publishing the draft")
+		ev.IsDraft = false
+		Expect(model.UpdateEvent(ctx, db, ev)).To(Succeed())
+
+		By("updating the published event")
+		ev.Title = "Updated"
+		Expect(model.UpdateEvent(ctx, db, ev)).To(Succeed())
+
+		By("withdrawing the event to drafts")
+		ev.IsDraft = true
+		Expect(model.UpdateEvent(ctx, db, ev)).To(Succeed())
+
+		By("deleting the draft")
+		Expect(model.DeleteEvent(ctx, db, ev)).To(Succeed())
+
+		Expect(eventTypes(ctx, all)).To(HaveExactElements(
+			domain.WebhookPublished,
+			domain.WebhookUpdated,
+			domain.WebhookUnpublished,
+		))
+		Expect(eventTypes(ctx, published)).To(HaveExactElements(
+			domain.WebhookPublished,
+		))
+
+		deliveries := Must(model.ListWebhookDeliveries(ctx, db, all.ID, 100))
+		Expect(deliveries).To(HaveLen(3))
+
+		payload := domain.WebhookPayload{}
+		Expect(json.Unmarshal(deliveries[1].Payload, &payload)).To(Succeed())
+		Expect(payload.Type).To(Equal(domain.WebhookUpdated))
+		Expect(payload.Event).To(SatisfyAll(
+			HaveField("ID", Equal(ev.ID.String())),
+			HaveField("Title", Equal("Updated")),
+			HaveField("Categories", HaveExactElements("Music")),
+		))
+	})
+
+	Specify("deleting a published event is queued", func(ctx SpecContext) {
+		ev := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().Add(24 * time.Hour),
+			Title:       "Event",
+			Description: "Desc",
+		}
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+		Expect(model.DeleteEvent(ctx, db, ev)).To(Succeed())
+
+		Expect(eventTypes(ctx, all)).To(HaveExactElements(
+			domain.WebhookPublished,
+			domain.WebhookDeleted,
+		))
+	})
+
+	Specify("scheduled publishing is queued", func(ctx SpecContext) {
+		ev := &domain.Event{
+			ID:          snowflake.Generate(),
+			StartAt:     time.Now().Add(24 * time.Hour),
+			Title:       "Scheduled",
+			Description: "Desc",
+			IsDraft:     true,
+			PublishAt:   time.Now().Add(-time.Minute),
+		}
+
+		Expect(model.InsertEvent(ctx, db, ev)).To(Succeed())
+		Expect(Must(model.PublishScheduledEvents(ctx, db, time.Now()))).To(HaveExactElements(ev.ID))
+
+		Expect(eventTypes(ctx, published)).To(HaveExactElements(domain.WebhookPublished))
+	})
+
+	Specify("failed delivery is retried later", func(ctx SpecContext) {
+		now := time.Now()
+
+		d := &domain.WebhookDelivery{
+			WebhookID: all.ID,
+			EventType: domain.WebhookPing,
+			Payload:   []byte("{}"),
+		}
+		Expect(model.InsertWebhookDelivery(ctx, db, d)).To(Succeed())
+		Expect(Must(model.ListDueWebhookDeliveries(ctx, db, now, 10))).To(HaveLen(1))
+
+		d.SetFailed(http.StatusBadGateway, errors.New("502 Bad Gateway"), now)
+		Expect(model.UpdateWebhookDelivery(ctx, db, d)).To(Succeed())
+
+		Expect(Must(model.ListDueWebhookDeliveries(ctx, db, now, 10))).To(BeEmpty())
+		Expect(Must(model.ListDueWebhookDeliveries(ctx, db, now.Add(time.Minute), 10))).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("Attempts", Equal(1)),
+				HaveField("ResponseCode", Equal(http.StatusBadGateway)),
+				HaveField("LastError", Equal("502 Bad Gateway")),
+			),
+		))
+
+		d.SetDelivered(http.StatusOK, now.Add(time.Minute))
+		Expect(model.UpdateWebhookDelivery(ctx, db, d)).To(Succeed())
+		Expect(Must(model.ListDueWebhookDeliveries(ctx, db, now.Add(time.Hour), 10))).To(BeEmpty())
+
+		By("cleaning up old deliveries")
+		Expect(model.DeleteOldWebhookDeliveries(ctx, db, now.Add(time.Hour))).To(Succeed())
+		Expect(Must(model.ListWebhookDeliveries(ctx, db, all.ID, 10))).To(BeEmpty())
+	})
+
+	Specify("deleting a webhook deletes its deliveries", func(ctx SpecContext) {
+		Expect(model.InsertWebhookDelivery(ctx, db, &domain.WebhookDelivery{
+			WebhookID: all.ID,
+			EventType: domain.WebhookPing,
+			Payload:   []byte("{}"),
+		})).To(Succeed())
+
+		Expect(model.DeleteWebhook(ctx, db, all.ID)).To(Succeed())
+
+		Expect(Must(model.ListWebhooks(ctx, db))).To(HaveExactElements(Equal(published)))
+		Expect(Must(model.ListWebhookDeliveries(ctx, db, all.ID, 10))).To(BeEmpty())
+	})
+})
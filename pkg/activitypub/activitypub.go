@@ -0,0 +1,280 @@
+// Package activitypub implements the ActivityPub vocabulary and
+// a client for delivering signed activities to remote inboxes.
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/pkg/httpsig"
+)
+
+// ContentType is the content type of ActivityPub documents.
+const ContentType = "application/activity+json"
+
+// Public is the special collection addressing everyone.
+const Public = "https://www.w3.org/ns/activitystreams#Public"
+
+// Context is the JSON-LD context of documents.
+var Context = []string{
+	"https://www.w3.org/ns/activitystreams",
+	"https://w3id.org/security/v1",
+}
+
+// Actor is an ActivityPub actor.
+type Actor struct {
+	Context           any        `json:"@context,omitempty"`
+	ID                string     `json:"id"`
+	Type              string     `json:"type"`
+	PreferredUsername string     `json:"preferredUsername,omitempty"`
+	Name              string     `json:"name,omitempty"`
+	Summary           string     `json:"summary,omitempty"`
+	URL               string     `json:"url,omitempty"`
+	Inbox             string     `json:"inbox"`
+	Outbox            string     `json:"outbox,omitempty"`
+	Followers         string     `json:"followers,omitempty"`
+	Endpoints         *Endpoints `json:"endpoints,omitempty"`
+	PublicKey         *PublicKey `json:"publicKey,omitempty"`
+	Icon              *Image     `json:"icon,omitempty"`
+}
+
+// SharedInbox returns the shared inbox of the actor's server or the actor's inbox.
+func (a *Actor) SharedInbox() string {
+	if a.Endpoints != nil && a.Endpoints.SharedInbox != "" {
+		return a.Endpoints.SharedInbox
+	}
+
+	return a.Inbox
+}
+
+// Endpoints are the additional endpoints of an actor.
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+// PublicKey is the signing key of an actor.
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Image is an image attachment.
+type Image struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType,omitempty"`
+	URL       string `json:"url"`
+}
+
+// Activity is an ActivityPub activity.
+type Activity struct {
+	Context   any        `json:"@context,omitempty"`
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Actor     string     `json:"actor"`
+	Published *time.Time `json:"published,omitempty"`
+	To        []string   `json:"to,omitempty"`
+	Cc        []string   `json:"cc,omitempty"`
+
+	// Object is an object or an object ID.
+	Object any `json:"object"`
+}
+
+// ObjectID returns the ID of the object of a decoded activity.
+func (a *Activity) ObjectID() string {
+	switch obj := a.Object.(type) {
+	case string:
+		return obj
+	case map[string]any:
+		id, _ := obj["id"].(string)
+		return id
+	default:
+		return ""
+	}
+}
+
+// ObjectType returns the type of the object of a decoded activity.
+// It is empty when the object is an ID.
+func (a *Activity) ObjectType() string {
+	if obj, ok := a.Object.(map[string]any); ok {
+		t, _ := obj["type"].(string)
+		return t
+	}
+
+	return ""
+}
+
+// Event is an event object.
+type Event struct {
+	Context      any       `json:"@context,omitempty"`
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	Name         string    `json:"name"`
+	Content      string    `json:"content"`
+	MediaType    string    `json:"mediaType"`
+	URL          string    `json:"url"`
+	StartTime    time.Time `json:"startTime"`
+	Timezone     string    `json:"timezone,omitempty"`
+	Published    time.Time `json:"published"`
+	AttributedTo string    `json:"attributedTo"`
+	To           []string  `json:"to"`
+	Cc           []string  `json:"cc,omitempty"`
+	Location     *Place    `json:"location,omitempty"`
+	Tag          []Tag     `json:"tag,omitempty"`
+	Language     string    `json:"contentLanguage,omitempty"`
+}
+
+// Place is the location of an event.
+type Place struct {
+	Type      string  `json:"type"`
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
+
+// Tag is a hashtag.
+type Tag struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// Tombstone replaces a deleted object.
+type Tombstone struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// OrderedCollection is an ordered collection.
+type OrderedCollection struct {
+	Context      any    `json:"@context,omitempty"`
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	TotalItems   int    `json:"totalItems"`
+	OrderedItems []any  `json:"orderedItems,omitempty"`
+}
+
+// WebFinger is a WebFinger resource descriptor.
+type WebFinger struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases,omitempty"`
+	Links   []WebFingerLink `json:"links"`
+}
+
+// WebFingerLink is a link of a WebFinger resource.
+type WebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+// Client fetches and delivers signed ActivityPub documents on behalf of an actor.
+type Client struct {
+	client *http.Client
+	keyID  string
+	key    *rsa.PrivateKey
+}
+
+// FetchActor fetches a remote actor. The ID may contain a key fragment.
+// The actor must be hosted on the origin of the ID so that a server
+// can't publish keys for actors of other servers.
+func (c *Client) FetchActor(ctx context.Context, id string) (*Actor, error) {
+	id, _, _ = strings.Cut(id, "#")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, id, nil)
+	if err != nil {
+		return nil, calendar.InvalidValue.New("Invalid actor ID", err)
+	}
+
+	req.Header.Set("Accept", ContentType)
+
+	if err := httpsig.Sign(req, c.keyID, c.key, nil); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching actor %s: %s", id, resp.Status)
+	}
+
+	actor := &Actor{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(actor); err != nil {
+		return nil, fmt.Errorf("decoding actor %s: %w", id, err)
+	}
+
+	if !sameOrigin(actor.ID, id) {
+		return nil, fmt.Errorf("actor %s is not hosted at %s", actor.ID, id)
+	}
+
+	return actor, nil
+}
+
+// Post delivers a signed document to an inbox. Responses other than 2xx are errors.
+func (c *Client) Post(ctx context.Context, inbox string, doc any) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", ContentType)
+
+	if err := httpsig.Sign(req, c.keyID, c.key, body); err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("posting to %s: %s", inbox, resp.Status)
+	}
+
+	return nil
+}
+
+// NewClient creates a new client which signs requests with the key of an actor.
+func NewClient(client *http.Client, keyID string, key *rsa.PrivateKey) *Client {
+	return &Client{
+		client: client,
+		keyID:  keyID,
+		key:    key,
+	}
+}
+
+// sameOrigin reports whether two absolute URLs have the same scheme and host.
+func sameOrigin(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil || ua.Host == "" {
+		return false
+	}
+
+	ub, err := url.Parse(b)
+	if err != nil || ub.Host == "" {
+		return false
+	}
+
+	return strings.EqualFold(ua.Scheme, ub.Scheme) && strings.EqualFold(ua.Host, ub.Host)
+}
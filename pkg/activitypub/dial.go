@@ -0,0 +1,59 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// NewPublicHTTPClient returns an HTTP client which refuses to connect to
+// loopback, private and link-local addresses. Remote servers choose the
+// addresses of actors and inboxes, so they must not reach the internal network.
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			// The address is resolved, so the check covers DNS rebinding.
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			if !isPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("refusing to connect to non-public address %s", addrPort.Addr())
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// isPublicAddr reports whether addr is a public unicast address.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsValid() &&
+		addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
@@ -0,0 +1,145 @@
+// Package blobstore stores files on disk addressed by the SHA-256 hash of their content.
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrInvalidHash is returned for malformed hashes.
+var ErrInvalidHash = errors.New("invalid hash")
+
+// Blob is a stored file.
+type Blob struct {
+	Hash    string
+	ModTime time.Time
+}
+
+// Store is a content-addressed file store.
+type Store struct {
+	dir string
+}
+
+// Put stores data and returns its hash. Storing the same data again is a no-op.
+func (s *Store) Put(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	path := s.path(hash)
+
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so that readers never see a partial file.
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// Open opens a stored file.
+func (s *Store) Open(hash string) (*os.File, error) {
+	if !validHash(hash) {
+		return nil, ErrInvalidHash
+	}
+
+	return os.Open(s.path(hash))
+}
+
+// Delete deletes a stored file. Deleting a missing file is not an error.
+func (s *Store) Delete(hash string) error {
+	if !validHash(hash) {
+		return ErrInvalidHash
+	}
+
+	if err := os.Remove(s.path(hash)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// List lists all stored files.
+func (s *Store) List() ([]Blob, error) {
+	var blobs []Blob
+
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() || !validHash(d.Name()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		blobs = append(blobs, Blob{
+			Hash:    d.Name(),
+			ModTime: info.ModTime(),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing blobs: %w", err)
+	}
+
+	return blobs, nil
+}
+
+// path returns the file path of a hash. Files are spread
+// into subdirectories by the first two characters of the hash.
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash)
+}
+
+func validHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(hash)
+
+	return err == nil
+}
+
+// New creates a new store in dir.
+func New(dir string) *Store {
+	return &Store{
+		dir: dir,
+	}
+}
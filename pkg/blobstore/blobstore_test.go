@@ -0,0 +1,50 @@
+package blobstore_test
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/mgnsk/calendar/pkg/blobstore"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("blob store", func() {
+	var store *blobstore.Store
+
+	BeforeEach(func() {
+		store = blobstore.New(GinkgoT().TempDir())
+	})
+
+	Specify("files are stored by content hash", func() {
+		hash := Must(store.Put([]byte("poster")))
+		Expect(hash).To(Equal("293b9207228b7854bc3ccb2959ebea1583e066d41983124a5b381d6fdf6575f8"))
+
+		By("storing the same content again", func() {
+			Expect(store.Put([]byte("poster"))).To(Equal(hash))
+		})
+
+		f := Must(store.Open(hash))
+		defer f.Close()
+		Expect(io.ReadAll(f)).To(Equal([]byte("poster")))
+
+		Expect(store.List()).To(HaveExactElements(HaveField("Hash", hash)))
+	})
+
+	Specify("files are deleted", func() {
+		hash := Must(store.Put([]byte("program")))
+
+		Expect(store.Delete(hash)).To(Succeed())
+		Expect(store.Delete(hash)).To(Succeed())
+
+		_, err := store.Open(hash)
+		Expect(err).To(MatchError(fs.ErrNotExist))
+		Expect(store.List()).To(BeEmpty())
+	})
+
+	Specify("invalid hashes are rejected", func() {
+		_, err := store.Open("../../etc/passwd")
+		Expect(err).To(MatchError(blobstore.ErrInvalidHash))
+	})
+})
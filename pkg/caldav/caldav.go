@@ -0,0 +1,302 @@
+// Package caldav implements the WebDAV and CalDAV XML vocabulary
+// used by calendar clients.
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/mgnsk/calendar"
+)
+
+// XML namespaces.
+const (
+	NamespaceDAV            = "DAV:"
+	NamespaceCalDAV         = "urn:ietf:params:xml:ns:caldav"
+	NamespaceCalendarServer = "http://calendarserver.org/ns/"
+)
+
+// Property names.
+var (
+	ResourceType                  = xml.Name{Space: NamespaceDAV, Local: "resourcetype"}
+	DisplayName                   = xml.Name{Space: NamespaceDAV, Local: "displayname"}
+	GetETag                       = xml.Name{Space: NamespaceDAV, Local: "getetag"}
+	GetContentType                = xml.Name{Space: NamespaceDAV, Local: "getcontenttype"}
+	SyncToken                     = xml.Name{Space: NamespaceDAV, Local: "sync-token"}
+	Owner                         = xml.Name{Space: NamespaceDAV, Local: "owner"}
+	CurrentUserPrincipal          = xml.Name{Space: NamespaceDAV, Local: "current-user-principal"}
+	CurrentUserPrivilegeSet       = xml.Name{Space: NamespaceDAV, Local: "current-user-privilege-set"}
+	PrincipalURL                  = xml.Name{Space: NamespaceDAV, Local: "principal-URL"}
+	SupportedReportSet            = xml.Name{Space: NamespaceDAV, Local: "supported-report-set"}
+	CalendarHomeSet               = xml.Name{Space: NamespaceCalDAV, Local: "calendar-home-set"}
+	CalendarData                  = xml.Name{Space: NamespaceCalDAV, Local: "calendar-data"}
+	SupportedCalendarComponentSet = xml.Name{Space: NamespaceCalDAV, Local: "supported-calendar-component-set"}
+	GetCTag                       = xml.Name{Space: NamespaceCalendarServer, Local: "getctag"}
+)
+
+// Report names.
+var (
+	CalendarQuery    = xml.Name{Space: NamespaceCalDAV, Local: "calendar-query"}
+	CalendarMultiget = xml.Name{Space: NamespaceCalDAV, Local: "calendar-multiget"}
+	SyncCollection   = xml.Name{Space: NamespaceDAV, Local: "sync-collection"}
+)
+
+// Property is a property or a nested element of a property.
+type Property struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []Property `xml:",any"`
+}
+
+// NewProperty creates a new property with a text value.
+func NewProperty(name xml.Name, text string) Property {
+	return Property{
+		XMLName: name,
+		Text:    text,
+	}
+}
+
+// NewParentProperty creates a new property with nested elements.
+func NewParentProperty(name xml.Name, children ...Property) Property {
+	return Property{
+		XMLName:  name,
+		Children: children,
+	}
+}
+
+// NewElement creates a new element in the namespace of a property.
+func NewElement(space, local string, children ...Property) Property {
+	return NewParentProperty(xml.Name{Space: space, Local: local}, children...)
+}
+
+// Href creates a new href element.
+func Href(href string) Property {
+	return NewProperty(xml.Name{Space: NamespaceDAV, Local: "href"}, href)
+}
+
+// Multistatus is a multi-status response body.
+type Multistatus struct {
+	XMLName   xml.Name   `xml:"DAV: multistatus"`
+	Responses []Response `xml:"response"`
+	SyncToken string     `xml:"sync-token,omitempty"`
+}
+
+// Response is the status of a resource in a multi-status response.
+type Response struct {
+	Href     string     `xml:"href"`
+	Propstat []Propstat `xml:"propstat,omitempty"`
+	Status   string     `xml:"status,omitempty"`
+}
+
+// Propstat is a group of properties with the same status.
+type Propstat struct {
+	Prop   Prop   `xml:"prop"`
+	Status string `xml:"status"`
+}
+
+// Prop is a list of properties.
+type Prop struct {
+	Properties []Property `xml:",any"`
+}
+
+// Error is an error response body with a precondition or postcondition.
+type Error struct {
+	XMLName   xml.Name `xml:"DAV: error"`
+	Condition Property
+}
+
+// Status returns the status line of an HTTP status code.
+func Status(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+// NewResponse creates the response of a resource with the requested properties.
+// All properties are included when names is nil.
+// Requested properties the resource does not have are reported as not found.
+func NewResponse(href string, props []Property, names []xml.Name) Response {
+	resp := Response{Href: href}
+
+	if names == nil {
+		resp.Propstat = append(resp.Propstat, Propstat{
+			Prop:   Prop{Properties: props},
+			Status: Status(http.StatusOK),
+		})
+
+		return resp
+	}
+
+	var found, missing []Property
+
+	for _, name := range names {
+		i := slices.IndexFunc(props, func(p Property) bool {
+			return p.XMLName == name
+		})
+
+		if i >= 0 {
+			found = append(found, props[i])
+		} else {
+			missing = append(missing, Property{XMLName: name})
+		}
+	}
+
+	if len(found) > 0 {
+		resp.Propstat = append(resp.Propstat, Propstat{
+			Prop:   Prop{Properties: found},
+			Status: Status(http.StatusOK),
+		})
+	}
+
+	if len(missing) > 0 {
+		resp.Propstat = append(resp.Propstat, Propstat{
+			Prop:   Prop{Properties: missing},
+			Status: Status(http.StatusNotFound),
+		})
+	}
+
+	return resp
+}
+
+// PropNames is a list of requested property names.
+type PropNames []xml.Name
+
+// UnmarshalXML decodes the names of the child elements of a prop element.
+func (p *PropNames) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Propfind is a PROPFIND request body.
+type Propfind struct {
+	XMLName  xml.Name  `xml:"DAV: propfind"`
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     PropNames `xml:"DAV: prop"`
+}
+
+// Names returns the requested property names or nil for all properties.
+func (p *Propfind) Names() []xml.Name {
+	if p.AllProp != nil || p.PropName != nil || len(p.Prop) == 0 {
+		return nil
+	}
+
+	return p.Prop
+}
+
+// ParsePropfind parses a PROPFIND request body. An empty body requests all properties.
+func ParsePropfind(r io.Reader) (*Propfind, error) {
+	p := &Propfind{}
+
+	if err := xml.NewDecoder(r).Decode(p); err != nil {
+		if errors.Is(err, io.EOF) {
+			return p, nil
+		}
+
+		return nil, calendar.InvalidValue.New("Invalid PROPFIND body", err)
+	}
+
+	return p, nil
+}
+
+// TimeRange is a time range filter.
+type TimeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+// CompFilter is a calendar component filter.
+type CompFilter struct {
+	Name        string       `xml:"name,attr"`
+	TimeRange   *TimeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilters []CompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// Filter is a calendar query filter.
+type Filter struct {
+	CompFilter CompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// Report is a REPORT request body.
+type Report struct {
+	XMLName   xml.Name
+	AllProp   *struct{} `xml:"DAV: allprop"`
+	Prop      PropNames `xml:"DAV: prop"`
+	Hrefs     []string  `xml:"DAV: href"`
+	SyncToken string    `xml:"DAV: sync-token"`
+	Filter    *Filter   `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// Names returns the requested property names or nil for all properties.
+func (r *Report) Names() []xml.Name {
+	if r.AllProp != nil || len(r.Prop) == 0 {
+		return nil
+	}
+
+	return r.Prop
+}
+
+// TimeRange returns the event time range of a calendar query.
+// Zero times mean the range is open.
+func (r *Report) TimeRange() (start, end time.Time, err error) {
+	if r.Filter == nil {
+		return
+	}
+
+	for _, f := range r.Filter.CompFilter.CompFilters {
+		if f.Name != "VEVENT" || f.TimeRange == nil {
+			continue
+		}
+
+		if f.TimeRange.Start != "" {
+			if start, err = time.Parse(timeLayout, f.TimeRange.Start); err != nil {
+				return time.Time{}, time.Time{}, calendar.InvalidValue.New("Invalid time range", err)
+			}
+		}
+
+		if f.TimeRange.End != "" {
+			if end, err = time.Parse(timeLayout, f.TimeRange.End); err != nil {
+				return time.Time{}, time.Time{}, calendar.InvalidValue.New("Invalid time range", err)
+			}
+		}
+	}
+
+	return start, end, nil
+}
+
+const timeLayout = "20060102T150405Z"
+
+// ParseReport parses a REPORT request body.
+func ParseReport(r io.Reader) (*Report, error) {
+	report := &Report{}
+
+	if err := xml.NewDecoder(r).Decode(report); err != nil {
+		return nil, calendar.InvalidValue.New("Invalid REPORT body", err)
+	}
+
+	switch report.XMLName {
+	case CalendarQuery, CalendarMultiget, SyncCollection:
+		return report, nil
+	default:
+		return nil, calendar.InvalidValue.New("Unsupported report")
+	}
+}
@@ -0,0 +1,74 @@
+package caldav_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/pkg/caldav"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CalDAV", func() {
+	Specify("empty PROPFIND requests all properties", func() {
+		p := Must(caldav.ParsePropfind(strings.NewReader("")))
+		Expect(p.Names()).To(BeNil())
+	})
+
+	Specify("PROPFIND property names are parsed", func() {
+		p := Must(caldav.ParsePropfind(strings.NewReader(`<?xml version="1.0"?>
+<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/">
+  <D:prop><D:displayname/><CS:getctag/></D:prop>
+</D:propfind>`)))
+
+		Expect(p.Names()).To(HaveExactElements(caldav.DisplayName, caldav.GetCTag))
+	})
+
+	Specify("missing properties are reported as not found", func() {
+		resp := caldav.NewResponse("/calendar/", []caldav.Property{
+			caldav.NewProperty(caldav.DisplayName, "Calendar"),
+		}, []xml.Name{caldav.DisplayName, caldav.GetCTag})
+
+		Expect(resp.Propstat).To(HaveExactElements(
+			SatisfyAll(
+				HaveField("Status", caldav.Status(http.StatusOK)),
+				HaveField("Prop.Properties", HaveExactElements(HaveField("Text", "Calendar"))),
+			),
+			SatisfyAll(
+				HaveField("Status", caldav.Status(http.StatusNotFound)),
+				HaveField("Prop.Properties", HaveExactElements(HaveField("XMLName", caldav.GetCTag))),
+			),
+		))
+	})
+
+	Specify("calendar query time range is parsed", func() {
+		report := Must(caldav.ParseReport(strings.NewReader(`<?xml version="1.0"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/></D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="20300101T000000Z" end="20300201T000000Z"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`)))
+
+		Expect(report.XMLName).To(Equal(caldav.CalendarQuery))
+		Expect(report.Names()).To(HaveExactElements(caldav.GetETag))
+
+		start, end, err := report.TimeRange()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(start).To(Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(end).To(Equal(time.Date(2030, 2, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	Specify("unsupported report is invalid", func() {
+		_, err := caldav.ParseReport(strings.NewReader(`<D:expand-property xmlns:D="DAV:"/>`))
+		Expect(err).To(MatchError(calendar.InvalidValue))
+	})
+})
@@ -0,0 +1,247 @@
+// Package httpsig implements rsa-sha256 HTTP message signatures
+// as used by ActivityPub servers (draft-cavage-http-signatures).
+package httpsig
+
+import (
+	"cmp"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+)
+
+// MaxClockSkew is the maximum difference between the Date header of a signed request and the current time.
+const MaxClockSkew = time.Hour
+
+// Sign signs a request with the private key. It sets the Date header and
+// the Digest header of the body. The body must be the request body or nil for requests without a body.
+func Sign(r *http.Request, keyID string, key *rsa.PrivateKey, body []byte) error {
+	headers := []string{"(request-target)", "host", "date"}
+
+	r.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+	if body != nil {
+		r.Header.Set("Digest", Digest(body))
+		headers = append(headers, "digest")
+	}
+
+	hash := sha256.Sum256([]byte(signingString(r, headers)))
+
+	sig, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID,
+		strings.Join(headers, " "),
+		base64.StdEncoding.EncodeToString(sig),
+	))
+
+	return nil
+}
+
+// KeyID returns the key ID of a signed request.
+func KeyID(r *http.Request) (string, error) {
+	params, err := parseSignature(r.Header.Get("Signature"))
+	if err != nil {
+		return "", err
+	}
+
+	return params["keyId"], nil
+}
+
+// Verify verifies the signature of a request with the public key.
+// Requests with a body must sign the Digest header of the body.
+func Verify(r *http.Request, key *rsa.PublicKey, body []byte, now time.Time) error {
+	params, err := parseSignature(r.Header.Get("Signature"))
+	if err != nil {
+		return err
+	}
+
+	switch params["algorithm"] {
+	case "", "rsa-sha256", "hs2019":
+	default:
+		return calendar.Forbidden.New("Unsupported signature algorithm")
+	}
+
+	headers := strings.Fields(strings.ToLower(cmp.Or(params["headers"], "date")))
+
+	if !slices.Contains(headers, "(request-target)") || !slices.Contains(headers, "date") {
+		return calendar.Forbidden.New("Signature must include the request target and date")
+	}
+
+	date, err := http.ParseTime(r.Header.Get("Date"))
+	if err != nil {
+		return calendar.Forbidden.New("Invalid date", err)
+	}
+
+	if d := now.Sub(date); d > MaxClockSkew || d < -MaxClockSkew {
+		return calendar.Forbidden.New("Signature expired")
+	}
+
+	if len(body) > 0 {
+		if !slices.Contains(headers, "digest") {
+			return calendar.Forbidden.New("Signature must include the digest")
+		}
+
+		if r.Header.Get("Digest") != Digest(body) {
+			return calendar.Forbidden.New("Digest mismatch")
+		}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return calendar.Forbidden.New("Invalid signature", err)
+	}
+
+	hash := sha256.Sum256([]byte(signingString(r, headers)))
+
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig); err != nil {
+		return calendar.Forbidden.New("Invalid signature", err)
+	}
+
+	return nil
+}
+
+// Digest returns the Digest header value of a body.
+func Digest(body []byte) string {
+	hash := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// EncodePrivateKey encodes a private key in PEM format.
+func EncodePrivateKey(key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: must(x509.MarshalPKCS8PrivateKey(key)),
+	}))
+}
+
+// DecodePrivateKey decodes a PEM encoded private key.
+func DecodePrivateKey(s string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, calendar.InvalidValue.New("Invalid private key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, calendar.InvalidValue.New("Invalid private key", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, calendar.InvalidValue.New("Private key must be an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+// EncodePublicKey encodes a public key in PEM format.
+func EncodePublicKey(key *rsa.PublicKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: must(x509.MarshalPKIXPublicKey(key)),
+	}))
+}
+
+// DecodePublicKey decodes a PEM encoded public key.
+func DecodePublicKey(s string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, calendar.InvalidValue.New("Invalid public key")
+	}
+
+	var key any
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		k, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, calendar.InvalidValue.New("Invalid public key", err)
+		}
+		key = k
+
+	default:
+		k, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, calendar.InvalidValue.New("Invalid public key", err)
+		}
+		key = k
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, calendar.InvalidValue.New("Public key must be an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+// signingString builds the string to sign from the signed headers.
+func signingString(r *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+
+	for _, h := range headers {
+		switch h {
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(r.Method), r.URL.RequestURI()))
+
+		case "host":
+			host := r.Host
+			if host == "" {
+				host = r.URL.Host
+			}
+			lines = append(lines, "host: "+host)
+
+		default:
+			lines = append(lines, h+": "+strings.Join(r.Header.Values(h), ", "))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// parseSignature parses the parameters of a Signature header.
+func parseSignature(header string) (map[string]string, error) {
+	params := map[string]string{}
+
+	for header != "" {
+		key, rest, ok := strings.Cut(header, "=")
+		if !ok || !strings.HasPrefix(rest, `"`) {
+			return nil, calendar.Forbidden.New("Invalid signature header")
+		}
+
+		value, rest, ok := strings.Cut(rest[1:], `"`)
+		if !ok {
+			return nil, calendar.Forbidden.New("Invalid signature header")
+		}
+
+		params[strings.TrimSpace(key)] = value
+		header = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+
+	if params["keyId"] == "" || params["signature"] == "" {
+		return nil, calendar.Forbidden.New("Missing signature")
+	}
+
+	return params, nil
+}
+
+func must[V any](v V, err error) V {
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
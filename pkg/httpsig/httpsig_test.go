@@ -0,0 +1,73 @@
+package httpsig_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/pkg/httpsig"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTP signatures", func() {
+	var key *rsa.PrivateKey
+
+	BeforeEach(func() {
+		key = Must(rsa.GenerateKey(rand.Reader, 2048))
+	})
+
+	newRequest := func(body []byte) *http.Request {
+		r := Must(http.NewRequest(http.MethodPost, "https://remote.testing/inbox?x=1", bytes.NewReader(body)))
+		Expect(httpsig.Sign(r, "https://local.testing/actor#main-key", key, body)).To(Succeed())
+
+		return r
+	}
+
+	Specify("signed request is verified", func() {
+		body := []byte(`{"type":"Follow"}`)
+		r := newRequest(body)
+
+		Expect(Must(httpsig.KeyID(r))).To(Equal("https://local.testing/actor#main-key"))
+		Expect(r.Header.Get("Signature")).To(ContainSubstring(`headers="(request-target) host date digest"`))
+		Expect(httpsig.Verify(r, &key.PublicKey, body, time.Now())).To(Succeed())
+	})
+
+	Specify("tampered body is rejected", func() {
+		r := newRequest([]byte(`{"type":"Follow"}`))
+
+		Expect(httpsig.Verify(r, &key.PublicKey, []byte(`{"type":"Undo"}`), time.Now())).To(MatchError(calendar.Forbidden))
+	})
+
+	Specify("tampered header is rejected", func() {
+		body := []byte(`{"type":"Follow"}`)
+		r := newRequest(body)
+		r.URL.Path = "/other"
+
+		Expect(httpsig.Verify(r, &key.PublicKey, body, time.Now())).To(MatchError(calendar.Forbidden))
+	})
+
+	Specify("signature of another key is rejected", func() {
+		body := []byte(`{"type":"Follow"}`)
+		r := newRequest(body)
+		other := Must(rsa.GenerateKey(rand.Reader, 2048))
+
+		Expect(httpsig.Verify(r, &other.PublicKey, body, time.Now())).To(MatchError(calendar.Forbidden))
+	})
+
+	Specify("expired signature is rejected", func() {
+		body := []byte(`{"type":"Follow"}`)
+		r := newRequest(body)
+
+		Expect(httpsig.Verify(r, &key.PublicKey, body, time.Now().Add(2*time.Hour))).To(MatchError(calendar.Forbidden))
+	})
+
+	Specify("keys are encoded", func() {
+		Expect(Must(httpsig.DecodePrivateKey(httpsig.EncodePrivateKey(key)))).To(Equal(key))
+		Expect(Must(httpsig.DecodePublicKey(httpsig.EncodePublicKey(&key.PublicKey)))).To(Equal(&key.PublicKey))
+	})
+})
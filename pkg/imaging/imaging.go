@@ -0,0 +1,220 @@
+// Package imaging resizes uploaded images in pure Go.
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // Register gif decoder. Only the first frame of animations is decoded.
+	"image/jpeg"
+	"image/png"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Register webp decoder.
+)
+
+// MaxPixels is the maximum number of pixels of a decoded image.
+const MaxPixels = 50_000_000
+
+// ErrTooLarge is returned when the image dimensions exceed MaxPixels.
+var ErrTooLarge = errors.New("image dimensions too large")
+
+// Image is an encoded image.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Resize decodes a JPEG, PNG, GIF or WebP image and scales it down to fit
+// within maxSize x maxSize pixels. JPEG orientation is applied to the pixels.
+//
+// The result is re-encoded as PNG when the source is PNG or GIF or has
+// transparency and as JPEG otherwise. Metadata such as EXIF is not copied.
+func Resize(data []byte, maxSize int) (*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	img := scale(src, maxSize)
+
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	var buf bytes.Buffer
+
+	result := &Image{
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+
+	if format == "png" || format == "gif" || !img.Opaque() {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		result.ContentType = "image/png"
+	} else {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		result.ContentType = "image/jpeg"
+	}
+
+	result.Data = buf.Bytes()
+
+	return result, nil
+}
+
+// scale scales the image down to fit within maxSize x maxSize pixels.
+func scale(src image.Image, maxSize int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	if w > maxSize || h > maxSize {
+		if w >= h {
+			h = max(1, h*maxSize/w)
+			w = maxSize
+		} else {
+			w = max(1, w*maxSize/h)
+			h = maxSize
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	if w == b.Dx() && h == b.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	} else {
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	}
+
+	return dst
+}
+
+// orient transforms the image according to the EXIF orientation value.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+
+	var dst *image.RGBA
+	if orientation >= 5 {
+		// Width and height are swapped.
+		dst = image.NewRGBA(image.Rect(0, 0, h, w))
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, w, h))
+	}
+
+	for y := range h {
+		for x := range w {
+			var dx, dy int
+
+			switch orientation {
+			case 2: // Mirrored horizontally.
+				dx, dy = w-1-x, y
+			case 3: // Rotated 180.
+				dx, dy = w-1-x, h-1-y
+			case 4: // Mirrored vertically.
+				dx, dy = x, h-1-y
+			case 5: // Transposed.
+				dx, dy = y, x
+			case 6: // Rotated 90 clockwise.
+				dx, dy = h-1-y, x
+			case 7: // Transversed.
+				dx, dy = h-1-y, w-1-x
+			case 8: // Rotated 90 counter-clockwise.
+				dx, dy = y, w-1-x
+			}
+
+			dst.SetRGBA(dx, dy, src.RGBAAt(x, y))
+		}
+	}
+
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG image or 0 if not found.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 0
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 0
+		}
+
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of scan or end of image.
+			return 0
+		}
+
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 0
+		}
+
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+
+		i += 2 + size
+	}
+
+	return 0
+}
+
+// exifOrientation returns the orientation tag value of the first IFD of TIFF data.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+
+	for i := range count {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+
+	return 0
+}
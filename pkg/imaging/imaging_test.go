@@ -0,0 +1,105 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/mgnsk/calendar/pkg/imaging"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+
+	// Mark the top left corner.
+	for y := range h / 4 {
+		for x := range w / 4 {
+			img.Set(x, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+
+	return img
+}
+
+// withOrientation inserts an EXIF segment with the orientation tag after the JPEG SOI marker.
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	var buf bytes.Buffer
+	buf.Write(data[:2])
+	buf.Write([]byte{0xFF, 0xE1})
+	buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(segment)+2)))
+	buf.Write(segment)
+	buf.Write(data[2:])
+
+	return buf.Bytes()
+}
+
+var _ = Describe("resizing images", func() {
+	Specify("PNG is scaled down preserving aspect ratio", func() {
+		var buf bytes.Buffer
+		Expect(png.Encode(&buf, newImage(800, 400))).To(Succeed())
+
+		img := Must(imaging.Resize(buf.Bytes(), 200))
+		Expect(img.ContentType).To(Equal("image/png"))
+		Expect(img.Width).To(Equal(200))
+		Expect(img.Height).To(Equal(100))
+
+		decoded := Must(png.Decode(bytes.NewReader(img.Data)))
+		Expect(decoded.Bounds().Size()).To(Equal(image.Pt(200, 100)))
+	})
+
+	Specify("small images are not scaled up", func() {
+		var buf bytes.Buffer
+		Expect(jpeg.Encode(&buf, newImage(100, 50), nil)).To(Succeed())
+
+		img := Must(imaging.Resize(buf.Bytes(), 200))
+		Expect(img.ContentType).To(Equal("image/jpeg"))
+		Expect(img.Width).To(Equal(100))
+		Expect(img.Height).To(Equal(50))
+	})
+
+	Specify("EXIF orientation is applied and EXIF is stripped", func() {
+		var buf bytes.Buffer
+		Expect(jpeg.Encode(&buf, newImage(80, 40), nil)).To(Succeed())
+
+		data := withOrientation(buf.Bytes(), 6)
+		Expect(data).To(ContainSubstring("Exif"))
+
+		img := Must(imaging.Resize(data, 200))
+		Expect(img.Data).NotTo(ContainSubstring("Exif"))
+		Expect(img.Width).To(Equal(40))
+		Expect(img.Height).To(Equal(80))
+
+		decoded := Must(jpeg.Decode(bytes.NewReader(img.Data)))
+
+		By("asserting the top left corner was rotated to the top right", func() {
+			r, g, b, _ := decoded.At(35, 5).RGBA()
+			Expect(b).To(BeNumerically(">", r))
+			Expect(b).To(BeNumerically(">", g))
+		})
+	})
+
+	Specify("invalid images are rejected", func() {
+		_, err := imaging.Resize([]byte("not an image"), 200)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,210 @@
+// Package mailer sends email over SMTP.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the SMTP client configuration.
+type Config struct {
+	Host string
+	Port int
+
+	// Username and Password are used for PLAIN authentication when Username is set.
+	Username string
+	Password string
+
+	// From is the sender address, optionally with a name.
+	From string
+}
+
+// Message is an email message with text and HTML alternatives.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Client is an SMTP client.
+type Client struct {
+	cfg Config
+}
+
+// NewClient creates a new SMTP client.
+func NewClient(cfg Config) *Client {
+	return &Client{
+		cfg: cfg,
+	}
+}
+
+// Send sends a message. Port 465 uses implicit TLS, other ports use
+// STARTTLS when the server supports it.
+func (c *Client) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(c.cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid to address: %w", err)
+	}
+
+	data, err := compose(from, to, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port)))
+	if err != nil {
+		return err
+	}
+
+	// Abort the conversation when the context is done.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	if c.cfg.Port == 465 {
+		conn = tls.Client(conn, &tls.Config{ServerName: c.cfg.Host})
+	}
+
+	client, err := smtp.NewClient(conn, c.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.cfg.Host}); err != nil {
+			return err
+		}
+	}
+
+	if c.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// compose formats the message as a multipart/alternative MIME message.
+func compose(from, to *mail.Address, msg Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	mw := multipart.NewWriter(&buf)
+
+	// Header values must not contain line breaks.
+	subject := strings.Join(strings.Fields(msg.Subject), " ")
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", from.String())
+	header.Set("To", to.String())
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header.Set("Date", now.Format(time.RFC1123Z))
+	header.Set("Message-Id", messageID(from.Address))
+	header.Set("Mime-Version", "1.0")
+	header.Set("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{
+		"boundary": mw.Boundary(),
+	}))
+
+	var head bytes.Buffer
+
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-Id", "Mime-Version", "Content-Type"} {
+		fmt.Fprintf(&head, "%s: %s\r\n", key, header.Get(key))
+	}
+
+	head.WriteString("\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		if part.body == "" {
+			continue
+		}
+
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qw := quotedprintable.NewWriter(w)
+
+		if _, err := qw.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return append(head.Bytes(), buf.Bytes()...), nil
+}
+
+// messageID returns a random message ID in the domain of the address.
+func messageID(address string) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	domain := "localhost"
+	if i := strings.LastIndexByte(address, '@'); i >= 0 {
+		domain = address[i+1:]
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
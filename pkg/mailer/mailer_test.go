@@ -0,0 +1,94 @@
+package mailer_test
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+
+	"github.com/mgnsk/calendar/pkg/mailer"
+	"github.com/mgnsk/calendar/pkg/mailer/smtptest"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sending email", func() {
+	var (
+		srv    *smtptest.Server
+		client *mailer.Client
+	)
+
+	BeforeEach(func() {
+		srv = smtptest.NewServer()
+		DeferCleanup(srv.Close)
+
+		client = mailer.NewClient(mailer.Config{
+			Host: srv.Host,
+			Port: srv.Port,
+			From: "Calendar <calendar@calendar.testing>",
+		})
+	})
+
+	Specify("message is sent with text and HTML parts", func(ctx SpecContext) {
+		Expect(client.Send(ctx, mailer.Message{
+			To:      "user@calendar.testing",
+			Subject: "Tere tulemast\r\nBcc: injected@calendar.testing",
+			Text:    "Hello, world!",
+			HTML:    "<p>Hello, world!</p>",
+		})).To(Succeed())
+
+		messages := srv.Messages()
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0].From).To(Equal("calendar@calendar.testing"))
+		Expect(messages[0].To).To(HaveExactElements("user@calendar.testing"))
+
+		msg := Must(mail.ReadMessage(bytes.NewReader(messages[0].Data)))
+
+		var dec mime.WordDecoder
+		Expect(dec.DecodeHeader(msg.Header.Get("Subject"))).To(Equal("Tere tulemast Bcc: injected@calendar.testing"))
+		Expect(msg.Header.Get("From")).To(Equal(`"Calendar" <calendar@calendar.testing>`))
+		Expect(msg.Header.Get("Bcc")).To(BeEmpty())
+
+		mediaType, params := Must2(mime.ParseMediaType(msg.Header.Get("Content-Type")))
+		Expect(mediaType).To(Equal("multipart/alternative"))
+
+		r := multipart.NewReader(msg.Body, params["boundary"])
+
+		text := Must(r.NextPart())
+		Expect(text.Header.Get("Content-Type")).To(Equal("text/plain; charset=utf-8"))
+		Expect(string(Must(io.ReadAll(text)))).To(Equal("Hello, world!"))
+
+		html := Must(r.NextPart())
+		Expect(html.Header.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+		Expect(string(Must(io.ReadAll(html)))).To(Equal("<p>Hello, world!</p>"))
+	})
+
+	Specify("rejected recipient returns an error", func(ctx SpecContext) {
+		srv.SetFailing(true)
+
+		Expect(client.Send(ctx, mailer.Message{
+			To:      "user@calendar.testing",
+			Subject: "Subject",
+			Text:    "Text",
+		})).To(MatchError(ContainSubstring("451")))
+
+		Expect(srv.Messages()).To(BeEmpty())
+	})
+
+	Specify("invalid recipient returns an error", func(ctx SpecContext) {
+		Expect(client.Send(ctx, mailer.Message{
+			To:      "not an address",
+			Subject: "Subject",
+			Text:    "Text",
+		})).NotTo(Succeed())
+	})
+})
+
+func Must2[A, B any](a A, b B, err error) (A, B) {
+	GinkgoHelper()
+	Expect(err).NotTo(HaveOccurred())
+
+	return a, b
+}
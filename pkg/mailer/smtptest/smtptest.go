@@ -0,0 +1,173 @@
+// Package smtptest provides an in-process SMTP server for tests.
+package smtptest
+
+import (
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Message is a message received by the server.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Server is an SMTP server which stores the received messages in memory.
+// It supports neither TLS nor authentication.
+type Server struct {
+	Host string
+	Port int
+
+	ln      net.Listener
+	wg      sync.WaitGroup
+	failing atomic.Bool
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewServer starts a new server on a local port.
+func NewServer() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	addr := ln.Addr().(*net.TCPAddr)
+
+	s := &Server{
+		Host: addr.IP.String(),
+		Port: addr.Port,
+		ln:   ln,
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s
+}
+
+// Messages returns the received messages.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message{}, s.messages...)
+}
+
+// SetFailing configures the server to reject recipients with a temporary error.
+func (s *Server) SetFailing(failing bool) {
+	s.failing.Store(failing)
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	_ = s.ln.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+
+			s.handle(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (s *Server) handle(c *textproto.Conn) {
+	var msg Message
+
+	reply := func(code int, text string) bool {
+		return c.PrintfLine("%s %s", strconv.Itoa(code), text) == nil
+	}
+
+	if !reply(220, "smtptest ready") {
+		return
+	}
+
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply(250, "smtptest")
+
+		case "MAIL":
+			msg = Message{From: trimPath(arg, "FROM:")}
+			reply(250, "OK")
+
+		case "RCPT":
+			if s.failing.Load() {
+				reply(451, "Try again later")
+				continue
+			}
+			msg.To = append(msg.To, trimPath(arg, "TO:"))
+			reply(250, "OK")
+
+		case "DATA":
+			if !reply(354, "End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+
+			data, err := io.ReadAll(c.DotReader())
+			if err != nil {
+				return
+			}
+			msg.Data = data
+
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+
+			reply(250, "OK")
+
+		case "RSET":
+			msg = Message{}
+			reply(250, "OK")
+
+		case "NOOP":
+			reply(250, "OK")
+
+		case "QUIT":
+			reply(221, "Bye")
+			return
+
+		default:
+			reply(502, "Command not implemented")
+		}
+	}
+}
+
+// trimPath returns the address of a MAIL FROM or RCPT TO argument.
+func trimPath(arg, prefix string) string {
+	arg = strings.TrimSpace(arg)
+	if len(arg) >= len(prefix) && strings.EqualFold(arg[:len(prefix)], prefix) {
+		arg = arg[len(prefix):]
+	}
+
+	arg, _, _ = strings.Cut(arg, " ")
+
+	return strings.Trim(arg, "<>")
+}
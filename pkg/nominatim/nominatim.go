@@ -0,0 +1,161 @@
+// Package nominatim implements a client for the Nominatim geocoding API.
+package nominatim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"golang.org/x/time/rate"
+)
+
+// DefaultBaseURL is the public OpenStreetMap Nominatim instance.
+const DefaultBaseURL = "https://nominatim.openstreetmap.org"
+
+// UserAgent identifies the application as required by the Nominatim usage policy.
+const UserAgent = "Calendar - github.com/mgnsk/calendar"
+
+// Place is a geocoding result.
+type Place struct {
+	DisplayName string
+	OSMType     string
+	OSMID       uint64
+	Latitude    float64
+	Longitude   float64
+}
+
+// Client is a Nominatim API client.
+type Client struct {
+	baseURL string
+	client  *http.Client
+	limiter *rate.Limiter
+	limit   int
+}
+
+// Option configures the client.
+type Option func(*Client)
+
+// WithHTTPClient configures the HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithRateLimit configures the minimum interval between requests.
+// The public Nominatim instance allows at most one request per second.
+func WithRateLimit(interval time.Duration) Option {
+	return func(c *Client) {
+		c.limiter = rate.NewLimiter(rate.Every(interval), 1)
+	}
+}
+
+// WithResultLimit configures the maximum number of results.
+func WithResultLimit(limit int) Option {
+	return func(c *Client) {
+		c.limit = limit
+	}
+}
+
+// Search searches places by free-text query.
+// Language is an Accept-Language value for the result names.
+func (c *Client) Search(ctx context.Context, query, language string) ([]Place, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, calendar.Timeout.New("Geocoding rate limit exceeded", err)
+	}
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	u = u.JoinPath("search")
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("format", "jsonv2")
+	q.Set("limit", strconv.Itoa(c.limit))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", UserAgent)
+	if language != "" {
+		req.Header.Set("Accept-Language", language)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting geocoding results: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding request failed: %s", res.Status)
+	}
+
+	type result struct {
+		DisplayName string `json:"display_name"`
+		OSMType     string `json:"osm_type"`
+		OSMID       uint64 `json:"osm_id"`
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+	}
+
+	results := []result{}
+
+	if err := json.NewDecoder(res.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("error decoding geocoding results: %w", err)
+	}
+
+	places := make([]Place, 0, len(results))
+
+	for _, r := range results {
+		lat, err := strconv.ParseFloat(strings.TrimSpace(r.Lat), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude %q: %w", r.Lat, err)
+		}
+
+		lng, err := strconv.ParseFloat(strings.TrimSpace(r.Lon), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude %q: %w", r.Lon, err)
+		}
+
+		places = append(places, Place{
+			DisplayName: r.DisplayName,
+			OSMType:     r.OSMType,
+			OSMID:       r.OSMID,
+			Latitude:    lat,
+			Longitude:   lng,
+		})
+	}
+
+	return places, nil
+}
+
+// NewClient creates a new Nominatim client for a Nominatim-compatible API at baseURL.
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: baseURL,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		limiter: rate.NewLimiter(rate.Every(time.Second), 1),
+		limit:   5,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
@@ -0,0 +1,71 @@
+package nominatim_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/mgnsk/calendar"
+	"github.com/mgnsk/calendar/pkg/nominatim"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("searching places", func() {
+	var (
+		ts       *httptest.Server
+		requests atomic.Int64
+		lastReq  *http.Request
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			lastReq = r
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"display_name":"Sveta, Telliskivi 60a, Tallinn","osm_type":"node","osm_id":123,"lat":"59.4400","lon":"24.7300"}]`))
+		}))
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("results are decoded", func(ctx SpecContext) {
+		client := nominatim.NewClient(ts.URL)
+
+		places := Must(client.Search(ctx, "sveta tallinn", "et"))
+
+		Expect(places).To(HaveExactElements(
+			MatchAllFields(Fields{
+				"DisplayName": Equal("Sveta, Telliskivi 60a, Tallinn"),
+				"OSMType":     Equal("node"),
+				"OSMID":       Equal(uint64(123)),
+				"Latitude":    Equal(59.44),
+				"Longitude":   Equal(24.73),
+			}),
+		))
+
+		Expect(lastReq.URL.Path).To(Equal("/search"))
+		Expect(lastReq.URL.Query().Get("q")).To(Equal("sveta tallinn"))
+		Expect(lastReq.Header.Get("Accept-Language")).To(Equal("et"))
+		Expect(lastReq.Header.Get("User-Agent")).To(Equal(nominatim.UserAgent))
+	})
+
+	Specify("requests are rate limited", func(ctx SpecContext) {
+		client := nominatim.NewClient(ts.URL, nominatim.WithRateLimit(time.Hour))
+
+		Must(client.Search(ctx, "sveta", ""))
+
+		ctx2, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Search(ctx2, "sveta", "")
+		Expect(err).To(MatchError(calendar.Timeout))
+		Expect(requests.Load()).To(Equal(int64(1)))
+	})
+})
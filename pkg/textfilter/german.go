@@ -0,0 +1,180 @@
+package textfilter
+
+import (
+	"slices"
+	"strings"
+)
+
+// stemGerman implements the Snowball German stemming algorithm.
+// See https://snowballstem.org/algorithms/german/stemmer.html.
+func stemGerman(word string) string {
+	w := []rune(strings.ReplaceAll(word, "ß", "ss"))
+
+	// Put u and y between vowels into upper case.
+	for i := 1; i < len(w)-1; i++ {
+		if isGermanVowel(w[i-1]) && isGermanVowel(w[i+1]) {
+			switch w[i] {
+			case 'u':
+				w[i] = 'U'
+			case 'y':
+				w[i] = 'Y'
+			}
+		}
+	}
+
+	r1, r2 := len(w), len(w)
+	if len(w) >= 3 {
+		r1 = germanRegion(w, 0)
+		r2 = germanRegion(w, r1)
+		r1 = max(r1, 3)
+	}
+
+	w = germanStep1(w, r1)
+	w = germanStep2(w, r1)
+	w = germanStep3(w, r1, r2)
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'U':
+			return 'u'
+		case 'Y':
+			return 'y'
+		case 'ä':
+			return 'a'
+		case 'ö':
+			return 'o'
+		case 'ü':
+			return 'u'
+		default:
+			return r
+		}
+	}, string(w))
+}
+
+func germanStep1(w []rune, r1 int) []rune {
+	switch {
+	case hasRuneSuffix(w, "ern"):
+		if len(w)-3 >= r1 {
+			w = w[:len(w)-3]
+		}
+
+	case hasRuneSuffix(w, "em"), hasRuneSuffix(w, "er"):
+		if len(w)-2 >= r1 {
+			w = w[:len(w)-2]
+		}
+
+	case hasRuneSuffix(w, "en"), hasRuneSuffix(w, "es"), hasRuneSuffix(w, "e"):
+		n := 2
+		if hasRuneSuffix(w, "e") {
+			n = 1
+		}
+
+		if len(w)-n >= r1 {
+			w = w[:len(w)-n]
+			if hasRuneSuffix(w, "niss") {
+				w = w[:len(w)-1]
+			}
+		}
+
+	case hasRuneSuffix(w, "s"):
+		if len(w)-1 >= r1 && len(w) >= 2 && slices.Contains(germanSEndings, w[len(w)-2]) {
+			w = w[:len(w)-1]
+		}
+	}
+
+	return w
+}
+
+func germanStep2(w []rune, r1 int) []rune {
+	switch {
+	case hasRuneSuffix(w, "est"):
+		if len(w)-3 >= r1 {
+			w = w[:len(w)-3]
+		}
+
+	case hasRuneSuffix(w, "en"), hasRuneSuffix(w, "er"):
+		if len(w)-2 >= r1 {
+			w = w[:len(w)-2]
+		}
+
+	case hasRuneSuffix(w, "st"):
+		// The st-ending must itself be preceded by at least 3 letters.
+		if len(w)-2 >= r1 && len(w) >= 6 && slices.Contains(germanSTEndings, w[len(w)-3]) {
+			w = w[:len(w)-2]
+		}
+	}
+
+	return w
+}
+
+func germanStep3(w []rune, r1, r2 int) []rune {
+	precededByE := func(n int) bool {
+		return len(w) > n && w[len(w)-n-1] == 'e'
+	}
+
+	switch {
+	case hasRuneSuffix(w, "isch"):
+		if len(w)-4 >= r2 && !precededByE(4) {
+			w = w[:len(w)-4]
+		}
+
+	case hasRuneSuffix(w, "lich"), hasRuneSuffix(w, "heit"):
+		if len(w)-4 >= r2 {
+			w = w[:len(w)-4]
+			if (hasRuneSuffix(w, "er") || hasRuneSuffix(w, "en")) && len(w)-2 >= r1 {
+				w = w[:len(w)-2]
+			}
+		}
+
+	case hasRuneSuffix(w, "keit"):
+		if len(w)-4 >= r2 {
+			w = w[:len(w)-4]
+			switch {
+			case hasRuneSuffix(w, "lich") && len(w)-4 >= r2:
+				w = w[:len(w)-4]
+			case hasRuneSuffix(w, "ig") && len(w)-2 >= r2:
+				w = w[:len(w)-2]
+			}
+		}
+
+	case hasRuneSuffix(w, "end"), hasRuneSuffix(w, "ung"):
+		if len(w)-3 >= r2 {
+			w = w[:len(w)-3]
+			if hasRuneSuffix(w, "ig") && len(w)-2 >= r2 && !precededByE(2) {
+				w = w[:len(w)-2]
+			}
+		}
+
+	case hasRuneSuffix(w, "ig"), hasRuneSuffix(w, "ik"):
+		if len(w)-2 >= r2 && !precededByE(2) {
+			w = w[:len(w)-2]
+		}
+	}
+
+	return w
+}
+
+// germanRegion returns the start of the region after the first
+// non-vowel following a vowel, searching from start.
+func germanRegion(w []rune, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if isGermanVowel(w[i-1]) && !isGermanVowel(w[i]) {
+			return i + 1
+		}
+	}
+
+	return len(w)
+}
+
+func isGermanVowel(r rune) bool {
+	return strings.ContainsRune("aeiouyäöü", r)
+}
+
+func hasRuneSuffix(w []rune, suffix string) bool {
+	return strings.HasSuffix(string(w), suffix)
+}
+
+var (
+	germanSEndings  = []rune("bdfghklmnrt")
+	germanSTEndings = []rune("bdfghklmnt")
+)
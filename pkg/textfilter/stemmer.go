@@ -0,0 +1,66 @@
+package textfilter
+
+import (
+	"github.com/kljensen/snowball/english"
+	"github.com/kljensen/snowball/french"
+	"github.com/kljensen/snowball/hungarian"
+	"github.com/kljensen/snowball/norwegian"
+	"github.com/kljensen/snowball/russian"
+	"github.com/kljensen/snowball/spanish"
+	"github.com/kljensen/snowball/swedish"
+)
+
+// Stemmer reduces a lowercase word to its stem.
+type Stemmer interface {
+	Stem(word string) string
+}
+
+// StemmerFunc is a function implementing Stemmer.
+type StemmerFunc func(word string) string
+
+// Stem the word.
+func (f StemmerFunc) Stem(word string) string {
+	return f(word)
+}
+
+// Language is a language known to tag extraction.
+type Language struct {
+	Code string
+	Name string
+}
+
+// Languages lists the languages with a stemmer or a stop word preset.
+var Languages = []Language{
+	{Code: "en", Name: "English"},
+	{Code: "de", Name: "German"},
+	{Code: "et", Name: "Estonian"},
+	{Code: "fr", Name: "French"},
+	{Code: "es", Name: "Spanish"},
+	{Code: "sv", Name: "Swedish"},
+	{Code: "no", Name: "Norwegian"},
+	{Code: "hu", Name: "Hungarian"},
+	{Code: "ru", Name: "Russian"},
+}
+
+var stemmers = map[string]Stemmer{
+	"en": snowballStemmer(english.Stem),
+	"de": StemmerFunc(stemGerman),
+	"fr": snowballStemmer(french.Stem),
+	"es": snowballStemmer(spanish.Stem),
+	"sv": snowballStemmer(swedish.Stem),
+	"no": snowballStemmer(norwegian.Stem),
+	"hu": snowballStemmer(hungarian.Stem),
+	"ru": snowballStemmer(russian.Stem),
+}
+
+// NewStemmer returns the Snowball stemmer for a language code.
+func NewStemmer(code string) (Stemmer, bool) {
+	s, ok := stemmers[code]
+	return s, ok
+}
+
+func snowballStemmer(stem func(word string, stemStopWords bool) string) Stemmer {
+	return StemmerFunc(func(word string) string {
+		return stem(word, true)
+	})
+}
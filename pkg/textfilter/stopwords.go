@@ -0,0 +1,38 @@
+package textfilter
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+//go:embed stopwords/*.txt
+var stopWordPresets embed.FS
+
+// StopWordPresets returns languages with a bundled stop word preset.
+func StopWordPresets() []Language {
+	var presets []Language
+
+	for _, lang := range Languages {
+		if _, err := fs.Stat(stopWordPresets, stopWordPresetPath(lang.Code)); err == nil {
+			presets = append(presets, lang)
+		}
+	}
+
+	return presets
+}
+
+// LoadStopWordPreset loads the bundled stop word preset for a language code.
+func LoadStopWordPreset(code string) ([]string, error) {
+	b, err := stopWordPresets.ReadFile(stopWordPresetPath(code))
+	if err != nil {
+		return nil, fmt.Errorf("no stop word preset for language %q: %w", code, err)
+	}
+
+	return strings.Fields(string(b)), nil
+}
+
+func stopWordPresetPath(code string) string {
+	return "stopwords/" + code + ".txt"
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // EnsureQuoted ensures the string is quoted.
@@ -12,26 +13,19 @@ func EnsureQuoted(s string) string {
 	return strconv.Quote(s)
 }
 
-// PrepareFTSSearchStrings prepares an SQLITE FTS search string of quoted words.
-func PrepareFTSSearchStrings(s string) (quoted []string) {
-	fields := splitString(s)
-
-	for _, field := range fields {
-		switch field {
-		case "AND", "OR", "NOT":
-			quoted = append(quoted, field)
-
-		default:
-			quoted = append(quoted, EnsureQuoted(field))
-		}
+// EscapeFormula prefixes a spreadsheet cell with a quote
+// when it would otherwise be evaluated as a formula.
+func EscapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
 	}
 
-	return quoted
+	return s
 }
 
-// splitString splits a string by one or more runs of whitespace while
+// SplitQuoted splits a string by one or more runs of whitespace while
 // attempting to keep the most common bases of quote usage.
-func splitString(s string) []string {
+func SplitQuoted(s string) []string {
 	quoted := false
 	return strings.FieldsFunc(s, func(r rune) bool {
 		if unicode.In(r, unicode.Quotation_Mark) {
@@ -43,6 +37,33 @@ func splitString(s string) []string {
 
 // GetTags returns all lowercased words.
 func GetTags(s string) []string {
+	return NewTagExtractor(nil).GetTags(s)
+}
+
+// TagExtractor extracts tags from text.
+type TagExtractor struct {
+	stemmer   Stemmer
+	stopWords map[string]struct{}
+}
+
+// NewTagExtractor creates a new tag extractor. Stemmer is optional.
+// Stop words are excluded before and after stemming.
+func NewTagExtractor(stemmer Stemmer, stopWords ...string) *TagExtractor {
+	x := &TagExtractor{
+		stemmer:   stemmer,
+		stopWords: make(map[string]struct{}, len(stopWords)),
+	}
+
+	for _, word := range stopWords {
+		x.stopWords[strings.ToLower(word)] = struct{}{}
+	}
+
+	return x
+}
+
+// GetTags returns lowercased and optionally stemmed words
+// of at least 3 characters.
+func (x *TagExtractor) GetTags(s string) []string {
 	var tags []string
 
 	// Replace all non-letter and non-number characters with spaces.
@@ -54,13 +75,31 @@ func GetTags(s string) []string {
 	}, s)
 
 	for word := range strings.FieldsSeq(s) {
-		if len(word) >= 3 {
-			word = strings.ToLower(word)
-			tags = append(tags, word)
+		if utf8.RuneCountInString(word) < 3 {
+			continue
+		}
+
+		word = strings.ToLower(word)
+		if x.isStopWord(word) {
+			continue
 		}
+
+		if x.stemmer != nil {
+			word = x.stemmer.Stem(word)
+			if word == "" || x.isStopWord(word) {
+				continue
+			}
+		}
+
+		tags = append(tags, word)
 	}
 
 	return tags
 }
 
+func (x *TagExtractor) isStopWord(word string) bool {
+	_, ok := x.stopWords[word]
+	return ok
+}
+
 const quotes = "\"'`"
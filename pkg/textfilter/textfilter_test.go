@@ -7,51 +7,6 @@ import (
 	"github.com/mgnsk/calendar/pkg/textfilter"
 )
 
-func TestPrepareFTSSearchStrings(t *testing.T) {
-	type testcase struct {
-		source   string
-		expected []string
-	}
-
-	for _, tc := range []testcase{
-		{
-			source:   `a`,
-			expected: []string{`"a"`},
-		},
-		{
-			source:   `a b`,
-			expected: []string{`"a"`, `"b"`},
-		},
-		{
-			source:   `"one" "two"`,
-			expected: []string{`"one"`, `"two"`},
-		},
-		{
-			source:   `"one one" "two two"`,
-			expected: []string{`"one one"`, `"two two"`},
-		},
-		{
-			source:   `a AND b`,
-			expected: []string{`"a"`, `AND`, `"b"`},
-		},
-		{
-			source:   `AND AND a`,
-			expected: []string{`AND`, `AND`, `"a"`},
-		},
-		{
-			source:   `a AND AND`,
-			expected: []string{`"a"`, `AND`, `AND`},
-		},
-	} {
-		t.Run(tc.source, func(t *testing.T) {
-			result := textfilter.PrepareFTSSearchStrings(tc.source)
-			if !reflect.DeepEqual(result, tc.expected) {
-				t.Fatalf("expected %v, got %v", tc.expected, result)
-			}
-		})
-	}
-}
-
 func TestGetTags(t *testing.T) {
 	type testcase struct {
 		source   string
@@ -116,3 +71,118 @@ func TestGetTags(t *testing.T) {
 		})
 	}
 }
+
+func TestTagExtractor(t *testing.T) {
+	en, _ := textfilter.NewStemmer("en")
+	de, _ := textfilter.NewStemmer("de")
+
+	type testcase struct {
+		name      string
+		stemmer   textfilter.Stemmer
+		stopWords []string
+		source    string
+		expected  []string
+	}
+
+	for _, tc := range []testcase{
+		{
+			name:     "length is counted in characters",
+			source:   "öö ääk 日本語",
+			expected: []string{"ääk", "日本語"},
+		},
+		{
+			name:      "stop words are excluded",
+			stopWords: []string{"The"},
+			source:    "The concert",
+			expected:  []string{"concert"},
+		},
+		{
+			name:     "english words are stemmed",
+			stemmer:  en,
+			source:   "Concert concerts",
+			expected: []string{"concert", "concert"},
+		},
+		{
+			name:      "stemmed stop words are excluded",
+			stemmer:   en,
+			stopWords: []string{"concert"},
+			source:    "concerts",
+			expected:  nil,
+		},
+		{
+			name:     "german words are stemmed",
+			stemmer:  de,
+			source:   "Konzert Konzerte Konzerten",
+			expected: []string{"konzert", "konzert", "konzert"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := textfilter.NewTagExtractor(tc.stemmer, tc.stopWords...).GetTags(tc.source)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGermanStemmer(t *testing.T) {
+	stemmer, ok := textfilter.NewStemmer("de")
+	if !ok {
+		t.Fatal("expected german stemmer")
+	}
+
+	for source, expected := range map[string]string{
+		"konzerte":      "konzert",
+		"häuser":        "haus",
+		"laufen":        "lauf",
+		"möglichkeiten": "moglich",
+		"straße":        "strass",
+		"kenntnisse":    "kenntnis",
+		"aufeinander":   "aufeinand",
+	} {
+		t.Run(source, func(t *testing.T) {
+			if result := stemmer.Stem(source); result != expected {
+				t.Fatalf("expected %s, got %s", expected, result)
+			}
+		})
+	}
+}
+
+func TestLoadStopWordPreset(t *testing.T) {
+	for _, lang := range textfilter.StopWordPresets() {
+		t.Run(lang.Code, func(t *testing.T) {
+			words, err := textfilter.LoadStopWordPreset(lang.Code)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(words) == 0 {
+				t.Fatal("expected stop words")
+			}
+		})
+	}
+
+	if _, err := textfilter.LoadStopWordPreset("xx"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestEscapeFormula(t *testing.T) {
+	for source, expected := range map[string]string{
+		"":                       "",
+		"Alice":                  "Alice",
+		"alice@example.testing":  "alice@example.testing",
+		`=HYPERLINK("http://x")`: `'=HYPERLINK("http://x")`,
+		"+cmd|' /C calc'!A0":     "'+cmd|' /C calc'!A0",
+		"-2+3":                   "'-2+3",
+		"@SUM(A1)":               "'@SUM(A1)",
+		"\t=1":                   "'\t=1",
+		"\r=1":                   "'\r=1",
+	} {
+		t.Run(source, func(t *testing.T) {
+			if result := textfilter.EscapeFormula(source); result != expected {
+				t.Fatalf("expected %q, got %q", expected, result)
+			}
+		})
+	}
+}
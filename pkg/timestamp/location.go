@@ -0,0 +1,25 @@
+package timestamp
+
+import (
+	"sync"
+	"time"
+)
+
+var locations sync.Map
+
+// LoadLocation loads a time zone by IANA name, caching the result since
+// time.LoadLocation reads the time zone database on every call.
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	locations.Store(name, loc)
+
+	return loc, nil
+}
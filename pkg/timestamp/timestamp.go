@@ -1,27 +1,17 @@
 package timestamp
 
 import (
-	"fmt"
+	"time"
 )
 
-// FormatDay returns day with the ordinal suffix for day.
-func FormatDay(day int) string {
-	return fmt.Sprintf("%d%s", day, getDaySuffix(day))
-}
+// CalendarDays returns the number of calendar days from now until t
+// in the location of now.
+func CalendarDays(now, t time.Time) int {
+	t = t.In(now.Location())
 
-func getDaySuffix(n int) string {
-	if n >= 11 && n <= 13 {
-		return "th"
-	}
+	// Compare calendar dates in UTC to avoid DST affecting the day length.
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
-	switch n % 10 {
-	case 1:
-		return "st"
-	case 2:
-		return "nd"
-	case 3:
-		return "rd"
-	default:
-		return "th"
-	}
+	return int(day.Sub(today).Hours() / 24)
 }
@@ -0,0 +1,60 @@
+package timestamp_test
+
+import (
+	"time"
+
+	"github.com/mgnsk/calendar/pkg/timestamp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("calendar days",
+	func(t time.Time, expected int) {
+		now := time.Date(2025, 3, 29, 23, 30, 0, 0, time.UTC)
+		Expect(timestamp.CalendarDays(now, t)).To(Equal(expected))
+	},
+	Entry("earlier today", time.Date(2025, 3, 29, 1, 0, 0, 0, time.UTC), 0),
+	Entry("tomorrow", time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC), 1),
+	Entry("yesterday", time.Date(2025, 3, 28, 12, 0, 0, 0, time.UTC), -1),
+	Entry("in a week", time.Date(2025, 4, 5, 12, 0, 0, 0, time.UTC), 7),
+	Entry("in another time zone", time.Date(2025, 3, 30, 1, 0, 0, 0, time.FixedZone("", 2*3600)), 0),
+)
+
+var _ = Describe("time zone transitions", func() {
+	Specify("transitions cover the requested range", func() {
+		loc, err := time.LoadLocation("Europe/Tallinn")
+		Expect(err).NotTo(HaveOccurred())
+
+		transitions := timestamp.Transitions(loc,
+			time.Date(2025, 2, 1, 0, 0, 0, 0, loc),
+			time.Date(2025, 12, 1, 0, 0, 0, 0, loc),
+		)
+
+		Expect(transitions).To(HaveExactElements(
+			HaveField("IsDST", false),
+			SatisfyAll(
+				HaveField("At", BeTemporally("==", time.Date(2025, 3, 30, 1, 0, 0, 0, time.UTC))),
+				HaveField("OffsetFrom", 2*3600),
+				HaveField("OffsetTo", 3*3600),
+				HaveField("Name", "EEST"),
+				HaveField("IsDST", true),
+			),
+			SatisfyAll(
+				HaveField("At", BeTemporally("==", time.Date(2025, 10, 26, 1, 0, 0, 0, time.UTC))),
+				HaveField("OffsetFrom", 3*3600),
+				HaveField("OffsetTo", 2*3600),
+				HaveField("Name", "EET"),
+				HaveField("IsDST", false),
+			),
+		))
+	})
+
+	Specify("zones without transitions have a single entry", func() {
+		transitions := timestamp.Transitions(time.UTC, time.Now(), time.Now().Add(time.Hour))
+
+		Expect(transitions).To(HaveExactElements(SatisfyAll(
+			HaveField("At", BeZero()),
+			HaveField("OffsetTo", 0),
+		)))
+	})
+})
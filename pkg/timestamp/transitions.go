@@ -0,0 +1,72 @@
+package timestamp
+
+import (
+	"time"
+)
+
+// Transition is a change of UTC offset or abbreviation in a time zone.
+type Transition struct {
+	// At is the instant of the transition.
+	// It is zero for the first transition of a zone which has always had the same offset.
+	At time.Time
+
+	OffsetFrom int
+	OffsetTo   int
+	Name       string
+	IsDST      bool
+}
+
+// Transitions returns the transitions of loc which are needed to describe all instants
+// between from and until: the transition into the period in effect at from and all
+// transitions up to until.
+func Transitions(loc *time.Location, from, until time.Time) []Transition {
+	from = from.In(loc)
+	until = until.In(loc)
+
+	start, _ := from.ZoneBounds()
+	name, offset := from.Zone()
+
+	if start.IsZero() {
+		// The zone has always had this offset.
+		return append([]Transition{{
+			OffsetFrom: offset,
+			OffsetTo:   offset,
+			Name:       name,
+			IsDST:      from.IsDST(),
+		}}, transitionsAfter(from, until)...)
+	}
+
+	_, prevOffset := start.Add(-time.Second).Zone()
+
+	return append([]Transition{{
+		At:         start,
+		OffsetFrom: prevOffset,
+		OffsetTo:   offset,
+		Name:       name,
+		IsDST:      start.IsDST(),
+	}}, transitionsAfter(from, until)...)
+}
+
+func transitionsAfter(t, until time.Time) []Transition {
+	var result []Transition
+
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || end.After(until) {
+			return result
+		}
+
+		_, prevOffset := t.Zone()
+		name, offset := end.Zone()
+
+		result = append(result, Transition{
+			At:         end,
+			OffsetFrom: prevOffset,
+			OffsetTo:   offset,
+			Name:       name,
+			IsDST:      end.IsDST(),
+		})
+
+		t = end
+	}
+}
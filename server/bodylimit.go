@@ -0,0 +1,44 @@
+package server
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// unlimitedBodyKey is the context key of the request body without the default limit.
+const unlimitedBodyKey = "unlimited_body"
+
+// BodyLimit returns a middleware which replaces the default request body limit.
+// It must run before the request body is read, for example before CSRF checks.
+func BodyLimit(limit string) echo.MiddlewareFunc {
+	bodyLimit := middleware.BodyLimit(limit)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		limited := bodyLimit(next)
+
+		return func(c echo.Context) error {
+			if body, ok := c.Get(unlimitedBodyKey).(io.ReadCloser); ok {
+				c.Request().Body = body
+			}
+
+			return limited(c)
+		}
+	}
+}
+
+// defaultBodyLimit limits the request body size to limit bytes unless replaced with BodyLimit.
+func defaultBodyLimit(limit int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			c.Set(unlimitedBodyKey, req.Body)
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	. "github.com/mgnsk/calendar/pkg/testing"
+	"github.com/mgnsk/calendar/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("request body limits", func() {
+	var ts *httptest.Server
+
+	// readForm reads the form like the CSRF middleware.
+	readForm := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, err := c.FormParams(); err != nil {
+				return echo.ErrStatusRequestEntityTooLarge
+			}
+
+			return next(c)
+		}
+	}
+
+	ok := func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	post := func(path string, size int) int {
+		GinkgoHelper()
+
+		r := Must(ts.Client().PostForm(ts.URL+path, url.Values{
+			"value": {strings.Repeat("a", size)},
+		}))
+		defer r.Body.Close()
+
+		return r.StatusCode
+	}
+
+	BeforeEach(func() {
+		e := server.NewServer()
+
+		e.Group("", readForm).POST("/default", ok)
+		e.Group("", server.BodyLimit("3M"), readForm).POST("/large", ok)
+
+		ts = httptest.NewServer(e)
+		DeferCleanup(ts.Close)
+	})
+
+	Specify("default limit applies to other routes", func() {
+		Expect(post("/default", 1000)).To(Equal(http.StatusNoContent))
+		Expect(post("/default", 2<<20)).To(Equal(http.StatusRequestEntityTooLarge))
+	})
+
+	Specify("default limit is replaced before the body is read", func() {
+		Expect(post("/large", 2<<20)).To(Equal(http.StatusNoContent))
+		Expect(post("/large", 4<<20)).To(Equal(http.StatusRequestEntityTooLarge))
+	})
+
+	Specify("limits do not leak to other servers", func() {
+		e := server.NewServer()
+		e.Group("", readForm).POST("/large", ok)
+
+		other := httptest.NewServer(e)
+		defer other.Close()
+
+		r := Must(other.Client().PostForm(other.URL+"/large", url.Values{
+			"value": {strings.Repeat("a", 2<<20)},
+		}))
+		defer r.Body.Close()
+
+		Expect(r.StatusCode).To(Equal(http.StatusRequestEntityTooLarge))
+	})
+})
@@ -3,12 +3,15 @@ package server
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/labstack/echo/v4"
 	"github.com/mgnsk/calendar"
 	"github.com/mgnsk/calendar/domain"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/mgnsk/calendar/model"
+	"github.com/mgnsk/calendar/pkg/timestamp"
 	"github.com/uptrace/bun"
 )
 
@@ -18,7 +21,34 @@ type Context struct {
 
 	User     *domain.User
 	Settings *domain.Settings
+	Locale   *i18n.Locale
 	CSRF     string
+
+	// Timezone is the viewer time zone or nil if not known.
+	Timezone *time.Location
+}
+
+// TimezoneCookieName is the name of the viewer time zone cookie.
+const TimezoneCookieName = "timezone"
+
+// SiteLocation returns the site time zone or UTC if not configured.
+func (c *Context) SiteLocation() *time.Location {
+	if c.Settings != nil && c.Settings.Timezone != "" {
+		if loc, err := timestamp.LoadLocation(c.Settings.Timezone); err == nil {
+			return loc
+		}
+	}
+
+	return time.UTC
+}
+
+// Location returns the viewer time zone or the site time zone if not known.
+func (c *Context) Location() *time.Location {
+	if c.Timezone != nil {
+		return c.Timezone
+	}
+
+	return c.SiteLocation()
 }
 
 // HandlerFunc defines a function to serve HTTP requests, using the custom context.
@@ -37,7 +67,13 @@ func Wrap(db *bun.DB, sm *scs.SessionManager, next HandlerFunc) echo.HandlerFunc
 			CSRF:    csrf,
 		}
 
-		settings, err := model.GetSettings(c.Request().Context(), db)
+		if cookie, err := c.Cookie(TimezoneCookieName); err == nil && cookie.Value != "" {
+			if loc, err := timestamp.LoadLocation(cookie.Value); err == nil {
+				ctx.Timezone = loc
+			}
+		}
+
+		settings, err := model.GetCachedSettings(c.Request().Context(), db)
 		if err != nil {
 			if !errors.Is(err, calendar.NotFound) {
 				return err
@@ -51,6 +87,13 @@ func Wrap(db *bun.DB, sm *scs.SessionManager, next HandlerFunc) echo.HandlerFunc
 
 		ctx.Settings = settings
 
+		var defaultLocale string
+		if settings != nil {
+			defaultLocale = settings.Locale
+		}
+
+		ctx.Locale = i18n.Negotiate(c.Request().Header.Get("Accept-Language"), defaultLocale)
+
 		if sm == nil {
 			// Public endpoint.
 			return next(ctx)
@@ -12,6 +12,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/mgnsk/calendar"
 	"github.com/mgnsk/calendar/html"
+	"github.com/mgnsk/calendar/i18n"
 	"github.com/mgnsk/wreck"
 )
 
@@ -79,8 +80,9 @@ func ErrorHandler() echo.HTTPErrorHandler {
 
 		var werr *wreck.Error
 		if errors.As(err, &werr) {
-			if v, ok := wreck.Value[int](werr, calendar.KeyHTTPCode); ok {
-				code = v
+			// Note: slog stores int attributes as int64.
+			if v, ok := wreck.Value[int64](werr, calendar.KeyHTTPCode); ok {
+				code = int(v)
 			}
 			msg = cmp.Or(werr.Message(), msg)
 		} else if errors.Is(err, context.DeadlineExceeded) {
@@ -91,16 +93,18 @@ func ErrorHandler() echo.HTTPErrorHandler {
 			msg = cmp.Or(fmt.Sprint(he.Message), msg)
 		}
 
-		errText := fmt.Sprintf("Error %d: %s", code, msg)
-
 		c.Response().Status = code
 
+		locale := i18n.Negotiate(c.Request().Header.Get("Accept-Language"), "")
+
 		if err := html.Page(html.PageProps{
-			Title:        "Error",
+			Title:        locale.T("Error"),
+			Locale:       locale,
 			User:         nil,
 			Path:         c.Path(),
+			Query:        nil,
 			CSRF:         "",
-			Children:     html.ErrorMain(errText),
+			Children:     html.ErrorMain(locale, code, msg),
 			FlashSuccess: "",
 		}).Render(c.Response()); err != nil {
 			Logger(c).Error("error rendering error page", "reason", err)
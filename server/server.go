@@ -1,6 +1,7 @@
 package server
 
 import (
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -8,7 +9,8 @@ import (
 )
 
 // NewServer creates a new calendar server.
-func NewServer() *echo.Echo {
+// Image sources such as map tile servers are allowed by the content security policy.
+func NewServer(imgSources ...string) *echo.Echo {
 	e := echo.New()
 
 	e.HTTPErrorHandler = ErrorHandler()
@@ -28,11 +30,11 @@ func NewServer() *echo.Echo {
 			XSSProtection:         "1; mode=block",
 			ContentTypeNosniff:    "nosniff",
 			XFrameOptions:         "SAMEORIGIN",
-			ContentSecurityPolicy: "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval'; style-src 'self' 'unsafe-inline'; connect-src 'self' *.openstreetmap.org",
+			ContentSecurityPolicy: "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval'; style-src 'self' 'unsafe-inline'; connect-src 'self'; img-src " + strings.Join(append([]string{"'self'", "data:"}, imgSources...), " "),
 			HSTSPreloadEnabled:    false,
 		}),
 
-		middleware.BodyLimit("1M"),
+		defaultBodyLimit(1<<20),
 	)
 
 	e.Server.ReadTimeout = time.Minute
@@ -40,3 +42,17 @@ func NewServer() *echo.Echo {
 
 	return e
 }
+
+// AllowFraming allows the response to be shown in frames on the origins
+// in addition to the same origin.
+func AllowFraming(c echo.Context, origins ...string) {
+	h := c.Response().Header()
+	h.Del(echo.HeaderXFrameOptions)
+
+	policy := "frame-ancestors " + strings.Join(append([]string{"'self'"}, origins...), " ")
+	if csp := h.Get(echo.HeaderContentSecurityPolicy); csp != "" {
+		policy = csp + "; " + policy
+	}
+
+	h.Set(echo.HeaderContentSecurityPolicy, policy)
+}
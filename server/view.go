@@ -1,6 +1,8 @@
 package server
 
 import (
+	"time"
+
 	"github.com/alexedwards/scs/v2"
 	"github.com/labstack/echo/v4"
 	"github.com/mgnsk/calendar/html"
@@ -15,15 +17,29 @@ func RenderPage(
 ) error {
 	// Note: Pop must be before writing headers.
 	successMessage := sm.PopString(c.Request().Context(), "flash-success")
+	errorMessage := sm.PopString(c.Request().Context(), "flash-error")
 
 	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
 
 	return html.Page(html.PageProps{
 		Title:        c.Settings.Title,
+		Settings:     c.Settings,
+		Locale:       c.Locale,
 		User:         c.User,
 		Path:         c.Path(),
+		Query:        c.QueryParams(),
 		CSRF:         c.CSRF,
+		Timezone:     timezoneName(c.Timezone),
 		Children:     content,
 		FlashSuccess: successMessage,
+		FlashError:   errorMessage,
 	}).Render(c.Response())
 }
+
+func timezoneName(loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+
+	return loc.String()
+}